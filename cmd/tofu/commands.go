@@ -140,6 +140,30 @@ func initCommands(
 			}, nil
 		},
 
+		"encryption": func() (cli.Command, error) {
+			return &command.EncryptionCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"encryption schema": func() (cli.Command, error) {
+			return &command.EncryptionSchemaCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"encryption status": func() (cli.Command, error) {
+			return &command.EncryptionStatusCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"encryption validate": func() (cli.Command, error) {
+			return &command.EncryptionValidateCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"env": func() (cli.Command, error) {
 			return &command.WorkspaceCommand{
 				Meta:       meta,
@@ -80,6 +80,15 @@ func realMain() int {
 		Ui.Error(fmt.Sprintf("Unset environment variable %s if you don't intend to collect telemetry from OpenTofu.", openTelemetryExporterEnvVar))
 		return 1
 	}
+	shutdownMetrics, err := openTelemetryMetricsInit()
+	if err != nil {
+		// As above, this can only fail if OpenTofu was run with an explicit
+		// environment variable to enable metrics collection.
+		Ui.Error(fmt.Sprintf("Could not initialize metrics telemetry: %s", err))
+		Ui.Error(fmt.Sprintf("Unset environment variable %s if you don't intend to collect metrics from OpenTofu.", openTelemetryMetricsExporterEnvVar))
+		return 1
+	}
+	defer shutdownMetrics()
 	var ctx context.Context
 	var otelSpan trace.Span
 	{
@@ -12,6 +12,7 @@ import (
 	"go.opentelemetry.io/contrib/exporters/autoexport"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
@@ -31,6 +32,14 @@ import (
 // better based on experience with this experiment.
 const openTelemetryExporterEnvVar = "OTEL_TRACES_EXPORTER"
 
+// If this environment variable is set to "otlp" when running OpenTofu CLI
+// then we'll enable an experimental OTLP metrics exporter.
+//
+// BEWARE! This is not a committed external interface, for the same reasons
+// as described for openTelemetryExporterEnvVar. The names and attributes of
+// the exported metrics may change in any release.
+const openTelemetryMetricsExporterEnvVar = "OTEL_METRICS_EXPORTER"
+
 // tracer is the OpenTelemetry tracer to use for traces in package main only.
 var tracer trace.Tracer
 
@@ -65,11 +74,7 @@ func openTelemetryInit() error {
 		return nil // By default we just discard all telemetry calls
 	}
 
-	otelResource := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceNameKey.String("OpenTofu CLI"),
-		semconv.ServiceVersionKey.String(version.Version),
-	)
+	otelResource := openTelemetryResource()
 
 	// If the environment variable was set to explicitly enable telemetry
 	// then we'll enable it, using the "autoexport" library to automatically
@@ -91,3 +96,48 @@ func openTelemetryInit() error {
 
 	return nil
 }
+
+// openTelemetryMetricsInit initializes the optional OpenTelemetry metrics
+// exporter, following the same rules as openTelemetryInit does for traces:
+// metrics are discarded unless the standard OpenTelemetry environment
+// variable OTEL_METRICS_EXPORTER=otlp is set, in which case the OTLP exporter
+// is configured by the standard OTLP exporter environment variables.
+//
+// The returned function must be called before OpenTofu exits, to export the
+// metrics collected since the last periodic export. Most commands finish
+// long before the first periodic export would happen.
+func openTelemetryMetricsInit() (func(), error) {
+	// As for traces, the "autoexport" helper would otherwise expect to find
+	// an OTLP server on localhost if no environment variables are set.
+	if os.Getenv(openTelemetryMetricsExporterEnvVar) != "otlp" {
+		return func() {}, nil // By default we just discard all metrics
+	}
+
+	reader, err := autoexport.NewMetricReader(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(openTelemetryResource()),
+	)
+	otel.SetMeterProvider(provider)
+
+	return func() {
+		// Errors here can only be reported to the telemetry error handler,
+		// since the command itself has already completed.
+		if err := provider.Shutdown(context.Background()); err != nil {
+			otel.Handle(err)
+		}
+	}, nil
+}
+
+// openTelemetryResource describes OpenTofu CLI as the source of the exported
+// telemetry.
+func openTelemetryResource() *resource.Resource {
+	return resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String("OpenTofu CLI"),
+		semconv.ServiceVersionKey.String(version.Version),
+	)
+}
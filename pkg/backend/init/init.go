@@ -27,6 +27,7 @@ import (
 	backendKubernetes "github.com/we-dcode/opentofu/pkg/backend/remote-state/kubernetes"
 	backendOSS "github.com/we-dcode/opentofu/pkg/backend/remote-state/oss"
 	backendPg "github.com/we-dcode/opentofu/pkg/backend/remote-state/pg"
+	backendRedis "github.com/we-dcode/opentofu/pkg/backend/remote-state/redis"
 	backendS3 "github.com/we-dcode/opentofu/pkg/backend/remote-state/s3"
 	backendCloud "github.com/we-dcode/opentofu/pkg/cloud"
 )
@@ -72,6 +73,7 @@ func Init(services *disco.Disco) {
 		"kubernetes": func(enc encryption.StateEncryption) backend.Backend { return backendKubernetes.New(enc) },
 		"oss":        func(enc encryption.StateEncryption) backend.Backend { return backendOSS.New(enc) },
 		"pg":         func(enc encryption.StateEncryption) backend.Backend { return backendPg.New(enc) },
+		"redis":      func(enc encryption.StateEncryption) backend.Backend { return backendRedis.New(enc) },
 		"s3":         func(enc encryption.StateEncryption) backend.Backend { return backendS3.New(enc) },
 
 		// Terraform Cloud 'backend'
@@ -28,6 +28,7 @@ func TestInit_backend(t *testing.T) {
 		{"gcs", "*gcs.Backend"},
 		{"inmem", "*inmem.Backend"},
 		{"pg", "*pg.Backend"},
+		{"redis", "*redis.Backend"},
 		{"s3", "*s3.Backend"},
 	}
 
@@ -303,6 +303,12 @@ func (b *Local) opApply(
 		return
 	}
 
+	if r, ok := opState.(statemgr.StoredVersionReporter); ok {
+		if version := r.StoredStateVersion(); version != statemgr.UnknownStoredVersion {
+			op.View.StateVersionWritten(version)
+		}
+	}
+
 	if applyDiags.HasErrors() {
 		op.ReportResult(runningOp, diags)
 		return
@@ -327,6 +333,13 @@ func (b *Local) backupStateForError(stateFile *statefile.File, err error, view v
 		"Failed to save state",
 		fmt.Sprintf("Error saving state: %s", err),
 	))
+	if errors.Is(err, statemgr.ErrStateChanged) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"State changed since it was last read",
+			stateChangedError,
+		))
+	}
 
 	local := statemgr.NewFilesystem("errored.tfstate", b.encryption)
 	writeErr := local.WriteStateForMigration(stateFile, true)
@@ -375,6 +388,11 @@ To retry writing this state, use the following command:
     tofu state push errored.tfstate
 `
 
+const stateChangedError = `Another run has written the state in the configured backend since OpenTofu last read it, so OpenTofu didn't overwrite it to avoid losing the changes of the other run. This can happen when state locking is disabled or not supported by the backend.
+
+Run "tofu refresh" to read the latest state, and review the changes made by the other run before retrying.
+`
+
 const stateWriteConsoleFallbackError = `The errors shown above prevented OpenTofu from writing the updated state to
 the configured backend and from creating a local backup file. As a fallback,
 the raw state data is printed above as a JSON object.
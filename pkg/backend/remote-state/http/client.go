@@ -206,13 +206,20 @@ func (c *httpClient) Get() (*remote.Payload, error) {
 
 	// Check for the MD5
 	if raw := resp.Header.Get("Content-MD5"); raw != "" {
-		md5, err := base64.StdEncoding.DecodeString(raw)
+		sum, err := base64.StdEncoding.DecodeString(raw)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"Failed to decode Content-MD5 '%s': %w", raw, err)
 		}
 
-		payload.MD5 = md5
+		// Make sure the data wasn't corrupted on its way to us before we
+		// trust it.
+		if hash := md5.Sum(payload.Data); !bytes.Equal(hash[:], sum) {
+			return nil, fmt.Errorf(
+				"State corrupted in transit: the received data doesn't match the Content-MD5 '%s' sent by the server. Please try again.", raw)
+		}
+
+		payload.MD5 = sum
 	} else {
 		// Generate the MD5
 		hash := md5.Sum(payload.Data)
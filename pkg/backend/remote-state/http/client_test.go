@@ -7,12 +7,15 @@ package http
 
 import (
 	"bytes"
+	"crypto/md5"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -129,6 +132,63 @@ func TestHTTPClient(t *testing.T) {
 	remote.TestClient(t, client)
 }
 
+func TestHTTPClient_GetChecksum(t *testing.T) {
+	data := []byte(`{"version":4}`)
+	sum := md5.Sum(data)
+	wrongSum := md5.Sum([]byte("something else"))
+
+	tests := map[string]struct {
+		contentMD5 string
+		wantErr    string
+	}{
+		"no checksum": {
+			contentMD5: "",
+		},
+		"matching checksum": {
+			contentMD5: base64.StdEncoding.EncodeToString(sum[:]),
+		},
+		"mismatched checksum": {
+			contentMD5: base64.StdEncoding.EncodeToString(wrongSum[:]),
+			wantErr:    "State corrupted in transit",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.contentMD5 != "" {
+					w.Header().Set("Content-MD5", tc.contentMD5)
+				}
+				w.Write(data)
+			}))
+			defer ts.Close()
+
+			url, err := url.Parse(ts.URL)
+			if err != nil {
+				t.Fatalf("Parse: %s", err)
+			}
+			client := &httpClient{URL: url, Client: retryablehttp.NewClient()}
+
+			payload, err := client.Get()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got: %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !bytes.Equal(payload.Data, data) {
+				t.Fatalf("wrong data: %s", payload.Data)
+			}
+			if !bytes.Equal(payload.MD5, sum[:]) {
+				t.Fatalf("wrong MD5: %x", payload.MD5)
+			}
+		})
+	}
+}
+
 type testHTTPHandler struct {
 	Data   []byte
 	Locked bool
@@ -56,6 +56,21 @@ func New(enc encryption.StateEncryption) backend.Backend {
 				Optional:    true,
 				Description: "initializes the state in a locked configuration",
 			},
+			"get_delay": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "duration to wait before reading the state, such as \"100ms\"",
+			},
+			"put_delay": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "duration to wait before writing the state, such as \"100ms\"",
+			},
+			"lock_delay": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "duration to wait before locking or unlocking the state, such as \"100ms\"",
+			},
 		},
 	}
 	backend := &Backend{Backend: s, encryption: enc}
@@ -66,20 +81,38 @@ func New(enc encryption.StateEncryption) backend.Backend {
 type Backend struct {
 	*schema.Backend
 	encryption encryption.StateEncryption
+
+	// Artificial latency applied to the operations of the remote clients
+	// created by this backend, to simulate slow backends in tests.
+	getDelay  time.Duration
+	putDelay  time.Duration
+	lockDelay time.Duration
 }
 
 func (b *Backend) configure(ctx context.Context) error {
 	states.Lock()
 	defer states.Unlock()
 
-	defaultClient := &RemoteClient{
-		Name: backend.DefaultStateName,
+	data := schema.FromContextBackendConfig(ctx)
+	for key, delay := range map[string]*time.Duration{
+		"get_delay":  &b.getDelay,
+		"put_delay":  &b.putDelay,
+		"lock_delay": &b.lockDelay,
+	} {
+		v, ok := data.GetOk(key)
+		if !ok || v.(string) == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v.(string))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		*delay = d
 	}
 
-	states.m[backend.DefaultStateName] = remote.NewState(defaultClient, b.encryption)
+	states.m[backend.DefaultStateName] = remote.NewState(b.newClient(backend.DefaultStateName), b.encryption)
 
 	// set the default client lock info per the test config
-	data := schema.FromContextBackendConfig(ctx)
 	if v, ok := data.GetOk("lock_id"); ok && v.(string) != "" {
 		info := statemgr.NewLockInfo()
 		info.ID = v.(string)
@@ -124,12 +157,7 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 
 	s := states.m[name]
 	if s == nil {
-		s = remote.NewState(
-			&RemoteClient{
-				Name: name,
-			},
-			b.encryption,
-		)
+		s = remote.NewState(b.newClient(name), b.encryption)
 		states.m[name] = s
 
 		// to most closely replicate other implementations, we are going to
@@ -156,6 +184,17 @@ func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	return s, nil
 }
 
+// newClient returns a remote client for the named state, configured with the
+// artificial latency of the backend.
+func (b *Backend) newClient(name string) *RemoteClient {
+	return &RemoteClient{
+		Name:      name,
+		GetDelay:  b.getDelay,
+		PutDelay:  b.putDelay,
+		LockDelay: b.lockDelay,
+	}
+}
+
 type stateMap struct {
 	sync.Mutex
 	m map[string]*remote.State
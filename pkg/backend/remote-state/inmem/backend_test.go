@@ -9,6 +9,7 @@ import (
 	"flag"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 
@@ -54,6 +55,38 @@ func TestBackendConfig(t *testing.T) {
 	}
 }
 
+func TestBackendConfig_delays(t *testing.T) {
+	defer Reset()
+
+	config := map[string]interface{}{
+		"get_delay":  "20ms",
+		"put_delay":  "30ms",
+		"lock_delay": "40ms",
+	}
+
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(config)).(*Backend)
+
+	for _, name := range []string{backend.DefaultStateName, "other"} {
+		s, err := b.StateMgr(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := s.(*remote.State).Client.(*RemoteClient)
+		if c.GetDelay != 20*time.Millisecond || c.PutDelay != 30*time.Millisecond || c.LockDelay != 40*time.Millisecond {
+			t.Fatalf("wrong delays for %q: get %s, put %s, lock %s", name, c.GetDelay, c.PutDelay, c.LockDelay)
+		}
+
+		start := time.Now()
+		if _, err := c.Get(); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed < c.GetDelay {
+			t.Fatalf("Get returned after %s, before the configured delay", elapsed)
+		}
+	}
+}
+
 func TestBackend(t *testing.T) {
 	defer Reset()
 	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), hcl.EmptyBody()).(*Backend)
@@ -6,47 +6,124 @@
 package inmem
 
 import (
+	"bytes"
 	"crypto/md5"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/we-dcode/opentofu/pkg/states/remote"
 	"github.com/we-dcode/opentofu/pkg/states/statemgr"
 )
 
 // RemoteClient is a remote client that stores data in memory for testing.
+//
+// Get, Put, and Delete are safe for concurrent use.
 type RemoteClient struct {
+	// mu guards Data, MD5, and puts.
+	mu sync.RWMutex
+
 	Data []byte
 	MD5  []byte
 	Name string
+
+	// GetDelay, PutDelay, and LockDelay are the durations to wait before
+	// reading, writing, and locking or unlocking the state respectively.
+	// These simulate the latency of a real remote backend in tests.
+	GetDelay  time.Duration
+	PutDelay  time.Duration
+	LockDelay time.Duration
+
+	// FailLock makes every call to Lock fail with a statemgr.LockError, to
+	// simulate a backend which is unable to acquire locks.
+	FailLock bool
+
+	// FailPutAfter, when greater than zero, makes every call to Put fail once
+	// that many calls have succeeded, to simulate a backend which stops
+	// accepting writes.
+	FailPutAfter int
+
+	// puts counts the successful calls to Put, for FailPutAfter.
+	puts int
 }
 
 func (c *RemoteClient) Get() (*remote.Payload, error) {
+	time.Sleep(c.GetDelay)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	if c.Data == nil {
 		return nil, nil
 	}
 
+	// Return copies, so that callers can't modify the stored state.
 	return &remote.Payload{
-		Data: c.Data,
-		MD5:  c.MD5,
+		Data: bytes.Clone(c.Data),
+		MD5:  bytes.Clone(c.MD5),
 	}, nil
 }
 
 func (c *RemoteClient) Put(data []byte) error {
+	time.Sleep(c.PutDelay)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.put(data)
+}
+
+// PutIfMatch implements remote.ClientConditionalPutter, using the MD5 of the
+// stored state as its version.
+func (c *RemoteClient) PutIfMatch(data []byte, md5 []byte) error {
+	time.Sleep(c.PutDelay)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !bytes.Equal(c.MD5, md5) {
+		return fmt.Errorf("writing state %q: %w", c.Name, statemgr.ErrStateChanged)
+	}
+	return c.put(data)
+}
+
+// put stores data. The caller must hold c.mu.
+func (c *RemoteClient) put(data []byte) error {
+	if c.FailPutAfter > 0 && c.puts >= c.FailPutAfter {
+		return fmt.Errorf("simulated failure writing state %q after %d successful writes", c.Name, c.puts)
+	}
+	c.puts++
+
 	md5 := md5.Sum(data)
 
-	c.Data = data
+	c.Data = bytes.Clone(data)
 	c.MD5 = md5[:]
 	return nil
 }
 
 func (c *RemoteClient) Delete() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.Data = nil
 	c.MD5 = nil
 	return nil
 }
 
 func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
+	time.Sleep(c.LockDelay)
+
+	if c.FailLock {
+		return "", &statemgr.LockError{
+			Err: fmt.Errorf("simulated failure locking state %q", c.Name),
+		}
+	}
+
 	return locks.lock(c.Name, info)
 }
+
 func (c *RemoteClient) Unlock(id string) error {
+	time.Sleep(c.LockDelay)
+
 	return locks.unlock(c.Name, id)
 }
@@ -6,12 +6,20 @@
 package inmem
 
 import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/we-dcode/opentofu/pkg/backend"
 	"github.com/we-dcode/opentofu/pkg/encryption"
+	statespkg "github.com/we-dcode/opentofu/pkg/states"
 	"github.com/we-dcode/opentofu/pkg/states/remote"
+	"github.com/we-dcode/opentofu/pkg/states/statemgr"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestRemoteClient_impl(t *testing.T) {
@@ -40,3 +48,212 @@ func TestInmemLocks(t *testing.T) {
 
 	remote.TestRemoteLocks(t, s.(*remote.State).Client, s.(*remote.State).Client)
 }
+
+func TestRemoteClient_failLock(t *testing.T) {
+	defer Reset()
+
+	c := &RemoteClient{Name: "fail-lock", FailLock: true}
+
+	_, err := c.Lock(statemgr.NewLockInfo())
+	if err == nil {
+		t.Fatal("expected lock to fail")
+	}
+
+	var lockErr *statemgr.LockError
+	if !errors.As(err, &lockErr) {
+		t.Fatalf("expected a statemgr.LockError, got %T", err)
+	}
+	if lockErr.Retriable() {
+		t.Fatal("simulated lock failures should not be retriable")
+	}
+
+	// The failure must not leave a lock behind.
+	c.FailLock = false
+	id, err := c.Lock(statemgr.NewLockInfo())
+	if err != nil {
+		t.Fatalf("unexpected error locking state: %s", err)
+	}
+	if err := c.Unlock(id); err != nil {
+		t.Fatalf("unexpected error unlocking state: %s", err)
+	}
+}
+
+func TestRemoteClient_failPutAfter(t *testing.T) {
+	c := &RemoteClient{Name: "fail-put", FailPutAfter: 2}
+
+	for i := 0; i < 2; i++ {
+		if err := c.Put([]byte("data")); err != nil {
+			t.Fatalf("unexpected error on write %d: %s", i+1, err)
+		}
+	}
+
+	if err := c.Put([]byte("more data")); err == nil {
+		t.Fatal("expected the third write to fail")
+	}
+
+	// The failed write must not change the stored state.
+	p, err := c.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(p.Data) != "data" {
+		t.Fatalf("unexpected state data %q", p.Data)
+	}
+}
+
+func TestRemoteClient_concurrent(t *testing.T) {
+	c := &RemoteClient{Name: "concurrent"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := c.Put([]byte(fmt.Sprintf("data %d", i))); err != nil {
+				t.Errorf("unexpected error writing state: %s", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get(); err != nil {
+				t.Errorf("unexpected error reading state: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	p, err := c.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := md5.Sum(p.Data); !bytes.Equal(p.MD5, want[:]) {
+		t.Fatalf("MD5 %x does not match data %q", p.MD5, p.Data)
+	}
+
+	// Modifying the returned payload must not modify the stored state.
+	p.Data[0] = 'X'
+	again, err := c.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Data[0] == 'X' {
+		t.Fatal("Get returned the internal state data")
+	}
+}
+
+func TestRemoteClient_putIfMatch(t *testing.T) {
+	c := &RemoteClient{Name: "put-if-match"}
+
+	// Without stored state, only a write expecting no state succeeds.
+	if err := c.PutIfMatch([]byte("data"), []byte("wrong")); !errors.Is(err, statemgr.ErrStateChanged) {
+		t.Fatalf("expected statemgr.ErrStateChanged, got: %v", err)
+	}
+	if err := c.PutIfMatch([]byte("data"), nil); err != nil {
+		t.Fatalf("unexpected error writing state: %s", err)
+	}
+
+	p, err := c.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PutIfMatch([]byte("new data"), p.MD5); err != nil {
+		t.Fatalf("unexpected error writing state: %s", err)
+	}
+
+	// The state read before the last write is outdated now.
+	err = c.PutIfMatch([]byte("stale data"), p.MD5)
+	if !errors.Is(err, statemgr.ErrStateChanged) {
+		t.Fatalf("expected statemgr.ErrStateChanged, got: %v", err)
+	}
+	p, err = c.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(p.Data) != "new data" {
+		t.Fatalf("unexpected state data %q", p.Data)
+	}
+}
+
+func TestRemoteClient_concurrentPersist(t *testing.T) {
+	c := &RemoteClient{Name: "concurrent-persist"}
+
+	// Two state managers which read the same state, like two concurrent runs
+	// without locking.
+	s1 := remote.NewState(c, encryption.StateEncryptionDisabled())
+	s2 := remote.NewState(c, encryption.StateEncryptionDisabled())
+	s1.EnableConditionalWrites()
+	s2.EnableConditionalWrites()
+
+	if err := s1.WriteState(statespkg.NewState()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s1.PersistState(nil); err != nil {
+		t.Fatalf("unexpected error persisting state: %s", err)
+	}
+	for _, s := range []*remote.State{s1, s2} {
+		if err := s.RefreshState(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state := statespkg.NewState()
+	state.RootModule().SetOutputValue("foo", cty.StringVal("one"), false)
+	if err := s1.WriteState(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := s1.PersistState(nil); err != nil {
+		t.Fatalf("unexpected error persisting state: %s", err)
+	}
+
+	// The second write must not clobber the first one.
+	state = statespkg.NewState()
+	state.RootModule().SetOutputValue("foo", cty.StringVal("two"), false)
+	if err := s2.WriteState(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.PersistState(nil); !errors.Is(err, statemgr.ErrStateChanged) {
+		t.Fatalf("expected statemgr.ErrStateChanged, got: %v", err)
+	}
+
+	// After a refresh, the second state manager can write again.
+	if err := s2.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.WriteState(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.PersistState(nil); err != nil {
+		t.Fatalf("unexpected error persisting state: %s", err)
+	}
+
+	// Now the first state manager is outdated as well.
+	state.RootModule().SetOutputValue("foo", cty.StringVal("three"), false)
+	if err := s1.WriteState(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := s1.PersistState(nil); !errors.Is(err, statemgr.ErrStateChanged) {
+		t.Fatalf("expected statemgr.ErrStateChanged, got: %v", err)
+	}
+}
+
+func TestRemoteClient_concurrentPersistUnconditional(t *testing.T) {
+	c := &RemoteClient{Name: "concurrent-persist-unconditional"}
+
+	// Without conditional writes, the last write wins.
+	s1 := remote.NewState(c, encryption.StateEncryptionDisabled())
+	s2 := remote.NewState(c, encryption.StateEncryptionDisabled())
+	for _, s := range []*remote.State{s1, s2} {
+		if err := s.RefreshState(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, s := range []*remote.State{s1, s2} {
+		if err := s.WriteState(statespkg.NewState()); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.PersistState(nil); err != nil {
+			t.Fatalf("unexpected error persisting state: %s", err)
+		}
+	}
+}
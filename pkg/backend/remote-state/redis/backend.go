@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/we-dcode/opentofu/pkg/backend"
+	"github.com/we-dcode/opentofu/pkg/encryption"
+	"github.com/we-dcode/opentofu/pkg/legacy/helper/schema"
+)
+
+// New creates a new backend for Redis remote state.
+func New(enc encryption.StateEncryption) backend.Backend {
+	s := &schema.Backend{
+		Schema: map[string]*schema.Schema{
+			"key": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Key to store state in Redis",
+			},
+
+			"address": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Address of the Redis server in the format 'host:port'",
+				DefaultFunc: schema.EnvDefaultFunc("REDIS_ADDR", "localhost:6379"),
+			},
+
+			"username": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Username to authenticate with using Redis ACLs",
+				DefaultFunc: schema.EnvDefaultFunc("REDIS_USERNAME", ""),
+			},
+
+			"password": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password to authenticate with",
+				DefaultFunc: schema.EnvDefaultFunc("REDIS_PASSWORD", ""),
+			},
+
+			"database": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Number of the Redis database to select",
+				Default:     0,
+			},
+		},
+	}
+
+	result := &Backend{Backend: s, encryption: enc}
+	result.Backend.ConfigureFunc = result.configure
+	return result
+}
+
+type Backend struct {
+	*schema.Backend
+	encryption encryption.StateEncryption
+
+	// The fields below are set from configure
+	client *redis.Client
+	key    string
+}
+
+func (b *Backend) configure(ctx context.Context) error {
+	// Grab the resource data
+	data := schema.FromContextBackendConfig(ctx)
+
+	b.key = data.Get("key").(string)
+	if b.key == "" {
+		return fmt.Errorf("key must not be empty")
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     data.Get("address").(string),
+		Username: data.Get("username").(string),
+		Password: data.Get("password").(string),
+		DB:       data.Get("database").(int),
+	})
+
+	// Check the connection and the credentials up front, so a
+	// misconfiguration is reported during init.
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	b.client = client
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/we-dcode/opentofu/pkg/backend"
+	"github.com/we-dcode/opentofu/pkg/states"
+	"github.com/we-dcode/opentofu/pkg/states/remote"
+	"github.com/we-dcode/opentofu/pkg/states/statemgr"
+)
+
+const (
+	keyEnvPrefix = "-env:"
+)
+
+func (b *Backend) Workspaces() ([]string, error) {
+	ctx := context.TODO()
+
+	// Find the state keys of all workspaces. SCAN may return a key more than
+	// once, so we use a map to remove the duplicates.
+	prefix := b.key + keyEnvPrefix
+	envs := map[string]struct{}{}
+	iter := b.client.Scan(ctx, 0, escapeGlob(prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := strings.TrimPrefix(iter.Val(), prefix)
+
+		// Ignore anything with a "/" in it, such as the lock keys, since
+		// workspace names can't contain one.
+		if strings.ContainsRune(key, '/') {
+			continue
+		}
+
+		envs[key] = struct{}{}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(envs))
+	for k := range envs {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+
+	return append([]string{backend.DefaultStateName}, result...), nil
+}
+
+func (b *Backend) DeleteWorkspace(name string, _ bool) error {
+	if name == backend.DefaultStateName || name == "" {
+		return fmt.Errorf("can't delete default state")
+	}
+
+	// Delete it. We just delete it without any locking since
+	// the DeleteState API is documented as such.
+	return b.client.Del(context.TODO(), b.path(name)).Err()
+}
+
+func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
+	var stateMgr = remote.NewState(
+		&RemoteClient{
+			Client: b.client,
+			Key:    b.path(name),
+		},
+		b.encryption,
+	)
+
+	// the default state always exists
+	if name == backend.DefaultStateName {
+		return stateMgr, nil
+	}
+
+	// Grab a lock, we use this to write an empty state if one doesn't
+	// exist already. We have to write an empty state as a sentinel value
+	// so Workspaces() knows it exists.
+	lockInfo := statemgr.NewLockInfo()
+	lockInfo.Operation = "init"
+	lockId, err := stateMgr.Lock(lockInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock state in Redis: %w", err)
+	}
+
+	// Local helper function so we can call it multiple places
+	lockUnlock := func(parent error) error {
+		if err := stateMgr.Unlock(lockId); err != nil {
+			return fmt.Errorf(strings.TrimSpace(errStateUnlock), lockId, err)
+		}
+
+		return parent
+	}
+
+	// Grab the value
+	if err := stateMgr.RefreshState(); err != nil {
+		err = lockUnlock(err)
+		return nil, err
+	}
+
+	// If we have no state, we have to create an empty state
+	if v := stateMgr.State(); v == nil {
+		if err := stateMgr.WriteState(states.NewState()); err != nil {
+			err = lockUnlock(err)
+			return nil, err
+		}
+		if err := stateMgr.PersistState(nil); err != nil {
+			err = lockUnlock(err)
+			return nil, err
+		}
+	}
+
+	// Unlock, the state should now be initialized
+	if err := lockUnlock(nil); err != nil {
+		return nil, err
+	}
+
+	return stateMgr, nil
+}
+
+func (b *Backend) path(name string) string {
+	if name == backend.DefaultStateName {
+		return b.key
+	}
+	return b.key + keyEnvPrefix + name
+}
+
+// escapeGlob escapes the characters of s that have a special meaning in the
+// patterns of the SCAN command.
+func escapeGlob(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+const errStateUnlock = `
+Error unlocking Redis state. Lock ID: %s
+
+Error: %w
+
+You may have to force-unlock this state in order to use it again.
+The Redis backend acquires a lock during initialization to ensure
+the minimum required keys are prepared.
+`
@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redis
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/we-dcode/opentofu/pkg/backend"
+	"github.com/we-dcode/opentofu/pkg/encryption"
+)
+
+func TestBackend_impl(t *testing.T) {
+	var _ backend.Backend = new(Backend)
+}
+
+// newRedisTestBackend returns a backend configured to store its state at key
+// in the given in-memory Redis server.
+func newRedisTestBackend(t *testing.T, srv *miniredis.Miniredis, key string) backend.Backend {
+	t.Helper()
+
+	return backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
+		"address": srv.Addr(),
+		"key":     key,
+	}))
+}
+
+func TestBackend(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	key := fmt.Sprintf("tf-unit/%s", time.Now().String())
+
+	// Get the backend. We need two to test locking.
+	b1 := newRedisTestBackend(t, srv, key)
+	b2 := newRedisTestBackend(t, srv, key)
+
+	// Test
+	backend.TestBackendStates(t, b1)
+	backend.TestBackendStateLocks(t, b1, b2)
+	backend.TestBackendStateForceUnlock(t, b1, b2)
+}
+
+func TestBackend_globKey(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	// Workspaces of a key with glob characters must not pick up the
+	// workspaces of other keys which happen to match the pattern.
+	other := newRedisTestBackend(t, srv, "tf-unit-x")
+	if _, err := other.StateMgr("foo"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	b := newRedisTestBackend(t, srv, "tf-unit-?")
+	backend.TestBackendStates(t, b)
+}
+
+func TestBackend_auth(t *testing.T) {
+	srv := miniredis.RunT(t)
+	srv.RequireUserAuth("tofu", "secret")
+
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
+		"address":  srv.Addr(),
+		"key":      "tf-unit",
+		"username": "tofu",
+		"password": "secret",
+	}))
+	backend.TestBackendStates(t, b)
+
+	// Wrong credentials must fail when configuring the backend.
+	_, _, errs := backend.TestBackendConfigWarningsAndErrors(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
+		"address":  srv.Addr(),
+		"key":      "tf-unit",
+		"username": "tofu",
+		"password": "wrong",
+	}))
+	if len(errs) == 0 {
+		t.Fatal("expected an error configuring the backend with the wrong password")
+	}
+}
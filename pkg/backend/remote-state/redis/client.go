@@ -0,0 +1,135 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redis
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/we-dcode/opentofu/pkg/states/remote"
+	"github.com/we-dcode/opentofu/pkg/states/statemgr"
+)
+
+const (
+	lockSuffix = "/.lock"
+)
+
+// unlockScript deletes the lock key only if it still holds the lock with the
+// given ID, so a client can never release a lock taken by someone else. It
+// returns 1 if the lock was released, 0 if the state wasn't locked, and -1 if
+// the state is locked with a different ID.
+var unlockScript = redis.NewScript(`
+local info = redis.call("GET", KEYS[1])
+if not info then
+	return 0
+end
+if cjson.decode(info)["ID"] ~= ARGV[1] then
+	return -1
+end
+redis.call("DEL", KEYS[1])
+return 1
+`)
+
+// RemoteClient is a remote client that stores data in Redis.
+type RemoteClient struct {
+	Client *redis.Client
+	Key    string
+}
+
+func (c *RemoteClient) Get() (*remote.Payload, error) {
+	data, err := c.Client.Get(context.TODO(), c.Key).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+		// No existing state returns empty.
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	md5 := md5.Sum(data)
+	return &remote.Payload{
+		Data: data,
+		MD5:  md5[:],
+	}, nil
+}
+
+func (c *RemoteClient) Put(data []byte) error {
+	return c.Client.Set(context.TODO(), c.Key, data, 0).Err()
+}
+
+func (c *RemoteClient) Delete() error {
+	return c.Client.Del(context.TODO(), c.Key).Err()
+}
+
+func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
+	info.Path = c.Key
+
+	// SET NX only succeeds if nobody holds the lock, which makes checking and
+	// taking the lock a single atomic operation.
+	ok, err := c.Client.SetNX(context.TODO(), c.lockKey(), info.Marshal(), 0).Result()
+	if err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+	if !ok {
+		lockErr := &statemgr.LockError{Err: errors.New("state locked")}
+		lockInfo, err := c.getLockInfo()
+		if err != nil {
+			lockErr.Err = errors.Join(lockErr.Err, err)
+		}
+		lockErr.Info = lockInfo
+		return "", lockErr
+	}
+
+	return info.ID, nil
+}
+
+func (c *RemoteClient) Unlock(id string) error {
+	res, err := unlockScript.Run(context.TODO(), c.Client, []string{c.lockKey()}, id).Int()
+	if err != nil {
+		return &statemgr.LockError{Err: err}
+	}
+
+	switch res {
+	case 0:
+		return &statemgr.LockError{Err: errors.New("state not locked")}
+	case -1:
+		lockErr := &statemgr.LockError{Err: fmt.Errorf("lock id %q does not match existing lock", id)}
+		lockInfo, err := c.getLockInfo()
+		if err != nil {
+			lockErr.Err = errors.Join(lockErr.Err, err)
+		}
+		lockErr.Info = lockInfo
+		return lockErr
+	}
+
+	return nil
+}
+
+// getLockInfo returns the information of the current lock, or nil if the
+// state isn't locked.
+func (c *RemoteClient) getLockInfo() (*statemgr.LockInfo, error) {
+	data, err := c.Client.Get(context.TODO(), c.lockKey()).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	info := &statemgr.LockInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("failed to decode lock info: %w", err)
+	}
+	return info, nil
+}
+
+func (c *RemoteClient) lockKey() string {
+	return c.Key + lockSuffix
+}
@@ -0,0 +1,101 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/we-dcode/opentofu/pkg/backend"
+	"github.com/we-dcode/opentofu/pkg/states/remote"
+	"github.com/we-dcode/opentofu/pkg/states/statemgr"
+)
+
+func TestRemoteClient_impl(t *testing.T) {
+	var _ remote.Client = new(RemoteClient)
+	var _ remote.ClientLocker = new(RemoteClient)
+}
+
+func TestRemoteClient(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	b := newRedisTestBackend(t, srv, fmt.Sprintf("tf-unit/%s", time.Now().String()))
+
+	// Grab the client
+	state, err := b.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Test
+	remote.TestClient(t, state.(*remote.State).Client)
+}
+
+func TestRemoteClient_locks(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	key := fmt.Sprintf("tf-unit/%s", time.Now().String())
+
+	s1, err := newRedisTestBackend(t, srv, key).StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	s2, err := newRedisTestBackend(t, srv, key).StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+}
+
+func TestRemoteClient_unlockWrongID(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	s, err := newRedisTestBackend(t, srv, "tf-unit").StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c := s.(*remote.State).Client.(*RemoteClient)
+
+	var lockErr *statemgr.LockError
+	if err := c.Unlock("missing"); !errors.As(err, &lockErr) {
+		t.Fatalf("expected a LockError unlocking an unlocked state, got: %v", err)
+	}
+
+	info := statemgr.NewLockInfo()
+	info.Operation = "test"
+	id, err := c.Lock(info)
+	if err != nil {
+		t.Fatalf("unable to get lock: %s", err)
+	}
+
+	// Unlocking with the wrong ID must not release the lock, and must report
+	// who holds it.
+	err = c.Unlock("wrong")
+	if !errors.As(err, &lockErr) {
+		t.Fatalf("expected a LockError, got: %v", err)
+	}
+	if lockErr.Info == nil || lockErr.Info.ID != id {
+		t.Fatalf("expected the lock info of lock %q, got: %#v", id, lockErr.Info)
+	}
+	if lockErr.Info.Path != c.Key {
+		t.Fatalf("expected lock path %q, got %q", c.Key, lockErr.Info.Path)
+	}
+	if !srv.Exists(c.Key + lockSuffix) {
+		t.Fatal("lock was released with the wrong ID")
+	}
+
+	if err := c.Unlock(id); err != nil {
+		t.Fatalf("error unlocking: %s", err)
+	}
+	if srv.Exists(c.Key + lockSuffix) {
+		t.Fatal("lock was not released")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/big"
 	"net/http"
 	"net/url"
 	"os"
@@ -43,6 +44,9 @@ const (
 	stateServiceID     = "state.v2"
 	tfeServiceID       = "tfe.v2.1"
 	genericHostname    = "localtofu.com"
+
+	defaultStateUploadMaxAttempts = 1
+	defaultStateUploadRetryDelay  = time.Second
 )
 
 // Remote is an implementation of EnhancedBackend that performs all
@@ -97,6 +101,19 @@ type Remote struct {
 	// a warning diagnostic instead of an error.
 	ignoreVersionConflict bool
 
+	// compressState, if true, will gzip compress the state before it is
+	// uploaded to the remote backend.
+	compressState bool
+
+	// stateUploadMaxAttempts and stateUploadRetryDelay configure the retries
+	// of state uploads which fail with a transient error.
+	stateUploadMaxAttempts int
+	stateUploadRetryDelay  time.Duration
+
+	// conditionalStateWrites, if true, will refuse to upload a state if the
+	// stored state changed since it was last read.
+	conditionalStateWrites bool
+
 	encryption encryption.StateEncryption
 }
 
@@ -131,6 +148,26 @@ func (b *Remote) ConfigSchema() *configschema.Block {
 				Optional:    true,
 				Description: schemaDescriptions["token"],
 			},
+			"compress_state": {
+				Type:        cty.Bool,
+				Optional:    true,
+				Description: schemaDescriptions["compress_state"],
+			},
+			"state_upload_max_attempts": {
+				Type:        cty.Number,
+				Optional:    true,
+				Description: schemaDescriptions["state_upload_max_attempts"],
+			},
+			"state_upload_retry_delay": {
+				Type:        cty.String,
+				Optional:    true,
+				Description: schemaDescriptions["state_upload_retry_delay"],
+			},
+			"conditional_state_writes": {
+				Type:        cty.Bool,
+				Optional:    true,
+				Description: schemaDescriptions["conditional_state_writes"],
+			},
 		},
 
 		BlockTypes: map[string]*configschema.NestedBlock{
@@ -201,6 +238,28 @@ func (b *Remote) PrepareConfig(obj cty.Value) (cty.Value, tfdiags.Diagnostics) {
 		))
 	}
 
+	if val := obj.GetAttr("state_upload_max_attempts"); !val.IsNull() {
+		if attempts, acc := val.AsBigFloat().Int64(); acc != big.Exact || attempts < 1 {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid state_upload_max_attempts value",
+				`The "state_upload_max_attempts" attribute value must be a whole number of at least 1.`,
+				cty.Path{cty.GetAttrStep{Name: "state_upload_max_attempts"}},
+			))
+		}
+	}
+
+	if val := obj.GetAttr("state_upload_retry_delay"); !val.IsNull() {
+		if delay, err := time.ParseDuration(val.AsString()); err != nil || delay < 0 {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid state_upload_retry_delay value",
+				`The "state_upload_retry_delay" attribute value must be a non-negative duration, like "2s".`,
+				cty.Path{cty.GetAttrStep{Name: "state_upload_retry_delay"}},
+			))
+		}
+	}
+
 	return obj, diags
 }
 
@@ -262,6 +321,28 @@ func (b *Remote) Configure(obj cty.Value) tfdiags.Diagnostics {
 		}
 	}
 
+	// Get the state compression setting.
+	if val := obj.GetAttr("compress_state"); !val.IsNull() {
+		b.compressState = val.True()
+	}
+
+	// Get the state upload retry settings, which were validated in
+	// PrepareConfig.
+	b.stateUploadMaxAttempts = defaultStateUploadMaxAttempts
+	if val := obj.GetAttr("state_upload_max_attempts"); !val.IsNull() {
+		attempts, _ := val.AsBigFloat().Int64()
+		b.stateUploadMaxAttempts = int(attempts)
+	}
+	b.stateUploadRetryDelay = defaultStateUploadRetryDelay
+	if val := obj.GetAttr("state_upload_retry_delay"); !val.IsNull() {
+		b.stateUploadRetryDelay, _ = time.ParseDuration(val.AsString())
+	}
+
+	// Get the conditional state write setting.
+	if val := obj.GetAttr("conditional_state_writes"); !val.IsNull() {
+		b.conditionalStateWrites = val.True()
+	}
+
 	// Determine if we are forced to use the local backend.
 	b.forceLocal = os.Getenv("TF_FORCE_LOCAL_BACKEND") != ""
 
@@ -708,7 +789,10 @@ func (b *Remote) StateMgr(name string) (statemgr.Full, error) {
 		// This is optionally set during OpenTofu Enterprise runs.
 		runID: os.Getenv("TFE_RUN_ID"),
 
-		encryption: b.encryption,
+		compress:          b.compressState,
+		uploadMaxAttempts: b.stateUploadMaxAttempts,
+		uploadRetryDelay:  b.stateUploadRetryDelay,
+		encryption:        b.encryption,
 	}
 
 	state := remote.NewState(client, b.encryption)
@@ -722,6 +806,9 @@ func (b *Remote) StateMgr(name string) (statemgr.Full, error) {
 		// by this special case.
 		state.DisableIntermediateSnapshots()
 	}
+	if b.conditionalStateWrites {
+		state.EnableConditionalWrites()
+	}
 	return state, nil
 }
 
@@ -1109,4 +1196,13 @@ var schemaDescriptions = map[string]string{
 	"prefix": "A prefix used to filter workspaces using a single configuration. New workspaces\n" +
 		"will automatically be prefixed with this prefix. If omitted only the default\n" +
 		"workspace can be used. This option conflicts with \"name\"",
+	"compress_state": "Whether to gzip compress the state before uploading it. Compressed states can\n" +
+		"only be read by OpenTofu versions which support state compression.",
+	"state_upload_max_attempts": "The number of times to attempt a state upload which fails with a transient\n" +
+		"error, such as a server or network error. Defaults to 1, which disables retries.",
+	"state_upload_retry_delay": "The delay before the first retry of a failed state upload, which doubles after\n" +
+		"each further attempt. Defaults to \"1s\".",
+	"conditional_state_writes": "Whether to refuse to upload a state if the stored state changed since it was\n" +
+		"last read. This is checked right before the upload, so it doesn't exclude every\n" +
+		"concurrent write.",
 }
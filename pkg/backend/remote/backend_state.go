@@ -7,13 +7,20 @@ package remote
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
 
 	tfe "github.com/hashicorp/go-tfe"
 
@@ -33,6 +40,60 @@ type remoteClient struct {
 	workspace      *tfe.Workspace
 	forcePush      bool
 	encryption     encryption.StateEncryption
+
+	// compress, if true, gzip compresses the state before uploading it.
+	// Compressed states are recognized by the gzip header when reading, so
+	// uncompressed states stored by other clients remain readable.
+	compress bool
+
+	// uploadMaxAttempts is the number of times a state upload is attempted
+	// if it fails with a transient error. uploadRetryDelay is the delay
+	// before the first retry, which doubles for every further retry.
+	uploadMaxAttempts int
+	uploadRetryDelay  time.Duration
+
+	// stateVersion is the state version created by the last successful
+	// Put, or nil if no state was stored yet.
+	stateVersion *tfe.StateVersion
+
+	// lastVersionID is the ID of the state version last read by Get or
+	// created by Put, or "" if no state was stored, and lastMD5 is the MD5
+	// of its state as returned by Get. PutIfMatch uses them to tell whether
+	// the stored state changed. They're only set if hasLastVersion is true.
+	lastVersionID  string
+	lastMD5        []byte
+	hasLastVersion bool
+}
+
+// gzipMagic is the header all gzip streams start with. A state file always
+// starts with a JSON object, so this unambiguously marks a compressed state.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compressState gzip compresses the given state for upload.
+func compressState(state []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(state); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressState returns the given downloaded state, decompressing it first
+// if it was compressed by compressState.
+func decompressState(state []byte) ([]byte, error) {
+	if !bytes.HasPrefix(state, gzipMagic) {
+		return state, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(state))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
 }
 
 // Get the remote state.
@@ -43,6 +104,7 @@ func (r *remoteClient) Get() (*remote.Payload, error) {
 	if err != nil {
 		if err == tfe.ErrResourceNotFound {
 			// If no state exists, then return nil.
+			r.setLastVersion("", nil)
 			return nil, nil
 		}
 		return nil, fmt.Errorf("Error retrieving state: %w", err)
@@ -55,11 +117,18 @@ func (r *remoteClient) Get() (*remote.Payload, error) {
 
 	// If the state is empty, then return nil.
 	if len(state) == 0 {
+		r.setLastVersion(sv.ID, nil)
 		return nil, nil
 	}
 
+	state, err = decompressState(state)
+	if err != nil {
+		return nil, fmt.Errorf("Error decompressing state: %w", err)
+	}
+
 	// Get the MD5 checksum of the state.
 	sum := md5.Sum(state)
+	r.setLastVersion(sv.ID, sum[:])
 
 	return &remote.Payload{
 		Data: state,
@@ -67,7 +136,7 @@ func (r *remoteClient) Get() (*remote.Payload, error) {
 	}, nil
 }
 
-func (r *remoteClient) uploadStateFallback(ctx context.Context, stateFile *statefile.File, state []byte, jsonStateOutputs []byte) error {
+func (r *remoteClient) uploadStateFallback(ctx context.Context, stateFile *statefile.File, state []byte, jsonStateOutputs []byte) (*tfe.StateVersion, error) {
 	options := tfe.StateVersionCreateOptions{
 		Lineage:          tfe.String(stateFile.Lineage),
 		Serial:           tfe.Int64(int64(stateFile.Serial)),
@@ -84,12 +153,11 @@ func (r *remoteClient) uploadStateFallback(ctx context.Context, stateFile *state
 	}
 
 	// Create the new state.
-	_, err := r.client.StateVersions.Create(ctx, r.workspace.ID, options)
+	sv, err := r.client.StateVersions.Create(ctx, r.workspace.ID, options)
 	if err != nil {
-		r.stateUploadErr = true
-		return fmt.Errorf("error uploading state in compatibility mode: %w", err)
+		return nil, fmt.Errorf("error uploading state in compatibility mode (%s): %w", r.runIDDescription(), err)
 	}
-	return err
+	return sv, nil
 }
 
 // Put the remote state.
@@ -111,15 +179,25 @@ func (r *remoteClient) Put(state []byte) error {
 		return fmt.Errorf("error converting output values to json: %w", err)
 	}
 
+	// The MD5 checksum must match the uploaded bytes, so it's calculated
+	// after the optional compression.
+	payload := state
+	if r.compress {
+		payload, err = compressState(state)
+		if err != nil {
+			return fmt.Errorf("error compressing state: %w", err)
+		}
+	}
+
 	options := tfe.StateVersionUploadOptions{
 		StateVersionCreateOptions: tfe.StateVersionCreateOptions{
 			Lineage:          tfe.String(stateFile.Lineage),
 			Serial:           tfe.Int64(int64(stateFile.Serial)),
-			MD5:              tfe.String(fmt.Sprintf("%x", md5.Sum(state))),
+			MD5:              tfe.String(fmt.Sprintf("%x", md5.Sum(payload))),
 			Force:            tfe.Bool(r.forcePush),
 			JSONStateOutputs: tfe.String(base64.StdEncoding.EncodeToString(o)),
 		},
-		RawState: state,
+		RawState: payload,
 	}
 
 	// If we have a run ID, make sure to add it to the options
@@ -128,20 +206,153 @@ func (r *remoteClient) Put(state []byte) error {
 		options.Run = &tfe.Run{ID: r.runID}
 	}
 
-	// Create the new state.
-	// Create the new state.
-	_, err = r.client.StateVersions.Upload(ctx, r.workspace.ID, options)
+	// Create the new state, retrying transient failures. The same options
+	// are used for every attempt, so the state stays associated with the run.
+	var sv *tfe.StateVersion
+	delay := r.uploadRetryDelay
+	for attempt := 1; ; attempt++ {
+		sv, err = r.uploadState(ctx, options, stateFile, payload, o)
+		if err == nil || attempt >= r.uploadMaxAttempts || !isTransientUploadError(err) {
+			break
+		}
+		log.Printf("[WARN] State upload attempt %d of %d failed, retrying in %s: %s", attempt, r.uploadMaxAttempts, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+	if err != nil {
+		r.stateUploadErr = true
+		return err
+	}
+
+	r.stateVersion = sv
+	sum := md5.Sum(state)
+	r.setLastVersion(sv.ID, sum[:])
+	return nil
+}
+
+// PutIfMatch implements remote.ClientConditionalPutter, which the state
+// manager only uses if conditional_state_writes is set.
+//
+// This is a best-effort check: the API can't upload a state conditionally,
+// so the stored state is compared with the state the given MD5 belongs to
+// right before the upload. That costs an extra request for every write, and
+// doesn't exclude a concurrent write between the check and the upload, which
+// only the workspace lock protects against.
+func (r *remoteClient) PutIfMatch(state []byte, readMD5 []byte) error {
+	if err := r.checkUnchanged(readMD5); err != nil {
+		return err
+	}
+	return r.Put(state)
+}
+
+// checkUnchanged returns an error wrapping statemgr.ErrStateChanged if the
+// MD5 of the stored state, as returned by Get, isn't the given one.
+func (r *remoteClient) checkUnchanged(readMD5 []byte) error {
+	if !r.hasLastVersion || !bytes.Equal(readMD5, r.lastMD5) {
+		// We don't know the state version the MD5 belongs to, so we have
+		// to download the stored state to compare it.
+		payload, err := r.Get()
+		if err != nil {
+			return err
+		}
+		var storedMD5 []byte
+		if payload != nil {
+			storedMD5 = payload.MD5
+		}
+		if !bytes.Equal(readMD5, storedMD5) {
+			return fmt.Errorf("writing state of workspace %s: %w", r.workspace.Name, statemgr.ErrStateChanged)
+		}
+		return nil
+	}
+
+	// Otherwise, the state changed if the current state version isn't the
+	// one we last read or created.
+	currentID := ""
+	sv, err := r.client.StateVersions.ReadCurrent(context.Background(), r.workspace.ID)
+	switch {
+	case err == nil:
+		currentID = sv.ID
+	case err != tfe.ErrResourceNotFound:
+		return fmt.Errorf("Error retrieving state: %w", err)
+	}
+	if currentID != r.lastVersionID {
+		return fmt.Errorf("writing state of workspace %s: %w", r.workspace.Name, statemgr.ErrStateChanged)
+	}
+	return nil
+}
+
+// setLastVersion records the state version last read or created, and the
+// MD5 of its state, for PutIfMatch.
+func (r *remoteClient) setLastVersion(id string, sum []byte) {
+	r.lastVersionID = id
+	r.lastMD5 = sum
+	r.hasLastVersion = true
+}
+
+// StoredVersion returns the serial of the state version created by the last
+// successful Put, by implementing remote.ClientVersionReporter.
+func (r *remoteClient) StoredVersion() int64 {
+	if r.stateVersion == nil {
+		return statemgr.UnknownStoredVersion
+	}
+	return r.stateVersion.Serial
+}
+
+// uploadState makes a single attempt to create the new state.
+func (r *remoteClient) uploadState(ctx context.Context, options tfe.StateVersionUploadOptions, stateFile *statefile.File, payload []byte, jsonStateOutputs []byte) (*tfe.StateVersion, error) {
+	sv, err := r.client.StateVersions.Upload(ctx, r.workspace.ID, options)
 	if errors.Is(err, tfe.ErrStateVersionUploadNotSupported) {
 		// Create the new state with content included in the request (Terraform Enterprise v202306-1 and below)
 		log.Println("[INFO] Detected that state version upload is not supported. Retrying using compatibility state upload.")
-		return r.uploadStateFallback(ctx, stateFile, state, o)
+		return r.uploadStateFallback(ctx, stateFile, payload, jsonStateOutputs)
 	}
 	if err != nil {
-		r.stateUploadErr = true
-		return fmt.Errorf("error uploading state: %w", err)
+		return nil, fmt.Errorf("error uploading state (%s): %w", r.runIDDescription(), err)
 	}
+	return sv, nil
+}
 
-	return nil
+// transientStatuses are the HTTP statuses of server errors which are likely
+// to be resolved by retrying the request.
+var transientStatuses = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// isTransientUploadError returns true if the given state upload error is
+// likely to be resolved by retrying the upload. Errors which indicate a
+// conflict with the stored state, such as a serial or lineage mismatch, are
+// never transient.
+func isTransientUploadError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	// go-tfe reports server errors without an error payload with just the
+	// status line of the response.
+	msg := err.Error()
+	for _, status := range transientStatuses {
+		if strings.Contains(msg, fmt.Sprintf("%d %s", status, http.StatusText(status))) {
+			return true
+		}
+	}
+	return false
+}
+
+// runIDDescription describes the run ID the state is associated with, for
+// use in error messages.
+func (r *remoteClient) runIDDescription() string {
+	if r.runID == "" {
+		return "no run ID"
+	}
+	return fmt.Sprintf("run ID %q", r.runID)
 }
 
 // Delete the remote state.
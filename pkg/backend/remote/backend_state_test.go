@@ -7,7 +7,15 @@ package remote
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/we-dcode/opentofu/pkg/backend"
 	"github.com/we-dcode/opentofu/pkg/cloud"
@@ -15,10 +23,13 @@ import (
 	"github.com/we-dcode/opentofu/pkg/states"
 	"github.com/we-dcode/opentofu/pkg/states/remote"
 	"github.com/we-dcode/opentofu/pkg/states/statefile"
+	"github.com/we-dcode/opentofu/pkg/states/statemgr"
 )
 
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(remoteClient)
+	var _ remote.ClientVersionReporter = new(remoteClient)
+	var _ remote.ClientConditionalPutter = new(remoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -61,3 +72,322 @@ func TestRemoteClient_Put_withRunID(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
+
+func TestRemoteClient_Put_storedVersion(t *testing.T) {
+	client := testRemoteClient(t).(*remoteClient)
+
+	if got := client.StoredVersion(); got != statemgr.UnknownStoredVersion {
+		t.Fatalf("expected an unknown version before Put, got %d", got)
+	}
+
+	sf := statefile.New(states.NewState(), "", 7)
+	var buf bytes.Buffer
+	if err := statefile.Write(sf, &buf, encryption.StateEncryptionDisabled()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.Put(buf.Bytes()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got, want := client.StoredVersion(), int64(7); got != want {
+		t.Fatalf("wrong stored version %d; want %d", got, want)
+	}
+}
+
+func TestRemoteClient_PutIfMatch(t *testing.T) {
+	b, bCleanup := testBackendDefault(t)
+	defer bCleanup()
+
+	newClient := func() *remoteClient {
+		raw, err := b.StateMgr(backend.DefaultStateName)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return raw.(*remote.State).Client.(*remoteClient)
+	}
+	client1 := newClient()
+	client2 := newClient()
+
+	stateData := func(serial uint64) []byte {
+		sf := statefile.New(states.NewState(), "lineage", serial)
+		var buf bytes.Buffer
+		if err := statefile.Write(sf, &buf, encryption.StateEncryptionDisabled()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	// There's no stored state yet.
+	if err := client1.PutIfMatch(stateData(1), nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	payload, err := client2.Get()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if payload == nil {
+		t.Fatal("expected a stored state, got none")
+	}
+
+	// The first client writes again, based on the state it wrote before,
+	// which the second client has read.
+	if err := client1.PutIfMatch(stateData(2), payload.MD5); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// So the second client must not overwrite the new state.
+	err = client2.PutIfMatch(stateData(3), payload.MD5)
+	if !errors.Is(err, statemgr.ErrStateChanged) {
+		t.Fatalf("expected a state changed error, got %v", err)
+	}
+
+	// Unless it reads the new state first.
+	payload, err = client2.Get()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client2.PutIfMatch(stateData(3), payload.MD5); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// An MD5 of no state version known to the client is compared with the
+	// stored state.
+	err = client1.PutIfMatch(stateData(4), payload.MD5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	err = client1.PutIfMatch(stateData(5), []byte("outdated"))
+	if !errors.Is(err, statemgr.ErrStateChanged) {
+		t.Fatalf("expected a state changed error, got %v", err)
+	}
+
+	payload, err = client1.Get()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !bytes.Equal(payload.Data, stateData(4)) {
+		t.Fatalf("wrong stored state\ngot:  %s\nwant: %s", payload.Data, stateData(4))
+	}
+}
+
+func TestRemote_conditionalStateWrites(t *testing.T) {
+	for name, enabled := range map[string]bool{"default": false, "enabled": true} {
+		t.Run(name, func(t *testing.T) {
+			b, bCleanup := testBackendDefault(t)
+			defer bCleanup()
+			b.conditionalStateWrites = enabled
+
+			// Two state managers which read the same state, like two
+			// concurrent runs without locking.
+			s1, err := b.StateMgr(backend.DefaultStateName)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			s2, err := b.StateMgr(backend.DefaultStateName)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := s1.WriteState(states.NewState()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := s1.PersistState(nil); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for _, s := range []statemgr.Full{s1, s2} {
+				if err := s.RefreshState(); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+
+			state := states.NewState()
+			state.RootModule().SetOutputValue("foo", cty.StringVal("one"), false)
+			if err := s1.WriteState(state); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := s1.PersistState(nil); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			// The second state manager only overwrites the new state if
+			// conditional writes aren't enabled.
+			state = states.NewState()
+			state.RootModule().SetOutputValue("foo", cty.StringVal("two"), false)
+			if err := s2.WriteState(state); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			err = s2.PersistState(nil)
+			switch {
+			case enabled && !errors.Is(err, statemgr.ErrStateChanged):
+				t.Fatalf("expected a state changed error, got %v", err)
+			case !enabled && err != nil:
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRemoteClient_Put_withRunIDError(t *testing.T) {
+	runID := cloud.GenerateID("run-")
+	t.Setenv("TFE_RUN_ID", runID)
+
+	// Create a new test client.
+	client := testRemoteClient(t)
+
+	// Change the run ID the mock expects, so it rejects the state.
+	t.Setenv("TFE_RUN_ID", cloud.GenerateID("run-"))
+
+	sf := statefile.New(states.NewState(), "", 0)
+	var buf bytes.Buffer
+	statefile.Write(sf, &buf, encryption.StateEncryptionDisabled())
+
+	err := client.Put(buf.Bytes())
+	if err == nil {
+		t.Fatal("expected an error, got none")
+	}
+	if !strings.Contains(err.Error(), runID) {
+		t.Fatalf("expected error to contain the run ID %q, got: %s", runID, err)
+	}
+}
+
+// flakyStateVersions fails the first uploads with the given error, and records
+// the run ID of every upload attempt.
+type flakyStateVersions struct {
+	tfe.StateVersions
+
+	failures int
+	err      error
+	runIDs   []string
+}
+
+func (f *flakyStateVersions) Upload(ctx context.Context, workspaceID string, options tfe.StateVersionUploadOptions) (*tfe.StateVersion, error) {
+	runID := ""
+	if options.Run != nil {
+		runID = options.Run.ID
+	}
+	f.runIDs = append(f.runIDs, runID)
+
+	if f.failures > 0 {
+		f.failures--
+		return nil, f.err
+	}
+	return f.StateVersions.Upload(ctx, workspaceID, options)
+}
+
+func TestRemoteClient_Put_retry(t *testing.T) {
+	runID := cloud.GenerateID("run-")
+	t.Setenv("TFE_RUN_ID", runID)
+
+	client := testRemoteClient(t).(*remoteClient)
+	client.uploadMaxAttempts = 3
+	client.uploadRetryDelay = time.Millisecond
+
+	flaky := &flakyStateVersions{
+		StateVersions: client.client.StateVersions,
+		failures:      2,
+		err:           errors.New("503 Service Unavailable"),
+	}
+	client.client.StateVersions = flaky
+
+	sf := statefile.New(states.NewState(), "", 0)
+	var buf bytes.Buffer
+	statefile.Write(sf, &buf, encryption.StateEncryptionDisabled())
+
+	if err := client.Put(buf.Bytes()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Every attempt must carry the run ID.
+	if len(flaky.runIDs) != 3 {
+		t.Fatalf("expected 3 upload attempts, got %d", len(flaky.runIDs))
+	}
+	for i, got := range flaky.runIDs {
+		if got != runID {
+			t.Fatalf("attempt %d: expected run ID %q, got %q", i+1, runID, got)
+		}
+	}
+
+	// The state must have been stored.
+	payload, err := client.Get()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if payload == nil || !bytes.Equal(payload.Data, buf.Bytes()) {
+		t.Fatalf("state was not stored")
+	}
+}
+
+func TestRemoteClient_Put_retryNotTransient(t *testing.T) {
+	client := testRemoteClient(t).(*remoteClient)
+	client.uploadMaxAttempts = 3
+	client.uploadRetryDelay = time.Millisecond
+
+	flaky := &flakyStateVersions{
+		StateVersions: client.client.StateVersions,
+		failures:      1,
+		err:           tfe.ErrResourceNotFound,
+	}
+	client.client.StateVersions = flaky
+
+	sf := statefile.New(states.NewState(), "", 0)
+	var buf bytes.Buffer
+	statefile.Write(sf, &buf, encryption.StateEncryptionDisabled())
+
+	if err := client.Put(buf.Bytes()); err == nil {
+		t.Fatal("expected an error, got none")
+	}
+	if len(flaky.runIDs) != 1 {
+		t.Fatalf("expected a single upload attempt, got %d", len(flaky.runIDs))
+	}
+	if !client.stateUploadErr {
+		t.Fatal("expected the upload error to be recorded")
+	}
+}
+
+func TestRemoteClient_Put_compressed(t *testing.T) {
+	client := testRemoteClient(t).(*remoteClient)
+	client.compress = true
+
+	// Create a state with repetitive, and therefore compressible, content.
+	state := states.NewState()
+	for i := 0; i < 100; i++ {
+		state.RootModule().SetOutputValue(
+			fmt.Sprintf("output_%d", i),
+			cty.StringVal(strings.Repeat("compressible ", 10)),
+			false,
+		)
+	}
+	sf := statefile.New(state, "", 0)
+	var buf bytes.Buffer
+	if err := statefile.Write(sf, &buf, encryption.StateEncryptionDisabled()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	original := buf.Bytes()
+
+	if err := client.Put(original); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Check the stored state is compressed.
+	ctx := context.Background()
+	sv, err := client.client.StateVersions.ReadCurrent(ctx, client.workspace.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	stored, err := client.client.StateVersions.Download(ctx, sv.DownloadURL)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(stored) >= len(original) {
+		t.Fatalf("expected stored state to be smaller than %d bytes, got %d bytes", len(original), len(stored))
+	}
+
+	// Check the state is transparently decompressed.
+	payload, err := client.Get()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !bytes.Equal(payload.Data, original) {
+		t.Fatalf("wrong state after round-trip\ngot:  %s\nwant: %s", payload.Data, original)
+	}
+}
@@ -54,9 +54,13 @@ func TestRemote_config(t *testing.T) {
 	}{
 		"with_a_nonexisting_organization": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal(mockedBackendHost),
-				"organization": cty.StringVal("nonexisting"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.StringVal(mockedBackendHost),
+				"organization":              cty.StringVal("nonexisting"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.NullVal(cty.String),
@@ -66,9 +70,13 @@ func TestRemote_config(t *testing.T) {
 		},
 		"with_a_missing_hostname": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("oracle"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.NullVal(cty.String),
+				"organization":              cty.StringVal("oracle"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.NullVal(cty.String),
@@ -78,9 +86,13 @@ func TestRemote_config(t *testing.T) {
 		},
 		"with_an_unknown_host": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal("nonexisting.local"),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.StringVal("nonexisting.local"),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.NullVal(cty.String),
@@ -91,9 +103,13 @@ func TestRemote_config(t *testing.T) {
 		// localhost advertises TFE services, but has no token in the credentials
 		"without_a_token": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal("localhost"),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.StringVal("localhost"),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.NullVal(cty.String),
@@ -103,9 +119,13 @@ func TestRemote_config(t *testing.T) {
 		},
 		"with_a_name": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.NullVal(cty.String),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.NullVal(cty.String),
@@ -114,9 +134,13 @@ func TestRemote_config(t *testing.T) {
 		},
 		"with_a_prefix": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.NullVal(cty.String),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.NullVal(cty.String),
 					"prefix": cty.StringVal("my-app-"),
@@ -125,9 +149,13 @@ func TestRemote_config(t *testing.T) {
 		},
 		"without_either_a_name_and_a_prefix": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.NullVal(cty.String),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.NullVal(cty.String),
 					"prefix": cty.NullVal(cty.String),
@@ -137,9 +165,13 @@ func TestRemote_config(t *testing.T) {
 		},
 		"with_both_a_name_and_a_prefix": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.NullVal(cty.String),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.StringVal("my-app-"),
@@ -181,9 +213,13 @@ func TestRemote_versionConstraints(t *testing.T) {
 	}{
 		"compatible version": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal(mockedBackendHost),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.StringVal(mockedBackendHost),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.NullVal(cty.String),
@@ -193,9 +229,13 @@ func TestRemote_versionConstraints(t *testing.T) {
 		},
 		"version too old": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal(mockedBackendHost),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.StringVal(mockedBackendHost),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.NullVal(cty.String),
@@ -206,9 +246,13 @@ func TestRemote_versionConstraints(t *testing.T) {
 		},
 		"version too new": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal(mockedBackendHost),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":                  cty.StringVal(mockedBackendHost),
+				"organization":              cty.StringVal("hashicorp"),
+				"token":                     cty.NullVal(cty.String),
+				"compress_state":            cty.NullVal(cty.Bool),
+				"state_upload_max_attempts": cty.NullVal(cty.Number),
+				"state_upload_retry_delay":  cty.NullVal(cty.String),
+				"conditional_state_writes":  cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name":   cty.StringVal("prod"),
 					"prefix": cty.NullVal(cty.String),
@@ -787,9 +831,13 @@ func TestRemote_ServiceDiscoveryAliases(t *testing.T) {
 	b := New(testDisco(s), encryption.StateEncryptionDisabled())
 
 	diag := b.Configure(cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.StringVal(mockedBackendHost),
-		"organization": cty.StringVal("hashicorp"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":                  cty.StringVal(mockedBackendHost),
+		"organization":              cty.StringVal("hashicorp"),
+		"token":                     cty.NullVal(cty.String),
+		"compress_state":            cty.NullVal(cty.Bool),
+		"state_upload_max_attempts": cty.NullVal(cty.Number),
+		"state_upload_retry_delay":  cty.NullVal(cty.String),
+		"conditional_state_writes":  cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name":   cty.StringVal("prod"),
 			"prefix": cty.NullVal(cty.String),
@@ -73,9 +73,13 @@ func testInput(t *testing.T, answers map[string]string) *mockInput {
 
 func testBackendDefault(t *testing.T) (*Remote, func()) {
 	obj := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.StringVal(mockedBackendHost),
-		"organization": cty.StringVal("hashicorp"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":                  cty.StringVal(mockedBackendHost),
+		"organization":              cty.StringVal("hashicorp"),
+		"token":                     cty.NullVal(cty.String),
+		"compress_state":            cty.NullVal(cty.Bool),
+		"state_upload_max_attempts": cty.NullVal(cty.Number),
+		"state_upload_retry_delay":  cty.NullVal(cty.String),
+		"conditional_state_writes":  cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name":   cty.StringVal("prod"),
 			"prefix": cty.NullVal(cty.String),
@@ -86,9 +90,13 @@ func testBackendDefault(t *testing.T) (*Remote, func()) {
 
 func testBackendNoDefault(t *testing.T) (*Remote, func()) {
 	obj := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.StringVal(mockedBackendHost),
-		"organization": cty.StringVal("hashicorp"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":                  cty.StringVal(mockedBackendHost),
+		"organization":              cty.StringVal("hashicorp"),
+		"token":                     cty.NullVal(cty.String),
+		"compress_state":            cty.NullVal(cty.Bool),
+		"state_upload_max_attempts": cty.NullVal(cty.Number),
+		"state_upload_retry_delay":  cty.NullVal(cty.String),
+		"conditional_state_writes":  cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name":   cty.NullVal(cty.String),
 			"prefix": cty.StringVal("my-app-"),
@@ -99,9 +107,13 @@ func testBackendNoDefault(t *testing.T) (*Remote, func()) {
 
 func testBackendNoOperations(t *testing.T) (*Remote, func()) {
 	obj := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.StringVal(mockedBackendHost),
-		"organization": cty.StringVal("no-operations"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":                  cty.StringVal(mockedBackendHost),
+		"organization":              cty.StringVal("no-operations"),
+		"token":                     cty.NullVal(cty.String),
+		"compress_state":            cty.NullVal(cty.Bool),
+		"state_upload_max_attempts": cty.NullVal(cty.Number),
+		"state_upload_retry_delay":  cty.NullVal(cty.String),
+		"conditional_state_writes":  cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name":   cty.StringVal("prod"),
 			"prefix": cty.NullVal(cty.String),
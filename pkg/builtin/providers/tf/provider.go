@@ -169,6 +169,13 @@ func (p *Provider) ImportResourceState(req providers.ImportResourceStateRequest)
 	panic("unimplemented - terraform_remote_state has no resources")
 }
 
+// MoveResourceState requests that the given resource state be moved from
+// another resource type. The terraform provider doesn't support moves.
+func (p *Provider) MoveResourceState(req providers.MoveResourceStateRequest) (resp providers.MoveResourceStateResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("move to resource type %q is not supported", req.TargetTypeName))
+	return resp
+}
+
 // ValidateResourceConfig is used to to validate the resource configuration values.
 func (p *Provider) ValidateResourceConfig(req providers.ValidateResourceConfigRequest) providers.ValidateResourceConfigResponse {
 	return validateDataStoreResourceConfig(req)
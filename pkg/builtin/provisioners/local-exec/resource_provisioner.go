@@ -131,6 +131,15 @@ func (p *provisioner) ProvisionResource(req provisioners.ProvisionResourceReques
 	if wdVal := req.Config.GetAttr("working_dir"); !wdVal.IsNull() {
 		workingdir = wdVal.AsString()
 	}
+	if workingdir != "" {
+		// The working directory is often interpolated from resource
+		// attributes, so we check it here to give a clearer error than the
+		// one returned when starting the command.
+		if diags := validateWorkingDir(workingdir); diags.HasErrors() {
+			resp.Diagnostics = resp.Diagnostics.Append(diags)
+			return resp
+		}
+	}
 
 	// Set up the reader that will read the output from the command.
 	// We use an os.Pipe so that the *os.File can be passed directly to the
@@ -208,6 +217,38 @@ func (p *provisioner) ProvisionResource(req provisioners.ProvisionResourceReques
 	return resp
 }
 
+// validateWorkingDir checks that the given working directory exists and is a
+// directory.
+func validateWorkingDir(dir string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid local-exec working directory",
+			fmt.Sprintf("The working directory %q does not exist.", dir),
+			cty.GetAttrPath("working_dir"),
+		))
+	case err != nil:
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid local-exec working directory",
+			fmt.Sprintf("Failed to read the working directory %q: %s.", dir, err),
+			cty.GetAttrPath("working_dir"),
+		))
+	case !info.IsDir():
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid local-exec working directory",
+			fmt.Sprintf("The working directory %q is not a directory.", dir),
+			cty.GetAttrPath("working_dir"),
+		))
+	}
+	return diags
+}
+
 func (p *provisioner) Stop() error {
 	p.cancel()
 	return nil
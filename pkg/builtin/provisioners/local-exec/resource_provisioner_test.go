@@ -8,6 +8,7 @@ package localexec
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -178,6 +179,62 @@ func TestResourceProvider_ApplyCustomWorkingDirectory(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_ApplyInvalidWorkingDirectory(t *testing.T) {
+	td := t.TempDir()
+	file := filepath.Join(td, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		workingDir string
+		wantDetail string
+	}{
+		"missing": {
+			workingDir: filepath.Join(td, "missing"),
+			wantDetail: fmt.Sprintf("The working directory %q does not exist.", filepath.Join(td, "missing")),
+		},
+		"file": {
+			workingDir: file,
+			wantDetail: fmt.Sprintf("The working directory %q is not a directory.", file),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			output := cli.NewMockUi()
+			p := New()
+			schema := p.GetSchema().Provisioner
+
+			c, err := schema.CoerceValue(cty.ObjectVal(map[string]cty.Value{
+				"working_dir": cty.StringVal(tc.workingDir),
+				"command":     cty.StringVal("echo hello"),
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp := p.ProvisionResource(provisioners.ProvisionResourceRequest{
+				Config:   c,
+				UIOutput: output,
+			})
+
+			if len(resp.Diagnostics) != 1 {
+				t.Fatalf("expected one diagnostic, got %d: %s", len(resp.Diagnostics), resp.Diagnostics.Err())
+			}
+			desc := resp.Diagnostics[0].Description()
+			if desc.Summary != "Invalid local-exec working directory" || desc.Detail != tc.wantDetail {
+				t.Fatalf("wrong diagnostic\ngot:  %s: %s\nwant: %s", desc.Summary, desc.Detail, tc.wantDetail)
+			}
+
+			// The command must not have been run.
+			if got := output.OutputWriter.String(); got != "" {
+				t.Fatalf("unexpected output: %s", got)
+			}
+		})
+	}
+}
+
 func TestResourceProvider_ApplyCustomEnv(t *testing.T) {
 	output := cli.NewMockUi()
 	p := New()
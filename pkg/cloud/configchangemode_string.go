@@ -11,6 +11,7 @@ func _() {
 	_ = x[ConfigMigrationIn-8600]
 	_ = x[ConfigMigrationOut-8598]
 	_ = x[ConfigChangeInPlace-8635]
+	_ = x[ConfigCloudReconfiguration-8644]
 	_ = x[ConfigChangeIrrelevant-129335]
 }
 
@@ -18,7 +19,8 @@ const (
 	_ConfigChangeMode_name_0 = "ConfigMigrationOut"
 	_ConfigChangeMode_name_1 = "ConfigMigrationIn"
 	_ConfigChangeMode_name_2 = "ConfigChangeInPlace"
-	_ConfigChangeMode_name_3 = "ConfigChangeIrrelevant"
+	_ConfigChangeMode_name_3 = "ConfigCloudReconfiguration"
+	_ConfigChangeMode_name_4 = "ConfigChangeIrrelevant"
 )
 
 func (i ConfigChangeMode) String() string {
@@ -29,8 +31,10 @@ func (i ConfigChangeMode) String() string {
 		return _ConfigChangeMode_name_1
 	case i == 8635:
 		return _ConfigChangeMode_name_2
-	case i == 129335:
+	case i == 8644:
 		return _ConfigChangeMode_name_3
+	case i == 129335:
+		return _ConfigChangeMode_name_4
 	default:
 		return "ConfigChangeMode(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
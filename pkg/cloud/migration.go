@@ -6,6 +6,9 @@
 package cloud
 
 import (
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/we-dcode/opentofu/pkg/configs"
 	legacy "github.com/we-dcode/opentofu/pkg/legacy/tofu"
 )
@@ -36,6 +39,13 @@ const (
 	// need to do any actual migration.
 	ConfigChangeInPlace ConfigChangeMode = '↻'
 
+	// ConfigCloudReconfiguration represents when both the working directory
+	// state and the config call for using Cloud mode, but the organization
+	// or the selection of workspaces changed, and so the user might need to
+	// select a workspace again. Only DetectCloudReconfiguration reports this
+	// mode; DetectConfigChangeType reports ConfigChangeInPlace instead.
+	ConfigCloudReconfiguration ConfigChangeMode = '⇄'
+
 	// ConfigChangeIrrelevant represents when the config and working directory
 	// state disagree but neither calls for using Cloud mode, and so the
 	// Cloud integration is not involved in dealing with this.
@@ -92,9 +102,59 @@ func DetectConfigChangeType(wdState *legacy.BackendState, config *configs.Backen
 
 }
 
+// DetectCloudReconfiguration is like DetectConfigChangeType, but also
+// distinguishes a change of the organization or the workspaces selector
+// between the working directory state and the configuration, which it
+// reports as ConfigCloudReconfiguration rather than ConfigChangeInPlace.
+//
+// If either of the two configurations cannot be decoded, for example because
+// the cloud block is invalid, the change is reported as ConfigChangeInPlace
+// and the problem is left for the usual backend configuration to report.
+func DetectCloudReconfiguration(wdState *legacy.BackendState, config *configs.Backend, haveLocalStates bool) ConfigChangeMode {
+	mode := DetectConfigChangeType(wdState, config, haveLocalStates)
+	if mode != ConfigChangeInPlace || wdState == nil || wdState.Type != "cloud" {
+		// Only a working directory already in cloud mode can be reconfigured.
+		return mode
+	}
+
+	schema := (&Cloud{}).ConfigSchema()
+	wdVal, err := wdState.Config(schema)
+	if err != nil {
+		return mode
+	}
+	configVal, diags := hcldec.Decode(config.Config, schema.DecoderSpec(), nil)
+	if diags.HasErrors() {
+		return mode
+	}
+
+	if !cloudWorkspaceSelector(wdVal).RawEquals(cloudWorkspaceSelector(configVal)) {
+		return ConfigCloudReconfiguration
+	}
+	return mode
+}
+
+// cloudWorkspaceSelector extracts the settings which decide the remote
+// workspaces a cloud configuration refers to.
+func cloudWorkspaceSelector(val cty.Value) cty.Value {
+	getAttr := func(obj cty.Value, name string, ty cty.Type) cty.Value {
+		if obj.IsNull() || !obj.IsKnown() {
+			return cty.NullVal(ty)
+		}
+		return obj.GetAttr(name)
+	}
+
+	workspaces := getAttr(val, "workspaces", cty.DynamicPseudoType)
+	return cty.ObjectVal(map[string]cty.Value{
+		"organization": getAttr(val, "organization", cty.String),
+		"name":         getAttr(workspaces, "name", cty.String),
+		"project":      getAttr(workspaces, "project", cty.String),
+		"tags":         getAttr(workspaces, "tags", cty.Set(cty.String)),
+	})
+}
+
 func (m ConfigChangeMode) InvolvesCloud() bool {
 	switch m {
-	case ConfigMigrationIn, ConfigMigrationOut, ConfigChangeInPlace:
+	case ConfigMigrationIn, ConfigMigrationOut, ConfigChangeInPlace, ConfigCloudReconfiguration:
 		return true
 	default:
 		return false
@@ -8,6 +8,10 @@ package cloud
 import (
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/we-dcode/opentofu/pkg/configs"
 	legacy "github.com/we-dcode/opentofu/pkg/legacy/tofu"
 )
@@ -141,3 +145,129 @@ func TestDetectConfigChangeType(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectCloudReconfiguration(t *testing.T) {
+	stateVal := func(org, workspace string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"hostname":     cty.NullVal(cty.String),
+			"organization": cty.StringVal(org),
+			"token":        cty.NullVal(cty.String),
+			"workspaces": cty.ObjectVal(map[string]cty.Value{
+				"name":    cty.StringVal(workspace),
+				"project": cty.NullVal(cty.String),
+				"tags":    cty.NullVal(cty.Set(cty.String)),
+			}),
+		})
+	}
+
+	tests := map[string]struct {
+		stateType  string
+		stateVal   cty.Value
+		configType string
+		config     string
+		want       ConfigChangeMode
+	}{
+		"unchanged": {
+			`cloud`, stateVal("hashicorp", "prod"),
+			`cloud`, `
+organization = "hashicorp"
+workspaces {
+  name = "prod"
+}
+`,
+			ConfigChangeInPlace,
+		},
+		"token change only": {
+			`cloud`, stateVal("hashicorp", "prod"),
+			`cloud`, `
+organization = "hashicorp"
+token        = "secret"
+workspaces {
+  name = "prod"
+}
+`,
+			ConfigChangeInPlace,
+		},
+		"organization changed": {
+			`cloud`, stateVal("hashicorp", "prod"),
+			`cloud`, `
+organization = "opentofu"
+workspaces {
+  name = "prod"
+}
+`,
+			ConfigCloudReconfiguration,
+		},
+		"workspace name changed": {
+			`cloud`, stateVal("hashicorp", "prod"),
+			`cloud`, `
+organization = "hashicorp"
+workspaces {
+  name = "staging"
+}
+`,
+			ConfigCloudReconfiguration,
+		},
+		"workspace name replaced by tags": {
+			`cloud`, stateVal("hashicorp", "prod"),
+			`cloud`, `
+organization = "hashicorp"
+workspaces {
+  tags = ["app"]
+}
+`,
+			ConfigCloudReconfiguration,
+		},
+		"init cloud": {
+			``, cty.NilVal,
+			`cloud`, `
+organization = "hashicorp"
+`,
+			ConfigChangeInPlace,
+		},
+		"migrate cloud to local": {
+			`cloud`, stateVal("hashicorp", "prod"),
+			`local`, ``,
+			ConfigMigrationOut,
+		},
+	}
+
+	schema := (&Cloud{}).ConfigSchema()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var state *legacy.BackendState
+			var config *configs.Backend
+			if test.stateType != "" {
+				state = &legacy.BackendState{
+					Type: test.stateType,
+				}
+				if err := state.SetConfig(test.stateVal, schema); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.configType != "" {
+				f, diags := hclsyntax.ParseConfig([]byte(test.config), "test.tf", hcl.InitialPos)
+				if diags.HasErrors() {
+					t.Fatal(diags.Error())
+				}
+				config = &configs.Backend{
+					Type:   test.configType,
+					Config: f.Body,
+				}
+			}
+			got := DetectCloudReconfiguration(state, config, false)
+
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.want)
+			}
+			if got == ConfigCloudReconfiguration {
+				if !got.InvolvesCloud() {
+					t.Errorf("reconfiguration does not involve cloud")
+				}
+				if got.IsCloudMigration() {
+					t.Errorf("reconfiguration is a cloud migration")
+				}
+			}
+		})
+	}
+}
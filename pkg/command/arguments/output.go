@@ -6,6 +6,13 @@
 package arguments
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/we-dcode/opentofu/pkg/tfdiags"
 )
 
@@ -15,17 +22,50 @@ type Output struct {
 	// outputs.
 	Name string
 
-	// StatePath is an optional path to a state file, from which outputs will
-	// be loaded.
-	StatePath string
+	// StatePaths are optional paths to state files, from which outputs will
+	// be loaded. When more than one path is given, the root outputs of all
+	// the state files are merged into a single result.
+	StatePaths []string
+
+	// StatePrefixes are optional prefixes for the names of the outputs read
+	// from the state file at the same position in StatePaths. If given, there
+	// must be exactly one prefix per state path.
+	StatePrefixes []string
 
-	// ViewType specifies which output format to use: human, JSON, or "raw".
+	// ViewType specifies which output format to use: human, JSON, YAML, env,
+	// or "raw".
 	ViewType ViewType
 
 	Vars *Vars
 
 	// ShowSensitive is used to display the value of variables marked as sensitive.
 	ShowSensitive bool
+
+	// OutputFile is an optional path to a file to which the rendered outputs
+	// are written instead of stdout. The file is only replaced once all the
+	// outputs have been rendered successfully.
+	OutputFile string
+
+	// JSONStream selects the streaming variant of the JSON view, which emits
+	// one JSON object per output value and line instead of a single document.
+	JSONStream bool
+
+	// Check makes the command fail if the named output doesn't exist or, if
+	// no name is given, if there are no outputs at all.
+	Check bool
+
+	// Path is an optional path to a nested value within the named output,
+	// which is shown instead of the whole output value.
+	Path cty.Path
+
+	// Humanize renders timestamps and byte counts in a friendlier form. It
+	// only affects the human view and is ignored by all other formats.
+	Humanize bool
+
+	// DiffStatePath is an optional path to a previous state snapshot. If
+	// set, the changes from the outputs of that snapshot to the current
+	// outputs are shown instead of the outputs themselves.
+	DiffStatePath string
 }
 
 // ParseOutput processes CLI arguments, returning an Output value and errors.
@@ -37,13 +77,22 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 		Vars: &Vars{},
 	}
 
-	var jsonOutput, rawOutput bool
-	var statePath string
+	var jsonOutput, jsonStreamOutput, rawOutput, yamlOutput bool
+	var outputFormat, rawPath string
 	cmdFlags := extendedFlagSet("output", nil, nil, output.Vars)
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.BoolVar(&jsonStreamOutput, "json-stream", false, "json-stream")
 	cmdFlags.BoolVar(&rawOutput, "raw", false, "raw")
-	cmdFlags.StringVar(&statePath, "state", "", "path")
+	cmdFlags.BoolVar(&yamlOutput, "yaml", false, "yaml")
+	cmdFlags.StringVar(&outputFormat, "format", "", "format")
+	cmdFlags.Var((*flagStringSlice)(&output.StatePaths), "state", "path")
+	cmdFlags.Var((*flagStringSlice)(&output.StatePrefixes), "state-prefix", "prefix")
+	cmdFlags.StringVar(&output.OutputFile, "output-file", "", "path")
 	cmdFlags.BoolVar(&output.ShowSensitive, "show-sensitive", false, "displays sensitive values")
+	cmdFlags.BoolVar(&output.Check, "check", false, "check")
+	cmdFlags.StringVar(&rawPath, "path", "", "path")
+	cmdFlags.BoolVar(&output.Humanize, "humanize", false, "humanize")
+	cmdFlags.StringVar(&output.DiffStatePath, "diff", "", "path")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -62,6 +111,18 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 		))
 	}
 
+	if jsonStreamOutput && rawOutput {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			"The -raw and -json-stream options are mutually-exclusive.",
+		))
+
+		// Since the desired output format is unknowable, fall back to default
+		jsonStreamOutput = false
+		rawOutput = false
+	}
+
 	if jsonOutput && rawOutput {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -74,12 +135,60 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 		rawOutput = false
 	}
 
-	output.StatePath = statePath
+	if yamlOutput && (jsonOutput || jsonStreamOutput || rawOutput) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			"The -yaml option is mutually-exclusive with the -json, -json-stream, and -raw options.",
+		))
+
+		// Since the desired output format is unknowable, fall back to default
+		jsonOutput = false
+		jsonStreamOutput = false
+		rawOutput = false
+		yamlOutput = false
+	}
+
+	envOutput := false
+	switch outputFormat {
+	case "":
+	case "env":
+		envOutput = true
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			fmt.Sprintf("The -format option does not support %q. The only supported format is \"env\".", outputFormat),
+		))
+	}
+
+	if envOutput && (jsonOutput || jsonStreamOutput || rawOutput || yamlOutput) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			"The -format=env option is mutually-exclusive with the -json, -json-stream, -raw, and -yaml options.",
+		))
+
+		// Since the desired output format is unknowable, fall back to default
+		jsonOutput = false
+		jsonStreamOutput = false
+		rawOutput = false
+		yamlOutput = false
+		envOutput = false
+	}
 
 	if len(args) > 0 {
 		output.Name = args[0]
 	}
 
+	if len(output.StatePrefixes) > 0 && len(output.StatePrefixes) != len(output.StatePaths) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Mismatched -state-prefix options",
+			fmt.Sprintf("The -state-prefix option must be given once for each -state option, but got %d -state-prefix and %d -state options.", len(output.StatePrefixes), len(output.StatePaths)),
+		))
+	}
+
 	if rawOutput && output.Name == "" {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -88,14 +197,98 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 		))
 	}
 
+	if rawPath != "" {
+		if output.Name == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Output name required",
+				"You must give the name of a single output value when using the -path option.",
+			))
+		}
+
+		path, pathDiags := parseOutputPath(rawPath)
+		diags = diags.Append(pathDiags)
+		output.Path = path
+	}
+
+	if output.DiffStatePath != "" {
+		if jsonStreamOutput || rawOutput || yamlOutput || envOutput {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid output format",
+				"The -diff option only supports the default format and the -json option.",
+			))
+
+			// Since the desired output format is unknowable, fall back to default
+			jsonStreamOutput = false
+			rawOutput = false
+			yamlOutput = false
+			envOutput = false
+		}
+		if output.Path != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible options",
+				"The -diff and -path options are mutually-exclusive.",
+			))
+		}
+	}
+
 	switch {
+	case jsonStreamOutput:
+		output.ViewType = ViewJSON
+		output.JSONStream = true
 	case jsonOutput:
 		output.ViewType = ViewJSON
 	case rawOutput:
 		output.ViewType = ViewRaw
+	case yamlOutput:
+		output.ViewType = ViewYAML
+	case envOutput:
+		output.ViewType = ViewEnv
 	default:
 		output.ViewType = ViewHuman
 	}
 
 	return output, diags
 }
+
+// parseOutputPath parses a traversal relative to an output value, such as
+// subnets[0].cidr, into a cty.Path.
+func parseOutputPath(raw string) (cty.Path, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// The path is parsed as a traversal from a placeholder root, which is
+	// then discarded. This allows the path to start with either an attribute
+	// name or an index.
+	src := "output"
+	if !strings.HasPrefix(raw, "[") && !strings.HasPrefix(raw, ".") {
+		src += "."
+	}
+	src += raw
+
+	traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(src), "", hcl.Pos{Line: 1, Column: 1})
+	if syntaxDiags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			fmt.Sprintf("Invalid -path %q", raw),
+			syntaxDiags[0].Detail,
+		))
+		return nil, diags
+	}
+
+	path := make(cty.Path, 0, len(traversal)-1)
+	for _, step := range traversal[1:] {
+		switch step := step.(type) {
+		case hcl.TraverseAttr:
+			path = path.GetAttr(step.Name)
+		case hcl.TraverseIndex:
+			path = path.Index(step.Key)
+		default:
+			// ParseTraversalAbs only produces attribute and index steps
+			// after the root.
+			panic(fmt.Sprintf("unsupported traversal step %T", step))
+		}
+	}
+	return path, diags
+}
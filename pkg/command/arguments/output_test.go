@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/we-dcode/opentofu/pkg/tfdiags"
 )
 
@@ -21,33 +23,109 @@ func TestParseOutput_valid(t *testing.T) {
 		"defaults": {
 			nil,
 			&Output{
-				Name:      "",
-				ViewType:  ViewHuman,
-				StatePath: "",
+				Name:     "",
+				ViewType: ViewHuman,
 			},
 		},
 		"json": {
 			[]string{"-json"},
 			&Output{
-				Name:      "",
-				ViewType:  ViewJSON,
-				StatePath: "",
+				Name:     "",
+				ViewType: ViewJSON,
+			},
+		},
+		"json-stream": {
+			[]string{"-json-stream"},
+			&Output{
+				Name:       "",
+				ViewType:   ViewJSON,
+				JSONStream: true,
 			},
 		},
 		"raw": {
 			[]string{"-raw", "foo"},
 			&Output{
-				Name:      "foo",
-				ViewType:  ViewRaw,
-				StatePath: "",
+				Name:     "foo",
+				ViewType: ViewRaw,
+			},
+		},
+		"yaml": {
+			[]string{"-yaml"},
+			&Output{
+				Name:     "",
+				ViewType: ViewYAML,
 			},
 		},
 		"state": {
 			[]string{"-state=foobar.tfstate", "-raw", "foo"},
 			&Output{
-				Name:      "foo",
-				ViewType:  ViewRaw,
-				StatePath: "foobar.tfstate",
+				Name:       "foo",
+				ViewType:   ViewRaw,
+				StatePaths: []string{"foobar.tfstate"},
+			},
+		},
+		"env": {
+			[]string{"-format=env"},
+			&Output{
+				Name:     "",
+				ViewType: ViewEnv,
+			},
+		},
+		"output file": {
+			[]string{"-output-file=outputs.json", "-json"},
+			&Output{
+				Name:       "",
+				ViewType:   ViewJSON,
+				OutputFile: "outputs.json",
+			},
+		},
+		"check": {
+			[]string{"-check", "foo"},
+			&Output{
+				Name:     "foo",
+				ViewType: ViewHuman,
+				Check:    true,
+			},
+		},
+		"humanize": {
+			[]string{"-humanize"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+				Humanize: true,
+			},
+		},
+		"diff": {
+			[]string{"-diff=old.tfstate", "-json"},
+			&Output{
+				Name:          "",
+				ViewType:      ViewJSON,
+				DiffStatePath: "old.tfstate",
+			},
+		},
+		"path": {
+			[]string{"-path=network.cidr", "foo"},
+			&Output{
+				Name:     "foo",
+				ViewType: ViewHuman,
+				Path:     cty.GetAttrPath("network").GetAttr("cidr"),
+			},
+		},
+		"multiple states": {
+			[]string{"-state=foo.tfstate", "-state=bar.tfstate", "-json"},
+			&Output{
+				Name:       "",
+				ViewType:   ViewJSON,
+				StatePaths: []string{"foo.tfstate", "bar.tfstate"},
+			},
+		},
+		"multiple states with prefixes": {
+			[]string{"-state=foo.tfstate", "-state-prefix=foo_", "-state=bar.tfstate", "-state-prefix=bar_"},
+			&Output{
+				Name:          "",
+				ViewType:      ViewHuman,
+				StatePaths:    []string{"foo.tfstate", "bar.tfstate"},
+				StatePrefixes: []string{"foo_", "bar_"},
 			},
 		},
 	}
@@ -59,7 +137,7 @@ func TestParseOutput_valid(t *testing.T) {
 				t.Fatalf("unexpected diags: %v", diags)
 			}
 			got.Vars = nil
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 		})
@@ -75,9 +153,8 @@ func TestParseOutput_invalid(t *testing.T) {
 		"unknown flag": {
 			[]string{"-boop"},
 			&Output{
-				Name:      "",
-				ViewType:  ViewHuman,
-				StatePath: "",
+				Name:     "",
+				ViewType: ViewHuman,
 			},
 			tfdiags.Diagnostics{
 				tfdiags.Sourceless(
@@ -90,9 +167,8 @@ func TestParseOutput_invalid(t *testing.T) {
 		"json and raw specified": {
 			[]string{"-json", "-raw"},
 			&Output{
-				Name:      "",
-				ViewType:  ViewHuman,
-				StatePath: "",
+				Name:     "",
+				ViewType: ViewHuman,
 			},
 			tfdiags.Diagnostics{
 				tfdiags.Sourceless(
@@ -102,12 +178,39 @@ func TestParseOutput_invalid(t *testing.T) {
 				),
 			},
 		},
+		"json-stream and raw specified": {
+			[]string{"-json-stream", "-raw"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -raw and -json-stream options are mutually-exclusive.",
+				),
+			},
+		},
+		"yaml and json specified": {
+			[]string{"-yaml", "-json"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -yaml option is mutually-exclusive with the -json, -json-stream, and -raw options.",
+				),
+			},
+		},
 		"raw with no name": {
 			[]string{"-raw"},
 			&Output{
-				Name:      "",
-				ViewType:  ViewRaw,
-				StatePath: "",
+				Name:     "",
+				ViewType: ViewRaw,
 			},
 			tfdiags.Diagnostics{
 				tfdiags.Sourceless(
@@ -117,12 +220,102 @@ func TestParseOutput_invalid(t *testing.T) {
 				),
 			},
 		},
+		"path with no name": {
+			[]string{"-path=cidr"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+				Path:     cty.GetAttrPath("cidr"),
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Output name required",
+					"You must give the name of a single output value when using the -path option.",
+				),
+			},
+		},
+		"unknown format": {
+			[]string{"-format=xml"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					`The -format option does not support "xml". The only supported format is "env".`,
+				),
+			},
+		},
+		"env with json": {
+			[]string{"-format=env", "-json"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -format=env option is mutually-exclusive with the -json, -json-stream, -raw, and -yaml options.",
+				),
+			},
+		},
+		"diff with yaml": {
+			[]string{"-diff=old.tfstate", "-yaml"},
+			&Output{
+				Name:          "",
+				ViewType:      ViewHuman,
+				DiffStatePath: "old.tfstate",
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -diff option only supports the default format and the -json option.",
+				),
+			},
+		},
+		"diff with path": {
+			[]string{"-diff=old.tfstate", "-path=cidr", "foo"},
+			&Output{
+				Name:          "foo",
+				ViewType:      ViewHuman,
+				Path:          cty.GetAttrPath("cidr"),
+				DiffStatePath: "old.tfstate",
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible options",
+					"The -diff and -path options are mutually-exclusive.",
+				),
+			},
+		},
+		"mismatched state prefixes": {
+			[]string{"-state=foo.tfstate", "-state=bar.tfstate", "-state-prefix=foo_"},
+			&Output{
+				Name:          "",
+				ViewType:      ViewHuman,
+				StatePaths:    []string{"foo.tfstate", "bar.tfstate"},
+				StatePrefixes: []string{"foo_"},
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Mismatched -state-prefix options",
+					"The -state-prefix option must be given once for each -state option, but got 1 -state-prefix and 2 -state options.",
+				),
+			},
+		},
 		"too many arguments": {
 			[]string{"-raw", "-state=foo.tfstate", "bar", "baz"},
 			&Output{
-				Name:      "bar",
-				ViewType:  ViewRaw,
-				StatePath: "foo.tfstate",
+				Name:       "bar",
+				ViewType:   ViewRaw,
+				StatePaths: []string{"foo.tfstate"},
 			},
 			tfdiags.Diagnostics{
 				tfdiags.Sourceless(
@@ -138,7 +331,7 @@ func TestParseOutput_invalid(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			got, gotDiags := ParseOutput(tc.args)
 			got.Vars = nil
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 			if !reflect.DeepEqual(gotDiags, tc.wantDiags) {
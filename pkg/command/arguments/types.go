@@ -15,6 +15,8 @@ const (
 	ViewHuman ViewType = 'H'
 	ViewJSON  ViewType = 'J'
 	ViewRaw   ViewType = 'R'
+	ViewYAML  ViewType = 'Y'
+	ViewEnv   ViewType = 'E'
 )
 
 func (vt ViewType) String() string {
@@ -27,6 +29,10 @@ func (vt ViewType) String() string {
 		return "json"
 	case ViewRaw:
 		return "raw"
+	case ViewYAML:
+		return "yaml"
+	case ViewEnv:
+		return "env"
 	default:
 		return "unknown"
 	}
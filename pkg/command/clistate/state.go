@@ -169,27 +169,66 @@ func (l *locker) Timeout() time.Duration {
 	return l.timeout
 }
 
-type noopLocker struct{}
+// NoopLocker is a Locker that never touches the state manager it is given.
+// It is used when locking is disabled, and records the Lock and Unlock calls
+// it gets so that callers and tests can inspect the intended locking without
+// a live lock.
+type NoopLocker struct {
+	mu         sync.Mutex
+	operations []string
+	locked     bool
+}
 
 // NewNoopLocker returns a valid Locker that does nothing.
 func NewNoopLocker() Locker {
-	return noopLocker{}
+	return &NoopLocker{}
 }
 
-var _ Locker = noopLocker{}
+var _ Locker = (*NoopLocker)(nil)
 
-func (l noopLocker) WithContext(ctx context.Context) Locker {
+// WithContext returns the same NoopLocker, so operations recorded through
+// the returned Locker are visible on the original one.
+func (l *NoopLocker) WithContext(ctx context.Context) Locker {
 	return l
 }
 
-func (l noopLocker) Lock(statemgr.Locker, string) tfdiags.Diagnostics {
+func (l *NoopLocker) Lock(_ statemgr.Locker, reason string) tfdiags.Diagnostics {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.operations = append(l.operations, "lock: "+reason)
+	l.locked = true
 	return nil
 }
 
-func (l noopLocker) Unlock() tfdiags.Diagnostics {
+func (l *NoopLocker) Unlock() tfdiags.Diagnostics {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.operations = append(l.operations, "unlock")
+	l.locked = false
 	return nil
 }
 
-func (l noopLocker) Timeout() time.Duration {
+func (l *NoopLocker) Timeout() time.Duration {
 	return 0
 }
+
+// Operations returns the Lock and Unlock calls so far, in order. A Lock call
+// is recorded as "lock: " followed by its reason, an Unlock call as "unlock".
+func (l *NoopLocker) Operations() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ret := make([]string, len(l.operations))
+	copy(ret, l.operations)
+	return ret
+}
+
+// Locked returns true if Lock was called more recently than Unlock.
+func (l *NoopLocker) Locked() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.locked
+}
@@ -6,6 +6,8 @@
 package clistate
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
 	"github.com/we-dcode/opentofu/pkg/command/arguments"
@@ -28,3 +30,32 @@ func TestUnlock(t *testing.T) {
 		t.Error("expected error")
 	}
 }
+
+func TestNoopLocker(t *testing.T) {
+	l := NewNoopLocker()
+
+	// The state manager must never be used, so a nil one is fine.
+	if diags := l.Lock(nil, "test-lock"); diags.HasErrors() {
+		t.Fatalf("unexpected lock error: %s", diags.Err())
+	}
+	noop := l.(*NoopLocker)
+	if !noop.Locked() {
+		t.Fatal("expected the locker to be locked")
+	}
+	if diags := l.Unlock(); diags.HasErrors() {
+		t.Fatalf("unexpected unlock error: %s", diags.Err())
+	}
+	if noop.Locked() {
+		t.Fatal("expected the locker to be unlocked")
+	}
+
+	// Operations recorded through a copy with another context are shared.
+	other := l.WithContext(context.Background())
+	other.Lock(nil, "other-lock")
+	other.Unlock()
+
+	want := []string{"lock: test-lock", "unlock", "lock: other-lock", "unlock"}
+	if got := noop.Operations(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong operations\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/we-dcode/opentofu/pkg/encryption"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+)
+
+// EncryptionSchemaCommand is a Command implementation that prints the schema
+// of the configuration of all available key providers and encryption methods.
+type EncryptionSchemaCommand struct {
+	Meta
+}
+
+func (c *EncryptionSchemaCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("encryption schema")
+	var jsonOutput bool
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The encryption schema command expects no arguments.")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	if !jsonOutput {
+		c.Ui.Error(
+			"The `tofu encryption schema` command requires the `-json` flag.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	schema, err := config.BuildSchema(encryption.DefaultRegistry)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to build the encryption configuration schema: %s", err))
+		return 1
+	}
+
+	jsonSchema, err := json.Marshal(schema)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to marshal the encryption configuration schema to JSON: %s", err))
+		return 1
+	}
+	c.Ui.Output(string(jsonSchema))
+
+	return 0
+}
+
+func (c *EncryptionSchemaCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] encryption schema -json
+
+  Prints out a JSON representation of the configuration of all available key
+  providers and encryption methods, for tools that validate key_provider and
+  method blocks.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EncryptionSchemaCommand) Synopsis() string {
+	return "Show the schema of the key providers and encryption methods"
+}
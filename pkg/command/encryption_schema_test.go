@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/we-dcode/opentofu/pkg/encryption"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+)
+
+func TestEncryptionSchema_error(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &EncryptionSchemaCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	// This test will always error because it's missing the -json flag
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("expected error, got:\n%s", ui.OutputWriter.String())
+	}
+}
+
+func TestEncryptionSchema_output(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &EncryptionSchemaCommand{Meta: Meta{Ui: ui}}
+
+	if code := c.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+	}
+
+	var got config.Schema
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every registered key provider and method must be part of the schema.
+	for _, descriptor := range encryption.DefaultRegistry.ListKeyProviderDescriptors() {
+		if _, ok := got.KeyProviders[string(descriptor.ID())]; !ok {
+			t.Errorf("missing key provider %s", descriptor.ID())
+		}
+	}
+	for _, descriptor := range encryption.DefaultRegistry.ListMethodDescriptors() {
+		if _, ok := got.Methods[string(descriptor.ID())]; !ok {
+			t.Errorf("missing method %s", descriptor.ID())
+		}
+	}
+
+	aesgcm, ok := got.Methods["aes_gcm"]
+	if !ok {
+		t.Fatal("missing method aes_gcm")
+	}
+	if attr := aesgcm.Attributes["keys"]; attr == nil || !attr.Required {
+		t.Errorf("expected the required keys attribute of the aes_gcm method, got: %#v", aesgcm.Attributes)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/mitchellh/cli"
+
+	"github.com/we-dcode/opentofu/pkg/encryption"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+// EncryptionStatusCommand is a Command implementation that reports how a
+// state file is encrypted, without decrypting it.
+type EncryptionStatusCommand struct {
+	Meta
+}
+
+// encryptionStatusKeyProvider describes a key provider used to encrypt a
+// state file, as reported by the encryption status command.
+type encryptionStatusKeyProvider struct {
+	MetaKey    string   `json:"meta_key"`
+	Address    string   `json:"address,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	KeyVersion string   `json:"key_version,omitempty"`
+	Methods    []string `json:"methods,omitempty"`
+}
+
+type encryptionStatus struct {
+	Encrypted         bool                          `json:"encrypted"`
+	EncryptionVersion string                        `json:"encryption_version,omitempty"`
+	KeyProviders      []encryptionStatusKeyProvider `json:"key_providers,omitempty"`
+}
+
+func (c *EncryptionStatusCommand) Run(args []string) int {
+	var jsonOutput bool
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("encryption status")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return cli.RunResultHelp
+	}
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("The encryption status command expects at most one argument with the path to a state file.")
+		return cli.RunResultHelp
+	}
+
+	path := DefaultStateFilename
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read state file: %s", err))
+		return 1
+	}
+
+	payload, err := encryption.InspectPayload(data)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read the encryption status of %s: %s", path, err))
+		return 1
+	}
+
+	// The configuration is only used to describe the key providers found in
+	// the state file, so no key providers are set up here and no keys are
+	// needed.
+	cfg, diags := c.encryptionConfig()
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	status := encryptionStatus{
+		Encrypted:         payload.Encrypted,
+		EncryptionVersion: payload.Version,
+	}
+	for _, kp := range payload.KeyProviders {
+		status.KeyProviders = append(status.KeyProviders, describeEncryptionKeyProvider(cfg, kp))
+	}
+
+	if jsonOutput {
+		out, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal encryption status to json: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+		c.showDiagnostics(diags)
+		return 0
+	}
+
+	c.showDiagnostics(diags)
+
+	if !status.Encrypted {
+		c.Ui.Output(fmt.Sprintf("The state file %s is unencrypted.", path))
+		return 0
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "The state file %s is encrypted (encryption version %s).\n", path, status.EncryptionVersion)
+	for _, kp := range status.KeyProviders {
+		fmt.Fprintf(&buf, "\nKey provider %s\n", kp.MetaKey)
+		if kp.Address != "" && kp.Address != kp.MetaKey {
+			fmt.Fprintf(&buf, "  Address:     %s\n", kp.Address)
+		}
+		if kp.Type != "" {
+			fmt.Fprintf(&buf, "  Type:        %s\n", kp.Type)
+		}
+		if kp.KeyVersion != "" {
+			fmt.Fprintf(&buf, "  Key version: %s\n", kp.KeyVersion)
+		}
+		if len(kp.Methods) != 0 {
+			fmt.Fprintf(&buf, "  Methods:     %s\n", strings.Join(kp.Methods, ", "))
+		}
+	}
+	c.Ui.Output(strings.TrimSpace(buf.String()))
+
+	return 0
+}
+
+// describeEncryptionKeyProvider matches the key provider metadata from a
+// state file against the encryption configuration, to find the type of the
+// key provider and the methods using it. Key providers which are no longer
+// configured are described by their metadata key alone.
+func describeEncryptionKeyProvider(cfg *config.EncryptionConfig, kp encryption.PayloadKeyProvider) encryptionStatusKeyProvider {
+	ret := encryptionStatusKeyProvider{
+		MetaKey:    string(kp.MetaKey),
+		KeyVersion: kp.KeyVersion,
+	}
+
+	// Instances of key providers expanded with for_each store their metadata
+	// under the key provider's metadata key, suffixed with the instance key.
+	baseMetaKey, instanceKey := kp.MetaKey, ""
+	if i := strings.Index(string(kp.MetaKey), "["); i >= 0 {
+		baseMetaKey, instanceKey = kp.MetaKey[:i], string(kp.MetaKey[i:])
+	}
+
+	var kpType, kpName string
+	if cfg != nil {
+		for _, kpc := range cfg.KeyProviderConfigs {
+			addr, diags := kpc.Addr()
+			if diags.HasErrors() {
+				continue
+			}
+			metaKey := keyprovider.MetaStorageKey(addr)
+			if kpc.EncryptedMetadataAlias != "" {
+				metaKey = keyprovider.MetaStorageKey(kpc.EncryptedMetadataAlias)
+			}
+			if metaKey == baseMetaKey {
+				kpType, kpName = kpc.Type, kpc.Name
+				break
+			}
+		}
+	}
+	if kpType == "" {
+		// Without an alias, the metadata key is the key provider address.
+		parts := strings.Split(string(baseMetaKey), ".")
+		if len(parts) != 3 || parts[0] != "key_provider" {
+			return ret
+		}
+		kpType, kpName = parts[1], parts[2]
+	}
+	ret.Type = kpType
+	ret.Address = fmt.Sprintf("key_provider.%s.%s%s", kpType, kpName, instanceKey)
+
+	if cfg == nil {
+		return ret
+	}
+	for _, mc := range cfg.MethodConfigs {
+		if methodUsesKeyProvider(mc, kpType, kpName) {
+			addr, diags := mc.Addr()
+			if diags.HasErrors() {
+				continue
+			}
+			ret.Methods = append(ret.Methods, string(addr))
+		}
+	}
+	return ret
+}
+
+// methodUsesKeyProvider returns true if any of the attributes of the method
+// configuration refer to the given key provider.
+func methodUsesKeyProvider(mc config.MethodConfig, kpType, kpName string) bool {
+	// Method blocks may contain nested blocks, in which case JustAttributes
+	// still returns the attributes alongside its error diagnostics.
+	attrs, _ := mc.Body.JustAttributes()
+	for _, attr := range attrs {
+		for _, traversal := range attr.Expr.Variables() {
+			if len(traversal) < 3 || traversal.RootName() != "key_provider" {
+				continue
+			}
+			typeAttr, typeOk := traversal[1].(hcl.TraverseAttr)
+			nameAttr, nameOk := traversal[2].(hcl.TraverseAttr)
+			if typeOk && nameOk && typeAttr.Name == kpType && nameAttr.Name == kpName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *EncryptionStatusCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] encryption status [options] [PATH]
+
+  Reports how the state file at PATH is encrypted, without decrypting it.
+  PATH defaults to "terraform.tfstate".
+
+  For an encrypted state file, this lists the key providers whose metadata
+  is stored in the file, along with their key version if the key provider
+  records one. Key providers present in the current configuration are
+  shown with their type and the methods using them.
+
+Options:
+
+  -json               If specified, output will be in JSON format.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EncryptionStatusCommand) Synopsis() string {
+	return "Show how a state file is encrypted"
+}
@@ -0,0 +1,114 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+const testEncryptionStatusConfig = `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "basic" {
+      passphrase = "Hello world! 123"
+    }
+    method "aes_gcm" "example" {
+      keys = key_provider.pbkdf2.basic
+    }
+    state {
+      method = method.aes_gcm.example
+    }
+  }
+}
+`
+
+// testEncryptionStatusPayload is an encrypted state with metadata for the
+// key provider in testEncryptionStatusConfig, and for one which is no longer
+// configured. The metadata is base64 encoded {"key_version":"v2"}.
+const testEncryptionStatusPayload = `{
+  "meta": {
+    "key_provider.pbkdf2.basic": "eyJrZXlfdmVyc2lvbiI6InYyIn0=",
+    "key_provider.pbkdf2.old": "e30="
+  },
+  "encrypted_data": "",
+  "encryption_version": "v0"
+}`
+
+func TestEncryptionStatus(t *testing.T) {
+	testCwd(t)
+	if err := os.WriteFile("main.tf", []byte(testEncryptionStatusConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(DefaultStateFilename, []byte(testEncryptionStatusPayload), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := cli.NewMockUi()
+	c := &EncryptionStatusCommand{
+		Meta: Meta{
+			Ui:               ui,
+			testingOverrides: metaOverridesForProvider(testProvider()),
+		},
+	}
+
+	if code := c.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var got encryptionStatus
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json output: %s", err)
+	}
+	want := encryptionStatus{
+		Encrypted:         true,
+		EncryptionVersion: "v0",
+		KeyProviders: []encryptionStatusKeyProvider{
+			{
+				MetaKey:    "key_provider.pbkdf2.basic",
+				Address:    "key_provider.pbkdf2.basic",
+				Type:       "pbkdf2",
+				KeyVersion: "v2",
+				Methods:    []string{"method.aes_gcm.example"},
+			},
+			{
+				MetaKey: "key_provider.pbkdf2.old",
+				Address: "key_provider.pbkdf2.old",
+				Type:    "pbkdf2",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong result\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestEncryptionStatus_unencrypted(t *testing.T) {
+	testCwd(t)
+	state := testState()
+	statePath := testStateFile(t, state)
+
+	ui := cli.NewMockUi()
+	c := &EncryptionStatusCommand{
+		Meta: Meta{
+			Ui:               ui,
+			testingOverrides: metaOverridesForProvider(testProvider()),
+		},
+	}
+
+	if code := c.Run([]string{statePath}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if got, want := ui.OutputWriter.String(), "is unencrypted."; !strings.Contains(got, want) {
+		t.Fatalf("expected output containing %q, got: %s", want, got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// EncryptionValidateCommand is a Command implementation that checks that the
+// encryption configuration works, without reading or writing any state.
+type EncryptionValidateCommand struct {
+	Meta
+}
+
+func (c *EncryptionValidateCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("encryption validate")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return cli.RunResultHelp
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The encryption validate command expects no arguments.")
+		return cli.RunResultHelp
+	}
+
+	// Setting up the encryption already contacts the key providers to obtain
+	// the encryption keys.
+	enc, diags := c.Encryption()
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	diags = diags.Append(enc.Validate())
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+
+	c.Ui.Output(c.Colorize().Color("[green][bold]Success![reset] The encryption configuration is valid."))
+	return 0
+}
+
+func (c *EncryptionValidateCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] encryption validate [options]
+
+  Validates the state and plan encryption configuration of the current
+  directory, including the TF_ENCRYPTION environment variable.
+
+  Every configured key provider is asked for its keys, and a small test
+  payload is encrypted and decrypted in memory for each encryption target.
+  This detects problems such as an unreachable key management service or
+  missing permissions before any state is written. No state or plan file
+  is read or written.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EncryptionValidateCommand) Synopsis() string {
+	return "Check that the encryption configuration works"
+}
@@ -0,0 +1,61 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestEncryptionValidate(t *testing.T) {
+	testCwd(t)
+	if err := os.WriteFile("main.tf", []byte(testEncryptionStatusConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := cli.NewMockUi()
+	c := &EncryptionValidateCommand{
+		Meta: Meta{
+			Ui:               ui,
+			testingOverrides: metaOverridesForProvider(testProvider()),
+		},
+	}
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "The encryption configuration is valid."; !strings.Contains(got, want) {
+		t.Fatalf("expected output containing %q, got: %s", want, got)
+	}
+
+	// No state file is written.
+	if _, err := os.Stat(DefaultStateFilename); !os.IsNotExist(err) {
+		t.Fatalf("expected no state file, got: %v", err)
+	}
+}
+
+func TestEncryptionValidate_invalidConfig(t *testing.T) {
+	testCwd(t)
+	config := strings.Replace(testEncryptionStatusConfig, "Hello world! 123", "too short", 1)
+	if err := os.WriteFile("main.tf", []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := cli.NewMockUi()
+	c := &EncryptionValidateCommand{
+		Meta: Meta{
+			Ui:               ui,
+			testingOverrides: metaOverridesForProvider(testProvider()),
+		},
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("expected exit code 1, got %d\n\n%s", code, ui.OutputWriter.String())
+	}
+}
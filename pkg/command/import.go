@@ -51,11 +51,27 @@ func (c *ImportCommand) Run(args []string) int {
 	cmdFlags.StringVar(&configPath, "config", pwd, "path")
 	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
 	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.BoolVar(&c.outputInJSON, "json", false, "json")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
 	}
 
+	viewType := arguments.ViewHuman
+	var jsonView *views.JSONView
+	if c.outputInJSON {
+		viewType = arguments.ViewJSON
+		jsonView = views.NewJSONView(c.View)
+		c.Meta.color = false
+		c.Meta.Color = false
+		c.oldUi = c.Ui
+		c.Ui = &WrappedUi{
+			cliUi:        c.oldUi,
+			jsonView:     jsonView,
+			outputInJSON: true,
+		}
+	}
+
 	args = cmdFlags.Args()
 	if len(args) != 2 {
 		c.Ui.Error("The import command expects two arguments.")
@@ -196,7 +212,7 @@ func (c *ImportCommand) Run(args []string) int {
 	}
 
 	// Build the operation
-	opReq := c.Operation(b, arguments.ViewHuman, enc)
+	opReq := c.Operation(b, viewType, enc)
 	opReq.ConfigDir = configPath
 	opReq.ConfigLoader, err = c.initConfigLoader()
 	if err != nil {
@@ -205,6 +221,11 @@ func (c *ImportCommand) Run(args []string) int {
 		return 1
 	}
 	opReq.Hooks = []tofu.Hook{c.uiHook()}
+	if jsonView != nil {
+		// Machine-readable progress messages replace the human-oriented
+		// ones, so they don't corrupt the JSON output.
+		opReq.Hooks = []tofu.Hook{views.NewJSONHook(jsonView)}
+	}
 	{
 		// Setup required variables/call for operation (usually done in Meta.RunOperation)
 		var moreDiags, callDiags tfdiags.Diagnostics
@@ -216,7 +237,7 @@ func (c *ImportCommand) Run(args []string) int {
 			return 1
 		}
 	}
-	opReq.View = views.NewOperation(arguments.ViewHuman, c.RunningInAutomation, c.View)
+	opReq.View = views.NewOperation(viewType, c.RunningInAutomation, c.View)
 
 	// Check remote OpenTofu version is compatible
 	remoteVersionDiags := c.remoteVersionCheck(b, opReq.Workspace)
@@ -337,6 +358,10 @@ Options:
 
   -input=false            Disable interactive input prompts.
 
+  -json                   Produce output in a machine-readable JSON format,
+                          reporting the import progress as structured
+                          messages. Implies -no-color.
+
   -lock=false             Don't hold a state lock during the operation. This is
                           dangerous if others might concurrently run commands
                           against the same workspace.
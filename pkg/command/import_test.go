@@ -6,6 +6,7 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -77,6 +78,79 @@ func TestImport(t *testing.T) {
 	testStateOutput(t, statePath, testImportStr)
 }
 
+func TestImport_json(t *testing.T) {
+	defer testChdir(t, testFixturePath("import-provider-implicit"))()
+
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &ImportCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	p.ImportResourceStateFn = nil
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "test_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("yay"),
+				}),
+			},
+		},
+	}
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Optional: true, Computed: true},
+					},
+				},
+			},
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-json",
+		"test_instance.foo",
+		"bar",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	// Every line of the output must be a JSON message, and the progress must
+	// be reported as a structured message rather than as text.
+	var progress map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output.Stdout()), "\n") {
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("output line is not JSON: %q", line)
+		}
+		if msg["type"] == "import_progress" {
+			progress = msg["hook"].(map[string]interface{})
+		}
+	}
+	if progress == nil {
+		t.Fatalf("no import_progress message in output:\n%s", output.Stdout())
+	}
+	if progress["success"] != true || progress["completed"] != float64(1) || progress["total"] != float64(1) {
+		t.Errorf("wrong import progress %#v", progress)
+	}
+
+	testStateOutput(t, statePath, testImportStr)
+}
+
 func TestImport_providerConfig(t *testing.T) {
 	defer testChdir(t, testFixturePath("import-provider"))()
 
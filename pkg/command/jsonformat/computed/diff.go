@@ -99,6 +99,12 @@ type RenderHumanOpts struct {
 
 	// ShowSensitive is used to display the value of variables marked as sensitive.
 	ShowSensitive bool
+
+	// CompactSmallCollections, if greater than zero, tells the Renderer to
+	// render sets with at most this many displayed elements on a single line,
+	// for example `["a", "b"]`, instead of one element per line. Sets are only
+	// compacted if every element fits on a single line.
+	CompactSmallCollections int
 }
 
 // NewRenderHumanOpts creates a new RenderHumanOpts struct with the required
@@ -126,5 +132,6 @@ func (opts RenderHumanOpts) Clone() RenderHumanOpts {
 		// an ancestor making the switch and affecting the entire tree.
 		OverrideForcesReplacement: false,
 		ShowSensitive:             opts.ShowSensitive,
+		CompactSmallCollections:   opts.CompactSmallCollections,
 	}
 }
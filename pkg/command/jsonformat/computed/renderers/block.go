@@ -179,3 +179,41 @@ func (renderer blockRenderer) RenderHuman(diff computed.Diff, indent int, opts c
 	buf.WriteString(fmt.Sprintf("%s%s}", formatIndent(indent), writeDiffActionSymbol(plans.NoOp, opts)))
 	return buf.String()
 }
+
+func (renderer blockRenderer) Children() []computed.DiffChild {
+	var children []computed.DiffChild
+
+	var attributeKeys []string
+	for key := range renderer.attributes {
+		attributeKeys = append(attributeKeys, key)
+	}
+	sort.Strings(attributeKeys)
+	for _, key := range attributeKeys {
+		children = append(children, computed.DiffChild{Step: key, Diff: renderer.attributes[key]})
+	}
+
+	for _, key := range renderer.blocks.GetAllKeys() {
+		beforeSensitive, afterSensitive := renderer.blocks.BeforeSensitiveBlocks[key], renderer.blocks.AfterSensitiveBlocks[key]
+		if beforeSensitive || afterSensitive {
+			// The entire group of blocks is sensitive, so we report it as a
+			// single sensitive child.
+			children = append(children, computed.DiffChild{
+				Step: key,
+				Diff: computed.NewDiff(SensitiveBlock(computed.Diff{}, beforeSensitive, afterSensitive), plans.NoOp, false),
+			})
+			continue
+		}
+
+		switch {
+		case renderer.blocks.IsSingleBlock(key):
+			children = append(children, computed.DiffChild{Step: key, Diff: renderer.blocks.SingleBlocks[key]})
+		case renderer.blocks.IsMapBlock(key):
+			children = append(children, computed.DiffChild{Step: key, Diff: computed.NewDiff(Map(renderer.blocks.MapBlocks[key]), plans.NoOp, false)})
+		case renderer.blocks.IsSetBlock(key):
+			children = append(children, computed.DiffChild{Step: key, Diff: computed.NewDiff(Set(renderer.blocks.SetBlocks[key]), plans.NoOp, false)})
+		case renderer.blocks.IsListBlock(key):
+			children = append(children, computed.DiffChild{Step: key, Diff: computed.NewDiff(List(renderer.blocks.ListBlocks[key]), plans.NoOp, false)})
+		}
+	}
+	return children
+}
@@ -14,6 +14,7 @@ import (
 )
 
 var _ computed.DiffRenderer = (*listRenderer)(nil)
+var _ computed.ParentRenderer = (*listRenderer)(nil)
 
 func List(elements []computed.Diff) computed.DiffRenderer {
 	return &listRenderer{
@@ -127,3 +128,11 @@ func (renderer listRenderer) RenderHuman(diff computed.Diff, indent int, opts co
 	buf.WriteString(fmt.Sprintf("%s%s]%s", formatIndent(indent), writeDiffActionSymbol(plans.NoOp, opts), nullSuffix(diff.Action, opts)))
 	return buf.String()
 }
+
+func (renderer listRenderer) Children() []computed.DiffChild {
+	var children []computed.DiffChild
+	for ix, element := range renderer.elements {
+		children = append(children, computed.DiffChild{Step: ix, Diff: element})
+	}
+	return children
+}
@@ -16,6 +16,7 @@ import (
 )
 
 var _ computed.DiffRenderer = (*mapRenderer)(nil)
+var _ computed.ParentRenderer = (*mapRenderer)(nil)
 
 func Map(elements map[string]computed.Diff) computed.DiffRenderer {
 	return &mapRenderer{
@@ -110,3 +111,17 @@ func (renderer mapRenderer) RenderHuman(diff computed.Diff, indent int, opts com
 	buf.WriteString(fmt.Sprintf("%s%s}%s", formatIndent(indent), writeDiffActionSymbol(plans.NoOp, opts), nullSuffix(diff.Action, opts)))
 	return buf.String()
 }
+
+func (renderer mapRenderer) Children() []computed.DiffChild {
+	var keys []string
+	for key := range renderer.elements {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var children []computed.DiffChild
+	for _, key := range keys {
+		children = append(children, computed.DiffChild{Step: key, Diff: renderer.elements[key]})
+	}
+	return children
+}
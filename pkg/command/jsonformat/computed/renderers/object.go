@@ -15,6 +15,7 @@ import (
 )
 
 var _ computed.DiffRenderer = (*objectRenderer)(nil)
+var _ computed.ParentRenderer = (*objectRenderer)(nil)
 
 func Object(attributes map[string]computed.Diff) computed.DiffRenderer {
 	return &objectRenderer{
@@ -98,3 +99,17 @@ func (renderer objectRenderer) RenderHuman(diff computed.Diff, indent int, opts
 	buf.WriteString(fmt.Sprintf("%s%s}%s", formatIndent(indent), writeDiffActionSymbol(plans.NoOp, opts), nullSuffix(diff.Action, opts)))
 	return buf.String()
 }
+
+func (renderer objectRenderer) Children() []computed.DiffChild {
+	var keys []string
+	for key := range renderer.attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var children []computed.DiffChild
+	for _, key := range keys {
+		children = append(children, computed.DiffChild{Step: key, Diff: renderer.attributes[key]})
+	}
+	return children
+}
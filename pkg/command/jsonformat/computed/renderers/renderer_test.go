@@ -7,6 +7,7 @@ package renderers
 
 import (
 	"encoding/json"
+	"math/rand"
 	"strings"
 	"testing"
 
@@ -1637,6 +1638,119 @@ jsonencode(
 [
       ~ 0 -> (known after apply),
     ]
+`,
+		},
+		"set_compact": {
+			diff: computed.Diff{
+				Renderer: Set([]computed.Diff{
+					{
+						Renderer: Primitive(json.Number("0"), nil, cty.Number),
+						Action:   plans.Delete,
+					},
+					{
+						Renderer: Primitive(nil, json.Number("1"), cty.Number),
+						Action:   plans.Create,
+					},
+				}),
+				Action: plans.Update,
+			},
+			opts: computed.RenderHumanOpts{
+				CompactSmallCollections: 3,
+			},
+			expected: "[- 0, + 1]",
+		},
+		"set_compact_forces_replacement": {
+			diff: computed.Diff{
+				Renderer: Set([]computed.Diff{
+					{
+						Renderer: Primitive(nil, json.Number("1"), cty.Number),
+						Action:   plans.Create,
+					},
+				}),
+				Action:  plans.Update,
+				Replace: true,
+			},
+			opts: computed.RenderHumanOpts{
+				CompactSmallCollections: 3,
+			},
+			expected: "[+ 1] # forces replacement",
+		},
+		"set_compact_empty": {
+			diff: computed.Diff{
+				Renderer: Set([]computed.Diff{}),
+				Action:   plans.Delete,
+			},
+			opts: computed.RenderHumanOpts{
+				CompactSmallCollections: 3,
+			},
+			expected: "[] -> null",
+		},
+		"set_compact_hidden_symbols": {
+			diff: computed.Diff{
+				Renderer: Set([]computed.Diff{
+					{
+						Renderer: Primitive(nil, "a", cty.String),
+						Action:   plans.Create,
+					},
+					{
+						Renderer: Primitive(nil, "b", cty.String),
+						Action:   plans.Create,
+					},
+				}),
+				Action: plans.Create,
+			},
+			opts: computed.RenderHumanOpts{
+				CompactSmallCollections: 3,
+				HideDiffActionSymbols:   true,
+			},
+			expected: `["a", "b"]`,
+		},
+		"set_compact_too_many_elements": {
+			diff: computed.Diff{
+				Renderer: Set([]computed.Diff{
+					{
+						Renderer: Primitive(nil, json.Number("1"), cty.Number),
+						Action:   plans.Create,
+					},
+					{
+						Renderer: Primitive(nil, json.Number("2"), cty.Number),
+						Action:   plans.Create,
+					},
+				}),
+				Action: plans.Update,
+			},
+			opts: computed.RenderHumanOpts{
+				CompactSmallCollections: 1,
+			},
+			expected: `
+[
+      + 1,
+      + 2,
+    ]
+`,
+		},
+		"set_compact_hidden_unchanged": {
+			diff: computed.Diff{
+				Renderer: Set([]computed.Diff{
+					{
+						Renderer: Primitive(json.Number("0"), json.Number("0"), cty.Number),
+						Action:   plans.NoOp,
+					},
+					{
+						Renderer: Primitive(nil, json.Number("1"), cty.Number),
+						Action:   plans.Create,
+					},
+				}),
+				Action: plans.Update,
+			},
+			opts: computed.RenderHumanOpts{
+				CompactSmallCollections: 3,
+			},
+			expected: `
+[
+      + 1,
+        # (1 unchanged element hidden)
+    ]
 `,
 		},
 		"create_empty_block": {
@@ -2278,3 +2392,62 @@ jsonencode(
 		})
 	}
 }
+
+func TestRenderers_SetStableOrder(t *testing.T) {
+	colorize := colorstring.Colorize{
+		Colors:  colorstring.DefaultColors,
+		Disable: true,
+	}
+
+	elements := []computed.Diff{
+		{
+			Renderer: Primitive(json.Number("3"), nil, cty.Number),
+			Action:   plans.Delete,
+		},
+		{
+			Renderer: Primitive(nil, json.Number("4"), cty.Number),
+			Action:   plans.Create,
+		},
+		{
+			Renderer: Primitive(json.Number("5"), json.Number("5"), cty.Number),
+			Action:   plans.NoOp,
+		},
+		{
+			Renderer: Primitive(json.Number("1"), nil, cty.Number),
+			Action:   plans.Delete,
+		},
+		{
+			Renderer: Primitive(nil, json.Number("2"), cty.Number),
+			Action:   plans.Create,
+		},
+	}
+
+	expected := strings.TrimSpace(`
+[
+      - 1,
+      - 3,
+      + 2,
+      + 4,
+        # (1 unchanged element hidden)
+    ]
+`)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		shuffled := make([]computed.Diff, len(elements))
+		copy(shuffled, elements)
+		rng.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+
+		diff := computed.Diff{
+			Renderer: Set(shuffled),
+			Action:   plans.Update,
+		}
+		opts := computed.RenderHumanOpts{Colorize: &colorize}
+		actual := diff.RenderHuman(0, opts)
+		if diff := cmp.Diff(expected, actual); len(diff) > 0 {
+			t.Fatalf("\nexpected:\n%s\nactual:\n%s\ndiff:\n%s\n", expected, actual, diff)
+		}
+	}
+}
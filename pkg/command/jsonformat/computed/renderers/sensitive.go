@@ -13,6 +13,7 @@ import (
 )
 
 var _ computed.DiffRenderer = (*sensitiveRenderer)(nil)
+var _ computed.SensitiveRenderer = (*sensitiveRenderer)(nil)
 
 func Sensitive(change computed.Diff, beforeSensitive, afterSensitive bool) computed.DiffRenderer {
 	return &sensitiveRenderer{
@@ -58,3 +59,7 @@ func (renderer sensitiveRenderer) WarningsHuman(diff computed.Diff, indent int,
 	}
 	return []string{warning}
 }
+
+func (renderer sensitiveRenderer) Sensitivity() (bool, bool) {
+	return renderer.beforeSensitive, renderer.afterSensitive
+}
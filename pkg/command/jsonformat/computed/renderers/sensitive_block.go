@@ -47,3 +47,7 @@ func (renderer sensitiveBlockRenderer) WarningsHuman(diff computed.Diff, indent
 		return []string{opts.Colorize.Color(fmt.Sprintf("  # [yellow]Warning[reset]: this block will be marked as sensitive and will not\n%s  # display in UI output after applying this change.", formatIndent(indent)))}
 	}
 }
+
+func (renderer sensitiveBlockRenderer) Sensitivity() (bool, bool) {
+	return renderer.beforeSensitive, renderer.afterSensitive
+}
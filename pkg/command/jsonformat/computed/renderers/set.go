@@ -8,12 +8,16 @@ package renderers
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/computed"
 	"github.com/we-dcode/opentofu/pkg/plans"
 )
 
 var _ computed.DiffRenderer = (*setRenderer)(nil)
+var _ computed.ParentRenderer = (*setRenderer)(nil)
+var _ computed.UnchangedElementsRenderer = (*setRenderer)(nil)
 
 func Set(elements []computed.Diff) computed.DiffRenderer {
 	return &setRenderer{
@@ -54,18 +58,48 @@ func (renderer setRenderer) RenderHuman(diff computed.Diff, indent int, opts com
 
 	unchangedElements := 0
 
-	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprintf("[%s\n", forcesReplacement(displayForcesReplacementInSelf, opts)))
+	var elements []renderedSetElement
 	for _, element := range renderer.elements {
 		if element.Action == plans.NoOp && !opts.ShowUnchangedChildren {
 			unchangedElements++
 			continue
 		}
 
-		for _, warning := range element.WarningsHuman(indent+1, opts) {
+		elements = append(elements, renderedSetElement{
+			diff:     element,
+			rendered: element.RenderHuman(indent+1, elementOpts),
+		})
+	}
+
+	// Sets are unordered, so we sort the elements to make sure the same diff
+	// is always rendered the same way. Removed elements come first and added
+	// elements last, matching the order we'd list them in for a list.
+	sort.SliceStable(elements, func(i, j int) bool {
+		if ri, rj := setElementActionRank(elements[i].diff.Action), setElementActionRank(elements[j].diff.Action); ri != rj {
+			return ri < rj
+		}
+		return elements[i].rendered < elements[j].rendered
+	})
+
+	if renderer.canRenderCompact(elements, unchangedElements, displayForcesReplacementInChildren, indent, opts) {
+		var rendered []string
+		for _, element := range elements {
+			symbol := ""
+			if element.diff.Action != plans.NoOp {
+				symbol = writeDiffActionSymbol(element.diff.Action, elementOpts)
+			}
+			rendered = append(rendered, symbol+element.rendered)
+		}
+		return fmt.Sprintf("[%s]%s%s", strings.Join(rendered, ", "), nullSuffix(diff.Action, opts), forcesReplacement(displayForcesReplacementInSelf, opts))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("[%s\n", forcesReplacement(displayForcesReplacementInSelf, opts)))
+	for _, element := range elements {
+		for _, warning := range element.diff.WarningsHuman(indent+1, opts) {
 			buf.WriteString(fmt.Sprintf("%s%s\n", formatIndent(indent+1), warning))
 		}
-		buf.WriteString(fmt.Sprintf("%s%s%s,\n", formatIndent(indent+1), writeDiffActionSymbol(element.Action, elementOpts), element.RenderHuman(indent+1, elementOpts)))
+		buf.WriteString(fmt.Sprintf("%s%s%s,\n", formatIndent(indent+1), writeDiffActionSymbol(element.diff.Action, elementOpts), element.rendered))
 	}
 
 	if unchangedElements > 0 {
@@ -75,3 +109,60 @@ func (renderer setRenderer) RenderHuman(diff computed.Diff, indent int, opts com
 	buf.WriteString(fmt.Sprintf("%s%s]%s", formatIndent(indent), writeDiffActionSymbol(plans.NoOp, opts), nullSuffix(diff.Action, opts)))
 	return buf.String()
 }
+
+// canRenderCompact returns true if the set should be rendered on a single
+// line, as requested by the CompactSmallCollections option. This is only
+// possible if every element fits on a single line, and there are no comments
+// like warnings or hidden element counts that would have to be displayed.
+func (renderer setRenderer) canRenderCompact(elements []renderedSetElement, unchangedElements int, displayForcesReplacementInChildren bool, indent int, opts computed.RenderHumanOpts) bool {
+	if opts.CompactSmallCollections <= 0 || len(elements) > opts.CompactSmallCollections {
+		return false
+	}
+	if unchangedElements > 0 || displayForcesReplacementInChildren {
+		return false
+	}
+	for _, element := range elements {
+		if strings.Contains(element.rendered, "\n") || len(element.diff.WarningsHuman(indent+1, opts)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// renderedSetElement pairs a set element with its rendered value, so it can
+// be sorted by it.
+type renderedSetElement struct {
+	diff     computed.Diff
+	rendered string
+}
+
+// setElementActionRank orders set elements by action: removed elements
+// first, then changed or unchanged elements, then added elements.
+func setElementActionRank(action plans.Action) int {
+	switch action {
+	case plans.Delete:
+		return 0
+	case plans.Create:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (renderer setRenderer) Children() []computed.DiffChild {
+	var children []computed.DiffChild
+	for ix, element := range renderer.elements {
+		children = append(children, computed.DiffChild{Step: ix, Diff: element})
+	}
+	return children
+}
+
+func (renderer setRenderer) UnchangedElementCount() int {
+	count := 0
+	for _, element := range renderer.elements {
+		if element.Action == plans.NoOp {
+			count++
+		}
+	}
+	return count
+}
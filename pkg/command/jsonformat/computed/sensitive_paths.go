@@ -0,0 +1,67 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package computed
+
+// SensitivePath describes a path within a diff that points to a value that is
+// sensitive before and/or after the change.
+//
+// The steps in Path are strings for object attributes, map keys and block
+// names, and ints for list, set and tuple indices, matching the paths used in
+// the JSON plan output.
+type SensitivePath struct {
+	Path            []interface{} `json:"path"`
+	BeforeSensitive bool          `json:"before_sensitive"`
+	AfterSensitive  bool          `json:"after_sensitive"`
+}
+
+// DiffChild is a single child diff of a complex diff, along with the path
+// step that leads to it.
+type DiffChild struct {
+	Step interface{}
+	Diff Diff
+}
+
+// ParentRenderer is implemented by the renderers of complex values, so the
+// child diffs can be inspected without rendering them.
+type ParentRenderer interface {
+	// Children returns the child diffs in a stable order.
+	Children() []DiffChild
+}
+
+// SensitiveRenderer is implemented by the renderers that hide sensitive
+// values.
+type SensitiveRenderer interface {
+	Sensitivity() (beforeSensitive, afterSensitive bool)
+}
+
+// SensitivePaths returns the paths of all the values within the diff that are
+// sensitive before and/or after the change, in a stable order.
+//
+// Values nested within a sensitive value are not reported separately, as the
+// entire value is sensitive.
+func (diff Diff) SensitivePaths() []SensitivePath {
+	var paths []SensitivePath
+	diff.collectSensitivePaths(nil, &paths)
+	return paths
+}
+
+func (diff Diff) collectSensitivePaths(path []interface{}, paths *[]SensitivePath) {
+	if sensitive, ok := diff.Renderer.(SensitiveRenderer); ok {
+		beforeSensitive, afterSensitive := sensitive.Sensitivity()
+		*paths = append(*paths, SensitivePath{
+			Path:            append([]interface{}{}, path...),
+			BeforeSensitive: beforeSensitive,
+			AfterSensitive:  afterSensitive,
+		})
+		return
+	}
+
+	if parent, ok := diff.Renderer.(ParentRenderer); ok {
+		for _, child := range parent.Children() {
+			child.Diff.collectSensitivePaths(append(path, child.Step), paths)
+		}
+	}
+}
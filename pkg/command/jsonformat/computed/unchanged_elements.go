@@ -0,0 +1,51 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package computed
+
+// UnchangedElements describes a collection within a diff, and the number of
+// its elements that are unchanged. The human readable output collapses these
+// elements into a single "N unchanged elements" line.
+//
+// The steps in Path follow the same rules as SensitivePath.Path.
+type UnchangedElements struct {
+	Path  []interface{} `json:"path"`
+	Count int           `json:"count"`
+}
+
+// UnchangedElementsRenderer is implemented by the renderers of collections
+// that hide their unchanged elements in the human readable output.
+type UnchangedElementsRenderer interface {
+	// UnchangedElementCount returns the number of direct children of the
+	// collection whose action is NoOp.
+	UnchangedElementCount() int
+}
+
+// UnchangedElements returns the collections within the diff that contain
+// unchanged elements, along with the number of them, in a stable order.
+//
+// Collections without any unchanged elements are not reported.
+func (diff Diff) UnchangedElements() []UnchangedElements {
+	var ret []UnchangedElements
+	diff.collectUnchangedElements(nil, &ret)
+	return ret
+}
+
+func (diff Diff) collectUnchangedElements(path []interface{}, ret *[]UnchangedElements) {
+	if collection, ok := diff.Renderer.(UnchangedElementsRenderer); ok {
+		if count := collection.UnchangedElementCount(); count > 0 {
+			*ret = append(*ret, UnchangedElements{
+				Path:  append([]interface{}{}, path...),
+				Count: count,
+			})
+		}
+	}
+
+	if parent, ok := diff.Renderer.(ParentRenderer); ok {
+		for _, child := range parent.Children() {
+			child.Diff.collectUnchangedElements(append(path, child.Step), ret)
+		}
+	}
+}
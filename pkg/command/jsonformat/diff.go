@@ -11,6 +11,7 @@ import (
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/structured"
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/structured/attribute_path"
 	"github.com/we-dcode/opentofu/pkg/command/jsonplan"
+	"github.com/we-dcode/opentofu/pkg/command/jsonprovider"
 	"github.com/we-dcode/opentofu/pkg/plans"
 )
 
@@ -72,6 +73,22 @@ func precomputeDiffs(plan Plan, mode plans.Mode) diffs {
 	return diffs
 }
 
+// ComputeResourceChangeDiff computes the diff of a single resource change in
+// the same way as the human readable plan, so machine readable output can
+// report details of it without parsing the rendered text. It returns false if
+// the given provider schemas don't contain the schema of the resource.
+func ComputeResourceChangeDiff(change jsonplan.ResourceChange, providerSchemas map[string]*jsonprovider.Provider) (computed.Diff, bool) {
+	if providerSchemas[change.ProviderName] == nil {
+		return computed.Diff{}, false
+	}
+	schema := Plan{ProviderSchemas: providerSchemas}.getSchema(change)
+	if schema == nil {
+		return computed.Diff{}, false
+	}
+	structuredChange := structured.FromJsonChange(change.Change, attribute_path.AlwaysMatcher())
+	return differ.ComputeDiffForBlock(structuredChange, schema.Block), true
+}
+
 type diffs struct {
 	drift   []diff
 	changes []diff
@@ -8,11 +8,15 @@ package differ
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/colorstring"
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
+	"github.com/we-dcode/opentofu/pkg/command/jsonformat/computed"
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/computed/renderers"
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/structured"
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/structured/attribute_path"
@@ -2943,6 +2947,281 @@ func TestSpecificCases(t *testing.T) {
 	}
 }
 
+func TestSensitivePaths(t *testing.T) {
+	input := structured.Change{
+		Before: map[string]interface{}{
+			"id": "i-1234",
+			"config": map[string]interface{}{
+				"password": "old",
+				"user":     "admin",
+			},
+			"disk": []interface{}{
+				map[string]interface{}{
+					"size": "10GB",
+				},
+			},
+		},
+		After: map[string]interface{}{
+			"id": "i-1234",
+			"config": map[string]interface{}{
+				"password": "new",
+				"user":     "admin",
+			},
+			"disk": []interface{}{
+				map[string]interface{}{
+					"size": "20GB",
+				},
+			},
+		},
+		BeforeSensitive: map[string]interface{}{
+			"config": map[string]interface{}{
+				"password": true,
+			},
+		},
+		AfterSensitive: map[string]interface{}{
+			"config": map[string]interface{}{
+				"password": true,
+			},
+			"disk": []interface{}{
+				map[string]interface{}{
+					"size": true,
+				},
+			},
+		},
+		ReplacePaths:       attribute_path.Empty(false),
+		RelevantAttributes: attribute_path.AlwaysMatcher(),
+	}
+	block := &jsonprovider.Block{
+		Attributes: map[string]*jsonprovider.Attribute{
+			"id": {
+				AttributeType: unmarshalType(t, cty.String),
+			},
+			"config": {
+				AttributeType: unmarshalType(t, cty.Object(map[string]cty.Type{
+					"password": cty.String,
+					"user":     cty.String,
+				})),
+			},
+		},
+		BlockTypes: map[string]*jsonprovider.BlockType{
+			"disk": {
+				NestingMode: "list",
+				Block: &jsonprovider.Block{
+					Attributes: map[string]*jsonprovider.Attribute{
+						"size": {
+							AttributeType: unmarshalType(t, cty.String),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := ComputeDiffForBlock(input, block).SensitivePaths()
+	want := []computed.SensitivePath{
+		{
+			Path:            []interface{}{"config", "password"},
+			BeforeSensitive: true,
+			AfterSensitive:  true,
+		},
+		{
+			Path:            []interface{}{"disk", 0, "size"},
+			BeforeSensitive: false,
+			AfterSensitive:  true,
+		},
+	}
+	if diff := cmp.Diff(want, got); len(diff) > 0 {
+		t.Fatalf("wrong sensitive paths\n%s", diff)
+	}
+}
+
+func TestUnchangedElements(t *testing.T) {
+	input := structured.Change{
+		Before: map[string]interface{}{
+			"tags": []interface{}{"a", "b", "c"},
+		},
+		After: map[string]interface{}{
+			"tags": []interface{}{"a", "b", "c", "d", "e"},
+		},
+		ReplacePaths:       attribute_path.Empty(false),
+		RelevantAttributes: attribute_path.AlwaysMatcher(),
+	}
+	block := &jsonprovider.Block{
+		Attributes: map[string]*jsonprovider.Attribute{
+			"tags": {
+				AttributeType: unmarshalType(t, cty.Set(cty.String)),
+			},
+		},
+	}
+
+	diff := ComputeDiffForBlock(input, block)
+	got := diff.UnchangedElements()
+	want := []computed.UnchangedElements{
+		{
+			Path:  []interface{}{"tags"},
+			Count: 3,
+		},
+	}
+	if diff := cmp.Diff(want, got); len(diff) > 0 {
+		t.Fatalf("wrong unchanged elements\n%s", diff)
+	}
+
+	// The human readable output reports the same count.
+	rendered := diff.RenderHuman(0, computed.NewRenderHumanOpts(&colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}, false))
+	if !strings.Contains(rendered, "3 unchanged elements hidden") {
+		t.Fatalf("expected the rendered diff to hide 3 elements, got:\n%s", rendered)
+	}
+}
+
+func TestSetElementMatching(t *testing.T) {
+	attribute := &jsonprovider.Attribute{
+		AttributeType: unmarshalType(t, cty.Set(cty.Object(map[string]cty.Type{
+			"name": cty.String,
+			"port": cty.Number,
+			"tls":  cty.Bool,
+		}))),
+	}
+
+	web := map[string]interface{}{
+		"name": "web",
+		"port": json.Number("80"),
+		"tls":  false,
+	}
+	api := map[string]interface{}{
+		"name": "api",
+		"port": json.Number("8080"),
+		"tls":  true,
+	}
+
+	tcs := map[string]struct {
+		input    structured.Change
+		validate renderers.ValidateDiffFunction
+	}{
+		"add": {
+			input: structured.Change{
+				Before: []interface{}{web},
+				After:  []interface{}{web, api},
+			},
+			validate: renderers.ValidateSet([]renderers.ValidateDiffFunction{
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive("web", "web", plans.NoOp, false),
+					"port": renderers.ValidatePrimitive(json.Number("80"), json.Number("80"), plans.NoOp, false),
+					"tls":  renderers.ValidatePrimitive(false, false, plans.NoOp, false),
+				}, plans.NoOp, false),
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive(nil, "api", plans.Create, false),
+					"port": renderers.ValidatePrimitive(nil, json.Number("8080"), plans.Create, false),
+					"tls":  renderers.ValidatePrimitive(nil, true, plans.Create, false),
+				}, plans.Create, false),
+			}, plans.Update, false),
+		},
+		"remove": {
+			input: structured.Change{
+				Before: []interface{}{web, api},
+				After:  []interface{}{web},
+			},
+			validate: renderers.ValidateSet([]renderers.ValidateDiffFunction{
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive("web", "web", plans.NoOp, false),
+					"port": renderers.ValidatePrimitive(json.Number("80"), json.Number("80"), plans.NoOp, false),
+					"tls":  renderers.ValidatePrimitive(false, false, plans.NoOp, false),
+				}, plans.NoOp, false),
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive("api", nil, plans.Delete, false),
+					"port": renderers.ValidatePrimitive(json.Number("8080"), nil, plans.Delete, false),
+					"tls":  renderers.ValidatePrimitive(true, nil, plans.Delete, false),
+				}, plans.Delete, false),
+			}, plans.Update, false),
+		},
+		"update_in_place": {
+			input: structured.Change{
+				Before: []interface{}{web, api},
+				After: []interface{}{
+					web,
+					map[string]interface{}{
+						"name": "api",
+						"port": json.Number("8443"),
+						"tls":  true,
+					},
+				},
+			},
+			validate: renderers.ValidateSet([]renderers.ValidateDiffFunction{
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive("web", "web", plans.NoOp, false),
+					"port": renderers.ValidatePrimitive(json.Number("80"), json.Number("80"), plans.NoOp, false),
+					"tls":  renderers.ValidatePrimitive(false, false, plans.NoOp, false),
+				}, plans.NoOp, false),
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive("api", "api", plans.NoOp, false),
+					"port": renderers.ValidatePrimitive(json.Number("8080"), json.Number("8443"), plans.Update, false),
+					"tls":  renderers.ValidatePrimitive(true, true, plans.NoOp, false),
+				}, plans.Update, false),
+			}, plans.Update, false),
+		},
+		"update_in_place_forces_replacement": {
+			input: structured.Change{
+				Before: []interface{}{api},
+				After: []interface{}{
+					map[string]interface{}{
+						"name": "api",
+						"port": json.Number("8443"),
+						"tls":  true,
+					},
+				},
+				ReplacePaths: &attribute_path.PathMatcher{
+					Paths: [][]interface{}{
+						{},
+					},
+				},
+			},
+			validate: renderers.ValidateSet([]renderers.ValidateDiffFunction{
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive("api", "api", plans.NoOp, false),
+					"port": renderers.ValidatePrimitive(json.Number("8080"), json.Number("8443"), plans.Update, false),
+					"tls":  renderers.ValidatePrimitive(true, true, plans.NoOp, false),
+				}, plans.Update, true),
+			}, plans.Update, true),
+		},
+		"mostly_different": {
+			input: structured.Change{
+				Before: []interface{}{api},
+				After: []interface{}{
+					map[string]interface{}{
+						"name": "admin",
+						"port": json.Number("8443"),
+						"tls":  true,
+					},
+				},
+			},
+			validate: renderers.ValidateSet([]renderers.ValidateDiffFunction{
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive("api", nil, plans.Delete, false),
+					"port": renderers.ValidatePrimitive(json.Number("8080"), nil, plans.Delete, false),
+					"tls":  renderers.ValidatePrimitive(true, nil, plans.Delete, false),
+				}, plans.Delete, false),
+				renderers.ValidateObject(map[string]renderers.ValidateDiffFunction{
+					"name": renderers.ValidatePrimitive(nil, "admin", plans.Create, false),
+					"port": renderers.ValidatePrimitive(nil, json.Number("8443"), plans.Create, false),
+					"tls":  renderers.ValidatePrimitive(nil, true, plans.Create, false),
+				}, plans.Create, false),
+			}, plans.Update, false),
+		},
+	}
+
+	for name, tc := range tcs {
+		if tc.input.RelevantAttributes == nil {
+			tc.input.RelevantAttributes = attribute_path.AlwaysMatcher()
+		}
+		if tc.input.ReplacePaths == nil {
+			tc.input.ReplacePaths = &attribute_path.PathMatcher{}
+		}
+
+		t.Run(name, func(t *testing.T) {
+			tc.validate(t, ComputeDiffForAttribute(tc.input, attribute))
+		})
+	}
+}
+
 // unmarshalType converts a cty.Type into a json.RawMessage understood by the
 // schema. It also lets the testing framework handle any errors to keep the API
 // clean.
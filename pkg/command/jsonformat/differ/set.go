@@ -7,6 +7,7 @@ package differ
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -86,6 +87,12 @@ func processSet(change structured.Change, process func(value structured.Change))
 		}
 	}
 
+	// Elements that have no exact match may still be the same element with
+	// some of its attributes changed, so we pair those up as well. This means
+	// they are rendered as an in-place update instead of as a removal and an
+	// unrelated addition.
+	matchSimilarElements(sliceValue, foundInBefore, foundInAfter)
+
 	clearRelevantStatus := func(change structured.Change) structured.Change {
 		// It's actually really difficult to render the diffs when some indices
 		// within a slice are relevant and others aren't. To make this simpler
@@ -135,3 +142,102 @@ func processSet(change structured.Change, process func(value structured.Change))
 		process(child)
 	}
 }
+
+// setElementSimilarityThreshold is the minimum share of attributes that an
+// element removed from a set and an element added to it must have in common
+// for processSet to treat them as the same element that has been updated.
+const setElementSimilarityThreshold = 0.5
+
+// matchSimilarElements pairs up the elements of before and after that
+// processSet couldn't match exactly, if they are similar enough according to
+// setElementSimilarityThreshold. The most similar pairs are matched first,
+// and each element is matched at most once.
+func matchSimilarElements(sliceValue structured.ChangeSlice, foundInBefore, foundInAfter map[int]int) {
+	type candidate struct {
+		beforeIx, afterIx int
+		similarity        float64
+	}
+
+	var candidates []candidate
+	for ix := 0; ix < len(sliceValue.Before); ix++ {
+		if foundInBefore[ix] >= 0 {
+			continue
+		}
+		for jx := 0; jx < len(sliceValue.After); jx++ {
+			if _, ok := foundInAfter[jx]; ok {
+				continue
+			}
+
+			// We don't pair up elements that are sensitive or unknown as a
+			// whole, since we can't show what changed within them anyway.
+			child := sliceValue.GetChild(ix, jx)
+			if child.IsBeforeSensitive() || child.IsAfterSensitive() || child.IsUnknown() {
+				continue
+			}
+
+			if similarity := elementSimilarity(child.Before, child.After); similarity >= setElementSimilarityThreshold {
+				candidates = append(candidates, candidate{
+					beforeIx:   ix,
+					afterIx:    jx,
+					similarity: similarity,
+				})
+			}
+		}
+	}
+
+	// The sort is stable, so pairs that are equally similar are matched in
+	// the order of the elements, which keeps the output deterministic.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+
+	for _, c := range candidates {
+		if foundInBefore[c.beforeIx] >= 0 {
+			continue
+		}
+		if _, ok := foundInAfter[c.afterIx]; ok {
+			continue
+		}
+		foundInBefore[c.beforeIx] = c.afterIx
+		foundInAfter[c.afterIx] = c.beforeIx
+	}
+}
+
+// elementSimilarity returns the share of the attributes of the given object
+// values that are equal, ignoring attributes that are null in both. Values
+// that aren't objects, such as the elements of a set of strings, are never
+// similar: a changed string is a different element.
+func elementSimilarity(before, after interface{}) float64 {
+	beforeAttrs, ok := before.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	afterAttrs, ok := after.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	total, equal := 0, 0
+	for key, beforeValue := range beforeAttrs {
+		afterValue := afterAttrs[key]
+		if beforeValue == nil && afterValue == nil {
+			continue
+		}
+		total++
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			equal++
+		}
+	}
+	for key, afterValue := range afterAttrs {
+		if _, ok := beforeAttrs[key]; ok || afterValue == nil {
+			// Attributes in before have been counted already.
+			continue
+		}
+		total++
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return float64(equal) / float64(total)
+}
@@ -3612,23 +3612,17 @@ func TestResourceChange_nestedSet(t *testing.T) {
   ~ resource "test_instance" "example" {
       ~ ami   = "ami-BEFORE" -> "ami-AFTER"
       ~ disks = [
-          - {
-              - mount_point = "/var/diska" -> null
-            },
-          + {
-              + mount_point = "/var/diska"
+          ~ {
               + size        = "50GB"
+                # (1 unchanged attribute hidden)
             },
             # (1 unchanged element hidden)
         ]
         id    = "i-02ae66f368e8518a9"
 
-      - root_block_device {
-          - volume_type = "gp2" -> null
-        }
-      + root_block_device {
+      ~ root_block_device {
           + new_field   = "new_value"
-          + volume_type = "gp2"
+            # (1 unchanged attribute hidden)
         }
     }`,
 		},
@@ -3675,13 +3669,9 @@ func TestResourceChange_nestedSet(t *testing.T) {
 -/+ resource "test_instance" "example" {
       ~ ami   = "ami-BEFORE" -> "ami-AFTER"
       ~ disks = [
-          - { # forces replacement
-              - mount_point = "/var/diska" -> null
-              - size        = "50GB" -> null
-            },
-          + { # forces replacement
-              + mount_point = "/var/diskb"
-              + size        = "50GB"
+          ~ { # forces replacement
+              ~ mount_point = "/var/diska" -> "/var/diskb"
+                # (1 unchanged attribute hidden)
             },
         ]
         id    = "i-02ae66f368e8518a9"
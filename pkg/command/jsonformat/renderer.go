@@ -16,6 +16,7 @@ import (
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/computed"
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/differ"
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat/structured"
+	"github.com/we-dcode/opentofu/pkg/command/jsonformat/structured/attribute_path"
 	"github.com/we-dcode/opentofu/pkg/command/jsonplan"
 	"github.com/we-dcode/opentofu/pkg/command/jsonprovider"
 	"github.com/we-dcode/opentofu/pkg/command/jsonstate"
@@ -115,6 +116,27 @@ func (renderer Renderer) RenderHumanState(state State) {
 	state.renderHumanStateOutputs(renderer, opts)
 }
 
+// RenderHumanOutputChanges renders the changes between two sets of root
+// module output values, such as the outputs of two state snapshots, in the
+// same way as the output changes of a plan. Outputs without changes are
+// omitted.
+func (renderer Renderer) RenderHumanOutputChanges(outputs map[string]jsonplan.Change) {
+	diffs := make(map[string]computed.Diff, len(outputs))
+	for key, output := range outputs {
+		change := structured.FromJsonChange(output, attribute_path.AlwaysMatcher())
+		diffs[key] = differ.ComputeDiffForOutput(change)
+	}
+
+	rendered := renderHumanDiffOutputs(renderer, diffs)
+	if len(rendered) == 0 {
+		renderer.Streams.Println("No changes to outputs.")
+		return
+	}
+
+	renderer.Streams.Print("Changes to Outputs:\n")
+	renderer.Streams.Printf("%s\n", rendered)
+}
+
 func (renderer Renderer) RenderLog(log *JSONLog) error {
 	switch log.Type {
 	case LogRefreshComplete,
@@ -752,6 +752,15 @@ func omitUnknowns(val cty.Value) cty.Value {
 	}
 }
 
+// MarshalUnknowns returns the JSON serialization of which parts of the given
+// value are unknown, in the same format as the "after_unknown" property of
+// changes: true for a wholly unknown value, or a structure mirroring the value
+// with true in place of its unknown parts.
+func MarshalUnknowns(val cty.Value) (json.RawMessage, error) {
+	unknowns := unknownAsBool(val)
+	return ctyjson.Marshal(unknowns, unknowns.Type())
+}
+
 // recursively iterate through a cty.Value, replacing unknown values (including
 // null) with cty.True and known values with cty.False.
 //
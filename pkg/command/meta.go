@@ -505,10 +505,12 @@ func (m *Meta) RunOperation(ctx context.Context, b backend.Enhanced, opReq *back
 		return nil, diags
 	}
 
+	start := time.Now()
 	op, err := b.Operation(ctx, opReq)
 	if err != nil {
 		return nil, diags.Append(fmt.Errorf("error starting operation: %w", err))
 	}
+	defer recordOperationDuration(ctx, opReq.Type, start)
 
 	// Wait for the operation to complete or an interrupt to occur
 	select {
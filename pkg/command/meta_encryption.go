@@ -39,6 +39,35 @@ func (m *Meta) EncryptionFromPath(path string) (encryption.Encryption, tfdiags.D
 }
 
 func (m *Meta) EncryptionFromModule(module *configs.Module) (encryption.Encryption, tfdiags.Diagnostics) {
+	cfg, diags := m.encryptionConfigFromModule(module)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	enc, encDiags := encryption.New(encryption.DefaultRegistry, cfg, module.StaticEvaluator)
+	diags = diags.Append(encDiags)
+
+	return enc, diags
+}
+
+// encryptionConfig returns the encryption configuration of the root module in the current working directory,
+// combined with the configuration from the environment. Unlike Encryption, this does not set up any key providers.
+func (m *Meta) encryptionConfig() (*config.EncryptionConfig, tfdiags.Diagnostics) {
+	path, err := os.Getwd()
+	if err != nil {
+		return nil, tfdiags.Diagnostics{}.Append(fmt.Errorf("Error getting pwd: %w", err))
+	}
+
+	module, diags := m.loadSingleModule(path, configs.SelectiveLoadEncryption)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	cfg, cfgDiags := m.encryptionConfigFromModule(module)
+	diags = diags.Append(cfgDiags)
+	return cfg, diags
+}
+
+func (m *Meta) encryptionConfigFromModule(module *configs.Module) (*config.EncryptionConfig, tfdiags.Diagnostics) {
 	cfg := module.Encryption
 	var diags tfdiags.Diagnostics
 
@@ -52,8 +81,5 @@ func (m *Meta) EncryptionFromModule(module *configs.Module) (encryption.Encrypti
 		cfg = cfg.Merge(envCfg)
 	}
 
-	enc, encDiags := encryption.New(encryption.DefaultRegistry, cfg, module.StaticEvaluator)
-	diags = diags.Append(encDiags)
-
-	return enc, diags
+	return cfg, diags
 }
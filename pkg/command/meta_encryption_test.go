@@ -0,0 +1,113 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption"
+)
+
+const testMetaEncryptionKeyProviderConfig = `
+terraform {
+  encryption {
+    key_provider "pbkdf2" "basic" {
+      passphrase = "Hello world! 123"
+    }
+  }
+}
+`
+
+func TestMetaEncryption_envSyntax(t *testing.T) {
+	testCwd(t)
+	if err := os.WriteFile("main.tf", []byte(testMetaEncryptionKeyProviderConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	envConfigs := map[string]string{
+		"hcl": `
+			method "aes_gcm" "example" {
+				keys = key_provider.pbkdf2.basic
+			}
+			state {
+				method = method.aes_gcm.example
+			}
+		`,
+		"json": `
+			{
+				"method": {
+					"aes_gcm": {
+						"example": {
+							"keys": "${key_provider.pbkdf2.basic}"
+						}
+					}
+				},
+				"state": {
+					"method": "${method.aes_gcm.example}"
+				}
+			}
+		`,
+	}
+
+	encs := make(map[string]encryption.Encryption)
+	for name, env := range envConfigs {
+		t.Setenv(encryptionConfigEnvName, env)
+		m := &Meta{}
+
+		cfg, diags := m.encryptionConfig()
+		if diags.HasErrors() {
+			t.Fatalf("%s: unexpected errors: %s", name, diags.Err())
+		}
+		if len(cfg.KeyProviderConfigs) != 1 || cfg.KeyProviderConfigs[0].Type != "pbkdf2" || cfg.KeyProviderConfigs[0].Name != "basic" {
+			t.Fatalf("%s: expected the key provider from the module, got %v", name, cfg.KeyProviderConfigs)
+		}
+		if len(cfg.MethodConfigs) != 1 || cfg.MethodConfigs[0].Type != "aes_gcm" || cfg.MethodConfigs[0].Name != "example" {
+			t.Fatalf("%s: expected the method from the environment, got %v", name, cfg.MethodConfigs)
+		}
+
+		enc, diags := m.Encryption()
+		if diags.HasErrors() {
+			t.Fatalf("%s: unexpected errors: %s", name, diags.Err())
+		}
+		encs[name] = enc
+	}
+
+	// The state encrypted with one syntax must be readable with the other, as both configure the same method.
+	payload := []byte(`{"serial": 1, "lineage": "test"}`)
+	encrypted, err := encs["hcl"].State().EncryptState(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(encrypted, payload) {
+		t.Fatal("expected the state to be encrypted")
+	}
+	decrypted, _, err := encs["json"].State().DecryptState(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, payload) {
+		t.Fatalf("wrong decrypted state: %s", decrypted)
+	}
+}
+
+func TestMetaEncryption_blankEnv(t *testing.T) {
+	testCwd(t)
+	if err := os.WriteFile("main.tf", []byte(testEncryptionStatusConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(encryptionConfigEnvName, "  \n")
+
+	m := &Meta{}
+	cfg, diags := m.encryptionConfig()
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if len(cfg.MethodConfigs) != 1 {
+		t.Fatalf("expected the module configuration to be used unchanged, got %v", cfg.MethodConfigs)
+	}
+}
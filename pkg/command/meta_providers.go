@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	plugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
 
 	"github.com/we-dcode/opentofu/pkg/addrs"
 	terraformProvider "github.com/we-dcode/opentofu/pkg/builtin/providers/tf"
@@ -367,6 +368,7 @@ func providerFactory(meta *providercache.CachedProvider) providers.Factory {
 			VersionedPlugins: tfplugin.VersionedPlugins,
 			SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", meta.Provider)),
 			SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", meta.Provider)),
+			GRPCDialOptions:  []grpc.DialOption{providerRPCMetricsDialOption(meta.Provider)},
 		}
 
 		client := plugin.NewClient(config)
@@ -436,6 +438,7 @@ func unmanagedProviderFactory(provider addrs.Provider, reattach *plugin.Reattach
 			Reattach:         reattach,
 			SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", provider)),
 			SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", provider)),
+			GRPCDialOptions:  []grpc.DialOption{providerRPCMetricsDialOption(provider)},
 		}
 
 		if reattach.ProtocolVersion == 0 {
@@ -6,13 +6,24 @@
 package command
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/we-dcode/opentofu/pkg/command/arguments"
 	"github.com/we-dcode/opentofu/pkg/command/views"
 	"github.com/we-dcode/opentofu/pkg/encryption"
+	"github.com/we-dcode/opentofu/pkg/plans"
+	"github.com/we-dcode/opentofu/pkg/plans/planfile"
 	"github.com/we-dcode/opentofu/pkg/states"
+	"github.com/we-dcode/opentofu/pkg/states/statefile"
 	"github.com/we-dcode/opentofu/pkg/tfdiags"
 )
 
@@ -37,7 +48,40 @@ func (c *OutputCommand) Run(rawArgs []string) int {
 
 	c.View.SetShowSensitive(args.ShowSensitive)
 
-	view := views.NewOutput(args.ViewType, c.View)
+	// When writing to a file, the outputs are rendered into a temporary file
+	// next to the destination, which only replaces the destination once
+	// rendering has succeeded.
+	baseView := c.View
+	var outFile *os.File
+	if args.OutputFile != "" {
+		f, err := createOutputTempFile(args.OutputFile)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to create output file",
+				fmt.Sprintf("Could not create a temporary file for %s: %s.", args.OutputFile, err),
+			))
+			c.View.Diagnostics(diags)
+			return 1
+		}
+		outFile = f
+		defer func() {
+			// This is a no-op for the file that has been renamed into place.
+			outFile.Close()
+			os.Remove(outFile.Name())
+		}()
+		baseView = c.View.WithStdout(outFile)
+	}
+
+	var view views.Output
+	if args.JSONStream {
+		view = views.NewOutputJSONStream(baseView)
+	} else {
+		view = views.NewOutput(args.ViewType, baseView)
+	}
+	if human, ok := view.(*views.OutputHuman); ok {
+		human.Humanize = args.Humanize
+	}
 
 	// Inject variables from args into meta for static evaluation
 	c.GatherVariables(args.Vars)
@@ -51,16 +95,63 @@ func (c *OutputCommand) Run(rawArgs []string) int {
 	}
 
 	// Fetch data from state
-	outputs, diags := c.Outputs(args.StatePath, enc)
+	outputs, diags := c.Outputs(args.StatePaths, args.StatePrefixes, enc)
 	if diags.HasErrors() {
 		view.Diagnostics(diags)
 		return 1
 	}
 
+	// With -check, a missing output is always an error, regardless of the
+	// output format.
+	if args.Check {
+		diags = diags.Append(views.CheckOutputs(args.Name, outputs))
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
+	// With -path, only the nested value within the named output is shown.
+	if args.Path != nil {
+		var pathDiags tfdiags.Diagnostics
+		outputs, pathDiags = views.OutputAtPath(args.Name, args.Path, outputs)
+		diags = diags.Append(pathDiags)
+		if pathDiags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
+	// With -diff, the changes from the outputs of the given state snapshot
+	// are shown instead of the outputs themselves.
+	if args.DiffStatePath != "" {
+		before, moreDiags := snapshotOutputs(args.DiffStatePath, enc)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		view = views.NewOutputDiff(args.ViewType, baseView, before)
+	}
+
+	// Planned output values may only be known after apply. Most machine
+	// readable formats can't represent that, so such values are rendered as
+	// null. The JSON view marks them as unknown itself, except for the
+	// streaming variant, and diffs can represent them like the output changes
+	// of a plan.
+	jsonMarksUnknown := args.ViewType == arguments.ViewJSON && !args.JSONStream
+	if args.ViewType != arguments.ViewHuman && args.ViewType != arguments.ViewRaw && !jsonMarksUnknown && args.DiffStatePath == "" {
+		outputs = unknownOutputsAsNull(outputs)
+	}
+
 	// Render the view
 	viewDiags := view.Output(args.Name, outputs)
 	diags = diags.Append(viewDiags)
 
+	if outFile != nil && !diags.HasErrors() {
+		diags = diags.Append(commitOutputFile(outFile, args.OutputFile))
+	}
+
 	view.Diagnostics(diags)
 
 	if diags.HasErrors() {
@@ -70,11 +161,144 @@ func (c *OutputCommand) Run(rawArgs []string) int {
 	return 0
 }
 
-func (c *OutputCommand) Outputs(statePath string, enc encryption.Encryption) (map[string]*states.OutputValue, tfdiags.Diagnostics) {
+// createOutputTempFile creates a new temporary file next to the given output
+// file path. Unlike os.CreateTemp, which restricts the file to its owner, the
+// file gets the same permissions as any newly created file, subject to the
+// umask, since it will replace the output file.
+func createOutputTempFile(path string) (*os.File, error) {
+	dir, base := filepath.Split(path)
+	for attempt := 0; ; attempt++ {
+		name := filepath.Join(dir, "."+base+"."+strconv.FormatUint(uint64(rand.Uint32()), 10)+".tmp")
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+		if errors.Is(err, fs.ErrExist) && attempt < 10000 {
+			continue
+		}
+		return f, err
+	}
+}
+
+// commitOutputFile closes the given temporary file and moves it to the given
+// destination path, replacing any existing file. An existing file keeps its
+// permissions.
+func commitOutputFile(f *os.File, path string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	var err error
+	if info, statErr := os.Stat(path); statErr == nil {
+		err = f.Chmod(info.Mode().Perm())
+	}
+	if err == nil {
+		err = f.Close()
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write output file",
+			fmt.Sprintf("Could not write the outputs to %s: %s.", path, err),
+		))
+	}
+	return diags
+}
+
+// Outputs returns the root module output values from the given state files.
+// If no paths are given, the outputs are read from the configured backend
+// as usual. When more than one path is given, the outputs of all the state
+// files are merged and an error is returned for any output name that is
+// defined in more than one of them.
+//
+// If prefixes are given, there must be one per state path, and the names of
+// the outputs from each state file are prefixed with the corresponding
+// prefix, so that states sharing output names can be merged.
+func (c *OutputCommand) Outputs(statePaths []string, prefixes []string, enc encryption.Encryption) (map[string]*states.OutputValue, tfdiags.Diagnostics) {
+	if len(statePaths) <= 1 {
+		statePath, prefix := "", ""
+		if len(statePaths) == 1 {
+			statePath = statePaths[0]
+		}
+		if len(prefixes) == 1 {
+			prefix = prefixes[0]
+		}
+		outputs, diags := c.stateOutputs(statePath, enc)
+		return prefixOutputs(outputs, prefix), diags
+	}
+
+	var diags tfdiags.Diagnostics
+	merged := make(map[string]*states.OutputValue)
+	sources := make(map[string]string)
+	for i, statePath := range statePaths {
+		outputs, moreDiags := c.stateOutputs(statePath, enc)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return nil, diags
+		}
+		if len(prefixes) != 0 {
+			outputs = prefixOutputs(outputs, prefixes[i])
+		}
+
+		for name, output := range outputs {
+			if prevPath, exists := sources[name]; exists {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Duplicate output value",
+					fmt.Sprintf("The output value %q is defined in both %q and %q. Each output name may only appear in one of the given state files.", name, prevPath, statePath),
+				))
+				continue
+			}
+			merged[name] = output
+			sources[name] = statePath
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return merged, diags
+}
+
+// prefixOutputs returns the given outputs with the prefix prepended to their
+// names.
+func prefixOutputs(outputs map[string]*states.OutputValue, prefix string) map[string]*states.OutputValue {
+	if prefix == "" || outputs == nil {
+		return outputs
+	}
+	ret := make(map[string]*states.OutputValue, len(outputs))
+	for name, output := range outputs {
+		ret[prefix+name] = output
+	}
+	return ret
+}
+
+// stateOutputs returns the root module output values from a single state,
+// optionally overriding the state path used by the backend. The path may
+// also point to a plan file, in which case the planned output values are
+// returned instead.
+func (c *OutputCommand) stateOutputs(statePath string, enc encryption.Encryption) (map[string]*states.OutputValue, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	// Allow state path override
+	var planErr error
 	if statePath != "" {
+		var outputs map[string]*states.OutputValue
+		outputs, planErr = planOutputs(statePath, enc)
+		if planErr == nil {
+			return outputs, diags
+		}
+		// If the file is definitely a plan file, there's no point in also
+		// trying to read it as a state file.
+		var unLocal *planfile.ErrUnusableLocalPlan
+		var unMisc *errUnusableDataMisc
+		if errors.As(planErr, &unLocal) || errors.As(planErr, &unMisc) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read plan file",
+				fmt.Sprintf("The file %s is a plan file, but it could not be read: %s.", statePath, planErr),
+			))
+			return nil, diags
+		}
+
 		c.Meta.statePath = statePath
 	}
 
@@ -103,12 +327,106 @@ func (c *OutputCommand) Outputs(statePath string, enc encryption.Encryption) (ma
 
 	output, err := stateStore.GetRootOutputValues()
 	if err != nil {
+		if planErr != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read the given file as a state or plan file",
+				fmt.Sprintf("State read error: %s\n\nPlan read error: %s", err, planErr),
+			))
+			return nil, diags
+		}
 		return nil, diags.Append(err)
 	}
 
 	return output, diags
 }
 
+// snapshotOutputs returns the root module output values from the state
+// snapshot in the given local file, for comparing with the current outputs.
+func snapshotOutputs(path string, enc encryption.Encryption) (map[string]*states.OutputValue, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	f, err := os.Open(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read state snapshot",
+			fmt.Sprintf("Could not open %s: %s.", path, err),
+		))
+		return nil, diags
+	}
+	defer f.Close()
+
+	stateFile, err := statefile.Read(f, enc.State())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read state snapshot",
+			fmt.Sprintf("The file %s could not be read as a state file: %s.", path, err),
+		))
+		return nil, diags
+	}
+
+	return stateFile.State.RootModule().OutputValues, diags
+}
+
+// unknownOutputsAsNull returns the given outputs with all unknown values
+// replaced by null values of the same type.
+func unknownOutputsAsNull(outputs map[string]*states.OutputValue) map[string]*states.OutputValue {
+	ret := make(map[string]*states.OutputValue, len(outputs))
+	for name, output := range outputs {
+		if output.Value.IsWhollyKnown() {
+			ret[name] = output
+			continue
+		}
+		ret[name] = &states.OutputValue{
+			Addr:      output.Addr,
+			Value:     cty.UnknownAsNull(output.Value),
+			Sensitive: output.Sensitive,
+		}
+	}
+	return ret
+}
+
+// planOutputs returns the planned root module output values from the local
+// plan file at the given path. Values which are only known after apply are
+// returned as unknown values.
+func planOutputs(path string, enc encryption.Encryption) (map[string]*states.OutputValue, error) {
+	pf, err := planfile.OpenWrapped(path, enc.Plan())
+	if err != nil {
+		return nil, err
+	}
+	lp, ok := pf.Local()
+	if !ok {
+		return nil, errUnusable(fmt.Errorf("saved cloud plans are not supported"), "cloud plan")
+	}
+
+	plan, err := lp.ReadPlan()
+	if err != nil {
+		return nil, errUnusable(err, "local plan")
+	}
+
+	outputs := make(map[string]*states.OutputValue)
+	for _, ocs := range plan.Changes.Outputs {
+		if !ocs.Addr.Module.IsRoot() || ocs.Action == plans.Delete {
+			continue
+		}
+		oc, err := ocs.Decode()
+		if err != nil {
+			return nil, errUnusable(fmt.Errorf("failed to decode planned value for output %s: %w", ocs.Addr.OutputValue.Name, err), "local plan")
+		}
+		// Sensitive values are marked in the plan, but outputs only track
+		// sensitivity at the top level.
+		value, marks := oc.After.UnmarkDeep()
+		outputs[ocs.Addr.OutputValue.Name] = &states.OutputValue{
+			Addr:      ocs.Addr,
+			Value:     value,
+			Sensitive: oc.Sensitive || len(marks) > 0,
+		}
+	}
+	return outputs, nil
+}
+
 func (c *OutputCommand) GatherVariables(args *arguments.Vars) {
 	// FIXME the arguments package currently trivially gathers variable related
 	// arguments in a heterogeneous slice, in order to minimize the number of
@@ -139,20 +457,69 @@ Options:
 
   -state=path        Path to the state file to read. Defaults to
                      "terraform.tfstate". Ignored when remote 
-                     state is used.
+                     state is used. Use this option more than once
+                     to merge the outputs of several state files.
+                     The path may also be a saved plan file, in which
+                     case the planned output values are shown.
+
+  -state-prefix=pfx  Prefix for the names of the outputs read from the
+                     state file given by the -state option at the same
+                     position, for example -state-prefix=network_. If
+                     used, it must be given once for each -state option.
+                     This allows merging state files which share output
+                     names.
 
   -no-color          If specified, output won't contain any color.
 
   -json              If specified, machine readable output will be
                      printed in JSON format.
 
+  -json-stream       If specified, output values will be printed as a
+                     stream of JSON objects, one per line, each with
+                     the name, value, and sensitivity of an output.
+
   -raw               For value types that can be automatically
                      converted to a string, will print the raw
                      string directly, rather than a human-oriented
-                     representation of the value.
+                     representation of the value. Sensitive values
+                     are only printed if -show-sensitive is also given.
+
+  -format=env        If specified, output will be printed as NAME=value
+                     lines which can be sourced by a shell. Sensitive
+                     values are omitted unless -show-sensitive is also
+                     given.
+
+  -yaml              If specified, output will be printed in YAML
+                     format. Sensitive values are redacted unless
+                     -show-sensitive is also given.
+
+  -output-file=path  If specified, the outputs are written to the given
+                     file instead of stdout. The file is only replaced
+                     once all outputs have been rendered successfully.
 
   -show-sensitive    If specified, sensitive values will be displayed.
 
+  -check             If specified, the command exits with status 1 if the
+                     named output doesn't exist or, if no name is given,
+                     if there are no outputs at all.
+
+  -path=PATH         If specified, only the value at the given path within
+                     the named output is shown, for example
+                     -path='subnets[0].cidr'.
+
+  -humanize          If specified, timestamps are shown relative to now,
+                     such as "3 days ago", and byte counts in IEC units,
+                     such as "1.5 GiB". Byte counts are recognized by
+                     names ending in "bytes" or "size". This only affects
+                     the default human-readable format.
+
+  -diff=path         If specified, the changes from the outputs of the
+                     state snapshot at the given path to the current
+                     outputs are shown instead of the outputs. Only the
+                     default format and -json are supported. Sensitive
+                     values are redacted unless -show-sensitive is also
+                     given.
+
   -var 'foo=bar'     Set a value for one of the input variables in the root
                      module of the configuration. Use this option more than
                      once to set more than one variable.
@@ -6,14 +6,18 @@
 package command
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/configs/configload"
+	"github.com/we-dcode/opentofu/pkg/plans"
 	"github.com/we-dcode/opentofu/pkg/states"
 )
 
@@ -52,6 +56,357 @@ func TestOutput(t *testing.T) {
 	}
 }
 
+func TestOutput_multipleStates(t *testing.T) {
+	fooState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+	bazState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "baz"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("qux"),
+			false,
+		)
+	})
+
+	fooStatePath := testStateFile(t, fooState)
+	bazStatePath := testStateFile(t, bazState)
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", fooStatePath,
+		"-state", bazStatePath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	actual := strings.TrimSpace(output.Stdout())
+	expected := "baz = \"qux\"\nfoo = \"bar\""
+	if actual != expected {
+		t.Fatalf("wrong output\ngot:  %#v\nwant: %#v", actual, expected)
+	}
+}
+
+func TestOutput_multipleStatesDuplicate(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+
+	firstStatePath := testStateFile(t, originalState)
+	secondStatePath := testStateFile(t, originalState)
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", firstStatePath,
+		"-state", secondStatePath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\n%s", code, output.Stdout())
+	}
+
+	if got, want := output.Stderr(), `The output value "foo" is defined in both`; !strings.Contains(got, want) {
+		t.Fatalf("expected error containing %q, got:\n%s", want, got)
+	}
+}
+
+func TestOutput_multipleStatesPrefixed(t *testing.T) {
+	networkState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "id"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("vpc-123"),
+			false,
+		)
+	})
+	databaseState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "id"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("db-456"),
+			false,
+		)
+	})
+
+	networkStatePath := testStateFile(t, networkState)
+	databaseStatePath := testStateFile(t, databaseState)
+
+	testCases := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"human": {
+			nil,
+			"database_id = \"db-456\"\nnetwork_id = \"vpc-123\"",
+		},
+		"json": {
+			[]string{"-json"},
+			"{\n  \"database_id\": {\n    \"sensitive\": false,\n    \"type\": \"string\",\n    \"value\": \"db-456\"\n  },\n  \"network_id\": {\n    \"sensitive\": false,\n    \"type\": \"string\",\n    \"value\": \"vpc-123\"\n  }\n}",
+		},
+		"yaml": {
+			[]string{"-yaml"},
+			"\"database_id\":\n  \"sensitive\": false\n  \"value\": \"db-456\"\n\"network_id\":\n  \"sensitive\": false\n  \"value\": \"vpc-123\"",
+		},
+		"single output": {
+			[]string{"-raw", "network_id"},
+			"vpc-123",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &OutputCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					View:             view,
+				},
+			}
+
+			args := append([]string{
+				"-state", networkStatePath,
+				"-state-prefix", "network_",
+				"-state", databaseStatePath,
+				"-state-prefix", "database_",
+			}, tc.args...)
+			code := c.Run(args)
+			output := done(t)
+			if code != 0 {
+				t.Fatalf("bad: \n%s", output.Stderr())
+			}
+
+			actual := strings.TrimSpace(output.Stdout())
+			if actual != tc.expected {
+				t.Fatalf("wrong output\ngot:  %#v\nwant: %#v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOutput_multipleStatesPrefixMismatch(t *testing.T) {
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", "network.tfstate",
+		"-state-prefix", "network_",
+		"-state", "database.tfstate",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\n%s", code, output.Stdout())
+	}
+
+	if got, want := output.Stderr(), "Mismatched -state-prefix options"; !strings.Contains(got, want) {
+		t.Fatalf("expected error containing %q, got:\n%s", want, got)
+	}
+}
+
+func TestOutput_outputFile(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+
+	statePath := testStateFile(t, originalState)
+	outPath := filepath.Join(t.TempDir(), "outputs.json")
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-output-file", outPath,
+		"-json",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+
+	if stdout := output.Stdout(); stdout != "" {
+		t.Fatalf("unexpected stdout: %s", stdout)
+	}
+
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\n  \"foo\": {\n    \"sensitive\": false,\n    \"type\": \"string\",\n    \"value\": \"bar\"\n  }\n}"
+	if actual := strings.TrimSpace(string(got)); actual != expected {
+		t.Fatalf("wrong output\ngot:  %#v\nwant: %#v", actual, expected)
+	}
+}
+
+func TestOutput_outputFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on Windows")
+	}
+
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+	statePath := testStateFile(t, originalState)
+	outDir := t.TempDir()
+
+	// A new output file gets the permissions of any other new file.
+	reference := filepath.Join(outDir, "reference")
+	f, err := os.Create(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	referenceInfo, err := os.Stat(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existingPath := filepath.Join(outDir, "existing.txt")
+	if err := os.WriteFile(existingPath, []byte("original"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(existingPath, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		path string
+		want fs.FileMode
+	}{
+		"new": {
+			path: filepath.Join(outDir, "new.txt"),
+			want: referenceInfo.Mode().Perm(),
+		},
+		"existing": {
+			path: existingPath,
+			want: 0o640,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &OutputCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					View:             view,
+				},
+			}
+
+			code := c.Run([]string{
+				"-state", statePath,
+				"-output-file", tc.path,
+			})
+			output := done(t)
+			if code != 0 {
+				t.Fatalf("bad: \n%s", output.Stderr())
+			}
+
+			info, err := os.Stat(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := info.Mode().Perm(); got != tc.want {
+				t.Fatalf("wrong file mode %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOutput_outputFileError(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+
+	statePath := testStateFile(t, originalState)
+	outDir := t.TempDir()
+	outPath := filepath.Join(outDir, "outputs.txt")
+	if err := os.WriteFile(outPath, []byte("original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-output-file", outPath,
+		"-raw",
+		"missing",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\n%s", code, output.Stdout())
+	}
+
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "original" {
+		t.Fatalf("the output file was modified: %s", got)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the original file in %s, got %d entries", outDir, len(entries))
+	}
+}
+
 func TestOutput_json(t *testing.T) {
 	originalState := states.BuildState(func(s *states.SyncState) {
 		s.SetOutputValue(
@@ -88,6 +443,146 @@ func TestOutput_json(t *testing.T) {
 	}
 }
 
+func TestOutput_humanize(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "created_at"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("2000-01-01T00:00:00Z"),
+			false,
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "disk_size"}.Absolute(addrs.RootModuleInstance),
+			cty.NumberIntVal(1610612736),
+			false,
+		)
+	})
+
+	statePath := testStateFile(t, originalState)
+
+	testCases := map[string]struct {
+		args []string
+		want []string
+	}{
+		"human": {
+			args: []string{"-humanize"},
+			want: []string{`created_at = "`, ` years ago"`, `disk_size = "1.5 GiB"`},
+		},
+		"json": {
+			args: []string{"-humanize", "-json"},
+			want: []string{`"value": "2000-01-01T00:00:00Z"`, `"value": 1610612736`},
+		},
+		"raw": {
+			args: []string{"-humanize", "-raw", "disk_size"},
+			want: []string{"1610612736"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &OutputCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					View:             view,
+				},
+			}
+
+			args := append([]string{"-state", statePath}, tc.args...)
+			code := c.Run(args)
+			output := done(t)
+			if code != 0 {
+				t.Fatalf("bad: \n%s", output.Stderr())
+			}
+
+			actual := output.Stdout()
+			for _, want := range tc.want {
+				if !strings.Contains(actual, want) {
+					t.Errorf("wrong output, expected %q in:\n%s", want, actual)
+				}
+			}
+			if name != "human" && strings.Contains(actual, "GiB") {
+				t.Errorf("-humanize must not affect %s output, got:\n%s", name, actual)
+			}
+		})
+	}
+}
+
+func TestOutput_rawPresence(t *testing.T) {
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "empty"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal(""),
+			false,
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "secret"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			true,
+		)
+	})
+
+	testCases := map[string]struct {
+		state      *states.State
+		name       string
+		wantCode   int
+		wantStderr string
+	}{
+		"empty string": {
+			state:    state,
+			name:     "empty",
+			wantCode: 0,
+		},
+		"missing": {
+			state:      state,
+			name:       "missing",
+			wantCode:   1,
+			wantStderr: `Output "missing" not found`,
+		},
+		"missing without outputs": {
+			state:      states.NewState(),
+			name:       "missing",
+			wantCode:   1,
+			wantStderr: `Output "missing" not found`,
+		},
+		"sensitive": {
+			state:      state,
+			name:       "secret",
+			wantCode:   1,
+			wantStderr: "Sensitive value for raw output",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			statePath := testStateFile(t, tc.state)
+
+			view, done := testView(t)
+			c := &OutputCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					View:             view,
+				},
+			}
+
+			code := c.Run([]string{"-state", statePath, "-raw", tc.name})
+			output := done(t)
+			if code != tc.wantCode {
+				t.Fatalf("wrong exit code %d, want %d\n%s", code, tc.wantCode, output.Stderr())
+			}
+			if got := output.Stdout(); got != "" {
+				t.Errorf("expected empty stdout, got: %q", got)
+			}
+			if tc.wantStderr == "" {
+				if got := output.Stderr(); got != "" {
+					t.Errorf("expected empty stderr, got: %s", got)
+				}
+			} else if got := output.Stderr(); !strings.Contains(got, tc.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got: %s", tc.wantStderr, got)
+			}
+		})
+	}
+}
+
 func TestOutput_emptyOutputs(t *testing.T) {
 	originalState := states.NewState()
 	statePath := testStateFile(t, originalState)
@@ -116,6 +611,256 @@ func TestOutput_emptyOutputs(t *testing.T) {
 	}
 }
 
+func TestOutput_check(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+	statePath := testStateFile(t, originalState)
+	emptyStatePath := testStateFile(t, states.NewState())
+
+	testCases := map[string]struct {
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		"present": {
+			args:     []string{"-check", "-state", statePath, "foo"},
+			wantCode: 0,
+		},
+		"missing": {
+			args:     []string{"-check", "-state", statePath, "missing"},
+			wantCode: 1,
+			wantErr:  `Output "missing" not found`,
+		},
+		"missing from empty state": {
+			args:     []string{"-check", "-state", emptyStatePath, "foo"},
+			wantCode: 1,
+			wantErr:  `Output "foo" not found`,
+		},
+		"no outputs": {
+			args:     []string{"-check", "-json", "-state", emptyStatePath},
+			wantCode: 1,
+			wantErr:  "No outputs found",
+		},
+		"any outputs": {
+			args:     []string{"-check", "-json", "-state", statePath},
+			wantCode: 0,
+		},
+		"missing from empty state without check": {
+			args:     []string{"-state", emptyStatePath, "foo"},
+			wantCode: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &OutputCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					View:             view,
+				},
+			}
+
+			code := c.Run(append([]string{"-no-color"}, tc.args...))
+			output := done(t)
+			if code != tc.wantCode {
+				t.Fatalf("wrong exit code %d; want %d\n%s", code, tc.wantCode, output.Stderr())
+			}
+			if tc.wantErr != "" && !strings.Contains(output.Stderr(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got:\n%s", tc.wantErr, output.Stderr())
+			}
+		})
+	}
+}
+
+func TestOutput_path(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "network"}.Absolute(addrs.RootModuleInstance),
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("main"),
+				"subnets": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"cidr": cty.StringVal("10.0.0.0/24"),
+					}),
+				}),
+				"tags": cty.MapVal(map[string]cty.Value{
+					"env": cty.StringVal("prod"),
+				}),
+			}),
+			false,
+		)
+	})
+	statePath := testStateFile(t, originalState)
+
+	testCases := map[string]struct {
+		args     []string
+		wantCode int
+		want     string
+		wantErr  string
+	}{
+		"human": {
+			args: []string{"-path=subnets[0].cidr", "network"},
+			want: "\"10.0.0.0/24\"\n",
+		},
+		"raw": {
+			args: []string{"-raw", "-path=subnets[0].cidr", "network"},
+			want: "10.0.0.0/24",
+		},
+		"json": {
+			args: []string{"-json", "-path=subnets[0]", "network"},
+			want: "{\"cidr\":\"10.0.0.0/24\"}\n",
+		},
+		"map key": {
+			args: []string{"-raw", `-path=tags["env"]`, "network"},
+			want: "prod",
+		},
+		"missing attribute": {
+			args:     []string{"-path=subnets[0].id", "network"},
+			wantCode: 1,
+			wantErr:  `Can't find .subnets[0].id in output value "network": the object has no attribute "id".`,
+		},
+		"missing element": {
+			args:     []string{"-path=subnets[1]", "network"},
+			wantCode: 1,
+			wantErr:  `Can't find .subnets[1] in output value "network": there is no element 1.`,
+		},
+		"index non-collection": {
+			args:     []string{"-path=name[0]", "network"},
+			wantCode: 1,
+			wantErr:  `Can't find .name[0] in output value "network": the value is string, which can't be indexed.`,
+		},
+		"invalid path": {
+			args:     []string{"-path=subnets[", "network"},
+			wantCode: 1,
+			wantErr:  `Invalid -path "subnets["`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &OutputCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					View:             view,
+				},
+			}
+
+			code := c.Run(append([]string{"-no-color", "-state", statePath}, tc.args...))
+			output := done(t)
+			if code != tc.wantCode {
+				t.Fatalf("wrong exit code %d; want %d\n%s", code, tc.wantCode, output.Stderr())
+			}
+			// Diagnostics are wrapped, so whitespace is normalized before
+			// looking for the expected error.
+			stderr := strings.Join(strings.Fields(output.Stderr()), " ")
+			if tc.wantErr != "" && !strings.Contains(stderr, tc.wantErr) {
+				t.Fatalf("expected error containing %q, got:\n%s", tc.wantErr, output.Stderr())
+			}
+			if tc.wantErr == "" && output.Stdout() != tc.want {
+				t.Fatalf("wrong output\ngot:  %q\nwant: %q", output.Stdout(), tc.want)
+			}
+		})
+	}
+}
+
+func TestOutput_planFile(t *testing.T) {
+	plan := testPlan(t)
+	for name, change := range map[string]plans.Change{
+		"foo":    {Action: plans.Create, Before: cty.NullVal(cty.DynamicPseudoType), After: cty.StringVal("bar")},
+		"id":     {Action: plans.Create, Before: cty.NullVal(cty.DynamicPseudoType), After: cty.UnknownVal(cty.String)},
+		"secret": {Action: plans.Create, Before: cty.NullVal(cty.DynamicPseudoType), After: cty.StringVal("hunter2")},
+		"old":    {Action: plans.Delete, Before: cty.StringVal("gone"), After: cty.NullVal(cty.DynamicPseudoType)},
+	} {
+		oc := &plans.OutputChange{
+			Addr:      addrs.OutputValue{Name: name}.Absolute(addrs.RootModuleInstance),
+			Change:    change,
+			Sensitive: name == "secret",
+		}
+		ocs, err := oc.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan.Changes.Outputs = append(plan.Changes.Outputs, ocs)
+	}
+	planPath := testPlanFile(t, configload.NewEmptySnapshot(), states.NewState(), plan)
+
+	invalidPath := filepath.Join(t.TempDir(), "invalid")
+	if err := os.WriteFile(invalidPath, []byte("not a state or plan"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		args     []string
+		wantCode int
+		want     string
+		wantErr  string
+	}{
+		"human": {
+			args: []string{"-state", planPath},
+			want: "foo = \"bar\"\nid = (known after apply)\nsecret = <sensitive>\n",
+		},
+		"human show-sensitive": {
+			args: []string{"-show-sensitive", "-state", planPath, "secret"},
+			want: "\"hunter2\"\n",
+		},
+		"json": {
+			args: []string{"-json", "-state", planPath, "id"},
+			want: "null\n",
+		},
+		"json stream": {
+			args: []string{"-json-stream", "-state", planPath, "id"},
+			want: "{\"name\":\"id\",\"value\":null,\"sensitive\":false}\n",
+		},
+		"raw unknown": {
+			args:     []string{"-raw", "-state", planPath, "id"},
+			wantCode: 1,
+			wantErr:  "won't be known until after a successful tofu apply",
+		},
+		"deleted": {
+			args:     []string{"-check", "-state", planPath, "old"},
+			wantCode: 1,
+			wantErr:  `Output "old" not found`,
+		},
+		"invalid file": {
+			args:     []string{"-state", invalidPath},
+			wantCode: 1,
+			wantErr:  "Failed to read the given file as a state or plan file",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &OutputCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					View:             view,
+				},
+			}
+
+			code := c.Run(append([]string{"-no-color"}, tc.args...))
+			output := done(t)
+			if code != tc.wantCode {
+				t.Fatalf("wrong exit code %d; want %d\n%s", code, tc.wantCode, output.Stderr())
+			}
+			stderr := strings.Join(strings.Fields(output.Stderr()), " ")
+			if tc.wantErr != "" && !strings.Contains(stderr, tc.wantErr) {
+				t.Fatalf("expected error containing %q, got:\n%s", tc.wantErr, output.Stderr())
+			}
+			if tc.wantErr == "" && output.Stdout() != tc.want {
+				t.Fatalf("wrong output\ngot:  %q\nwant: %q", output.Stdout(), tc.want)
+			}
+		})
+	}
+}
+
 func TestOutput_badVar(t *testing.T) {
 	originalState := states.BuildState(func(s *states.SyncState) {
 		s.SetOutputValue(
@@ -395,3 +1140,183 @@ func stateWithSensitiveValueForOutput() *states.State {
 	})
 	return state
 }
+
+func TestOutput_diff(t *testing.T) {
+	oldState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "changed"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("before"),
+			false,
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "removed"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("gone"),
+			false,
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "secret"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("old-password"),
+			true,
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "unchanged"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("same"),
+			false,
+		)
+	})
+	newState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "added"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("new"),
+			false,
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "changed"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("after"),
+			false,
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "secret"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("new-password"),
+			true,
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "unchanged"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("same"),
+			false,
+		)
+	})
+
+	oldPath := testStateFile(t, oldState)
+	newPath := testStateFile(t, newState)
+
+	testCases := map[string]struct {
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		"human": {
+			args: nil,
+			want: []string{
+				"Changes to Outputs:",
+				`+ added     = "new"`,
+				`~ changed   = "before" -> "after"`,
+				`- removed   = "gone" -> null`,
+				`~ secret    = (sensitive value)`,
+			},
+			notWant: []string{"unchanged", "password"},
+		},
+		"human show-sensitive": {
+			args: []string{"-show-sensitive"},
+			want: []string{`~ secret    = "old-password" -> "new-password"`},
+		},
+		"human name": {
+			args:    []string{"changed"},
+			want:    []string{`~ changed = "before" -> "after"`},
+			notWant: []string{"added", "removed", "secret"},
+		},
+		"json": {
+			args: []string{"-json"},
+			want: []string{
+				`"output_changes": {`,
+				`"create"`,
+				`"delete"`,
+				`"before": "before"`,
+				`"after": "after"`,
+				`"before_sensitive": true`,
+			},
+			notWant: []string{"unchanged", "password"},
+		},
+		"json show-sensitive": {
+			args: []string{"-json", "-show-sensitive"},
+			want: []string{`"before": "old-password"`, `"after": "new-password"`},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			view, done := testView(t)
+			c := &OutputCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(testProvider()),
+					View:             view,
+				},
+			}
+
+			args := append([]string{"-state", newPath, "-diff", oldPath}, tc.args...)
+			code := c.Run(args)
+			output := done(t)
+			if code != 0 {
+				t.Fatalf("bad: \n%s", output.Stderr())
+			}
+
+			actual := output.Stdout()
+			for _, want := range tc.want {
+				if !strings.Contains(actual, want) {
+					t.Errorf("wrong output, expected %q in:\n%s", want, actual)
+				}
+			}
+			for _, notWant := range tc.notWant {
+				if strings.Contains(actual, notWant) {
+					t.Errorf("wrong output, unexpected %q in:\n%s", notWant, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestOutput_diffNoChanges(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+	statePath := testStateFile(t, originalState)
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-state", statePath, "-diff", statePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.Stderr())
+	}
+	if got, want := output.Stdout(), "No changes to outputs.\n"; got != want {
+		t.Errorf("wrong output\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestOutput_diffMissingSnapshot(t *testing.T) {
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "foo"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("bar"),
+			false,
+		)
+	})
+	statePath := testStateFile(t, originalState)
+
+	view, done := testView(t)
+	c := &OutputCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-state", statePath, "-diff", filepath.Join(t.TempDir(), "missing.tfstate")})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\n%s", code, output.Stdout())
+	}
+	if !strings.Contains(output.Stderr(), "Failed to read state snapshot") {
+		t.Errorf("wrong error:\n%s", output.Stderr())
+	}
+}
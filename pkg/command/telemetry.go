@@ -6,12 +6,71 @@
 package command
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/backend"
 )
 
 var tracer trace.Tracer
 
+// These metric instruments are experimental, like the rest of the telemetry
+// OpenTofu exports. They discard all measurements unless the metrics exporter
+// is enabled in package main.
+var (
+	providerRPCCounter       metric.Int64Counter
+	operationDurationSeconds metric.Float64Histogram
+)
+
 func init() {
 	tracer = otel.Tracer("github.com/we-dcode/opentofu/pkg/command")
+
+	meter := otel.Meter("github.com/we-dcode/opentofu/pkg/command")
+	var err error
+	providerRPCCounter, err = meter.Int64Counter(
+		"opentofu.provider.rpc.calls",
+		metric.WithDescription("The number of RPC calls made to provider plugins."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	operationDurationSeconds, err = meter.Float64Histogram(
+		"opentofu.operation.duration",
+		metric.WithDescription("The duration of refresh, plan, and apply operations."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// providerRPCMetricsDialOption returns a gRPC dial option counting the unary
+// calls made to the given provider.
+func providerRPCMetricsDialOption(provider addrs.Provider) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		providerRPCCounter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("provider", provider.String()),
+			attribute.String("rpc.method", method),
+			attribute.Bool("error", err != nil),
+		))
+		return err
+	})
+}
+
+// recordOperationDuration records the time elapsed since start for an
+// operation of the given type.
+func recordOperationDuration(ctx context.Context, opType backend.OperationType, start time.Time) {
+	operationDurationSeconds.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("operation", strings.ToLower(strings.TrimPrefix(opType.String(), "OperationType"))),
+	))
 }
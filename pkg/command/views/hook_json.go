@@ -25,6 +25,12 @@ import (
 // How long to wait between sending heartbeat/progress messages
 const heartbeatInterval = 10 * time.Second
 
+// NewJSONHook returns a hook which renders the progress of an operation as
+// JSON messages, for commands which do not have a view of their own.
+func NewJSONHook(view *JSONView) tofu.Hook {
+	return newJSONHook(view)
+}
+
 func newJSONHook(view *JSONView) *jsonHook {
 	return &jsonHook{
 		view:      view,
@@ -163,6 +169,14 @@ func (h *jsonHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName stri
 	}
 }
 
+func (h *jsonHook) ProvisionProgress(addr addrs.AbsResourceInstance, typeName string, phase string, percent float64) {
+	h.view.Hook(json.NewProvisionStatus(addr, typeName, phase, percent))
+}
+
+func (h *jsonHook) ImportProgress(result tofu.ImportTargetResult, completed, total int) {
+	h.view.Hook(json.NewImportProgress(result.Addr, result.Success, completed, total))
+}
+
 func (h *jsonHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (tofu.HookAction, error) {
 	idKey, idValue := format.ObjectValueID(priorState)
 	h.view.Hook(json.NewRefreshStart(addr, idKey, idValue))
@@ -60,6 +60,8 @@ func TestJSONHook_create(t *testing.T) {
 	testHookReturnValues(t, action, err)
 
 	hook.ProvisionOutput(addr, "local-exec", `Executing: ["/bin/sh" "-c" "touch /etc/motd"]`)
+	hook.ProvisionProgress(addr, "local-exec", "downloading", 42.5)
+	hook.ProvisionProgress(addr, "local-exec", "installing", -1)
 
 	action, err = hook.PostProvisionInstanceStep(addr, "local-exec", nil)
 	testHookReturnValues(t, action, err)
@@ -143,6 +145,29 @@ func TestJSONHook_create(t *testing.T) {
 				"resource":    wantResource,
 			},
 		},
+		{
+			"@level":   "info",
+			"@message": "test_instance.boop: (local-exec) downloading (42.5%)",
+			"@module":  "tofu.ui",
+			"type":     "provision_status",
+			"hook": map[string]interface{}{
+				"phase":       "downloading",
+				"percent":     float64(42.5),
+				"provisioner": "local-exec",
+				"resource":    wantResource,
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "test_instance.boop: (local-exec) installing",
+			"@module":  "tofu.ui",
+			"type":     "provision_status",
+			"hook": map[string]interface{}{
+				"phase":       "installing",
+				"provisioner": "local-exec",
+				"resource":    wantResource,
+			},
+		},
 		{
 			"@level":   "info",
 			"@message": "test_instance.boop: (local-exec) Provisioning complete",
@@ -280,6 +305,44 @@ func TestJSONHook_errors(t *testing.T) {
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
 
+func TestJSONHook_importProgress(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	hook := newJSONHook(NewJSONView(NewView(streams)))
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "boop",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	hook.ImportProgress(tofu.ImportTargetResult{Addr: addr, Success: true}, 1, 3)
+
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "test_instance.boop: Import complete (1/3)",
+			"@module":  "tofu.ui",
+			"type":     "import_progress",
+			"hook": map[string]interface{}{
+				"resource": map[string]interface{}{
+					"addr":             string("test_instance.boop"),
+					"implied_provider": string("test"),
+					"module":           string(""),
+					"resource":         string("test_instance.boop"),
+					"resource_key":     nil,
+					"resource_name":    string("boop"),
+					"resource_type":    string("test_instance"),
+				},
+				"success":   true,
+				"completed": float64(1),
+				"total":     float64(3),
+			},
+		},
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
+
 func TestJSONHook_refresh(t *testing.T) {
 	streams, done := terminal.StreamsForTesting(t)
 	hook := newJSONHook(NewJSONView(NewView(streams)))
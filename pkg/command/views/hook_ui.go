@@ -307,6 +307,35 @@ func (h *UiHook) PostImportState(addr addrs.AbsResourceInstance, imported []prov
 	return tofu.HookActionContinue, nil
 }
 
+// importSpinnerFrames are the frames of the spinner shown next to the import
+// progress, one per finished target.
+var importSpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// ImportProgress shows how many of the import targets have been imported so
+// far. Importing a single target already reports its start and end, so the
+// progress is only shown for multiple targets. Without color, the spinner is
+// left out so that each update is a plain line of text.
+func (h *UiHook) ImportProgress(result tofu.ImportTargetResult, completed, total int) {
+	if total <= 1 {
+		return
+	}
+
+	status := "imported"
+	if !result.Success {
+		status = "failed"
+	}
+
+	if h.view.colorize.Disable {
+		h.println(fmt.Sprintf("Import progress: %d/%d (%s %s)", completed, total, result.Addr, status))
+		return
+	}
+	frame := importSpinnerFrames[(completed-1)%len(importSpinnerFrames)]
+	h.println(fmt.Sprintf(
+		h.view.colorize.Color("[reset][bold]%s Import progress: %d/%d[reset] (%s %s)"),
+		frame, completed, total, result.Addr, status,
+	))
+}
+
 func (h *UiHook) PrePlanImport(addr addrs.AbsResourceInstance, importID string) (tofu.HookAction, error) {
 	h.println(fmt.Sprintf(
 		h.view.colorize.Color("[reset][bold]%s: Preparing import... [id=%s]"),
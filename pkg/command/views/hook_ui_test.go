@@ -583,6 +583,61 @@ func TestPostImportState(t *testing.T) {
 	}
 }
 
+func TestImportProgress(t *testing.T) {
+	fooAddr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "foo",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	barAddr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "bar",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	report := func(h *UiHook) {
+		h.ImportProgress(tofu.ImportTargetResult{Addr: fooAddr, Success: true}, 1, 2)
+		h.ImportProgress(tofu.ImportTargetResult{Addr: barAddr}, 2, 2)
+	}
+
+	t.Run("no color", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		view := NewView(streams)
+		view.Configure(&arguments.View{NoColor: true})
+		report(NewUiHook(view))
+
+		want := `Import progress: 1/2 (test_instance.foo imported)
+Import progress: 2/2 (test_instance.bar failed)
+`
+		if got := done(t).Stdout(); got != want {
+			t.Fatalf("unexpected output\n got: %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("color", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		view := NewView(streams)
+		view.Configure(&arguments.View{NoColor: false})
+		report(NewUiHook(view))
+
+		want := "\x1b[0m\x1b[1m⠋ Import progress: 1/2\x1b[0m (test_instance.foo imported)\x1b[0m\n" +
+			"\x1b[0m\x1b[1m⠙ Import progress: 2/2\x1b[0m (test_instance.bar failed)\x1b[0m\n"
+		if got := done(t).Stdout(); got != want {
+			t.Fatalf("unexpected output\n got: %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("single target", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		h := NewUiHook(NewView(streams))
+		h.ImportProgress(tofu.ImportTargetResult{Addr: fooAddr, Success: true}, 1, 1)
+
+		if got := done(t).Stdout(); got != "" {
+			t.Fatalf("unexpected output for a single target: %q", got)
+		}
+	})
+}
+
 func TestTruncateId(t *testing.T) {
 	testCases := []struct {
 		Input    string
@@ -8,6 +8,7 @@ package json
 import (
 	"fmt"
 
+	"github.com/we-dcode/opentofu/pkg/command/jsonformat/computed"
 	"github.com/we-dcode/opentofu/pkg/plans"
 )
 
@@ -55,6 +56,22 @@ type ResourceInstanceChange struct {
 	Reason           ChangeReason  `json:"reason,omitempty"`
 	Importing        *Importing    `json:"importing,omitempty"`
 	GeneratedConfig  string        `json:"generated_config,omitempty"`
+
+	// SensitivePaths lists the values within the resource that are sensitive
+	// before and/or after the change. It is only known for the changes of a
+	// plan, and is empty if there are no sensitive values.
+	SensitivePaths []computed.SensitivePath `json:"sensitive_paths,omitempty"`
+
+	// UnchangedElements lists the collections within the resource that have
+	// unchanged elements, with the number of them. The human readable plan
+	// hides these elements. It is only known for the changes of a plan.
+	UnchangedElements []computed.UnchangedElements `json:"unchanged_elements,omitempty"`
+}
+
+// SetDiff records the details of the given computed diff of the change.
+func (c *ResourceInstanceChange) SetDiff(diff computed.Diff) {
+	c.SensitivePaths = diff.SensitivePaths()
+	c.UnchangedElements = diff.UnchangedElements()
 }
 
 func (c *ResourceInstanceChange) String() string {
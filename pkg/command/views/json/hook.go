@@ -198,6 +198,42 @@ func NewProvisionProgress(addr addrs.AbsResourceInstance, provisioner string, ou
 	}
 }
 
+// ProvisionStatus: triggered by ProvisionProgress hook
+type provisionStatus struct {
+	Resource    ResourceAddr `json:"resource"`
+	Provisioner string       `json:"provisioner"`
+	Phase       string       `json:"phase"`
+	Percent     *float64     `json:"percent,omitempty"`
+}
+
+var _ Hook = (*provisionStatus)(nil)
+
+func (h *provisionStatus) HookType() MessageType {
+	return MessageProvisionStatus
+}
+
+func (h *provisionStatus) String() string {
+	if h.Percent == nil {
+		return fmt.Sprintf("%s: (%s) %s", h.Resource.Addr, h.Provisioner, h.Phase)
+	}
+	return fmt.Sprintf("%s: (%s) %s (%g%%)", h.Resource.Addr, h.Provisioner, h.Phase, *h.Percent)
+}
+
+// NewProvisionStatus returns a hook for a progress report of a provisioner.
+// A negative percentage means that the provisioner couldn't estimate its
+// completion, and is omitted from the message.
+func NewProvisionStatus(addr addrs.AbsResourceInstance, provisioner string, phase string, percent float64) Hook {
+	h := &provisionStatus{
+		Resource:    newResourceAddr(addr),
+		Provisioner: provisioner,
+		Phase:       phase,
+	}
+	if percent >= 0 {
+		h.Percent = &percent
+	}
+	return h
+}
+
 // ProvisionComplete: triggered by PostProvisionInstanceStep hook
 type provisionComplete struct {
 	Resource    ResourceAddr `json:"resource"`
@@ -385,3 +421,37 @@ func actionNoun(action plans.Action) string {
 		return "Apply"
 	}
 }
+
+// ImportProgress: triggered by ImportProgress hook
+type importProgress struct {
+	Resource  ResourceAddr `json:"resource"`
+	Success   bool         `json:"success"`
+	Completed int          `json:"completed"`
+	Total     int          `json:"total"`
+}
+
+var _ Hook = (*importProgress)(nil)
+
+func (h *importProgress) HookType() MessageType {
+	return MessageImportProgress
+}
+
+func (h *importProgress) String() string {
+	status := "Import complete"
+	if !h.Success {
+		status = "Import failed"
+	}
+	return fmt.Sprintf("%s: %s (%d/%d)", h.Resource.Addr, status, h.Completed, h.Total)
+}
+
+// NewImportProgress returns a hook reporting that OpenTofu has finished with
+// the import target for the given address, which is the completed'th target
+// of total.
+func NewImportProgress(addr addrs.AbsResourceInstance, success bool, completed, total int) Hook {
+	return &importProgress{
+		Resource:  newResourceAddr(addr),
+		Success:   success,
+		Completed: completed,
+		Total:     total,
+	}
+}
@@ -26,10 +26,12 @@ const (
 	MessageApplyErrored      MessageType = "apply_errored"
 	MessageProvisionStart    MessageType = "provision_start"
 	MessageProvisionProgress MessageType = "provision_progress"
+	MessageProvisionStatus   MessageType = "provision_status"
 	MessageProvisionComplete MessageType = "provision_complete"
 	MessageProvisionErrored  MessageType = "provision_errored"
 	MessageRefreshStart      MessageType = "refresh_start"
 	MessageRefreshComplete   MessageType = "refresh_complete"
+	MessageImportProgress    MessageType = "import_progress"
 
 	// Test messages
 	MessageTestAbstract  MessageType = "test_abstract"
@@ -14,6 +14,7 @@ import (
 	"github.com/we-dcode/opentofu/pkg/command/arguments"
 	"github.com/we-dcode/opentofu/pkg/command/format"
 	"github.com/we-dcode/opentofu/pkg/command/jsonformat"
+	"github.com/we-dcode/opentofu/pkg/command/jsonformat/computed"
 	"github.com/we-dcode/opentofu/pkg/command/jsonplan"
 	"github.com/we-dcode/opentofu/pkg/command/jsonprovider"
 	"github.com/we-dcode/opentofu/pkg/command/views/json"
@@ -36,6 +37,8 @@ type Operation interface {
 	Plan(plan *plans.Plan, schemas *tofu.Schemas)
 	PlanNextStep(planPath string, genConfigPath string)
 
+	StateVersionWritten(version int64)
+
 	Diagnostics(diags tfdiags.Diagnostics)
 }
 
@@ -170,6 +173,12 @@ func (v *OperationHuman) PlanNextStep(planPath string, genConfigPath string) {
 	}
 }
 
+// StateVersionWritten reports the version the state storage assigned to the
+// state written at the end of the operation.
+func (v *OperationHuman) StateVersionWritten(version int64) {
+	v.view.streams.Printf("State version %d written.\n", version)
+}
+
 func (v *OperationHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -224,6 +233,11 @@ func (v *OperationJSON) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 		}
 	}
 
+	var providerSchemas map[string]*jsonprovider.Provider
+	if schemas != nil {
+		providerSchemas = jsonprovider.MarshalForRenderer(schemas)
+	}
+
 	cs := &json.ChangeSummary{
 		Operation: json.OperationPlanned,
 	}
@@ -250,7 +264,11 @@ func (v *OperationJSON) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 		}
 
 		if change.Action != plans.NoOp || !change.Addr.Equal(change.PrevRunAddr) || change.Importing != nil {
-			v.view.PlannedChange(json.NewResourceInstanceChange(change))
+			c := json.NewResourceInstanceChange(change)
+			if diff, ok := computeChangeDiff(change, schemas, providerSchemas); ok {
+				c.SetDiff(diff)
+			}
+			v.view.PlannedChange(c)
 		}
 	}
 
@@ -268,6 +286,21 @@ func (v *OperationJSON) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 	}
 }
 
+// computeChangeDiff computes the diff of the given change as shown by the
+// human readable plan. It returns false if the change can't be decoded with
+// the given schemas, in which case the planned change is logged without the
+// details of the diff.
+func computeChangeDiff(change *plans.ResourceInstanceChangeSrc, schemas *tofu.Schemas, providerSchemas map[string]*jsonprovider.Provider) (computed.Diff, bool) {
+	if schemas == nil {
+		return computed.Diff{}, false
+	}
+	changes, err := jsonplan.MarshalResourceChanges([]*plans.ResourceInstanceChangeSrc{change}, schemas)
+	if err != nil || len(changes) != 1 {
+		return computed.Diff{}, false
+	}
+	return jsonformat.ComputeResourceChangeDiff(changes[0], providerSchemas)
+}
+
 func (v *OperationJSON) PlannedChange(change *plans.ResourceInstanceChangeSrc) {
 	if change.Action == plans.Delete && change.Addr.Resource.Resource.Mode == addrs.DataResourceMode {
 		// Avoid rendering data sources on deletion
@@ -281,6 +314,11 @@ func (v *OperationJSON) PlannedChange(change *plans.ResourceInstanceChangeSrc) {
 func (v *OperationJSON) PlanNextStep(planPath string, genConfigPath string) {
 }
 
+// StateVersionWritten does nothing for the JSON view, as the state version
+// is only of interest in the human-readable summary.
+func (v *OperationJSON) StateVersionWritten(version int64) {
+}
+
 func (v *OperationJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
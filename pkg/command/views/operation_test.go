@@ -13,9 +13,11 @@ import (
 
 	"github.com/we-dcode/opentofu/pkg/addrs"
 	"github.com/we-dcode/opentofu/pkg/command/arguments"
+	"github.com/we-dcode/opentofu/pkg/configs/configschema"
 	"github.com/we-dcode/opentofu/pkg/encryption"
 	"github.com/we-dcode/opentofu/pkg/lang/globalref"
 	"github.com/we-dcode/opentofu/pkg/plans"
+	"github.com/we-dcode/opentofu/pkg/providers"
 	"github.com/we-dcode/opentofu/pkg/states"
 	"github.com/we-dcode/opentofu/pkg/states/statefile"
 	"github.com/we-dcode/opentofu/pkg/terminal"
@@ -476,6 +478,17 @@ func TestOperation_planNextStepInAutomation(t *testing.T) {
 	}
 }
 
+func TestOperation_stateVersionWritten(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOperation(arguments.ViewHuman, false, NewView(streams))
+
+	v.StateVersionWritten(42)
+
+	if got, want := done(t).Stdout(), "State version 42 written.\n"; got != want {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
 // Test all the trivial OperationJSON methods together. Y'know, for brevity.
 // This test is not a realistic stream of messages.
 func TestOperationJSON_logs(t *testing.T) {
@@ -1258,6 +1271,229 @@ func TestOperationJSON_planOutputChanges(t *testing.T) {
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
 
+func TestOperationJSON_planSensitivePaths(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := &OperationJSON{view: NewJSONView(NewView(streams))}
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"id": {Type: cty.String, Computed: true},
+		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"credentials": {
+				Nesting: configschema.NestingSingle,
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"user":     {Type: cty.String, Optional: true},
+						"password": {Type: cty.String, Optional: true, Sensitive: true},
+					},
+				},
+			},
+		},
+	}
+	schemas := &tofu.Schemas{
+		Providers: map[addrs.Provider]providers.ProviderSchema{
+			addrs.NewDefaultProvider("test"): {
+				ResourceTypes: map[string]providers.Schema{
+					"test_instance": {Block: schema},
+				},
+			},
+		},
+	}
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "boop",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	ty := schema.ImpliedType()
+	rc := &plans.ResourceInstanceChange{
+		Addr:        addr,
+		PrevRunAddr: addr,
+		ProviderAddr: addrs.RootModuleInstance.ProviderConfigDefault(
+			addrs.NewDefaultProvider("test"),
+		),
+		Change: plans.Change{
+			Action: plans.Create,
+			Before: cty.NullVal(ty),
+			After: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.UnknownVal(cty.String),
+				"credentials": cty.ObjectVal(map[string]cty.Value{
+					"user":     cty.StringVal("admin"),
+					"password": cty.StringVal("hunter2"),
+				}),
+			}),
+		},
+	}
+	rcs, err := rc.Encode(ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := &plans.Plan{
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{rcs},
+		},
+	}
+	v.Plan(plan, schemas)
+
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "test_instance.boop: Plan to create",
+			"@module":  "tofu.ui",
+			"type":     "planned_change",
+			"change": map[string]interface{}{
+				"action": "create",
+				"resource": map[string]interface{}{
+					"addr":             `test_instance.boop`,
+					"implied_provider": "test",
+					"module":           "",
+					"resource":         `test_instance.boop`,
+					"resource_key":     nil,
+					"resource_name":    "boop",
+					"resource_type":    "test_instance",
+				},
+				"sensitive_paths": []interface{}{
+					map[string]interface{}{
+						"path":             []interface{}{"credentials", "password"},
+						"before_sensitive": false,
+						"after_sensitive":  true,
+					},
+				},
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "Plan: 1 to add, 0 to change, 0 to destroy.",
+			"@module":  "tofu.ui",
+			"type":     "change_summary",
+			"changes": map[string]interface{}{
+				"operation": "plan",
+				"add":       float64(1),
+				"import":    float64(0),
+				"change":    float64(0),
+				"remove":    float64(0),
+			},
+		},
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
+
+func TestOperationJSON_planUnchangedElements(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := &OperationJSON{view: NewJSONView(NewView(streams))}
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"id":   {Type: cty.String, Computed: true},
+			"tags": {Type: cty.Set(cty.String), Optional: true},
+		},
+	}
+	schemas := &tofu.Schemas{
+		Providers: map[addrs.Provider]providers.ProviderSchema{
+			addrs.NewDefaultProvider("test"): {
+				ResourceTypes: map[string]providers.Schema{
+					"test_instance": {Block: schema},
+				},
+			},
+		},
+	}
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "boop",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	ty := schema.ImpliedType()
+
+	// Two of the five elements are changed, the other three are not.
+	rc := &plans.ResourceInstanceChange{
+		Addr:        addr,
+		PrevRunAddr: addr,
+		ProviderAddr: addrs.RootModuleInstance.ProviderConfigDefault(
+			addrs.NewDefaultProvider("test"),
+		),
+		Change: plans.Change{
+			Action: plans.Update,
+			Before: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.StringVal("i-1234"),
+				"tags": cty.SetVal([]cty.Value{
+					cty.StringVal("a"),
+					cty.StringVal("b"),
+					cty.StringVal("c"),
+					cty.StringVal("d"),
+					cty.StringVal("e"),
+				}),
+			}),
+			After: cty.ObjectVal(map[string]cty.Value{
+				"id": cty.StringVal("i-1234"),
+				"tags": cty.SetVal([]cty.Value{
+					cty.StringVal("a"),
+					cty.StringVal("b"),
+					cty.StringVal("c"),
+					cty.StringVal("x"),
+					cty.StringVal("y"),
+				}),
+			}),
+		},
+	}
+	rcs, err := rc.Encode(ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := &plans.Plan{
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{rcs},
+		},
+	}
+	v.Plan(plan, schemas)
+
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "test_instance.boop: Plan to update",
+			"@module":  "tofu.ui",
+			"type":     "planned_change",
+			"change": map[string]interface{}{
+				"action": "update",
+				"resource": map[string]interface{}{
+					"addr":             `test_instance.boop`,
+					"implied_provider": "test",
+					"module":           "",
+					"resource":         `test_instance.boop`,
+					"resource_key":     nil,
+					"resource_name":    "boop",
+					"resource_type":    "test_instance",
+				},
+				"unchanged_elements": []interface{}{
+					map[string]interface{}{
+						"path":  []interface{}{"tags"},
+						"count": float64(3),
+					},
+				},
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "Plan: 0 to add, 1 to change, 0 to destroy.",
+			"@module":  "tofu.ui",
+			"type":     "change_summary",
+			"changes": map[string]interface{}{
+				"operation": "plan",
+				"add":       float64(0),
+				"import":    float64(0),
+				"change":    float64(1),
+				"remove":    float64(0),
+			},
+		},
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
+
 func TestOperationJSON_plannedChange(t *testing.T) {
 	streams, done := terminal.StreamsForTesting(t)
 	v := &OperationJSON{view: NewJSONView(NewView(streams))}
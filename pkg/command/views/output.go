@@ -9,14 +9,21 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	ctyyaml "github.com/zclconf/go-cty-yaml"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/we-dcode/opentofu/pkg/command/arguments"
+	"github.com/we-dcode/opentofu/pkg/command/jsonplan"
+	"github.com/we-dcode/opentofu/pkg/lang/marks"
 	"github.com/we-dcode/opentofu/pkg/repl"
 	"github.com/we-dcode/opentofu/pkg/states"
 	"github.com/we-dcode/opentofu/pkg/tfdiags"
@@ -36,6 +43,10 @@ func NewOutput(vt arguments.ViewType, view *View) Output {
 		return &OutputJSON{view: view}
 	case arguments.ViewRaw:
 		return &OutputRaw{view: view}
+	case arguments.ViewYAML:
+		return &OutputYAML{view: view}
+	case arguments.ViewEnv:
+		return &OutputEnv{view: view}
 	case arguments.ViewHuman:
 		return &OutputHuman{view: view}
 	default:
@@ -47,6 +58,14 @@ func NewOutput(vt arguments.ViewType, view *View) Output {
 // source. This uses the same formatting logic as in the console REPL.
 type OutputHuman struct {
 	view *View
+
+	// Humanize enables rendering values which look like timestamps or byte
+	// counts in a friendlier form. See humanizeOutputValue for the details.
+	Humanize bool
+
+	// now returns the time which humanized timestamps are relative to. It
+	// defaults to time.Now and is only overridden by tests.
+	now func() time.Time
 }
 
 var _ Output = (*OutputHuman)(nil)
@@ -65,7 +84,7 @@ func (v *OutputHuman) Output(name string, outputs map[string]*states.OutputValue
 			diags = diags.Append(missingOutputError(name))
 			return diags
 		}
-		result := repl.FormatValue(output.Value, 0)
+		result := repl.FormatValue(v.humanize(name, output.Value), 0)
 		v.view.streams.Println(result)
 		return nil
 	}
@@ -83,14 +102,15 @@ func (v *OutputHuman) Output(name string, outputs map[string]*states.OutputValue
 		}
 		sort.Strings(ks)
 
+		values := states.OutputValuesObject(outputs)
 		for _, k := range ks {
-			vs := outputs[k]
-			if vs.Sensitive && !v.view.showSensitive {
+			val, visible := v.view.outputValue(values, k)
+			if !visible {
 				outputBuf.WriteString(fmt.Sprintf("%s = <sensitive>\n", k))
 				continue
 			}
 
-			result := repl.FormatValue(vs.Value, 0)
+			result := repl.FormatValue(v.humanize(k, val), 0)
 			outputBuf.WriteString(fmt.Sprintf("%s = %s\n", k, result))
 		}
 	}
@@ -104,6 +124,19 @@ func (v *OutputHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
 
+// humanize returns the given value of the named output as it should be
+// rendered, which is only different from the value itself if Humanize is set.
+func (v *OutputHuman) humanize(name string, val cty.Value) cty.Value {
+	if !v.Humanize {
+		return val
+	}
+	now := time.Now
+	if v.now != nil {
+		now = v.now
+	}
+	return humanizeOutputValue(name, val, now())
+}
+
 // The OutputRaw implementation renders single string, number, or boolean
 // output values directly and without quotes or other formatting. This is
 // intended for use in shell scripting or other environments where the exact
@@ -118,6 +151,13 @@ func (v *OutputRaw) Output(name string, outputs map[string]*states.OutputValue)
 	var diags tfdiags.Diagnostics
 
 	if len(outputs) == 0 {
+		if name != "" {
+			// A missing output must always fail in raw mode, so that shell
+			// scripts can tell it apart from an output which is an empty
+			// string.
+			diags = diags.Append(missingOutputError(name))
+			return diags
+		}
 		diags = diags.Append(noOutputsWarning())
 		return diags
 	}
@@ -127,20 +167,32 @@ func (v *OutputRaw) Output(name string, outputs map[string]*states.OutputValue)
 		return diags
 	}
 
-	output, ok := outputs[name]
-	if !ok {
+	if _, ok := outputs[name]; !ok {
 		diags = diags.Append(missingOutputError(name))
 		return diags
 	}
 
-	strV, err := convert.Convert(output.Value, cty.String)
+	val, visible := v.view.outputValue(states.OutputValuesObject(outputs), name)
+	if !visible {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Sensitive value for raw output",
+			fmt.Sprintf(
+				"Output value %q is marked as sensitive, so -raw mode will only print it if the -show-sensitive option is also set.",
+				name,
+			),
+		))
+		return diags
+	}
+
+	strV, err := rawOutputString(val)
 	if err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Unsupported value for raw output",
 			fmt.Sprintf(
 				"The -raw option only supports strings, numbers, and boolean values, but output value %q is %s.\n\nUse the -json option for machine-readable representations of output values that have complex types.",
-				name, output.Value.Type().FriendlyName(),
+				name, val.Type().FriendlyName(),
 			),
 		))
 		return diags
@@ -177,8 +229,28 @@ func (v *OutputRaw) Output(name string, outputs map[string]*states.OutputValue)
 	return nil
 }
 
+// Diagnostics renders all diagnostics to stderr, including warnings, so that
+// stdout only ever contains the raw value.
 func (v *OutputRaw) Diagnostics(diags tfdiags.Diagnostics) {
-	v.view.Diagnostics(diags)
+	v.view.WithStdout(v.view.streams.Stderr.File).Diagnostics(diags)
+}
+
+// rawOutputString converts the given value to the string printed by the raw
+// output format. Numbers and booleans are formatted directly rather than
+// through the usual type conversion, so that numbers keep their exact value
+// regardless of their magnitude or number of decimal places.
+func rawOutputString(val cty.Value) (cty.Value, error) {
+	if val.IsNull() || !val.IsKnown() {
+		return convert.Convert(val, cty.String)
+	}
+	switch val.Type() {
+	case cty.Number:
+		return cty.StringVal(val.AsBigFloat().Text('f', -1)), nil
+	case cty.Bool:
+		return cty.StringVal(strconv.FormatBool(val.True())), nil
+	default:
+		return convert.Convert(val, cty.String)
+	}
 }
 
 // The OutputJSON implementation renders outputs as JSON values. When rendering
@@ -201,6 +273,19 @@ func (v *OutputJSON) Output(name string, outputs map[string]*states.OutputValue)
 			return diags
 		}
 		value := output.Value
+		if !value.IsWhollyKnown() {
+			// A single value can't be told apart from a null, but the
+			// listing of all outputs can mark it as unknown.
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Output value not yet known",
+				fmt.Sprintf(
+					"The value for output value %q won't be known until after a successful tofu apply, so its unknown parts are rendered as null. Run this command without an output name to see which parts are unknown.",
+					name,
+				),
+			))
+			value = cty.UnknownAsNull(value)
+		}
 
 		jsonOutput, err := ctyjson.Marshal(value, value.Type())
 		if err != nil {
@@ -210,7 +295,7 @@ func (v *OutputJSON) Output(name string, outputs map[string]*states.OutputValue)
 
 		v.view.streams.Println(string(jsonOutput))
 
-		return nil
+		return diags
 	}
 
 	// Due to a historical accident, the switch from state version 2 to
@@ -219,20 +304,38 @@ func (v *OutputJSON) Output(name string, outputs map[string]*states.OutputValue)
 	// show in the single value case. We must now maintain that behavior
 	// for compatibility, so this is an emulation of the JSON
 	// serialization of outputs used in state format version 3.
+	//
+	// Values which are only known after apply, such as those read from a
+	// plan file, have no equivalent in the state. Their unknown parts are
+	// rendered as null, and the additional "unknown" property is either
+	// true for a wholly unknown value or mirrors the value with true in
+	// place of its unknown parts, so they can be told apart from real nulls.
 	type OutputMeta struct {
 		Sensitive bool            `json:"sensitive"`
 		Type      json.RawMessage `json:"type"`
 		Value     json.RawMessage `json:"value"`
+		Unknown   json.RawMessage `json:"unknown,omitempty"`
 	}
 	outputMetas := map[string]OutputMeta{}
 
 	for n, os := range outputs {
-		jsonVal, err := ctyjson.Marshal(os.Value, os.Value.Type())
+		var jsonUnknown json.RawMessage
+		value := os.Value
+		if !value.IsWhollyKnown() {
+			var err error
+			jsonUnknown, err = jsonplan.MarshalUnknowns(value)
+			if err != nil {
+				diags = diags.Append(err)
+				return diags
+			}
+			value = cty.UnknownAsNull(value)
+		}
+		jsonVal, err := ctyjson.Marshal(value, value.Type())
 		if err != nil {
 			diags = diags.Append(err)
 			return diags
 		}
-		jsonType, err := ctyjson.MarshalType(os.Value.Type())
+		jsonType, err := ctyjson.MarshalType(value.Type())
 		if err != nil {
 			diags = diags.Append(err)
 			return diags
@@ -241,6 +344,7 @@ func (v *OutputJSON) Output(name string, outputs map[string]*states.OutputValue)
 			Sensitive: os.Sensitive,
 			Type:      json.RawMessage(jsonType),
 			Value:     json.RawMessage(jsonVal),
+			Unknown:   jsonUnknown,
 		}
 	}
 
@@ -259,6 +363,241 @@ func (v *OutputJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
 
+// The OutputYAML implementation renders outputs as YAML documents. When
+// rendering a single output, only the value is displayed. When rendering all
+// outputs, the result is a mapping with keys matching the output names and
+// values including sensitivity metadata. Sensitive values are redacted to
+// null unless -show-sensitive is set.
+type OutputYAML struct {
+	view *View
+}
+
+var _ Output = (*OutputYAML)(nil)
+
+func (v *OutputYAML) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if name != "" {
+		output, ok := outputs[name]
+		if !ok {
+			diags = diags.Append(missingOutputError(name))
+			return diags
+		}
+
+		yamlOutput, err := ctyyaml.Standard.Marshal(output.Value)
+		if err != nil {
+			diags = diags.Append(err)
+			return diags
+		}
+
+		v.view.streams.Print(string(yamlOutput))
+
+		return nil
+	}
+
+	if len(outputs) == 0 {
+		v.view.streams.Println("{}")
+		return nil
+	}
+
+	values := states.OutputValuesObject(outputs)
+	outputMetas := make(map[string]cty.Value, len(outputs))
+	for n, os := range outputs {
+		value, visible := v.view.outputValue(values, n)
+		if !visible {
+			value = cty.NullVal(value.Type())
+		}
+		outputMetas[n] = cty.ObjectVal(map[string]cty.Value{
+			"sensitive": cty.BoolVal(os.Sensitive),
+			"value":     value,
+		})
+	}
+
+	yamlOutputs, err := ctyyaml.Standard.Marshal(cty.ObjectVal(outputMetas))
+	if err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+
+	v.view.streams.Print(string(yamlOutputs))
+
+	return nil
+}
+
+func (v *OutputYAML) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// The OutputEnv implementation renders outputs as NAME=value lines which can
+// be sourced by a POSIX shell. Output names are uppercased, and values are
+// single-quoted. Strings, numbers, and booleans are rendered directly, while
+// values of complex types are rendered as JSON strings. Sensitive values are
+// omitted unless -show-sensitive is set.
+type OutputEnv struct {
+	view *View
+}
+
+var _ Output = (*OutputEnv)(nil)
+
+// envNamePattern matches the names a POSIX shell accepts for variables.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (v *OutputEnv) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if len(outputs) == 0 {
+		diags = diags.Append(noOutputsWarning())
+		return diags
+	}
+
+	names := make([]string, 0, len(outputs))
+	if name != "" {
+		if _, ok := outputs[name]; !ok {
+			diags = diags.Append(missingOutputError(name))
+			return diags
+		}
+		names = append(names, name)
+	} else {
+		for n := range outputs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+	}
+
+	values := states.OutputValuesObject(outputs)
+	var buf strings.Builder
+	for _, n := range names {
+		val, visible := v.view.outputValue(values, n)
+		if !visible {
+			continue
+		}
+
+		envName := strings.ToUpper(n)
+		if !envNamePattern.MatchString(envName) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Output skipped",
+				fmt.Sprintf("The name of output value %q is not a valid shell variable name, so it was not included in the env output.", n),
+			))
+			continue
+		}
+
+		value, err := envValue(val)
+		if err != nil {
+			diags = diags.Append(err)
+			return diags
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", envName, shellQuote(value))
+	}
+
+	v.view.streams.Print(buf.String())
+
+	return diags
+}
+
+func (v *OutputEnv) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// envValue returns the string representation of the given value for the env
+// output format.
+func envValue(val cty.Value) (string, error) {
+	if val.IsNull() {
+		return "", nil
+	}
+	if strV, err := convert.Convert(val, cty.String); err == nil && strV.IsKnown() && !strV.IsNull() {
+		return strV.AsString(), nil
+	}
+	jsonVal, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return "", err
+	}
+	return string(jsonVal), nil
+}
+
+// shellQuote wraps the given string in single quotes, escaping any single
+// quotes it contains, so that a POSIX shell reads it back verbatim.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// NewOutputJSONStream returns an Output implementation that streams output
+// values as JSON lines.
+func NewOutputJSONStream(view *View) Output {
+	return &OutputJSONStream{view: view}
+}
+
+// The OutputJSONStream implementation renders outputs as a stream of JSON
+// objects, one per line, in alphabetical order. Each output value is
+// marshalled and written on its own so that large states do not have to be
+// held in memory twice, and consumers can process the outputs without
+// buffering the whole document.
+type OutputJSONStream struct {
+	view *View
+}
+
+var _ Output = (*OutputJSONStream)(nil)
+
+func (v *OutputJSONStream) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	type outputLine struct {
+		Name      string          `json:"name"`
+		Value     json.RawMessage `json:"value"`
+		Sensitive bool            `json:"sensitive"`
+	}
+
+	names := make([]string, 0, len(outputs))
+	if name != "" {
+		if _, ok := outputs[name]; !ok {
+			diags = diags.Append(missingOutputError(name))
+			return diags
+		}
+		names = append(names, name)
+	} else {
+		for n := range outputs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+	}
+
+	enc := json.NewEncoder(v.view.streams.Stdout.File)
+	for _, n := range names {
+		os := outputs[n]
+		jsonVal, err := ctyjson.Marshal(os.Value, os.Value.Type())
+		if err != nil {
+			diags = diags.Append(err)
+			return diags
+		}
+		// Encode writes a trailing newline after every value, which gives
+		// us the one-object-per-line format.
+		if err := enc.Encode(outputLine{
+			Name:      n,
+			Value:     json.RawMessage(jsonVal),
+			Sensitive: os.Sensitive,
+		}); err != nil {
+			diags = diags.Append(err)
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func (v *OutputJSONStream) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// outputValue returns the value of the named output within the given object
+// of output values, as returned by states.OutputValuesObject, with its marks
+// removed. The second result is false if the value is marked as sensitive and
+// -show-sensitive is not set, in which case the value must not be displayed.
+func (v *View) outputValue(outputs cty.Value, name string) (cty.Value, bool) {
+	val, valMarks := outputs.GetAttr(name).UnmarkDeep()
+	_, sensitive := valMarks[marks.Sensitive]
+	return val, !sensitive || v.showSensitive
+}
+
 // For text and raw output modes, an empty map of outputs is considered a
 // separate and higher priority failure mode than an output not being present
 // in a non-empty map. This warning diagnostic explains how this might have
@@ -276,6 +615,130 @@ func noOutputsWarning() tfdiags.Diagnostic {
 	)
 }
 
+// CheckOutputs returns an error if the named output is missing from the
+// given outputs or, if the name is empty, if there are no outputs at all.
+// This is used by the -check option, which makes these cases fail
+// consistently for all the output formats.
+func CheckOutputs(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if name != "" {
+		if _, ok := outputs[name]; !ok {
+			diags = diags.Append(missingOutputError(name))
+		}
+		return diags
+	}
+
+	if len(outputs) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No outputs found",
+			"The -check option requires at least one output value, but the "+
+				"state has no outputs defined, or all the defined outputs are "+
+				"empty. Please define an output in your configuration with the "+
+				"`output` keyword and run `tofu apply` for it to become available.",
+		))
+	}
+	return diags
+}
+
+// OutputAtPath returns the given outputs with the value of the named output
+// replaced by the nested value at the given path. This is used by the -path
+// option, so that all the output formats render only the nested value.
+//
+// If the named output doesn't exist, the outputs are returned unchanged so
+// that the view can report it as usual.
+func OutputAtPath(name string, path cty.Path, outputs map[string]*states.OutputValue) (map[string]*states.OutputValue, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	output, ok := outputs[name]
+	if !ok {
+		return outputs, diags
+	}
+
+	val := output.Value
+	for i, step := range path {
+		var err error
+		val, err = applyOutputPathStep(val, step)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid output path",
+				fmt.Sprintf(
+					"Can't find %s in output value %q: %s.",
+					tfdiags.FormatCtyPath(path[:i+1]), name, err,
+				),
+			))
+			return nil, diags
+		}
+	}
+
+	ret := make(map[string]*states.OutputValue, len(outputs))
+	for n, o := range outputs {
+		ret[n] = o
+	}
+	ret[name] = &states.OutputValue{
+		Addr:      output.Addr,
+		Value:     val,
+		Sensitive: output.Sensitive,
+	}
+	return ret, diags
+}
+
+// applyOutputPathStep returns the value at a single path step within the
+// given value. Unlike cty.PathStep.Apply, attributes and string indices can
+// be used interchangeably for both objects and maps, as in HCL expressions.
+func applyOutputPathStep(val cty.Value, step cty.PathStep) (cty.Value, error) {
+	if val.IsNull() {
+		return cty.NilVal, fmt.Errorf("the value is null")
+	}
+	if !val.IsKnown() {
+		return cty.NilVal, fmt.Errorf("the value is not yet known")
+	}
+
+	var key cty.Value
+	switch step := step.(type) {
+	case cty.GetAttrStep:
+		key = cty.StringVal(step.Name)
+	case cty.IndexStep:
+		key = step.Key
+	default:
+		return cty.NilVal, fmt.Errorf("unsupported path step %T", step)
+	}
+
+	ty := val.Type()
+	switch {
+	case ty.IsObjectType():
+		if key.Type() != cty.String || !ty.HasAttribute(key.AsString()) {
+			return cty.NilVal, fmt.Errorf("the object has no attribute %s", keyString(key))
+		}
+		return val.GetAttr(key.AsString()), nil
+	case ty.IsMapType():
+		if key.Type() != cty.String {
+			return cty.NilVal, fmt.Errorf("the value is %s, which can only be indexed by a string", ty.FriendlyName())
+		}
+	case ty.IsListType() || ty.IsTupleType():
+		if key.Type() != cty.Number {
+			return cty.NilVal, fmt.Errorf("the value is %s, which can only be indexed by a number", ty.FriendlyName())
+		}
+	default:
+		return cty.NilVal, fmt.Errorf("the value is %s, which can't be indexed", ty.FriendlyName())
+	}
+
+	if !val.HasIndex(key).True() {
+		return cty.NilVal, fmt.Errorf("there is no element %s", keyString(key))
+	}
+	return val.Index(key), nil
+}
+
+// keyString formats a path key as it would be written in an HCL expression.
+func keyString(key cty.Value) string {
+	if key.Type() == cty.String {
+		return strconv.Quote(key.AsString())
+	}
+	return key.AsBigFloat().Text('g', -1)
+}
+
 // Attempting to display a missing output results in this failure, which
 // includes suggestions on how to rectify the problem.
 func missingOutputError(name string) tfdiags.Diagnostic {
@@ -288,3 +751,155 @@ func missingOutputError(name string) tfdiags.Diagnostic {
 			"with new output variables until that command is run.",
 	)
 }
+
+// byteCountNamePattern matches the names of outputs and attributes whose
+// numbers are assumed to be byte counts by humanizeOutputValue.
+var byteCountNamePattern = regexp.MustCompile(`(?i)(^|_)(bytes|size)$`)
+
+// humanizeOutputValue makes a value easier to read for the human view, using
+// heuristics to recognize some common kinds of values:
+//
+//   - Strings in the RFC 3339 format are rendered as times relative to now,
+//     such as "3 days ago".
+//   - Whole numbers named like byte counts, such as disk_size or
+//     memory_bytes, are rendered in IEC units, such as "1.5 GiB". The name is
+//     that of the output or of the closest enclosing attribute or map key.
+//
+// Values which contain marks, and collections whose elements would no longer
+// share the same type, are returned unchanged. The result is only meant to be
+// displayed, as it may not conform to the type of the output.
+func humanizeOutputValue(name string, val cty.Value, now time.Time) cty.Value {
+	if val.ContainsMarked() || !val.IsWhollyKnown() || val.IsNull() {
+		return val
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		t, err := time.Parse(time.RFC3339, val.AsString())
+		if err != nil {
+			return val
+		}
+		return cty.StringVal(humanizeTime(t, now))
+
+	case ty == cty.Number:
+		bf := val.AsBigFloat()
+		if !byteCountNamePattern.MatchString(name) || !bf.IsInt() || bf.Sign() < 0 {
+			return val
+		}
+		return cty.StringVal(humanizeByteCount(bf))
+
+	case ty.IsObjectType():
+		attrs := make(map[string]cty.Value, len(ty.AttributeTypes()))
+		for k, attr := range val.AsValueMap() {
+			attrs[k] = humanizeOutputValue(k, attr, now)
+		}
+		return cty.ObjectVal(attrs)
+
+	case ty.IsTupleType():
+		if val.LengthInt() == 0 {
+			return val
+		}
+		elems := make([]cty.Value, 0, val.LengthInt())
+		for _, elem := range val.AsValueSlice() {
+			elems = append(elems, humanizeOutputValue(name, elem, now))
+		}
+		return cty.TupleVal(elems)
+
+	case ty.IsMapType():
+		if val.LengthInt() == 0 {
+			return val
+		}
+		elems := make(map[string]cty.Value, val.LengthInt())
+		var elemTy cty.Type
+		for k, elem := range val.AsValueMap() {
+			elems[k] = humanizeOutputValue(k, elem, now)
+			if elemTy == cty.NilType {
+				elemTy = elems[k].Type()
+			} else if !elems[k].Type().Equals(elemTy) {
+				return val
+			}
+		}
+		return cty.MapVal(elems)
+
+	case ty.IsListType() || ty.IsSetType():
+		if val.LengthInt() == 0 {
+			return val
+		}
+		elems := make([]cty.Value, 0, val.LengthInt())
+		for _, elem := range val.AsValueSlice() {
+			elem = humanizeOutputValue(name, elem, now)
+			if len(elems) > 0 && !elem.Type().Equals(elems[0].Type()) {
+				return val
+			}
+			elems = append(elems, elem)
+		}
+		if ty.IsSetType() {
+			return cty.SetVal(elems)
+		}
+		return cty.ListVal(elems)
+
+	default:
+		return val
+	}
+}
+
+// humanizeTime returns the time t relative to now, such as "3 days ago" or
+// "in 2 hours".
+func humanizeTime(t time.Time, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	const day = 24 * time.Hour
+	var amount int64
+	var unit string
+	switch {
+	case d < time.Minute:
+		if future {
+			return "in less than a minute"
+		}
+		return "less than a minute ago"
+	case d < time.Hour:
+		amount, unit = int64(d/time.Minute), "minute"
+	case d < day:
+		amount, unit = int64(d/time.Hour), "hour"
+	case d < 30*day:
+		amount, unit = int64(d/day), "day"
+	case d < 365*day:
+		amount, unit = int64(d/(30*day)), "month"
+	default:
+		amount, unit = int64(d/(365*day)), "year"
+	}
+	if amount != 1 {
+		unit += "s"
+	}
+
+	if future {
+		return fmt.Sprintf("in %d %s", amount, unit)
+	}
+	return fmt.Sprintf("%d %s ago", amount, unit)
+}
+
+// humanizeByteCount returns the given number of bytes in IEC units, such as
+// "1.5 KiB".
+func humanizeByteCount(n *big.Float) string {
+	if n.Cmp(big.NewFloat(1024)) < 0 {
+		i, _ := n.Int64()
+		return fmt.Sprintf("%d B", i)
+	}
+
+	f, _ := n.Float64()
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"}
+	unit := ""
+	for _, u := range units {
+		if f < 1024 {
+			break
+		}
+		f /= 1024
+		unit = u
+	}
+	return fmt.Sprintf("%.1f %s", f, unit)
+}
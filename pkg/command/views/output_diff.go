@@ -0,0 +1,188 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/command/arguments"
+	"github.com/we-dcode/opentofu/pkg/command/jsonformat"
+	"github.com/we-dcode/opentofu/pkg/command/jsonplan"
+	"github.com/we-dcode/opentofu/pkg/plans"
+	"github.com/we-dcode/opentofu/pkg/states"
+	"github.com/we-dcode/opentofu/pkg/tfdiags"
+)
+
+// NewOutputDiff returns an Output implementation for the given ViewType which
+// renders the changes from the given previous output values to the output
+// values it is called with, instead of the output values themselves. Only
+// the human and JSON view types are supported.
+func NewOutputDiff(vt arguments.ViewType, view *View, before map[string]*states.OutputValue) Output {
+	switch vt {
+	case arguments.ViewJSON:
+		return &OutputDiffJSON{view: view, before: before}
+	case arguments.ViewHuman:
+		return &OutputDiffHuman{view: view, before: before}
+	default:
+		panic(fmt.Sprintf("unknown view type %v", vt))
+	}
+}
+
+// The OutputDiffHuman implementation renders the changes to the outputs like
+// the output changes of a plan.
+type OutputDiffHuman struct {
+	view   *View
+	before map[string]*states.OutputValue
+}
+
+var _ Output = (*OutputDiffHuman)(nil)
+
+func (v *OutputDiffHuman) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	changes, diags := outputChanges(name, v.before, outputs)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	renderer := jsonformat.Renderer{
+		Colorize:            v.view.colorize,
+		Streams:             v.view.streams,
+		RunningInAutomation: v.view.runningInAutomation,
+		ShowSensitive:       v.view.showSensitive,
+	}
+	renderer.RenderHumanOutputChanges(changes)
+	return diags
+}
+
+func (v *OutputDiffHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// The OutputDiffJSON implementation renders the changes to the outputs as a
+// JSON object, with the changed outputs in an "output_changes" object which
+// uses the same format as the output changes of the JSON plan format. The
+// values of sensitive outputs are omitted unless -show-sensitive is set.
+type OutputDiffJSON struct {
+	view   *View
+	before map[string]*states.OutputValue
+}
+
+var _ Output = (*OutputDiffJSON)(nil)
+
+func (v *OutputDiffJSON) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	changes, diags := outputChanges(name, v.before, outputs)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	changed := make(map[string]jsonplan.Change, len(changes))
+	for n, change := range changes {
+		if len(change.Actions) == 1 && change.Actions[0] == "no-op" {
+			continue
+		}
+		if !v.view.showSensitive && (isSensitiveJSON(change.BeforeSensitive) || isSensitiveJSON(change.AfterSensitive)) {
+			change.Before = nil
+			change.After = nil
+			change.AfterUnknown = nil
+		}
+		changed[n] = change
+	}
+
+	jsonOutput, err := json.MarshalIndent(struct {
+		OutputChanges map[string]jsonplan.Change `json:"output_changes"`
+	}{changed}, "", "  ")
+	if err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+
+	v.view.streams.Println(string(jsonOutput))
+	return diags
+}
+
+func (v *OutputDiffJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// outputChanges returns the changes from the before to the after output
+// values in the JSON plan format, which is what the diff renderers work with.
+// If name is not empty, only the change to the named output is returned.
+func outputChanges(name string, before, after map[string]*states.OutputValue) (map[string]jsonplan.Change, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	names := make(map[string]struct{})
+	for n := range before {
+		names[n] = struct{}{}
+	}
+	for n := range after {
+		names[n] = struct{}{}
+	}
+	if name != "" {
+		if _, ok := names[name]; !ok {
+			diags = diags.Append(missingOutputError(name))
+			return nil, diags
+		}
+		names = map[string]struct{}{name: {}}
+	}
+
+	changes := &plans.Changes{}
+	for n := range names {
+		oc := &plans.OutputChange{
+			Addr: addrs.OutputValue{Name: n}.Absolute(addrs.RootModuleInstance),
+			Change: plans.Change{
+				Before: cty.NullVal(cty.DynamicPseudoType),
+				After:  cty.NullVal(cty.DynamicPseudoType),
+			},
+		}
+		prev, hasPrev := before[n]
+		cur, hasCur := after[n]
+		if hasPrev {
+			oc.Before = prev.Value
+			oc.Sensitive = prev.Sensitive
+		}
+		if hasCur {
+			oc.After = cur.Value
+			oc.Sensitive = oc.Sensitive || cur.Sensitive
+		}
+
+		switch {
+		case !hasPrev:
+			oc.Action = plans.Create
+		case !hasCur:
+			oc.Action = plans.Delete
+		case prev.Sensitive == cur.Sensitive && prev.Value.RawEquals(cur.Value):
+			oc.Action = plans.NoOp
+		default:
+			oc.Action = plans.Update
+		}
+
+		ocs, err := oc.Encode()
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("failed to encode the change of output %q: %w", n, err))
+			continue
+		}
+		changes.Outputs = append(changes.Outputs, ocs)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ret, err := jsonplan.MarshalOutputChanges(changes)
+	if err != nil {
+		diags = diags.Append(err)
+	}
+	return ret, diags
+}
+
+// isSensitiveJSON returns true if the given sensitivity of an output in the
+// JSON plan format marks the output as sensitive.
+func isSensitiveJSON(raw json.RawMessage) bool {
+	var sensitive bool
+	return json.Unmarshal(raw, &sensitive) == nil && sensitive
+}
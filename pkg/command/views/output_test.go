@@ -6,9 +6,12 @@
 package views
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/we-dcode/opentofu/pkg/command/arguments"
 	"github.com/we-dcode/opentofu/pkg/states"
 	"github.com/we-dcode/opentofu/pkg/terminal"
@@ -76,6 +79,109 @@ func TestOutputHuman_single(t *testing.T) {
 	}
 }
 
+func TestOutputHuman_humanize(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	outputs := map[string]*states.OutputValue{
+		"created_at": {
+			Value: cty.StringVal("2024-05-07T09:30:00Z"),
+		},
+		"expires_at": {
+			Value: cty.StringVal("2024-05-10T14:00:00Z"),
+		},
+		"disk_size": {
+			Value: cty.NumberIntVal(1610612736),
+		},
+		"instance": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"memory_bytes": cty.NumberIntVal(512),
+				"cpus":         cty.NumberIntVal(2048),
+			}),
+		},
+		"name": {
+			Value: cty.StringVal("2024"),
+		},
+	}
+
+	testCases := map[string]struct {
+		humanize bool
+		want     string
+	}{
+		"humanized": {
+			humanize: true,
+			want: `created_at = "3 days ago"
+disk_size = "1.5 GiB"
+expires_at = "in 2 hours"
+instance = {
+  "cpus" = 2048
+  "memory_bytes" = "512 B"
+}
+name = "2024"
+`,
+		},
+		"raw values": {
+			humanize: false,
+			want: `created_at = "2024-05-07T09:30:00Z"
+disk_size = 1610612736
+expires_at = "2024-05-10T14:00:00Z"
+instance = {
+  "cpus" = 2048
+  "memory_bytes" = 512
+}
+name = "2024"
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			v := &OutputHuman{
+				view:     NewView(streams),
+				Humanize: tc.humanize,
+				now:      func() time.Time { return now },
+			}
+
+			diags := v.Output("", outputs)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected diagnostics: %s", diags)
+			}
+
+			if got, want := done(t).Stdout(), tc.want; got != want {
+				t.Errorf("wrong result\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestHumanizeOutputValue_collections(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	// The elements of a list must keep sharing a type, so a list in which
+	// only some strings are timestamps is left unchanged.
+	mixed := cty.ListVal([]cty.Value{
+		cty.StringVal("2024-05-09T12:00:00Z"),
+		cty.StringVal("tomorrow"),
+	})
+	if got := humanizeOutputValue("times", mixed, now); !got.RawEquals(mixed) {
+		t.Errorf("wrong result for mixed list\ngot:  %#v\nwant: %#v", got, mixed)
+	}
+
+	sizes := cty.ListVal([]cty.Value{
+		cty.NumberIntVal(2048),
+		cty.NumberIntVal(100),
+	})
+	want := cty.ListVal([]cty.Value{
+		cty.StringVal("2.0 KiB"),
+		cty.StringVal("100 B"),
+	})
+	if got := humanizeOutputValue("volume_sizes", sizes, now); !got.RawEquals(sizes) {
+		t.Errorf("wrong result for unrecognized name\ngot:  %#v\nwant: %#v", got, sizes)
+	}
+	if got := humanizeOutputValue("volume_size", sizes, now); !got.RawEquals(want) {
+		t.Errorf("wrong result for list of sizes\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
 // Sensitive output values are rendered to the console intentionally when
 // requesting a single output.
 func TestOutput_sensitive(t *testing.T) {
@@ -83,6 +189,7 @@ func TestOutput_sensitive(t *testing.T) {
 		"human": arguments.ViewHuman,
 		"json":  arguments.ViewJSON,
 		"raw":   arguments.ViewRaw,
+		"yaml":  arguments.ViewYAML,
 	}
 	for name, vt := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -220,11 +327,274 @@ func TestOutputJSON_empty(t *testing.T) {
 	}
 }
 
+// JSON output marks values which are only known after apply as unknown, so
+// they can be told apart from real null values.
+func TestOutputJSON_unknown(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"id":      {Value: cty.UnknownVal(cty.String)},
+		"nothing": {Value: cty.NullVal(cty.String)},
+		"obj": {Value: cty.ObjectVal(map[string]cty.Value{
+			"a": cty.StringVal("x"),
+			"b": cty.UnknownVal(cty.String),
+		})},
+	}
+
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOutput(arguments.ViewJSON, NewView(streams))
+
+	diags := v.Output("", outputs)
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %s", diags.ErrWithWarnings())
+	}
+
+	got := map[string]map[string]interface{}{}
+	if err := json.Unmarshal([]byte(done(t).Stdout()), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]interface{}{
+		"id": {
+			"sensitive": false,
+			"type":      "string",
+			"value":     nil,
+			"unknown":   true,
+		},
+		"nothing": {
+			"sensitive": false,
+			"type":      "string",
+			"value":     nil,
+		},
+		"obj": {
+			"sensitive": false,
+			"type":      []interface{}{"object", map[string]interface{}{"a": "string", "b": "string"}},
+			"value":     map[string]interface{}{"a": "x", "b": nil},
+			"unknown":   map[string]interface{}{"b": true},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+// A single unknown JSON output value is rendered as null, with a warning.
+func TestOutputJSON_singleUnknown(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOutput(arguments.ViewJSON, NewView(streams))
+
+	diags := v.Output("id", map[string]*states.OutputValue{
+		"id": {Value: cty.UnknownVal(cty.String)},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if len(diags) != 1 || diags[0].Description().Summary != "Output value not yet known" {
+		t.Fatalf("expected a warning about the unknown value, got: %s", diags.ErrWithWarnings())
+	}
+
+	if got, want := done(t).Stdout(), "null\n"; got != want {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+// YAML output preserves value types and redacts sensitive values unless
+// -show-sensitive is set.
+func TestOutputYAML_all(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"foo": {
+			Value:     cty.StringVal("secret"),
+			Sensitive: true,
+		},
+		"bar": {
+			Value: cty.ListVal([]cty.Value{cty.True, cty.False}),
+		},
+		"baz": {
+			Value: cty.NumberIntVal(5),
+		},
+	}
+
+	testCases := map[string]struct {
+		showSensitive bool
+		want          string
+	}{
+		"redacted": {
+			false,
+			`"bar":
+  "sensitive": false
+  "value":
+  - true
+  - false
+"baz":
+  "sensitive": false
+  "value": 5
+"foo":
+  "sensitive": true
+  "value": null
+`,
+		},
+		"show-sensitive": {
+			true,
+			`"bar":
+  "sensitive": false
+  "value":
+  - true
+  - false
+"baz":
+  "sensitive": false
+  "value": 5
+"foo":
+  "sensitive": true
+  "value": "secret"
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			view := NewView(streams)
+			view.SetShowSensitive(tc.showSensitive)
+			v := NewOutput(arguments.ViewYAML, view)
+			diags := v.Output("", outputs)
+
+			if diags.HasErrors() {
+				t.Fatalf("unexpected diagnostics: %s", diags)
+			}
+
+			if got := done(t).Stdout(); got != tc.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// Env output renders shell-quoted NAME=value lines, skipping sensitive values
+// unless requested and names which aren't valid shell identifiers.
+func TestOutputEnv_all(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"foo": {
+			Value:     cty.StringVal("secret"),
+			Sensitive: true,
+		},
+		"bar": {
+			Value: cty.ListVal([]cty.Value{cty.True, cty.False}),
+		},
+		"baz": {
+			Value: cty.NumberIntVal(5),
+		},
+		"quote": {
+			Value: cty.StringVal("it's"),
+		},
+		"not-valid": {
+			Value: cty.StringVal("boop"),
+		},
+	}
+
+	testCases := map[string]struct {
+		showSensitive bool
+		want          string
+	}{
+		"redacted": {
+			false,
+			`BAR='[true,false]'
+BAZ='5'
+QUOTE='it'\''s'
+`,
+		},
+		"show-sensitive": {
+			true,
+			`BAR='[true,false]'
+BAZ='5'
+FOO='secret'
+QUOTE='it'\''s'
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			view := NewView(streams)
+			view.SetShowSensitive(tc.showSensitive)
+			v := NewOutput(arguments.ViewEnv, view)
+			diags := v.Output("", outputs)
+
+			if diags.HasErrors() {
+				t.Fatalf("unexpected diagnostics: %s", diags)
+			}
+			if len(diags) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+			}
+			if got, want := diags[0].Description().Summary, "Output skipped"; got != want {
+				t.Errorf("unexpected diagnostics: %s", diags)
+			}
+
+			if got := done(t).Stdout(); got != tc.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// Streaming JSON output renders one object per line in alphabetical order.
+func TestOutputJSONStream(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"foo": {
+			Value:     cty.StringVal("secret"),
+			Sensitive: true,
+		},
+		"bar": {
+			Value: cty.ListVal([]cty.Value{cty.True, cty.False}),
+		},
+	}
+
+	testCases := map[string]struct {
+		outputs map[string]*states.OutputValue
+		name    string
+		want    string
+	}{
+		"all": {
+			outputs,
+			"",
+			`{"name":"bar","value":[true,false],"sensitive":false}
+{"name":"foo","value":"secret","sensitive":true}
+`,
+		},
+		"single": {
+			outputs,
+			"foo",
+			`{"name":"foo","value":"secret","sensitive":true}
+`,
+		},
+		"empty": {
+			map[string]*states.OutputValue{},
+			"",
+			"",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			v := NewOutputJSONStream(NewView(streams))
+
+			diags := v.Output(tc.name, tc.outputs)
+
+			if diags.HasErrors() {
+				t.Fatalf("unexpected diagnostics: %s", diags)
+			}
+
+			if got := done(t).Stdout(); got != tc.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, tc.want)
+			}
+		})
+	}
+}
+
 // Human and raw formats render a warning if there are no outputs.
 func TestOutput_emptyWarning(t *testing.T) {
 	testCases := map[string]arguments.ViewType{
 		"human": arguments.ViewHuman,
 		"raw":   arguments.ViewRaw,
+		"env":   arguments.ViewEnv,
 	}
 
 	for name, vt := range testCases {
@@ -261,8 +631,14 @@ func TestOutputRaw(t *testing.T) {
 		"str":      cty.StringVal("bar"),
 		"multistr": cty.StringVal("bar\nbaz"),
 		"num":      cty.NumberIntVal(2),
+		"bignum":   cty.MustParseNumberVal("123456789012345678901234567890"),
+		"decimal":  cty.MustParseNumberVal("1.50"),
+		"negative": cty.MustParseNumberVal("-0.000000000000000000001"),
 		"bool":     cty.True,
+		"false":    cty.False,
 		"obj":      cty.EmptyObjectVal,
+		"list":     cty.ListVal([]cty.Value{cty.StringVal("bar")}),
+		"map":      cty.MapVal(map[string]cty.Value{"bar": cty.StringVal("baz")}),
 		"null":     cty.NullVal(cty.String),
 		"unknown":  cty.UnknownVal(cty.String),
 	}
@@ -274,8 +650,14 @@ func TestOutputRaw(t *testing.T) {
 		"str":      {WantOutput: "bar"},
 		"multistr": {WantOutput: "bar\nbaz"},
 		"num":      {WantOutput: "2"},
+		"bignum":   {WantOutput: "123456789012345678901234567890"},
+		"decimal":  {WantOutput: "1.5"},
+		"negative": {WantOutput: "-0.000000000000000000001"},
 		"bool":     {WantOutput: "true"},
+		"false":    {WantOutput: "false"},
 		"obj":      {WantErr: true},
+		"list":     {WantErr: true},
+		"map":      {WantErr: true},
 		"null":     {WantErr: true},
 		"unknown":  {WantErr: true},
 	}
@@ -306,6 +688,63 @@ func TestOutputRaw(t *testing.T) {
 	}
 }
 
+// Raw only renders sensitive outputs if -show-sensitive is set.
+// In raw mode, a missing output is an error even if there are no outputs at
+// all, so that it can't be mistaken for an empty string.
+func TestOutputRaw_noOutputs(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOutput(arguments.ViewRaw, NewView(streams))
+
+	diags := v.Output("foo", map[string]*states.OutputValue{})
+
+	if got, want := done(t).Stdout(), ""; got != want {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+	if !diags.HasErrors() {
+		t.Fatal("expected an error, got none")
+	}
+	if got, want := diags[0].Description().Summary, `Output "foo" not found`; got != want {
+		t.Errorf("wrong summary\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestOutputRaw_sensitive(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"secret": {Value: cty.StringVal("hunter2"), Sensitive: true},
+	}
+
+	t.Run("hidden", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		v := NewOutput(arguments.ViewRaw, NewView(streams))
+
+		diags := v.Output("secret", outputs)
+		if !diags.HasErrors() {
+			t.Fatalf("succeeded, but want error")
+		}
+		if got, want := diags.Err().Error(), "marked as sensitive"; !strings.Contains(got, want) {
+			t.Errorf("wrong diagnostic\ngot:  %s\nwant: %s", got, want)
+		}
+		if got := done(t).Stdout(); got != "" {
+			t.Errorf("unexpected output: %q", got)
+		}
+	})
+
+	t.Run("shown", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		view := NewView(streams)
+		view.SetShowSensitive(true)
+		v := NewOutput(arguments.ViewRaw, view)
+
+		diags := v.Output("secret", outputs)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags)
+		}
+		if got, want := done(t).Stdout(), "hunter2"; got != want {
+			t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+}
+
 // Raw cannot render all outputs.
 func TestOutputRaw_all(t *testing.T) {
 	streams, done := terminal.StreamsForTesting(t)
@@ -337,6 +776,8 @@ func TestOutput_missing(t *testing.T) {
 		"human": arguments.ViewHuman,
 		"json":  arguments.ViewJSON,
 		"raw":   arguments.ViewRaw,
+		"yaml":  arguments.ViewYAML,
+		"env":   arguments.ViewEnv,
 	}
 
 	for name, vt := range testCases {
@@ -6,6 +6,8 @@
 package views
 
 import (
+	"os"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/mitchellh/colorstring"
 	"github.com/we-dcode/opentofu/pkg/command/arguments"
@@ -187,3 +189,16 @@ func (v *View) outputHorizRule() {
 func (v *View) SetShowSensitive(showSensitive bool) {
 	v.showSensitive = showSensitive
 }
+
+// WithStdout returns a copy of the view which writes its regular output to
+// the given file instead of the configured stdout stream. Diagnostics are
+// still written to the original stderr stream.
+func (v *View) WithStdout(f *os.File) *View {
+	ret := *v
+	ret.streams = &terminal.Streams{
+		Stdout: &terminal.OutputStream{File: f},
+		Stderr: v.streams.Stderr,
+		Stdin:  v.streams.Stdin,
+	}
+	return &ret
+}
@@ -6,11 +6,15 @@
 package command
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
 	"github.com/we-dcode/opentofu/pkg/addrs"
 	"github.com/we-dcode/opentofu/pkg/backend"
@@ -113,6 +117,302 @@ func TestWorkspace_createAndList(t *testing.T) {
 	}
 }
 
+// Create some workspaces and test the JSON list output.
+func TestWorkspace_createAndListJSON(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	envs := []string{"test_a", "test_b"}
+
+	// create multiple workspaces
+	for _, env := range envs {
+		ui := new(cli.MockUi)
+		view, _ := testView(t)
+		newCmd := &WorkspaceNewCommand{
+			Meta: Meta{Ui: ui, View: view},
+		}
+		if code := newCmd.Run([]string{env}); code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+		}
+	}
+
+	t.Setenv(WorkspaceNameEnvVar, "test_a")
+
+	listCmd := &WorkspaceListCommand{}
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	listCmd.Meta = Meta{Ui: ui, View: view}
+
+	if code := listCmd.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	var got workspaceListJSON
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %s\n\n%s", err, ui.OutputWriter)
+	}
+
+	want := workspaceListJSON{
+		Workspaces: []string{"default", "test_a", "test_b"},
+		Current:    "test_a",
+		Overridden: true,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong result\n%s", diff)
+	}
+}
+
+// Create some workspaces and test the sorted and filtered list output.
+func TestWorkspace_listSortAndFilter(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	// create multiple workspaces, ending with test_b selected
+	for _, env := range []string{"prod-b", "prod-a", "test_b"} {
+		ui := new(cli.MockUi)
+		view, _ := testView(t)
+		newCmd := &WorkspaceNewCommand{
+			Meta: Meta{Ui: ui, View: view},
+		}
+		if code := newCmd.Run([]string{env}); code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+		}
+	}
+
+	testCases := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"sort by name": {
+			[]string{"-sort=name"},
+			"default\n  prod-a\n  prod-b\n* test_b",
+		},
+		"filter excludes current workspace": {
+			[]string{"-sort=name", "-filter=prod-*"},
+			"prod-a\n  prod-b",
+		},
+		"filter includes current workspace": {
+			[]string{"-filter=test_*"},
+			"* test_b",
+		},
+		"empty result": {
+			[]string{"-filter=staging-*"},
+			"",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			listCmd := &WorkspaceListCommand{}
+			ui := new(cli.MockUi)
+			view, _ := testView(t)
+			listCmd.Meta = Meta{Ui: ui, View: view}
+
+			if code := listCmd.Run(tc.args); code != 0 {
+				t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+			}
+
+			actual := strings.TrimSpace(ui.OutputWriter.String())
+			if actual != tc.expected {
+				t.Fatalf("\nexpected: %q\nactual:  %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWorkspace_listLong(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	// The prod workspace has a state snapshot, the test workspace has none.
+	for _, env := range []string{"prod", "test"} {
+		if err := os.MkdirAll(filepath.Join(local.DefaultWorkspaceDir, env), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	statePath := filepath.Join(local.DefaultWorkspaceDir, "prod", DefaultStateFilename)
+	err := statemgr.WriteAndPersist(statemgr.NewFilesystem(statePath, encryption.StateEncryptionDisabled()), states.NewState(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	if err := os.Chtimes(statePath, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"long": {
+			[]string{"-long"},
+			"* default\n  prod     2024-03-01T12:30:00Z\n  test",
+		},
+		"long without modification times": {
+			[]string{"-long", "-filter=test"},
+			"test",
+		},
+		"default output is unchanged": {
+			nil,
+			"* default\n  prod\n  test",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ui := new(cli.MockUi)
+			view, _ := testView(t)
+			listCmd := &WorkspaceListCommand{
+				Meta: Meta{Ui: ui, View: view},
+			}
+
+			if code := listCmd.Run(tc.args); code != 0 {
+				t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+			}
+
+			actual := strings.TrimSpace(ui.OutputWriter.String())
+			if actual != tc.expected {
+				t.Fatalf("\nexpected: %q\nactual:  %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWorkspacesLastModified_stateMgrError(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), DefaultStateFilename)
+	stateMgr := statemgr.NewFilesystem(statePath, encryption.StateEncryptionDisabled())
+	if err := statemgr.WriteAndPersist(stateMgr, states.NewState(), nil); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	if err := os.Chtimes(statePath, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	// The state of the broken workspace can't be loaded, which must only
+	// leave out its modification time.
+	b := &workspaceStateMgrsBackend{
+		stateMgrs: map[string]statemgr.Full{"prod": stateMgr},
+	}
+	got := workspacesLastModified(b, []string{"broken", "prod"})
+	want := map[string]time.Time{"prod": modified}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong modification times\n%s", diff)
+	}
+}
+
+// workspaceStateMgrsBackend is a backend.Backend returning the given state
+// managers, and failing to load the state of any other workspace.
+type workspaceStateMgrsBackend struct {
+	backend.Backend
+	stateMgrs map[string]statemgr.Full
+}
+
+func (b *workspaceStateMgrsBackend) StateMgr(workspace string) (statemgr.Full, error) {
+	if stateMgr, ok := b.stateMgrs[workspace]; ok {
+		return stateMgr, nil
+	}
+	return nil, fmt.Errorf("failed to load state of workspace %q", workspace)
+}
+
+func TestWorkspace_listShowLocks(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	// The prod workspace has the lock information of a crashed run left
+	// behind, the test workspace is not locked.
+	for _, env := range []string{"prod", "test"} {
+		if err := os.MkdirAll(filepath.Join(local.DefaultWorkspaceDir, env), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lockInfo := &statemgr.LockInfo{
+		ID:        "f1e2d3c4",
+		Operation: "OperationTypeApply",
+		Who:       "alice@example",
+		Created:   time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+	}
+	lockInfoPath := filepath.Join(local.DefaultWorkspaceDir, "prod", "."+DefaultStateFilename+".lock.info")
+	if err := os.WriteFile(lockInfoPath, lockInfo.Marshal(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	listCmd := &WorkspaceListCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := listCmd.Run([]string{"-show-locks"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	expected := "* default\n  prod (locked by alice@example, during OperationTypeApply, since 2024-03-01T12:30:00Z, ID f1e2d3c4)\n  test"
+	actual := strings.TrimSpace(ui.OutputWriter.String())
+	if actual != expected {
+		t.Fatalf("\nexpected: %q\nactual:  %q", expected, actual)
+	}
+
+	// Listing the locks must not have released or otherwise touched them.
+	if _, err := os.Stat(lockInfoPath); err != nil {
+		t.Fatalf("lock information was removed: %s", err)
+	}
+	if _, err := os.Stat(DefaultStateFilename); !os.IsNotExist(err) {
+		t.Fatalf("the default workspace state was created: %v", err)
+	}
+}
+
+func TestProbeWorkspaceLock(t *testing.T) {
+	t.Run("unlocked", func(t *testing.T) {
+		mgr := statemgr.NewFullFake(nil, nil)
+		if got := probeWorkspaceLock("default", mgr); got.Status != workspaceLockUnlocked {
+			t.Fatalf("expected %q, got %q", workspaceLockUnlocked, got.Status)
+		}
+		// The probe lock must have been released.
+		if _, err := mgr.Lock(statemgr.NewLockInfo()); err != nil {
+			t.Fatalf("state is still locked after probing: %s", err)
+		}
+	})
+	t.Run("locked", func(t *testing.T) {
+		mgr := statemgr.NewFullFake(nil, nil)
+		if _, err := mgr.Lock(statemgr.NewLockInfo()); err != nil {
+			t.Fatal(err)
+		}
+		got := probeWorkspaceLock("default", mgr)
+		if got.Status != workspaceLockLocked {
+			t.Fatalf("expected %q, got %q", workspaceLockLocked, got.Status)
+		}
+		if got.Info == nil {
+			t.Fatal("missing lock information")
+		}
+	})
+	t.Run("locking disabled", func(t *testing.T) {
+		mgr := &statemgr.LockDisabled{Inner: statemgr.NewFullFake(nil, nil)}
+		if got := probeWorkspaceLock("default", mgr); got.Status != workspaceLockUnknown {
+			t.Fatalf("expected %q, got %q", workspaceLockUnknown, got.Status)
+		}
+	})
+}
+
+func TestWorkspace_listInvalidFlags(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	for _, args := range [][]string{{"-sort=size"}, {"-filter=["}} {
+		listCmd := &WorkspaceListCommand{}
+		ui := new(cli.MockUi)
+		view, _ := testView(t)
+		listCmd.Meta = Meta{Ui: ui, View: view}
+
+		if code := listCmd.Run(args); code != 1 {
+			t.Fatalf("expected failure for %v, got: %d", args, code)
+		}
+	}
+}
+
 // Create some workspaces and test the show output.
 func TestWorkspace_createAndShow(t *testing.T) {
 	// Create a temporary working directory that is empty
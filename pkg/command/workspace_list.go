@@ -7,11 +7,19 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"path"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/posener/complete"
 
+	"github.com/we-dcode/opentofu/pkg/backend"
+	"github.com/we-dcode/opentofu/pkg/states/statemgr"
 	"github.com/we-dcode/opentofu/pkg/tfdiags"
 )
 
@@ -24,14 +32,30 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	envCommandShowWarning(c.Ui, c.LegacyName)
 
+	var jsonOutput, longOutput, showLocks bool
+	var sortOrder, filter string
 	cmdFlags := c.Meta.defaultFlagSet("workspace list")
 	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.BoolVar(&longOutput, "long", false, "long")
+	cmdFlags.BoolVar(&showLocks, "show-locks", false, "show-locks")
+	cmdFlags.StringVar(&sortOrder, "sort", workspaceSortNone, "sort order")
+	cmdFlags.StringVar(&filter, "filter", "", "glob")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return 1
 	}
 
+	if sortOrder != workspaceSortNone && sortOrder != workspaceSortName {
+		c.Ui.Error(fmt.Sprintf("Invalid -sort value %q: must be %q or %q.\n", sortOrder, workspaceSortName, workspaceSortNone))
+		return 1
+	}
+	if _, err := path.Match(filter, ""); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid -filter pattern %q: %s\n", filter, err))
+		return 1
+	}
+
 	args = cmdFlags.Args()
 	configPath, err := modulePath(args)
 	if err != nil {
@@ -74,8 +98,38 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 		return 1
 	}
 
+	states = filterWorkspaces(states, filter)
+	if sortOrder == workspaceSortName {
+		sort.Strings(states)
+	}
+
 	env, isOverridden := c.WorkspaceOverridden()
 
+	var lastModified map[string]time.Time
+	if longOutput {
+		lastModified = workspacesLastModified(b, states)
+	}
+
+	var locks map[string]workspaceLock
+	if showLocks {
+		var lockDiags tfdiags.Diagnostics
+		locks, lockDiags = workspacesLocks(b, states)
+		diags = diags.Append(lockDiags)
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	if jsonOutput {
+		return c.outputJSON(states, env, isOverridden, lastModified, locks)
+	}
+
+	nameWidth := 0
+	for _, s := range states {
+		nameWidth = max(nameWidth, len(s))
+	}
+
 	var out bytes.Buffer
 	for _, s := range states {
 		if s == env {
@@ -83,7 +137,15 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 		} else {
 			out.WriteString("  ")
 		}
-		out.WriteString(s + "\n")
+		if t, ok := lastModified[s]; ok {
+			out.WriteString(fmt.Sprintf("%-*s  %s", nameWidth, s, t.UTC().Format(time.RFC3339)))
+		} else {
+			out.WriteString(s)
+		}
+		if lock, ok := locks[s]; ok && lock.Status != workspaceLockUnlocked {
+			out.WriteString(" " + lock.annotation())
+		}
+		out.WriteString("\n")
 	}
 
 	c.Ui.Output(out.String())
@@ -95,12 +157,232 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 	return 0
 }
 
+const (
+	workspaceSortNone = "none"
+	workspaceSortName = "name"
+)
+
+// filterWorkspaces returns the workspaces whose names match the given glob
+// pattern, using path.Match semantics. An empty pattern matches everything.
+// The pattern must have been validated beforehand.
+func filterWorkspaces(workspaces []string, pattern string) []string {
+	if pattern == "" {
+		return workspaces
+	}
+	var ret []string
+	for _, w := range workspaces {
+		if ok, _ := path.Match(pattern, w); ok {
+			ret = append(ret, w)
+		}
+	}
+	return ret
+}
+
+// workspacesLastModified returns the time the state of each of the given
+// workspaces was last modified. Workspaces are omitted if the backend doesn't
+// record the modification time, if they have no state yet, or if their state
+// can't be loaded, which is only logged so that the list is still shown.
+func workspacesLastModified(b backend.Backend, workspaces []string) map[string]time.Time {
+	ret := make(map[string]time.Time)
+
+	for _, w := range workspaces {
+		stateMgr, err := b.StateMgr(w)
+		if err != nil {
+			log.Printf("[WARN] Failed to load state for workspace %q: %s", w, err)
+			continue
+		}
+		reporter, ok := stateMgr.(statemgr.LastModifiedReporter)
+		if !ok {
+			continue
+		}
+		t, ok, err := reporter.StateLastModified()
+		if err != nil {
+			log.Printf("[WARN] Failed to read the modification time of workspace %q: %s", w, err)
+			continue
+		}
+		if ok {
+			ret[w] = t
+		}
+	}
+
+	return ret
+}
+
+const (
+	workspaceLockUnlocked = "unlocked"
+	workspaceLockLocked   = "locked"
+	workspaceLockUnknown  = "unknown"
+)
+
+// workspaceLock describes whether the state of a workspace is locked. Info is
+// only set for locked workspaces, and may be nil if the backend doesn't report
+// who holds the lock.
+type workspaceLock struct {
+	Status string
+	Info   *statemgr.LockInfo
+}
+
+// annotation returns the note shown next to the workspace name in the
+// human-readable workspace list.
+func (l workspaceLock) annotation() string {
+	if l.Status != workspaceLockLocked || l.Info == nil {
+		return "(" + l.Status + ")"
+	}
+	var details []string
+	if l.Info.Who != "" {
+		details = append(details, "by "+l.Info.Who)
+	}
+	if l.Info.Operation != "" {
+		details = append(details, "during "+l.Info.Operation)
+	}
+	if !l.Info.Created.IsZero() {
+		details = append(details, "since "+l.Info.Created.UTC().Format(time.RFC3339))
+	}
+	if l.Info.ID != "" {
+		details = append(details, "ID "+l.Info.ID)
+	}
+	if len(details) == 0 {
+		return "(locked)"
+	}
+	return "(locked " + strings.Join(details, ", ") + ")"
+}
+
+// workspacesLocks returns whether the state of each of the given workspaces is
+// currently locked. The locks are never held: backends which can report the
+// lock status are queried, and for all others a lock is acquired and released
+// immediately. Workspaces whose lock status cannot be determined are reported
+// as unknown rather than failing the command.
+func workspacesLocks(b backend.Backend, workspaces []string) (map[string]workspaceLock, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	ret := make(map[string]workspaceLock, len(workspaces))
+
+	for _, w := range workspaces {
+		stateMgr, err := b.StateMgr(w)
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("Failed to load state for workspace %q: %w", w, err))
+			return nil, diags
+		}
+		ret[w] = probeWorkspaceLock(w, stateMgr)
+	}
+
+	return ret, diags
+}
+
+func probeWorkspaceLock(workspace string, stateMgr statemgr.Full) workspaceLock {
+	if reporter, ok := stateMgr.(statemgr.LockReporter); ok {
+		info, err := reporter.LockStatus()
+		if err != nil {
+			log.Printf("[WARN] Failed to read the lock status of workspace %q: %s", workspace, err)
+			return workspaceLock{Status: workspaceLockUnknown}
+		}
+		if info == nil {
+			return workspaceLock{Status: workspaceLockUnlocked}
+		}
+		return workspaceLock{Status: workspaceLockLocked, Info: info}
+	}
+
+	if _, ok := stateMgr.(*statemgr.LockDisabled); ok {
+		return workspaceLock{Status: workspaceLockUnknown}
+	}
+	if optional, ok := stateMgr.(statemgr.OptionalLocker); ok && !optional.IsLockingEnabled() {
+		return workspaceLock{Status: workspaceLockUnknown}
+	}
+
+	lockInfo := statemgr.NewLockInfo()
+	lockInfo.Operation = "workspace list"
+	id, err := stateMgr.Lock(lockInfo)
+	if err != nil {
+		var lockErr *statemgr.LockError
+		if errors.As(err, &lockErr) && lockErr.Info != nil {
+			return workspaceLock{Status: workspaceLockLocked, Info: lockErr.Info}
+		}
+		log.Printf("[WARN] Failed to probe the lock of workspace %q: %s", workspace, err)
+		return workspaceLock{Status: workspaceLockUnknown}
+	}
+	if err := stateMgr.Unlock(id); err != nil {
+		log.Printf("[ERROR] Failed to release the probe lock of workspace %q: %s", workspace, err)
+	}
+	if id == "" {
+		// Managers which don't actually lock return an empty lock ID, so we
+		// can't tell whether anybody else holds the lock.
+		return workspaceLock{Status: workspaceLockUnknown}
+	}
+	return workspaceLock{Status: workspaceLockUnlocked}
+}
+
+// workspaceListJSON is the machine-readable form of the workspace list
+// emitted when the -json flag is set.
+type workspaceListJSON struct {
+	Workspaces []string `json:"workspaces"`
+	Current    string   `json:"current"`
+	Overridden bool     `json:"overridden"`
+
+	// LastModified maps workspace names to the RFC 3339 time their state was
+	// last modified. It is only populated with the -long flag.
+	LastModified map[string]string `json:"last_modified,omitempty"`
+
+	// Locks maps workspace names to their lock status. It is only populated
+	// with the -show-locks flag.
+	Locks map[string]workspaceLockJSON `json:"locks,omitempty"`
+}
+
+// workspaceLockJSON is the machine-readable form of workspaceLock.
+type workspaceLockJSON struct {
+	Status string             `json:"status"`
+	Info   *statemgr.LockInfo `json:"info,omitempty"`
+}
+
+func (c *WorkspaceListCommand) outputJSON(workspaces []string, current string, isOverridden bool, lastModified map[string]time.Time, locks map[string]workspaceLock) int {
+	if workspaces == nil {
+		workspaces = []string{}
+	}
+
+	var lastModifiedJSON map[string]string
+	if len(lastModified) > 0 {
+		lastModifiedJSON = make(map[string]string, len(lastModified))
+		for w, t := range lastModified {
+			lastModifiedJSON[w] = t.UTC().Format(time.RFC3339)
+		}
+	}
+
+	var locksJSON map[string]workspaceLockJSON
+	if len(locks) > 0 {
+		locksJSON = make(map[string]workspaceLockJSON, len(locks))
+		for w, l := range locks {
+			locksJSON[w] = workspaceLockJSON{Status: l.Status, Info: l.Info}
+		}
+	}
+
+	out, err := json.MarshalIndent(workspaceListJSON{
+		Workspaces:   workspaces,
+		Current:      current,
+		Overridden:   isOverridden,
+		LastModified: lastModifiedJSON,
+		Locks:        locksJSON,
+	}, "", "  ")
+	if err != nil {
+		var diags tfdiags.Diagnostics
+		c.showDiagnostics(diags.Append(fmt.Errorf("Failed to marshal workspace list to JSON: %w", err)))
+		return 1
+	}
+
+	c.Ui.Output(string(out))
+
+	return 0
+}
+
 func (c *WorkspaceListCommand) AutocompleteArgs() complete.Predictor {
 	return complete.PredictDirs("")
 }
 
 func (c *WorkspaceListCommand) AutocompleteFlags() complete.Flags {
-	return nil
+	return complete.Flags{
+		"-json":       complete.PredictNothing,
+		"-long":       complete.PredictNothing,
+		"-show-locks": complete.PredictNothing,
+		"-sort":       complete.PredictSet(workspaceSortName, workspaceSortNone),
+		"-filter":     complete.PredictAnything,
+	}
 }
 
 func (c *WorkspaceListCommand) Help() string {
@@ -111,6 +393,25 @@ Usage: tofu [global options] workspace list [options]
 
 Options:
 
+  -json              If specified, the list of workspaces and the current
+                     workspace will be printed in JSON format.
+
+  -long              If specified, the time the state of each workspace was
+                     last modified is listed next to its name, for backends
+                     which record it.
+
+  -show-locks        If specified, workspaces whose state is currently locked
+                     are annotated with the lock holder, for example after a
+                     run crashed without releasing its lock. The locks are
+                     only probed, never held. Workspaces whose lock status
+                     the backend can't report are marked "(unknown)".
+
+  -sort=name|none    Sort order of the listed workspaces. "none" keeps the
+                     order returned by the backend. Defaults to "none".
+
+  -filter=GLOB       Only list workspaces whose name matches the given glob
+                     pattern, for example -filter='prod-*'.
+
   -var 'foo=bar'     Set a value for one of the input variables in the root
                      module of the configuration. Use this option more than
                      once to set more than one variable.
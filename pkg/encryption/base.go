@@ -6,6 +6,9 @@
 package encryption
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -32,9 +35,12 @@ type baseEncryption struct {
 	inputEncMeta  map[keyprovider.MetaStorageKey][]byte
 	outputEncMeta map[keyprovider.MetaStorageKey][]byte
 	staticEval    *configs.StaticEvaluator
+
+	// unencryptedReads allows the unencrypted method as a decryption fallback even if enforced is set.
+	unencryptedReads bool
 }
 
-func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (*baseEncryption, hcl.Diagnostics) {
+func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bool, unencryptedReads bool, name string, staticEval *configs.StaticEvaluator) (*baseEncryption, hcl.Diagnostics) {
 	base := &baseEncryption{
 		enc:           enc,
 		target:        target,
@@ -43,6 +49,8 @@ func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bo
 		inputEncMeta:  make(map[keyprovider.MetaStorageKey][]byte),
 		outputEncMeta: make(map[keyprovider.MetaStorageKey][]byte),
 		staticEval:    staticEval,
+
+		unencryptedReads: unencryptedReads,
 	}
 	// Setup the encryptor
 	//
@@ -79,9 +87,40 @@ func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bo
 }
 
 type basedata struct {
-	Meta    map[keyprovider.MetaStorageKey][]byte `json:"meta"`
-	Data    []byte                                `json:"encrypted_data"`
-	Version string                                `json:"encryption_version"` // This is both a sigil for a valid encrypted payload and a future compatibility field
+	Meta        map[keyprovider.MetaStorageKey][]byte `json:"meta"`
+	Data        []byte                                `json:"encrypted_data"`
+	Version     string                                `json:"encryption_version"` // This is both a sigil for a valid encrypted payload and a future compatibility field
+	Fingerprint *dataFingerprint                      `json:"fingerprint,omitempty"`
+}
+
+// dataFingerprint describes the encrypted data as it was written. It is computed over the ciphertext only, so it
+// reveals nothing about the plaintext or the keys. It allows decrypt to tell data that was damaged or truncated after
+// it was written apart from data that fails authentication, which means a wrong key or deliberate tampering.
+//
+// Payloads written before the fingerprint was introduced have none, which is why it is optional.
+type dataFingerprint struct {
+	Length int    `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+func newDataFingerprint(data []byte) *dataFingerprint {
+	sum := sha256.Sum256(data)
+	return &dataFingerprint{
+		Length: len(data),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+}
+
+// verify returns an error if data does not match the fingerprint.
+func (f *dataFingerprint) verify(data []byte) error {
+	actual := newDataFingerprint(data)
+	if actual.Length != f.Length {
+		return fmt.Errorf("expected %d bytes of encrypted data, found %d", f.Length, actual.Length)
+	}
+	if actual.SHA256 != f.SHA256 {
+		return fmt.Errorf("the checksum of the encrypted data does not match, expected %s, found %s", f.SHA256, actual.SHA256)
+	}
+	return nil
 }
 
 func IsEncryptionPayload(data []byte) (bool, error) {
@@ -110,9 +149,10 @@ func (base *baseEncryption) encrypt(data []byte, enhance func(basedata) interfac
 	}
 
 	es := basedata{
-		Version: encryptionVersion,
-		Meta:    base.outputEncMeta,
-		Data:    encd,
+		Version:     encryptionVersion,
+		Meta:        base.outputEncMeta,
+		Data:        encd,
+		Fingerprint: newDataFingerprint(encd),
 	}
 	jsond, err := json.Marshal(enhance(es))
 	if err != nil {
@@ -179,6 +219,13 @@ func (base *baseEncryption) decrypt(data []byte, validator func([]byte) error) (
 		return nil, StatusUnknown, fmt.Errorf("invalid encrypted payload version: %s != %s", inputData.Version, encryptionVersion)
 	}
 
+	if inputData.Fingerprint != nil {
+		// Damaged data can't be decrypted by any method, so there is no point in trying.
+		if err := inputData.Fingerprint.verify(inputData.Data); err != nil {
+			return nil, StatusUnknown, fmt.Errorf("the encrypted data for %s is malformed or truncated: %w", base.name, err)
+		}
+	}
+
 	// TODO Discuss if we should potentially cache this based on a json-encoded version of inputData.Meta and reduce overhead dramatically
 	methods, diags := base.buildTargetMethods(inputData.Meta, outputData.Meta)
 	if diags.HasErrors() {
@@ -209,6 +256,10 @@ func (base *baseEncryption) decrypt(data []byte, validator func([]byte) error) (
 
 	// This is good enough for now until we have better/distinct errors
 	errMessage := "decryption failed for all provided methods: "
+	if inputData.Fingerprint != nil {
+		// The data is intact, so the methods must have rejected it while authenticating it.
+		errMessage = fmt.Sprintf("authentication failed for %s, either the key is wrong or the encrypted data was tampered with: ", base.name)
+	}
 	sep := ""
 	for _, err := range errs {
 		errMessage += err.Error() + sep
@@ -216,3 +267,45 @@ func (base *baseEncryption) decrypt(data []byte, validator func([]byte) error) (
 	}
 	return nil, StatusUnknown, errors.New(errMessage)
 }
+
+// validationPayload is the data encrypted and decrypted by validate.
+var validationPayload = []byte("OpenTofu encryption validation")
+
+// validate encrypts a small payload with the encryption method and decrypts it again. Decryption builds the target
+// methods from the metadata written during encryption, so this also verifies that the key providers can recover the
+// decryption keys. Nothing is read from or written to any storage.
+func (base *baseEncryption) validate() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if len(base.encMethods) == 0 || unencrypted.Is(base.encMethods[0]) {
+		// Nothing to validate, unencrypted data is passed through as-is.
+		return diags
+	}
+
+	encrypted, err := base.encrypt(validationPayload, func(data basedata) interface{} { return data })
+	if err != nil {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Encryption validation failed",
+			Detail:   fmt.Sprintf("Failed to encrypt a test payload for %s: %s", base.name, err),
+		})
+	}
+
+	decrypted, _, err := base.decrypt(encrypted, func([]byte) error {
+		return errors.New("the test payload was not encrypted")
+	})
+	if err != nil {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Encryption validation failed",
+			Detail:   fmt.Sprintf("Failed to decrypt a test payload for %s: %s", base.name, err),
+		})
+	}
+	if !bytes.Equal(decrypted, validationPayload) {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Encryption validation failed",
+			Detail:   fmt.Sprintf("The decrypted test payload for %s does not match the original payload.", base.name),
+		})
+	}
+	return diags
+}
@@ -0,0 +1,316 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/configs"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/static"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcm"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/unencrypted"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
+)
+
+func TestEncryption_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		rawConfig string
+		wantErr   string
+	}{
+		"working": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+				plan {
+					method = method.aes_gcm.example
+				}
+			`,
+		},
+		"unencrypted": {
+			rawConfig: `
+				method "unencrypted" "example" {
+				}
+				state {
+					method = method.unencrypted.example
+				}
+			`,
+		},
+		"wrong-decryption-key": {
+			rawConfig: `
+				key_provider "forgetful" "basic" {
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.forgetful.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			wantErr: "Failed to decrypt a test payload for state",
+		},
+	}
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterKeyProvider(forgetfulKeyProviderDescriptor{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, diags := config.LoadConfigFromString("Test Config Source", test.rawConfig)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			diags = enc.Validate()
+			if test.wantErr == "" {
+				if diags.HasErrors() {
+					t.Fatalf("unexpected error: %s", diags.Error())
+				}
+				return
+			}
+			if !diags.HasErrors() {
+				t.Fatalf("expected an error containing %q, got none", test.wantErr)
+			}
+			if !strings.Contains(diags.Error(), test.wantErr) {
+				t.Fatalf("expected an error containing %q, got: %s", test.wantErr, diags.Error())
+			}
+		})
+	}
+}
+
+func TestEncryption_ValidateDisabled(t *testing.T) {
+	if diags := Disabled().Validate(); diags.HasErrors() {
+		t.Fatalf("unexpected error: %s", diags.Error())
+	}
+}
+
+func TestEncryption_BindToTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		bindToTarget     bool
+		wantRemoteDecErr bool
+	}{
+		"unbound": {
+			bindToTarget:     false,
+			wantRemoteDecErr: false,
+		},
+		"bound": {
+			bindToTarget:     true,
+			wantRemoteDecErr: true,
+		},
+	}
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rawConfig := fmt.Sprintf(`
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+					bind_to_target = %t
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+				remote_state_data_sources {
+					default {
+						method = method.aes_gcm.example
+					}
+				}
+			`, test.bindToTarget)
+			cfg, diags := config.LoadConfigFromString("Test Config Source", rawConfig)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+			enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			plainState := []byte(`{"terraform_version": "1.7.0", "serial": 1, "lineage": "test"}`)
+			encrypted, err := enc.State().EncryptState(plainState)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The same target must always be able to decrypt its own data.
+			decrypted, _, err := enc.State().DecryptState(encrypted)
+			if err != nil {
+				t.Fatalf("unexpected error decrypting with the same target: %v", err)
+			}
+			if !bytes.Equal(decrypted, plainState) {
+				t.Fatalf("incorrect decrypted state: %s", decrypted)
+			}
+
+			_, _, err = enc.RemoteState("other").DecryptState(encrypted)
+			if test.wantRemoteDecErr && err == nil {
+				t.Fatal("expected an error decrypting with a different target, got none")
+			}
+			if !test.wantRemoteDecErr && err != nil {
+				t.Fatalf("unexpected error decrypting with a different target: %v", err)
+			}
+		})
+	}
+}
+
+func TestEncryption_DecryptDamaged(t *testing.T) {
+	t.Parallel()
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	newEnc := func(t *testing.T, key string) Encryption {
+		t.Helper()
+		rawConfig := fmt.Sprintf(`
+			key_provider "static" "basic" {
+				key = %q
+			}
+			method "aes_gcm" "example" {
+				keys = key_provider.static.basic
+			}
+			state {
+				method = method.aes_gcm.example
+			}
+		`, key)
+		cfg, diags := config.LoadConfigFromString("Test Config Source", rawConfig)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return enc
+	}
+
+	enc := newEnc(t, "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169")
+	plainState := []byte(`{"terraform_version": "1.7.0", "serial": 1, "lineage": "test"}`)
+	encrypted, err := enc.State().EncryptState(plainState)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(encrypted, &payload); err != nil {
+			t.Fatal(err)
+		}
+		data, err := base64.StdEncoding.DecodeString(payload["encrypted_data"].(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload["encrypted_data"] = data[:len(data)/2]
+		truncated, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err = enc.State().DecryptState(truncated)
+		if err == nil {
+			t.Fatal("expected an error decrypting truncated data, got none")
+		}
+		if !strings.Contains(err.Error(), "malformed or truncated") {
+			t.Fatalf("expected a malformed data error, got: %v", err)
+		}
+	})
+
+	t.Run("wrong-key", func(t *testing.T) {
+		other := newEnc(t, "3169726f6f796565686561755137756f7572686134686f68676f65306870686f")
+		_, _, err := other.State().DecryptState(encrypted)
+		if err == nil {
+			t.Fatal("expected an error decrypting with the wrong key, got none")
+		}
+		if !strings.Contains(err.Error(), "authentication failed") {
+			t.Fatalf("expected an authentication error, got: %v", err)
+		}
+		if strings.Contains(err.Error(), "malformed or truncated") {
+			t.Fatalf("expected no malformed data error, got: %v", err)
+		}
+	})
+}
+
+// forgetfulKeyProviderDescriptor is a test key provider which never returns the key the data was encrypted with.
+type forgetfulKeyProviderDescriptor struct{}
+
+func (forgetfulKeyProviderDescriptor) ID() keyprovider.ID {
+	return "forgetful"
+}
+
+func (forgetfulKeyProviderDescriptor) ConfigStruct() keyprovider.Config {
+	return &forgetfulKeyProviderConfig{}
+}
+
+type forgetfulKeyProviderConfig struct{}
+
+func (c forgetfulKeyProviderConfig) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	return &forgetfulKeyProvider{}, nil, nil
+}
+
+type forgetfulKeyProvider struct{}
+
+func (p forgetfulKeyProvider) Provide(keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	out := keyprovider.Output{
+		EncryptionKey: make([]byte, 32),
+		DecryptionKey: make([]byte, 32),
+	}
+	if _, err := rand.Read(out.EncryptionKey); err != nil {
+		return keyprovider.Output{}, nil, err
+	}
+	if _, err := rand.Read(out.DecryptionKey); err != nil {
+		return keyprovider.Output{}, nil, err
+	}
+	return out, nil, nil
+}
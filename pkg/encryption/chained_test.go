@@ -0,0 +1,118 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/configs"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/chained"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/pbkdf2"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcm"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/unencrypted"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
+)
+
+func TestChainedKeyProvider(t *testing.T) {
+	sourceConfig := `key_provider "pbkdf2" "base1" {
+			passphrase = "Hello world! 123"
+		}
+		key_provider "pbkdf2" "base2" {
+			passphrase = "OpenTofu has Encryption"
+		}
+		key_provider "chained" "envelope" {
+			a = key_provider.pbkdf2.base1
+			b = key_provider.pbkdf2.base2
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.chained.envelope
+		}
+		state {
+			method = method.aes_gcm.example
+		}`
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(chained.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	parsedSourceConfig, diags := config.LoadConfigFromString("source", sourceConfig)
+	if diags.HasErrors() {
+		t.Fatalf("%v", diags.Error())
+	}
+
+	staticEval := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+
+	enc, diags := New(reg, parsedSourceConfig, staticEval)
+	if diags.HasErrors() {
+		t.Fatalf("%v", diags.Error())
+	}
+
+	sfe := enc.State()
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := sfe.EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(encryptedState) == string(testData) {
+		t.Fatalf("The state has not been encrypted.")
+	}
+
+	var payload basedata
+	if err := json.Unmarshal(encryptedState, &payload); err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, addr := range []keyprovider.MetaStorageKey{"key_provider.pbkdf2.base1", "key_provider.pbkdf2.base2"} {
+		if _, ok := payload.Meta[addr]; !ok {
+			t.Fatalf("The metadata of %s has not been stored.", addr)
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		decryptedState, _, err := sfe.DecryptState(encryptedState)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if string(decryptedState) != string(testData) {
+			t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+		}
+	})
+
+	// If either of the inner key providers fails, decryption must fail and the error must name the failing provider.
+	for _, addr := range []keyprovider.MetaStorageKey{"key_provider.pbkdf2.base1", "key_provider.pbkdf2.base2"} {
+		t.Run(string(addr)+"-fails", func(t *testing.T) {
+			var corrupted basedata
+			if err := json.Unmarshal(encryptedState, &corrupted); err != nil {
+				t.Fatalf("%v", err)
+			}
+			corrupted.Meta[addr] = []byte("not json")
+			corruptedState, err := json.Marshal(corrupted)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			_, _, err = sfe.DecryptState(corruptedState)
+			if err == nil {
+				t.Fatalf("Decryption succeeded despite the failing key provider.")
+			}
+			if !strings.Contains(err.Error(), string(addr)) {
+				t.Fatalf("The error does not name the failing key provider %s: %v", addr, err)
+			}
+		})
+	}
+}
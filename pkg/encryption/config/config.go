@@ -17,6 +17,8 @@ type EncryptionConfig struct {
 	KeyProviderConfigs []KeyProviderConfig `hcl:"key_provider,block"`
 	MethodConfigs      []MethodConfig      `hcl:"method,block"`
 
+	Defaults *DefaultsConfig `hcl:"defaults,block"`
+
 	State  *EnforceableTargetConfig `hcl:"state,block"`
 	Plan   *EnforceableTargetConfig `hcl:"plan,block"`
 	Remote *RemoteConfig            `hcl:"remote_state_data_sources,block"`
@@ -31,6 +33,33 @@ func (c *EncryptionConfig) Merge(override *EncryptionConfig) *EncryptionConfig {
 	return MergeConfigs(c, override)
 }
 
+// applyDefaults makes the method of the defaults block, if any, the default method of the state and plan targets. A
+// target without a block of its own uses only the default method, which is what an empty block would do. It must be
+// called again whenever the defaults or the targets change.
+func (c *EncryptionConfig) applyDefaults() {
+	var method hcl.Expression
+	if c.Defaults != nil && isExpressionSet(c.Defaults.Method) {
+		method = c.Defaults.Method
+	}
+
+	c.State = withDefaultMethod(c.State, method)
+	c.Plan = withDefaultMethod(c.Plan, method)
+}
+
+// withDefaultMethod returns a copy of the target with the given default method, so that targets shared with the
+// configurations a merged configuration was built from are not modified.
+func withDefaultMethod(target *EnforceableTargetConfig, method hcl.Expression) *EnforceableTargetConfig {
+	if target == nil {
+		if method == nil {
+			return nil
+		}
+		target = &EnforceableTargetConfig{}
+	}
+	ret := *target
+	ret.defaultMethod = method
+	return &ret
+}
+
 // GetKeyProvider takes type and name arguments to find a respective KeyProviderConfig in the list.
 func (c *EncryptionConfig) GetKeyProvider(kpType, kpName string) (KeyProviderConfig, bool) {
 	for _, kp := range c.KeyProviderConfigs {
@@ -41,14 +70,45 @@ func (c *EncryptionConfig) GetKeyProvider(kpType, kpName string) (KeyProviderCon
 	return KeyProviderConfig{}, false
 }
 
+func (c *EncryptionConfig) GetMethod(mType, mName string) (MethodConfig, bool) {
+	for _, m := range c.MethodConfigs {
+		if m.Type == mType && m.Name == mName {
+			return m, true
+		}
+	}
+	return MethodConfig{}, false
+}
+
 // KeyProviderConfig describes the terraform.encryption.key_provider.* block you can use to declare a key provider for
 // encryption. The Body field will contain the remaining undeclared fields the key provider can consume.
 type KeyProviderConfig struct {
 	// EncryptedMetadataAlias contains the key to identify the metadata by.
-	EncryptedMetadataAlias string   `hcl:"encrypted_metadata_alias,optional"`
-	Type                   string   `hcl:"type,label"`
-	Name                   string   `hcl:"name,label"`
-	Body                   hcl.Body `hcl:",remain"`
+	EncryptedMetadataAlias string `hcl:"encrypted_metadata_alias,optional"`
+	// ForEach optionally expands the key provider into one instance per element, addressable as
+	// key_provider.type.name["key"].
+	ForEach hcl.Expression `hcl:"for_each,optional"`
+	Type    string         `hcl:"type,label"`
+	Name    string         `hcl:"name,label"`
+	Body    hcl.Body       `hcl:",remain"`
+}
+
+// HasForEach returns true if the for_each meta-argument is set on the key provider. gohcl fills in a static null
+// expression when the attribute is absent, so this has to look at the expression itself.
+func (k KeyProviderConfig) HasForEach() bool {
+	return isExpressionSet(k.ForEach)
+}
+
+// isExpressionSet returns true if the given expression of an optional attribute was set in the configuration, taking
+// into account the static null expression gohcl fills in for absent attributes.
+func isExpressionSet(expr hcl.Expression) bool {
+	if expr == nil {
+		return false
+	}
+	if len(expr.Variables()) != 0 {
+		return true
+	}
+	val, diags := expr.Value(nil)
+	return diags.HasErrors() || !val.IsNull()
 }
 
 // Addr returns a keyprovider.Addr from the current configuration.
@@ -68,6 +128,13 @@ func (m MethodConfig) Addr() (method.Addr, hcl.Diagnostics) {
 	return method.NewAddr(m.Type, m.Name)
 }
 
+// DefaultsConfig describes the terraform.encryption.defaults block you can use to declare the method for the state and
+// plan targets which do not set a method themselves. It does not apply to remote state data sources, which read the
+// state of other projects.
+type DefaultsConfig struct {
+	Method hcl.Expression `hcl:"method,optional"`
+}
+
 // RemoteConfig describes the terraform.encryption.remote block you can use to declare encryption for remote state data
 // sources.
 type RemoteConfig struct {
@@ -75,26 +142,40 @@ type RemoteConfig struct {
 	Targets []NamedTargetConfig `hcl:"remote_state_data_source,block"`
 }
 
-// TargetConfig describes the target.encryption.state, target.encryption.plan, etc blocks.
+// TargetConfig describes the target.encryption.state, target.encryption.plan, etc blocks. The fallback blocks form an
+// ordered chain of methods which are only used for decryption.
 type TargetConfig struct {
-	Method   hcl.Expression `hcl:"method,optional"`
-	Fallback *TargetConfig  `hcl:"fallback,block"`
+	Method    hcl.Expression  `hcl:"method,optional"`
+	Fallbacks []*TargetConfig `hcl:"fallback,block"`
 }
 
-// EnforceableTargetConfig is an extension of the TargetConfig that supports the enforced form.
+// EnforceableTargetConfig is an extension of the TargetConfig that supports the enforced form. If
+// AllowUnencryptedReads is set, the unencrypted method is permitted in fallback blocks for reading data despite the
+// enforced form, but never as the method used for writing. If SensitiveOnly is set, only the sensitive values of a
+// state file are encrypted, which is not supported for plans.
 //
 // Note: This struct is copied because gohcl does not support embedding.
 type EnforceableTargetConfig struct {
-	Enforced bool           `hcl:"enforced,optional"`
-	Method   hcl.Expression `hcl:"method,optional"`
-	Fallback *TargetConfig  `hcl:"fallback,block"`
+	Enforced              bool            `hcl:"enforced,optional"`
+	AllowUnencryptedReads bool            `hcl:"allow_unencrypted_reads,optional"`
+	SensitiveOnly         bool            `hcl:"sensitive_only,optional"`
+	Method                hcl.Expression  `hcl:"method,optional"`
+	Fallbacks             []*TargetConfig `hcl:"fallback,block"`
+
+	// defaultMethod is the method of the defaults block, used if Method is not set.
+	defaultMethod hcl.Expression
 }
 
-// AsTargetConfig converts the struct into its parent TargetConfig.
+// AsTargetConfig converts the struct into its parent TargetConfig. The method of the defaults block is used if the
+// target does not set its own.
 func (e EnforceableTargetConfig) AsTargetConfig() *TargetConfig {
+	method := e.Method
+	if !isExpressionSet(method) && e.defaultMethod != nil {
+		method = e.defaultMethod
+	}
 	return &TargetConfig{
-		Method:   e.Method,
-		Fallback: e.Fallback,
+		Method:    method,
+		Fallbacks: e.Fallbacks,
 	}
 }
 
@@ -103,15 +184,15 @@ func (e EnforceableTargetConfig) AsTargetConfig() *TargetConfig {
 //
 // Note: This struct is copied because gohcl does not support embedding.
 type NamedTargetConfig struct {
-	Name     string         `hcl:"name,label"`
-	Method   hcl.Expression `hcl:"method,optional"`
-	Fallback *TargetConfig  `hcl:"fallback,block"`
+	Name      string          `hcl:"name,label"`
+	Method    hcl.Expression  `hcl:"method,optional"`
+	Fallbacks []*TargetConfig `hcl:"fallback,block"`
 }
 
 // AsTargetConfig converts the struct into its parent TargetConfig.
 func (n NamedTargetConfig) AsTargetConfig() *TargetConfig {
 	return &TargetConfig{
-		Method:   n.Method,
-		Fallback: n.Fallback,
+		Method:    n.Method,
+		Fallbacks: n.Fallbacks,
 	}
 }
@@ -18,11 +18,14 @@ import (
 // This method serves as an example for how someone using this library might want to load a configuration.
 // if they were not using gohcl directly.
 // However! Right now, this method should only be used in tests, as OpenTofu should be using gohcl to parse the configuration.
+//
+// Input starting with a '{' is parsed as HCL JSON, anything else as native HCL syntax, so both produce the same
+// configuration structure.
 func LoadConfigFromString(sourceName string, rawInput string) (*EncryptionConfig, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	var file *hcl.File
 
-	if strings.TrimSpace(rawInput)[0] == '{' {
+	if strings.HasPrefix(strings.TrimSpace(rawInput), "{") {
 		file, diags = json.Parse([]byte(rawInput), sourceName)
 	} else {
 		file, diags = hclsyntax.ParseConfig([]byte(rawInput), sourceName, hcl.Pos{Byte: 0, Line: 1, Column: 1})
@@ -0,0 +1,124 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestLoadConfigFromStringReportsAllErrors(t *testing.T) {
+	cfg, diags := LoadConfigFromString("test", `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+		state {
+			method  = method.aes_gcm.example
+			unknown = true
+		}
+	`)
+	if cfg != nil {
+		t.Fatalf("expected no configuration to be returned")
+	}
+
+	summaries := make(map[string]bool)
+	for _, diag := range diags {
+		summaries[diag.Summary] = true
+	}
+	for _, want := range []string{"Unsupported argument", "Duplicate key_provider"} {
+		if !summaries[want] {
+			t.Errorf("expected a %q error, got: %v", want, diags.Error())
+		}
+	}
+}
+
+func TestLoadConfigFromStringDefaults(t *testing.T) {
+	cfg, diags := LoadConfigFromString("test", `
+		method "aes_gcm" "example" {
+		}
+		method "unencrypted" "example" {
+		}
+		defaults {
+			method = method.aes_gcm.example
+		}
+		plan {
+			enforced = false
+			method   = method.unencrypted.example
+		}
+	`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	if cfg.State == nil {
+		t.Fatal("expected the defaults to add a state target")
+	}
+	if got, want := targetMethod(t, cfg.State), "method.aes_gcm.example"; got != want {
+		t.Errorf("wrong state method %q, want %q", got, want)
+	}
+	if got, want := targetMethod(t, cfg.Plan), "method.unencrypted.example"; got != want {
+		t.Errorf("wrong plan method %q, want %q", got, want)
+	}
+
+	// An override replaces the default method of the merged configuration, but not the method set by a target.
+	override, diags := LoadConfigFromString("override", `
+		defaults {
+			method = method.aes_gcm.other
+		}
+	`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	merged := cfg.Merge(override)
+	if got, want := targetMethod(t, merged.State), "method.aes_gcm.other"; got != want {
+		t.Errorf("wrong merged state method %q, want %q", got, want)
+	}
+	if got, want := targetMethod(t, merged.Plan), "method.unencrypted.example"; got != want {
+		t.Errorf("wrong merged plan method %q, want %q", got, want)
+	}
+	if got, want := targetMethod(t, cfg.State), "method.aes_gcm.example"; got != want {
+		t.Errorf("merging modified the state method to %q, want %q", got, want)
+	}
+}
+
+func TestLoadConfigFromStringDefaultsWithoutMethod(t *testing.T) {
+	_, diags := LoadConfigFromString("test", `
+		defaults {
+		}
+	`)
+	if !diags.HasErrors() || diags[0].Summary != "Missing default method" {
+		t.Fatalf("expected a missing default method error, got: %v", diags.Error())
+	}
+}
+
+// targetMethod returns the method reference used by the given target, including the method of the defaults block.
+func targetMethod(t *testing.T, target *EnforceableTargetConfig) string {
+	t.Helper()
+
+	traversal, diags := hcl.AbsTraversalForExpr(target.AsTargetConfig().Method)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	var ret string
+	for i, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			ret = step.Name
+		case hcl.TraverseAttr:
+			ret += "." + step.Name
+		default:
+			t.Fatalf("unexpected traversal step %d: %#v", i, step)
+		}
+	}
+	return ret
+}
@@ -18,14 +18,33 @@ func MergeConfigs(cfg *EncryptionConfig, override *EncryptionConfig) *Encryption
 	if override == nil {
 		return cfg
 	}
-	return &EncryptionConfig{
+	merged := &EncryptionConfig{
 		KeyProviderConfigs: mergeKeyProviderConfigs(cfg.KeyProviderConfigs, override.KeyProviderConfigs),
 		MethodConfigs:      mergeMethodConfigs(cfg.MethodConfigs, override.MethodConfigs),
 
+		Defaults: mergeDefaultsConfigs(cfg.Defaults, override.Defaults),
+
 		State:  mergeEnforceableTargetConfigs(cfg.State, override.State),
 		Plan:   mergeEnforceableTargetConfigs(cfg.Plan, override.Plan),
 		Remote: mergeRemoteConfigs(cfg.Remote, override.Remote),
 	}
+	merged.applyDefaults()
+	return merged
+}
+
+func mergeDefaultsConfigs(cfg *DefaultsConfig, override *DefaultsConfig) *DefaultsConfig {
+	if cfg == nil {
+		return override
+	}
+	if override == nil {
+		return cfg
+	}
+
+	merged := &DefaultsConfig{Method: cfg.Method}
+	if isExpressionSet(override.Method) {
+		merged.Method = override.Method
+	}
+	return merged
 }
 
 func mergeMethodConfigs(configs []MethodConfig, overrides []MethodConfig) []MethodConfig {
@@ -67,6 +86,9 @@ func mergeKeyProviderConfigs(configs []KeyProviderConfig, overrides []KeyProvide
 			if keyProvider.Type == override.Type && keyProvider.Name == override.Name {
 				// Override the existing key provider.
 				merged[i].Body = mergeBody(keyProvider.Body, override.Body)
+				if override.HasForEach() {
+					merged[i].ForEach = override.ForEach
+				}
 				wasOverridden = true
 				break
 			}
@@ -96,10 +118,10 @@ func mergeTargetConfigs(cfg *TargetConfig, override *TargetConfig) *TargetConfig
 		merged.Method = cfg.Method
 	}
 
-	if override.Fallback != nil {
-		merged.Fallback = override.Fallback
+	if len(override.Fallbacks) != 0 {
+		merged.Fallbacks = override.Fallbacks
 	} else {
-		merged.Fallback = cfg.Fallback
+		merged.Fallbacks = cfg.Fallbacks
 	}
 
 	return merged
@@ -113,11 +135,17 @@ func mergeEnforceableTargetConfigs(cfg *EnforceableTargetConfig, override *Enfor
 		return cfg
 	}
 
-	mergeTarget := mergeTargetConfigs(cfg.AsTargetConfig(), override.AsTargetConfig())
+	// The default methods are not merged, they are applied to the merged configuration again.
+	mergeTarget := mergeTargetConfigs(
+		&TargetConfig{Method: cfg.Method, Fallbacks: cfg.Fallbacks},
+		&TargetConfig{Method: override.Method, Fallbacks: override.Fallbacks},
+	)
 	return &EnforceableTargetConfig{
-		Enforced: cfg.Enforced || override.Enforced,
-		Method:   mergeTarget.Method,
-		Fallback: mergeTarget.Fallback,
+		Enforced:              cfg.Enforced || override.Enforced,
+		AllowUnencryptedReads: cfg.AllowUnencryptedReads || override.AllowUnencryptedReads,
+		SensitiveOnly:         cfg.SensitiveOnly || override.SensitiveOnly,
+		Method:                mergeTarget.Method,
+		Fallbacks:             mergeTarget.Fallbacks,
 	}
 }
 
@@ -143,9 +171,9 @@ func mergeRemoteConfigs(cfg *RemoteConfig, override *RemoteConfig) *RemoteConfig
 				// gohcl does not support struct embedding
 				mergeTarget := mergeTargetConfigs(t.AsTargetConfig(), overrideTarget.AsTargetConfig())
 				merged.Targets[i] = NamedTargetConfig{
-					Name:     t.Name,
-					Method:   mergeTarget.Method,
-					Fallback: mergeTarget.Fallback,
+					Name:      t.Name,
+					Method:    mergeTarget.Method,
+					Fallbacks: mergeTarget.Fallbacks,
 				}
 				break
 			}
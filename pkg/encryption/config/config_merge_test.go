@@ -237,18 +237,25 @@ func TestMergeKeyProviderConfigs(t *testing.T) {
 }
 
 func TestMergeTargetConfigs(t *testing.T) {
+	fallbacksOf := func(fallback *TargetConfig) []*TargetConfig {
+		if fallback == nil {
+			return nil
+		}
+		return []*TargetConfig{fallback}
+	}
+
 	makeTargetConfig := func(enforced bool, method hcl.Expression, fallback *TargetConfig) *TargetConfig {
 		return &TargetConfig{
-			Method:   method,
-			Fallback: fallback,
+			Method:    method,
+			Fallbacks: fallbacksOf(fallback),
 		}
 	}
 
 	makeEnforceableTargetConfig := func(enforced bool, method hcl.Expression, fallback *TargetConfig) *EnforceableTargetConfig {
 		return &EnforceableTargetConfig{
-			Enforced: enforced,
-			Method:   method,
-			Fallback: fallback,
+			Enforced:  enforced,
+			Method:    method,
+			Fallbacks: fallbacksOf(fallback),
 		}
 	}
 
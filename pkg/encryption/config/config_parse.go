@@ -19,10 +19,9 @@ import (
 func DecodeConfig(body hcl.Body, rng hcl.Range) (*EncryptionConfig, hcl.Diagnostics) {
 	cfg := &EncryptionConfig{DeclRange: rng}
 
+	// The duplicate checks below also run on a partially decoded configuration, so that all errors are reported at
+	// once.
 	diags := gohcl.DecodeBody(body, nil, cfg)
-	if diags.HasErrors() {
-		return nil, diags
-	}
 
 	for i, kp := range cfg.KeyProviderConfigs {
 		for j, okp := range cfg.KeyProviderConfigs {
@@ -52,6 +51,15 @@ func DecodeConfig(body hcl.Body, rng hcl.Range) (*EncryptionConfig, hcl.Diagnost
 		}
 	}
 
+	if cfg.Defaults != nil && !isExpressionSet(cfg.Defaults.Method) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing default method",
+			Detail:   "The defaults block must set the method used by the state and plan targets which do not set their own.",
+			Subject:  rng.Ptr(),
+		})
+	}
+
 	if cfg.Remote != nil {
 		for i, t := range cfg.Remote.Targets {
 			for j, ot := range cfg.Remote.Targets {
@@ -72,5 +80,6 @@ func DecodeConfig(body hcl.Body, rng hcl.Range) (*EncryptionConfig, hcl.Diagnost
 		return nil, diags
 	}
 
+	cfg.applyDefaults()
 	return cfg, diags
 }
@@ -0,0 +1,221 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/registry"
+)
+
+// SchemaFormatVersion is the version of the JSON format of the Schema. The minor version is incremented for
+// backwards-compatible additions, the major version for breaking changes.
+const SchemaFormatVersion = "1.0"
+
+// Schema is the machine-readable description of the configuration fields of all key providers and methods in a
+// registry. It is meant for tooling that validates key_provider and method blocks before handing them to OpenTofu.
+type Schema struct {
+	FormatVersion string                  `json:"format_version"`
+	KeyProviders  map[string]*BlockSchema `json:"key_providers"`
+	Methods       map[string]*BlockSchema `json:"methods"`
+}
+
+// BlockSchema describes the body of a key_provider or method block, or of a block nested within it.
+type BlockSchema struct {
+	Attributes map[string]*AttributeSchema `json:"attributes,omitempty"`
+	BlockTypes map[string]*BlockTypeSchema `json:"block_types,omitempty"`
+}
+
+// AttributeSchema describes a single attribute. The type uses the JSON serialization of cty types, the same as the
+// attribute types in the provider schemas shown by "tofu providers schema -json".
+type AttributeSchema struct {
+	Type     json.RawMessage `json:"type"`
+	Required bool            `json:"required,omitempty"`
+	Optional bool            `json:"optional,omitempty"`
+}
+
+// BlockTypeSchema describes a nested block type. The nesting mode is "single" if the block can appear at most once and
+// "list" if it can appear any number of times.
+type BlockTypeSchema struct {
+	NestingMode string       `json:"nesting_mode"`
+	Block       *BlockSchema `json:"block"`
+}
+
+// BuildSchema builds the Schema of all key providers and methods registered in the given registry, derived from the
+// hcl tags of their ConfigStruct() types. The meta-arguments shared by all key providers, such as for_each, are
+// included in the schema of every key provider.
+func BuildSchema(reg registry.Registry) (*Schema, error) {
+	metaArguments, err := structSchema(reflect.TypeOf(KeyProviderConfig{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the schema of the key provider meta-arguments: %w", err)
+	}
+
+	schema := &Schema{
+		FormatVersion: SchemaFormatVersion,
+		KeyProviders:  map[string]*BlockSchema{},
+		Methods:       map[string]*BlockSchema{},
+	}
+
+	for _, descriptor := range reg.ListKeyProviderDescriptors() {
+		block, err := structSchema(reflect.TypeOf(descriptor.ConfigStruct()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the schema of key provider %s: %w", descriptor.ID(), err)
+		}
+		for name, attr := range metaArguments.Attributes {
+			if _, ok := block.Attributes[name]; !ok {
+				if block.Attributes == nil {
+					block.Attributes = map[string]*AttributeSchema{}
+				}
+				block.Attributes[name] = attr
+			}
+		}
+		schema.KeyProviders[string(descriptor.ID())] = block
+	}
+
+	for _, descriptor := range reg.ListMethodDescriptors() {
+		block, err := structSchema(reflect.TypeOf(descriptor.ConfigStruct()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the schema of method %s: %w", descriptor.ID(), err)
+		}
+		schema.Methods[string(descriptor.ID())] = block
+	}
+
+	return schema, nil
+}
+
+// structSchema builds the schema of the body that gohcl decodes into the given struct type. Labels and remaining
+// bodies are not part of the schema, since they are not attributes or blocks of the body itself.
+func structSchema(rt reflect.Type) (*BlockSchema, error) {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", rt)
+	}
+
+	block := &BlockSchema{}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+		name, kind, _ := strings.Cut(tag, ",")
+
+		switch kind {
+		case "", "attr", "optional":
+			ty, err := impliedAttributeType(field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("invalid type of attribute %s: %w", name, err)
+			}
+			rawType, err := ctyjson.MarshalType(ty)
+			if err != nil {
+				return nil, fmt.Errorf("invalid type of attribute %s: %w", name, err)
+			}
+			if block.Attributes == nil {
+				block.Attributes = map[string]*AttributeSchema{}
+			}
+			block.Attributes[name] = &AttributeSchema{
+				Type:     rawType,
+				Required: kind != "optional",
+				Optional: kind == "optional",
+			}
+		case "block":
+			nestingMode := "single"
+			elemType := field.Type
+			if elemType.Kind() == reflect.Slice {
+				nestingMode = "list"
+				elemType = elemType.Elem()
+			}
+			nested, err := structSchema(elemType)
+			if err != nil {
+				return nil, fmt.Errorf("invalid type of block %s: %w", name, err)
+			}
+			if block.BlockTypes == nil {
+				block.BlockTypes = map[string]*BlockTypeSchema{}
+			}
+			block.BlockTypes[name] = &BlockTypeSchema{
+				NestingMode: nestingMode,
+				Block:       nested,
+			}
+		case "label", "remain":
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported hcl tag %q on field %s", tag, field.Name)
+		}
+	}
+	return block, nil
+}
+
+// impliedAttributeType returns the cty type of an attribute for a field of the given type. This matches the type
+// gocty infers for the field, except that interface types such as hcl.Expression accept any value, and structs without
+// cty tags are described by their hcl tags.
+func impliedAttributeType(rt reflect.Type) (cty.Type, error) {
+	switch rt.Kind() {
+	case reflect.Ptr:
+		return impliedAttributeType(rt.Elem())
+	case reflect.Interface:
+		return cty.DynamicPseudoType, nil
+	case reflect.Slice:
+		ety, err := impliedAttributeType(rt.Elem())
+		if err != nil {
+			return cty.NilType, err
+		}
+		return cty.List(ety), nil
+	case reflect.Map:
+		ety, err := impliedAttributeType(rt.Elem())
+		if err != nil {
+			return cty.NilType, err
+		}
+		return cty.Map(ety), nil
+	case reflect.Struct:
+		return impliedObjectType(rt)
+	default:
+		return gocty.ImpliedType(reflect.Zero(rt).Interface())
+	}
+}
+
+func impliedObjectType(rt reflect.Type) (cty.Type, error) {
+	if ty, err := gocty.ImpliedType(reflect.Zero(rt).Interface()); err == nil {
+		return ty, nil
+	}
+
+	attrTypes := map[string]cty.Type{}
+	var optional []string
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+		name, kind, _ := strings.Cut(tag, ",")
+		switch kind {
+		case "", "attr", "optional":
+		default:
+			return cty.NilType, fmt.Errorf("unsupported hcl tag %q on field %s of %s", tag, field.Name, rt)
+		}
+
+		ty, err := impliedAttributeType(field.Type)
+		if err != nil {
+			return cty.NilType, err
+		}
+		attrTypes[name] = ty
+		if kind == "optional" {
+			optional = append(optional, name)
+		}
+	}
+	if len(attrTypes) == 0 {
+		return cty.NilType, fmt.Errorf("no cty.Type for %s", rt)
+	}
+	return cty.ObjectWithOptionalAttrs(attrTypes, optional), nil
+}
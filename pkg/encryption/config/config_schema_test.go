@@ -0,0 +1,104 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/static"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcm"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
+)
+
+func TestBuildSchema(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := BuildSchema(reg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Round-trip through JSON to check the schema the way tooling sees it.
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("failed to marshal the schema: %v", err)
+	}
+	var got struct {
+		FormatVersion string `json:"format_version"`
+		KeyProviders  map[string]struct {
+			Attributes map[string]struct {
+				Type     json.RawMessage `json:"type"`
+				Required bool            `json:"required"`
+				Optional bool            `json:"optional"`
+			} `json:"attributes"`
+		} `json:"key_providers"`
+		Methods map[string]struct {
+			Attributes map[string]struct {
+				Type     json.RawMessage `json:"type"`
+				Required bool            `json:"required"`
+				Optional bool            `json:"optional"`
+			} `json:"attributes"`
+		} `json:"methods"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("failed to unmarshal the schema: %v", err)
+	}
+
+	if got.FormatVersion != SchemaFormatVersion {
+		t.Errorf("wrong format version %q", got.FormatVersion)
+	}
+	if len(got.KeyProviders) != 1 || len(got.Methods) != 1 {
+		t.Fatalf("expected exactly the registered key provider and method, got:\n%s", raw)
+	}
+
+	staticAttrs := got.KeyProviders["static"].Attributes
+	for name, wantType := range map[string]string{
+		"key":                      `"string"`,
+		"key_base64":               `"string"`,
+		"key_file":                 `"string"`,
+		"encrypted_metadata_alias": `"string"`,
+		"for_each":                 `"dynamic"`,
+	} {
+		attr, ok := staticAttrs[name]
+		if !ok {
+			t.Errorf("static key provider attribute %s is missing", name)
+			continue
+		}
+		if string(attr.Type) != wantType {
+			t.Errorf("static key provider attribute %s has type %s, expected %s", name, attr.Type, wantType)
+		}
+		if !attr.Optional || attr.Required {
+			t.Errorf("static key provider attribute %s should be optional", name)
+		}
+	}
+
+	aesgcmAttrs := got.Methods["aes_gcm"].Attributes
+	keys, ok := aesgcmAttrs["keys"]
+	if !ok {
+		t.Fatalf("aes_gcm method attribute keys is missing, got:\n%s", raw)
+	}
+	if !keys.Required {
+		t.Errorf("aes_gcm method attribute keys should be required")
+	}
+	if want := `["object",{"decryption_key":["list","number"],"encryption_key":["list","number"]}]`; string(keys.Type) != want {
+		t.Errorf("aes_gcm method attribute keys has type %s, expected %s", keys.Type, want)
+	}
+	for _, name := range []string{"aad", "bind_to_target"} {
+		if attr, ok := aesgcmAttrs[name]; !ok || !attr.Optional {
+			t.Errorf("aes_gcm method attribute %s should be present and optional", name)
+		}
+	}
+	if _, ok := aesgcmAttrs["for_each"]; ok {
+		t.Errorf("the key provider meta-arguments must not be added to methods")
+	}
+}
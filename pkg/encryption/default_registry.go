@@ -6,11 +6,21 @@
 package encryption
 
 import (
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/age"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/argon2id"
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/aws_kms"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/azure_keyvault"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/chained"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/external"
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/gcp_kms"
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/openbao"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/openbao_transit"
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/pbkdf2"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/pkcs11"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/systemd_creds"
 	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcm"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcmsiv"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/chacha20poly1305"
 	"github.com/we-dcode/opentofu/pkg/encryption/method/unencrypted"
 	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
 )
@@ -21,18 +31,48 @@ func init() {
 	if err := DefaultRegistry.RegisterKeyProvider(pbkdf2.New()); err != nil {
 		panic(err)
 	}
+	if err := DefaultRegistry.RegisterKeyProvider(argon2id.New()); err != nil {
+		panic(err)
+	}
 	if err := DefaultRegistry.RegisterKeyProvider(aws_kms.New()); err != nil {
 		panic(err)
 	}
 	if err := DefaultRegistry.RegisterKeyProvider(gcp_kms.New()); err != nil {
 		panic(err)
 	}
+	if err := DefaultRegistry.RegisterKeyProvider(azure_keyvault.New()); err != nil {
+		panic(err)
+	}
 	if err := DefaultRegistry.RegisterKeyProvider(openbao.New()); err != nil {
 		panic(err)
 	}
+	if err := DefaultRegistry.RegisterKeyProvider(openbao_transit.New()); err != nil {
+		panic(err)
+	}
+	if err := DefaultRegistry.RegisterKeyProvider(age.New()); err != nil {
+		panic(err)
+	}
+	if err := DefaultRegistry.RegisterKeyProvider(external.New()); err != nil {
+		panic(err)
+	}
+	if err := DefaultRegistry.RegisterKeyProvider(pkcs11.New()); err != nil {
+		panic(err)
+	}
+	if err := DefaultRegistry.RegisterKeyProvider(systemd_creds.New()); err != nil {
+		panic(err)
+	}
+	if err := DefaultRegistry.RegisterKeyProvider(chained.New()); err != nil {
+		panic(err)
+	}
 	if err := DefaultRegistry.RegisterMethod(aesgcm.New()); err != nil {
 		panic(err)
 	}
+	if err := DefaultRegistry.RegisterMethod(chacha20poly1305.New()); err != nil {
+		panic(err)
+	}
+	if err := DefaultRegistry.RegisterMethod(aesgcmsiv.New()); err != nil {
+		panic(err)
+	}
 	if err := DefaultRegistry.RegisterMethod(unencrypted.New()); err != nil {
 		panic(err)
 	}
@@ -6,6 +6,8 @@
 package encryption
 
 import (
+	"sort"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/we-dcode/opentofu/pkg/configs"
 	"github.com/we-dcode/opentofu/pkg/encryption/config"
@@ -24,6 +26,16 @@ type Encryption interface {
 	// RemoteState produces a StateEncryption for reading remote states using the terraform_remote_state data
 	// source.
 	RemoteState(string) StateEncryption
+
+	// Validate performs an encryption and decryption round-trip of a small in-memory payload with every configured
+	// target. This verifies that the key providers and methods actually work, for example that a KMS is reachable
+	// and the key can be used, without reading or writing any state or plan.
+	Validate() hcl.Diagnostics
+}
+
+// validatable is implemented by the target encryptions which can perform a round-trip test.
+type validatable interface {
+	validate() hcl.Diagnostics
 }
 
 type encryption struct {
@@ -35,6 +47,9 @@ type encryption struct {
 	// Inputs
 	cfg *config.EncryptionConfig
 	reg registry.Registry
+
+	// lookupEnv is used by the env function in the configuration. If nil, os.LookupEnv is used.
+	lookupEnv func(string) (string, bool)
 }
 
 // New creates a new Encryption provider from the given configuration and registry.
@@ -53,21 +68,29 @@ func New(reg registry.Registry, cfg *config.EncryptionConfig, staticEval *config
 	var encDiags hcl.Diagnostics
 
 	if cfg.State != nil {
-		enc.state, encDiags = newStateEncryption(enc, cfg.State.AsTargetConfig(), cfg.State.Enforced, "state", staticEval)
+		enc.state, encDiags = newStateEncryption(enc, cfg.State.AsTargetConfig(), cfg.State.Enforced, cfg.State.AllowUnencryptedReads, cfg.State.SensitiveOnly, "state", staticEval)
 		diags = append(diags, encDiags...)
 	} else {
 		enc.state = StateEncryptionDisabled()
 	}
 
 	if cfg.Plan != nil {
-		enc.plan, encDiags = newPlanEncryption(enc, cfg.Plan.AsTargetConfig(), cfg.Plan.Enforced, "plan", staticEval)
+		if cfg.Plan.SensitiveOnly {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported sensitive_only option",
+				Detail:   "The sensitive_only option is only supported for the state target. Plan files are always encrypted entirely.",
+				Subject:  cfg.DeclRange.Ptr(),
+			})
+		}
+		enc.plan, encDiags = newPlanEncryption(enc, cfg.Plan.AsTargetConfig(), cfg.Plan.Enforced, cfg.Plan.AllowUnencryptedReads, "plan", staticEval)
 		diags = append(diags, encDiags...)
 	} else {
 		enc.plan = PlanEncryptionDisabled()
 	}
 
 	if cfg.Remote != nil && cfg.Remote.Default != nil {
-		enc.remoteDefault, encDiags = newStateEncryption(enc, cfg.Remote.Default, false, "remote.default", staticEval)
+		enc.remoteDefault, encDiags = newStateEncryption(enc, cfg.Remote.Default, false, false, false, "remote.default", staticEval)
 		diags = append(diags, encDiags...)
 	} else {
 		enc.remoteDefault = StateEncryptionDisabled()
@@ -77,13 +100,14 @@ func New(reg registry.Registry, cfg *config.EncryptionConfig, staticEval *config
 		for _, remoteTarget := range cfg.Remote.Targets {
 			// TODO the addr here should be generated in one place.
 			addr := "remote.remote_state_datasource." + remoteTarget.Name
-			enc.remotes[remoteTarget.Name], encDiags = newStateEncryption(enc, remoteTarget.AsTargetConfig(), false, addr, staticEval)
+			enc.remotes[remoteTarget.Name], encDiags = newStateEncryption(enc, remoteTarget.AsTargetConfig(), false, false, false, addr, staticEval)
 			diags = append(diags, encDiags...)
 		}
 	}
 	if diags.HasErrors() {
 		return nil, diags
 	}
+	diags = append(diags, unusedDiags(cfg, reg)...)
 	return enc, diags
 }
 
@@ -102,6 +126,28 @@ func (e *encryption) RemoteState(name string) StateEncryption {
 	return e.remoteDefault
 }
 
+func (e *encryption) Validate() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	validate := func(target any) {
+		if v, ok := target.(validatable); ok {
+			diags = append(diags, v.validate()...)
+		}
+	}
+
+	validate(e.state)
+	validate(e.plan)
+	validate(e.remoteDefault)
+	names := make([]string, 0, len(e.remotes))
+	for name := range e.remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		validate(e.remotes[name])
+	}
+	return diags
+}
+
 // Mostly used in tests
 type encryptionDisabled struct{}
 
@@ -113,3 +159,4 @@ func (e *encryptionDisabled) Plan() PlanEncryption   { return PlanEncryptionDisa
 func (e *encryptionDisabled) RemoteState(name string) StateEncryption {
 	return StateEncryptionDisabled()
 }
+func (e *encryptionDisabled) Validate() hcl.Diagnostics { return nil }
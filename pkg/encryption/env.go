@@ -0,0 +1,34 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// envFunc constructs the env function available in the encryption configuration. It returns the value of the given
+// environment variable, so keys can be injected by CI systems without declaring an input variable for them. An unset
+// environment variable is an error, as an empty key is never what the user intended.
+func envFunc(lookupEnv func(string) (string, bool)) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			name := args[0].AsString()
+			value, ok := lookupEnv(name)
+			if !ok {
+				return cty.NilVal, function.NewArgErrorf(0, "Undefined environment variable %s", name)
+			}
+			return cty.StringVal(value), nil
+		},
+	})
+}
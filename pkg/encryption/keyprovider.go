@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/we-dcode/opentofu/pkg/addrs"
 	"github.com/we-dcode/opentofu/pkg/configs"
@@ -29,13 +30,15 @@ func (e *targetBuilder) setupKeyProviders() hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
 	e.keyValues = make(map[string]map[string]cty.Value)
+	e.rotatedKeyValues = make(map[string]map[string]cty.Value)
+	e.instanceKeys = make(map[string]map[string][]string)
+	e.failedKeyProviders = make(map[string]bool)
 
 	kpMap := make(map[string]cty.Value)
 	for _, keyProviderConfig := range e.cfg.KeyProviderConfigs {
+		// Carry on after a key provider fails, so that the errors of all key providers are reported at once. Key
+		// providers depending on a failed one are skipped without repeating its errors.
 		diags = append(diags, e.setupKeyProvider(keyProviderConfig, nil)...)
-		if diags.HasErrors() {
-			return diags
-		}
 		for name, kps := range e.keyValues {
 			kpMap[name] = cty.ObjectVal(kps)
 		}
@@ -50,6 +53,14 @@ func (e *targetBuilder) setupKeyProviders() hcl.Diagnostics {
 }
 
 func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []config.KeyProviderConfig) hcl.Diagnostics {
+	diags := e.setupKeyProviderOnce(cfg, stack)
+	if diags.HasErrors() {
+		e.failedKeyProviders[cfg.Type+"."+cfg.Name] = true
+	}
+	return diags
+}
+
+func (e *targetBuilder) setupKeyProviderOnce(cfg config.KeyProviderConfig, stack []config.KeyProviderConfig) hcl.Diagnostics {
 	// Ensure cfg.Type is in keyValues, if it isn't then add it in preparation for the next step
 	if _, ok := e.keyValues[cfg.Type]; !ok {
 		e.keyValues[cfg.Type] = make(map[string]cty.Value)
@@ -71,7 +82,7 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 	// the current key provider, then we have a circular reference and we should return an error
 	// to the user.
 	for _, s := range stack {
-		if s == cfg {
+		if s.Type == cfg.Type && s.Name == cfg.Name {
 			addr, diags := keyprovider.NewAddr(cfg.Type, cfg.Name)
 			diags = diags.Append(
 				&hcl.Diagnostic{
@@ -114,32 +125,34 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 		})
 	}
 
-	// Now that we know we have the correct Descriptor, we can decode the configuration
-	// and build the KeyProvider
-	keyProviderConfig := keyProviderDescriptor.ConfigStruct()
-
 	// Locate all the dependencies
-	deps, varDiags := gohcl.VariablesInBody(cfg.Body, keyProviderConfig)
+	deps, varDiags := gohcl.VariablesInBody(cfg.Body, keyProviderDescriptor.ConfigStruct())
 	diags = append(diags, varDiags...)
 	if diags.HasErrors() {
 		return diags
 	}
+	if cfg.HasForEach() {
+		deps = append(deps, cfg.ForEach.Variables()...)
+	}
 
 	// lang.References is going to fail parsing key_provider deps
 	// so we filter them out in nonKeyProviderDeps.
 	var nonKeyProviderDeps []hcl.Traversal
 
 	// Setting up key providers from deps.
+	depFailed := false
 	for _, dep := range deps {
-		// Key Provider references should be in the form key_provider.type.name
-		if len(dep) != 3 {
-			nonKeyProviderDeps = append(nonKeyProviderDeps, dep)
+		//nolint:errcheck // This will always be a TraverseRoot, panic is OK if that's not the case
+		depRoot := (dep[0].(hcl.TraverseRoot)).Name
+
+		// each.key and each.value are only set per instance below and are not known to the static evaluator.
+		if depRoot == "each" {
 			continue
 		}
 
-		//nolint:errcheck // This will always be a TraverseRoot, panic is OK if that's not the case
-		depRoot := (dep[0].(hcl.TraverseRoot)).Name
-		if depRoot != "key_provider" {
+		// Key Provider references should be in the form key_provider.type.name, or key_provider.type.name["key"]
+		// for key providers expanded with for_each.
+		if len(dep) < 3 || depRoot != "key_provider" {
 			nonKeyProviderDeps = append(nonKeyProviderDeps, dep)
 			continue
 		}
@@ -170,8 +183,17 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 			continue
 		}
 
-		depDiags := e.setupKeyProvider(kpc, stack)
-		diags = append(diags, depDiags...)
+		diags = append(diags, e.setupKeyProvider(kpc, stack)...)
+		if e.failedKeyProviders[depType+"."+depName] {
+			depFailed = true
+			continue
+		}
+		diags = append(diags, e.keyProviderInstanceDiags([]hcl.Traversal{dep})...)
+	}
+	if depFailed && !diags.HasErrors() {
+		// The dependency failed while setting up an earlier key provider and its errors have already been reported.
+		e.failedKeyProviders[cfg.Type+"."+cfg.Name] = true
+		return diags
 	}
 	if diags.HasErrors() {
 		// We should not continue now if we have any diagnostics that are errors
@@ -205,17 +227,93 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 		}
 	}
 
+	if !cfg.HasForEach() {
+		output, previous, provideDiags := e.provideKeyProvider(cfg, keyProviderDescriptor, evalCtx, metaKey)
+		diags = append(diags, provideDiags...)
+		if diags.HasErrors() {
+			return diags
+		}
+		if previous != cty.NilVal {
+			e.setRotatedKeyValue(cfg, previous)
+		}
+		e.keyValues[cfg.Type][cfg.Name] = output
+		return diags
+	}
+
+	forEach, forEachDiags := evalKeyProviderForEach(cfg, evalCtx)
+	diags = append(diags, forEachDiags...)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	// Each instance is provided separately with its own metadata, so that rotating or changing one instance does
+	// not affect the others.
+	instances := make(map[string]cty.Value, len(forEach))
+	previousInstances := make(map[string]cty.Value, len(forEach))
+	rotated := false
+	keys := make([]string, 0, len(forEach))
+	for key, value := range forEach {
+		instCtx := evalCtx.NewChild()
+		instCtx.Variables = map[string]cty.Value{
+			"each": cty.ObjectVal(map[string]cty.Value{
+				"key":   cty.StringVal(key),
+				"value": value,
+			}),
+		}
+		instMetaKey := keyprovider.MetaStorageKey(fmt.Sprintf("%s[%q]", metaKey, key))
+
+		output, previous, provideDiags := e.provideKeyProvider(cfg, keyProviderDescriptor, instCtx, instMetaKey)
+		diags = append(diags, provideDiags...)
+		if provideDiags.HasErrors() {
+			continue
+		}
+		instances[key] = output
+		previousInstances[key] = output
+		if previous != cty.NilVal {
+			previousInstances[key] = previous
+			rotated = true
+		}
+		keys = append(keys, key)
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if rotated {
+		e.setRotatedKeyValue(cfg, cty.ObjectVal(previousInstances))
+	}
+	if _, ok := e.instanceKeys[cfg.Type]; !ok {
+		e.instanceKeys[cfg.Type] = make(map[string][]string)
+	}
+	slices.Sort(keys)
+	e.instanceKeys[cfg.Type][cfg.Name] = keys
+	e.keyValues[cfg.Type][cfg.Name] = cty.ObjectVal(instances)
+
+	return diags
+}
+
+// provideKeyProvider decodes and builds a single key provider (instance) in the given evaluation context and fetches
+// its keys. If the key provider reports a key rotation, the previous key is returned as well, otherwise previous is
+// cty.NilVal.
+func (e *targetBuilder) provideKeyProvider(
+	cfg config.KeyProviderConfig,
+	keyProviderDescriptor keyprovider.Descriptor,
+	evalCtx *hcl.EvalContext,
+	metaKey keyprovider.MetaStorageKey,
+) (output cty.Value, previous cty.Value, diags hcl.Diagnostics) {
+	keyProviderConfig := keyProviderDescriptor.ConfigStruct()
+
 	// Initialize the Key Provider
 	decodeDiags := gohcl.DecodeBody(cfg.Body, evalCtx, keyProviderConfig)
 	diags = append(diags, decodeDiags...)
 	if diags.HasErrors() {
-		return diags
+		return cty.NilVal, cty.NilVal, diags
 	}
 
 	// Build the Key Provider from the configuration
 	keyProvider, keyMetaIn, err := keyProviderConfig.Build()
 	if err != nil {
-		return append(diags, &hcl.Diagnostic{
+		return cty.NilVal, cty.NilVal, append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Unable to build encryption key data",
 			Detail:   fmt.Sprintf("%s failed with error: %s", metaKey, err.Error()),
@@ -223,10 +321,11 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 	}
 
 	// Add the metadata
-	if meta, ok := e.inputKeyProviderMetadata[metaKey]; ok {
+	meta, hasInputMeta := e.inputKeyProviderMetadata[metaKey]
+	if hasInputMeta {
 		err := json.Unmarshal(meta, keyMetaIn)
 		if err != nil {
-			return append(diags, &hcl.Diagnostic{
+			return cty.NilVal, cty.NilVal, append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Unable to decode encrypted metadata (did you change your encryption config?)",
 				Detail:   fmt.Sprintf("metadata decoder for %s failed with error: %s", metaKey, err.Error()),
@@ -234,9 +333,9 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 		}
 	}
 
-	output, keyMetaOut, err := keyProvider.Provide(keyMetaIn)
+	keyOutput, keyMetaOut, err := keyProvider.Provide(keyMetaIn)
 	if err != nil {
-		return append(diags, &hcl.Diagnostic{
+		return cty.NilVal, cty.NilVal, append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Unable to fetch encryption key data",
 			Detail:   fmt.Sprintf("%s failed with error: %s", metaKey, err.Error()),
@@ -245,7 +344,7 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 
 	if keyMetaOut != nil {
 		if _, ok := e.outputKeyProviderMetadata[metaKey]; ok {
-			return append(diags, &hcl.Diagnostic{
+			return cty.NilVal, cty.NilVal, append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Duplicate metadata key",
 				Detail:   fmt.Sprintf("The metadata key %s is duplicated across multiple key providers for the same method; use the encrypted_metadata_alias option to specify unique metadata keys for each key provider in an encryption method", metaKey),
@@ -254,7 +353,7 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 		e.outputKeyProviderMetadata[metaKey], err = json.Marshal(keyMetaOut)
 
 		if err != nil {
-			return append(diags, &hcl.Diagnostic{
+			return cty.NilVal, cty.NilVal, append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Unable to encode encrypted metadata",
 				Detail:   fmt.Sprintf("The metadata encoder for %s failed with error: %s", metaKey, err.Error()),
@@ -262,8 +361,133 @@ func (e *targetBuilder) setupKeyProvider(cfg config.KeyProviderConfig, stack []c
 		}
 	}
 
-	e.keyValues[cfg.Type][cfg.Name] = output.Cty()
+	// If the key was rotated since the input was encrypted, the new key must only be used for encryption. The previous
+	// key is kept aside so that buildTargetMethods can add a decryption-only method for it.
+	previous = cty.NilVal
+	if hasInputMeta && len(keyOutput.DecryptionKey) != 0 && isKeyRotated(keyMetaIn, keyMetaOut) {
+		previousOutput := keyprovider.Output{
+			EncryptionKey: keyOutput.DecryptionKey,
+			DecryptionKey: keyOutput.DecryptionKey,
+		}
+		previous = previousOutput.Cty()
+		keyOutput.DecryptionKey = nil
+	}
+
+	return keyOutput.Cty(), previous, diags
+}
+
+func (e *targetBuilder) setRotatedKeyValue(cfg config.KeyProviderConfig, previous cty.Value) {
+	if _, ok := e.rotatedKeyValues[cfg.Type]; !ok {
+		e.rotatedKeyValues[cfg.Type] = make(map[string]cty.Value)
+	}
+	e.rotatedKeyValues[cfg.Type][cfg.Name] = previous
+}
+
+// evalKeyProviderForEach evaluates the for_each meta-argument of a key provider. It must be a known map, object or set
+// of strings, and the result maps each instance key to its each.value.
+func evalKeyProviderForEach(cfg config.KeyProviderConfig, evalCtx *hcl.EvalContext) (map[string]cty.Value, hcl.Diagnostics) {
+	val, diags := cfg.ForEach.Value(evalCtx)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	val, _ = val.UnmarkDeep()
+
+	invalid := func(detail string) hcl.Diagnostics {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid for_each argument",
+			Detail:   detail,
+			Subject:  cfg.ForEach.Range().Ptr(),
+		})
+	}
+
+	if val.IsNull() {
+		return nil, invalid("The given \"for_each\" argument value is null. A map, or set of strings is allowed.")
+	}
+	if !val.IsWhollyKnown() {
+		return nil, invalid("The \"for_each\" value of a key provider must be known when the encryption configuration is loaded.")
+	}
+
+	ty := val.Type()
+	result := make(map[string]cty.Value)
+	switch {
+	case ty.IsMapType() || ty.IsObjectType():
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			result[k.AsString()] = v
+		}
+	case ty.IsSetType() && ty.ElementType() == cty.String:
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.IsNull() {
+				return nil, invalid("The given \"for_each\" argument value contains a null value.")
+			}
+			result[v.AsString()] = v
+		}
+	default:
+		return nil, invalid(fmt.Sprintf("The given \"for_each\" argument value is unsuitable: the \"for_each\" argument must be a map, or set of strings, and you have provided a value of type %s.", ty.FriendlyName()))
+	}
+	return result, diags
+}
+
+// dependsOnFailedKeyProvider returns true if any of the traversals references a key provider which failed to set up.
+func (e *targetBuilder) dependsOnFailedKeyProvider(traversals []hcl.Traversal) bool {
+	for _, traversal := range traversals {
+		if len(traversal) < 3 || traversal.RootName() != "key_provider" {
+			continue
+		}
+		typeAttr, typeOk := traversal[1].(hcl.TraverseAttr)
+		nameAttr, nameOk := traversal[2].(hcl.TraverseAttr)
+		if typeOk && nameOk && e.failedKeyProviders[typeAttr.Name+"."+nameAttr.Name] {
+			return true
+		}
+	}
+	return false
+}
 
-	return nil
+// keyProviderInstanceDiags checks the traversals referencing instances of key providers expanded with for_each, like
+// key_provider.type.name["key"], and returns an error for each instance key which was not produced by the for_each.
+func (e *targetBuilder) keyProviderInstanceDiags(traversals []hcl.Traversal) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, traversal := range traversals {
+		if len(traversal) < 4 || traversal.RootName() != "key_provider" {
+			continue
+		}
+		typeAttr, typeOk := traversal[1].(hcl.TraverseAttr)
+		nameAttr, nameOk := traversal[2].(hcl.TraverseAttr)
+		index, indexOk := traversal[3].(hcl.TraverseIndex)
+		if !typeOk || !nameOk || !indexOk || index.Key.Type() != cty.String || !index.Key.IsKnown() || index.Key.IsNull() {
+			continue
+		}
+		keys, ok := e.instanceKeys[typeAttr.Name][nameAttr.Name]
+		if !ok {
+			continue
+		}
+		key := index.Key.AsString()
+		if !slices.Contains(keys, key) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Undefined Key Provider instance",
+				Detail: fmt.Sprintf(
+					"Key provider %s.%s has no instance with the key %q; the for_each argument produced the keys %q.",
+					typeAttr.Name, nameAttr.Name, key, keys,
+				),
+				Subject: traversal.SourceRange().Ptr(),
+			})
+		}
+	}
+	return diags
+}
 
+// isKeyRotated returns true if both metadata report a key version and the versions differ.
+func isKeyRotated(inMeta keyprovider.KeyMeta, outMeta keyprovider.KeyMeta) bool {
+	in, ok := inMeta.(keyprovider.VersionedKeyMeta)
+	if !ok {
+		return false
+	}
+	out, ok := outMeta.(keyprovider.VersionedKeyMeta)
+	if !ok {
+		return false
+	}
+	return in.KeyVersion() != "" && out.KeyVersion() != "" && in.KeyVersion() != out.KeyVersion()
 }
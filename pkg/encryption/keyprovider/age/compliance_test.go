@@ -0,0 +1,244 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package age
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
+)
+
+func generateIdentity(t *testing.T) *age.X25519Identity {
+	t.Helper()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate age identity: %v", err)
+	}
+	return identity
+}
+
+func TestKeyProvider(t *testing.T) {
+	identity := generateIdentity(t)
+	other := generateIdentity(t)
+
+	identityFile := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(identityFile, []byte("# created: test\n"+identity.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+							recipients = ["%s", "%s"]
+							identities = ["%s"]
+						}`, identity.Recipient(), other.Recipient(), identity),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(_ *Config, p *keyProvider) error {
+						if len(p.recipients) != 2 {
+							return fmt.Errorf("expected 2 recipients, got %d", len(p.recipients))
+						}
+						return nil
+					},
+				},
+				"identity-file": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+							identity_file = %q
+						}`, identityFile),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(_ *Config, p *keyProvider) error {
+						if len(p.identities) != 1 {
+							return fmt.Errorf("expected 1 identity, got %d", len(p.identities))
+						}
+						if len(p.recipients) != 1 {
+							return fmt.Errorf("expected the recipient to be derived from the identity, got %d recipients", len(p.recipients))
+						}
+						return nil
+					},
+				},
+				"recipients-only": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+							recipients = ["%s"]
+						}`, identity.Recipient()),
+					ValidHCL:   true,
+					ValidBuild: true,
+				},
+				"empty": {
+					HCL:        `key_provider "age" "foo" {}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-recipient": {
+					HCL: `key_provider "age" "foo" {
+							recipients = ["age1invalid"]
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-identity": {
+					HCL: `key_provider "age" "foo" {
+							identities = ["AGE-SECRET-KEY-1INVALID"]
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"missing-identity-file": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+							identity_file = %q
+						}`, filepath.Join(t.TempDir(), "missing.txt")),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+							recipients = ["%s"]
+							key_length = 17
+						}`, identity.Recipient()),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: fmt.Sprintf(`key_provider "age" "foo" {
+							recipients = ["%s"]
+							unknown_property = "foo"
+						}`, identity.Recipient()),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config: &Config{
+						Identities: []string{identity.String()},
+					},
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if p.keyLength != defaultKeyLength {
+							return fmt.Errorf("invalid default key length: %v", p.keyLength)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+					Validate:   nil,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: &Config{
+						Identities: []string{identity.String()},
+					},
+					Meta:      &keyMeta{},
+					IsPresent: false,
+					IsValid:   false,
+				},
+				"invalid": {
+					ValidConfig: &Config{
+						Identities: []string{identity.String()},
+					},
+					Meta:      &keyMeta{WrappedKey: []byte("not an age file")},
+					IsPresent: true,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: &Config{
+					Identities: []string{identity.String()},
+				},
+				ValidateKeys: func(dec []byte, enc []byte) error {
+					if len(dec) == 0 {
+						return fmt.Errorf("decryption key is empty")
+					}
+					if len(enc) == 0 {
+						return fmt.Errorf("encryption key is empty")
+					}
+					return nil
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if len(meta.WrappedKey) == 0 {
+						return fmt.Errorf("wrapped key is empty")
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestNoMatchingIdentity(t *testing.T) {
+	identity := generateIdentity(t)
+	other := generateIdentity(t)
+
+	encryptor, meta, err := Config{Recipients: []string{identity.Recipient().String()}}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, meta, err = encryptor.Provide(meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decryptor, _, err := Config{Identities: []string{other.String()}}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, err = decryptor.Provide(meta)
+	if err == nil {
+		t.Fatalf("expected an error when no identity matches")
+	}
+	var typedErr *keyprovider.ErrKeyProviderFailure
+	if !errors.As(err, &typedErr) {
+		t.Fatalf("expected %T, got %T: %v", typedErr, err, err)
+	}
+	if !strings.Contains(err.Error(), "none of the 1 configured age identities") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+func TestMultipleRecipients(t *testing.T) {
+	first := generateIdentity(t)
+	second := generateIdentity(t)
+
+	encryptor, meta, err := Config{
+		Recipients: []string{first.Recipient().String(), second.Recipient().String()},
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, meta, err := encryptor.Provide(meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, identity := range []*age.X25519Identity{first, second} {
+		decryptor, _, err := Config{Identities: []string{identity.String()}}.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		decOut, _, err := decryptor.Provide(meta)
+		if err != nil {
+			t.Fatalf("identity %d failed to decrypt: %v", i, err)
+		}
+		if string(decOut.DecryptionKey) != string(out.EncryptionKey) {
+			t.Fatalf("identity %d returned an incorrect decryption key", i)
+		}
+	}
+}
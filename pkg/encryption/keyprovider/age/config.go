@@ -0,0 +1,120 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package age
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+const defaultKeyLength = 32
+
+// Config contains the configuration for the age key provider supplied by the user.
+type Config struct {
+	// Recipients are the age public keys ("age1...") the data key is wrapped to. If empty, the recipients are
+	// derived from the configured identities.
+	Recipients []string `hcl:"recipients,optional"`
+	// IdentityFile is the path to an age identity file, as generated by age-keygen.
+	IdentityFile string `hcl:"identity_file,optional"`
+	// Identities are inline age secret keys ("AGE-SECRET-KEY-1...").
+	Identities []string `hcl:"identities,optional"`
+	// KeyLength is the length of the generated data key in bytes.
+	KeyLength int `hcl:"key_length,optional"`
+}
+
+// Build will create the usable key provider.
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultKeyLength
+	}
+	switch c.KeyLength {
+	case 16, 32, 64:
+	default:
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("data key length should one of 16, 32 or 64 bytes: got %v", c.KeyLength),
+		}
+	}
+
+	identities, err := c.identities()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recipients := make([]age.Recipient, 0, len(c.Recipients))
+	for i, r := range c.Recipients {
+		recipient, err := age.ParseX25519Recipient(strings.TrimSpace(r))
+		if err != nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("failed to parse recipient %d", i),
+				Cause:   err,
+			}
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if len(recipients) == 0 {
+		for _, identity := range identities {
+			if x25519, ok := identity.(*age.X25519Identity); ok {
+				recipients = append(recipients, x25519.Recipient())
+			}
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "at least one recipient, identity_file or identity must be configured",
+		}
+	}
+
+	return &keyProvider{
+		recipients: recipients,
+		identities: identities,
+		keyLength:  c.KeyLength,
+	}, new(keyMeta), nil
+}
+
+// identities returns the identities from the identity file and the inline identities, in that order.
+func (c Config) identities() ([]age.Identity, error) {
+	var identities []age.Identity
+
+	if c.IdentityFile != "" {
+		f, err := os.Open(c.IdentityFile)
+		if err != nil {
+			return nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("failed to open identity file %s", c.IdentityFile),
+				Cause:   err,
+			}
+		}
+		defer f.Close()
+
+		fileIdentities, err := age.ParseIdentities(f)
+		if err != nil {
+			return nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("failed to parse identity file %s", c.IdentityFile),
+				Cause:   err,
+			}
+		}
+		identities = append(identities, fileIdentities...)
+	}
+
+	for i, id := range c.Identities {
+		identity, err := age.ParseX25519Identity(strings.TrimSpace(id))
+		if err != nil {
+			// The age error messages may contain the key itself, so we don't pass it along.
+			return nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("failed to parse identity %d, please check that it is a valid age secret key", i),
+			}
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
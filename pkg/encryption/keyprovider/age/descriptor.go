@@ -0,0 +1,31 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package age
+
+import (
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+// New creates a new descriptor for the age key provider.
+func New() Descriptor {
+	return &descriptor{}
+}
+
+// Descriptor is an additional interface to allow for providing custom methods.
+type Descriptor interface {
+	keyprovider.Descriptor
+}
+
+type descriptor struct {
+}
+
+func (f descriptor) ID() keyprovider.ID {
+	return "age"
+}
+
+func (f descriptor) ConfigStruct() keyprovider.Config {
+	return &Config{}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package age_test
+
+import (
+	"fmt"
+	"strings"
+
+	"filippo.io/age"
+
+	"github.com/we-dcode/opentofu/pkg/configs"
+	"github.com/we-dcode/opentofu/pkg/encryption"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	agekeyprovider "github.com/we-dcode/opentofu/pkg/encryption/keyprovider/age"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcm"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
+)
+
+var hclConfigTemplate = `key_provider "age" "foo" {
+  identities = [%q]
+}
+
+method "aes_gcm" "bar" {
+  keys = key_provider.age.foo
+}
+
+plan {
+  method = method.aes_gcm.bar
+}
+`
+
+// Example is a full end-to-end example of encrypting and decrypting a plan file with a key wrapped to an age
+// identity. In real-world use the identity would be read from an identity file instead.
+func Example() {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		panic(err)
+	}
+
+	registry := lockingencryptionregistry.New()
+	if err := registry.RegisterKeyProvider(agekeyprovider.New()); err != nil {
+		panic(err)
+	}
+	if err := registry.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("test.hcl", fmt.Sprintf(hclConfigTemplate, identity.String()))
+	if diags.HasErrors() {
+		panic(diags)
+	}
+
+	staticEvaluator := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+
+	enc, diags := encryption.New(registry, cfg, staticEvaluator)
+	if diags.HasErrors() {
+		panic(diags)
+	}
+
+	encryptor := enc.Plan()
+
+	encryptedPlan, err := encryptor.EncryptPlan([]byte("Hello world!"))
+	if err != nil {
+		panic(err)
+	}
+	if strings.Contains(string(encryptedPlan), "Hello world!") {
+		panic("The plan was not encrypted!")
+	}
+	decryptedPlan, err := encryptor.DecryptPlan(encryptedPlan)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%s", decryptedPlan)
+	// Output: Hello world!
+}
@@ -0,0 +1,128 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package age contains a key provider that wraps a random data key to one or more age recipients.
+package age
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+type keyMeta struct {
+	// WrappedKey is the data key encrypted to all configured recipients in the age format.
+	WrappedKey []byte `json:"wrapped_key"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.WrappedKey) != 0
+}
+
+type keyProvider struct {
+	recipients []age.Recipient
+	identities []age.Identity
+	keyLength  int
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: invalid metadata type received: %T", rawMeta),
+		}
+	}
+
+	encryptionKey := make([]byte, p.keyLength)
+	if _, err := rand.Read(encryptionKey); err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate data key",
+			Cause:   err,
+		}
+	}
+
+	wrappedKey, err := p.wrap(encryptionKey)
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to wrap data key to the age recipients",
+			Cause:   err,
+		}
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: encryptionKey,
+	}
+
+	if inMeta.isPresent() {
+		out.DecryptionKey, err = p.unwrap(inMeta.WrappedKey)
+		if err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+	}
+
+	return out, &keyMeta{WrappedKey: wrappedKey}, nil
+}
+
+func (p keyProvider) wrap(key []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w, err := age.Encrypt(buf, p.recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(key); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (p keyProvider) unwrap(wrappedKey []byte) ([]byte, error) {
+	if len(p.identities) == 0 {
+		return nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "cannot decrypt the data key because no age identities are configured, please set identity_file or identities",
+		}
+	}
+
+	r, err := age.Decrypt(bytes.NewReader(wrappedKey), p.identities...)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, &keyprovider.ErrKeyProviderFailure{
+				Message: fmt.Sprintf(
+					"none of the %d configured age identities can decrypt the stored data key, it was likely encrypted to a different recipient",
+					len(p.identities),
+				),
+				Cause: err,
+			}
+		}
+		return nil, &keyprovider.ErrInvalidMetadata{
+			Message: "failed to read the stored age header",
+			Cause:   err,
+		}
+	}
+
+	key, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &keyprovider.ErrInvalidMetadata{
+			Message: "failed to decrypt the stored data key",
+			Cause:   err,
+		}
+	}
+	return key, nil
+}
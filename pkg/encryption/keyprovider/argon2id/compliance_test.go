@@ -0,0 +1,244 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
+)
+
+func TestCompliance(t *testing.T) {
+	validConfig := &Config{
+		randomSource: rand.Reader,
+		Passphrase:   "Hello world! 123",
+		KeyLength:    DefaultKeyLength,
+		Memory:       MinimumMemory,
+		Iterations:   DefaultIterations,
+		Parallelism:  DefaultParallelism,
+		SaltLength:   DefaultSaltLength,
+	}
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *Metadata, *argon2idKeyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *argon2idKeyProvider]{
+				"empty": {
+					HCL: `key_provider "argon2id" "foo" {
+}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+					Validate:   nil,
+				},
+				"basic": {
+					HCL: `key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *argon2idKeyProvider) error {
+						if config.Passphrase != "Hello world! 123" {
+							return fmt.Errorf("invalid passphrase after HCL parsing")
+						}
+						if keyProvider.Passphrase != "Hello world! 123" {
+							return fmt.Errorf("invalid passphrase in key provider")
+						}
+						if config.Memory != DefaultMemory {
+							return fmt.Errorf("incorrect default memory: %d", config.Memory)
+						}
+						return nil
+					},
+				},
+				"extended": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    key_length = %d
+    memory = %d
+    iterations = %d
+    parallelism = %d
+    salt_length = %d
+}`, DefaultKeyLength+1, DefaultMemory+1, DefaultIterations+1, DefaultParallelism+1, DefaultSaltLength+1),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *argon2idKeyProvider) error {
+						if config.KeyLength != DefaultKeyLength+1 {
+							return fmt.Errorf("incorrect key length after HCL parsing: %d", config.KeyLength)
+						}
+						if config.Memory != DefaultMemory+1 {
+							return fmt.Errorf("incorrect memory after HCL parsing: %d", config.Memory)
+						}
+						if config.Iterations != DefaultIterations+1 {
+							return fmt.Errorf("incorrect iterations after HCL parsing: %d", config.Iterations)
+						}
+						if config.Parallelism != DefaultParallelism+1 {
+							return fmt.Errorf("incorrect parallelism after HCL parsing: %d", config.Parallelism)
+						}
+						if config.SaltLength != DefaultSaltLength+1 {
+							return fmt.Errorf("incorrect salt length after HCL parsing: %d", config.SaltLength)
+						}
+						return nil
+					},
+				},
+				"short-passphrase": {
+					HCL: `key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 12"
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"too-little-memory": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    memory = %d
+}`, MinimumMemory-1),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"zero-iterations": {
+					HCL: `key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    iterations = 0
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"too-much-parallelism": {
+					HCL: fmt.Sprintf(`key_provider "argon2id" "foo" {
+    passphrase = "Hello world! 123"
+    parallelism = %d
+}`, MaximumParallelism+1),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *argon2idKeyProvider]{},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *Metadata]{
+				"not-present-salt": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Salt:        nil,
+						Memory:      MinimumMemory,
+						Iterations:  DefaultIterations,
+						Parallelism: DefaultParallelism,
+						KeyLength:   32,
+					},
+					IsPresent: false,
+				},
+				"not-present-memory": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Salt:        []byte("Hello world!"),
+						Memory:      0,
+						Iterations:  DefaultIterations,
+						Parallelism: DefaultParallelism,
+						KeyLength:   32,
+					},
+					IsPresent: false,
+				},
+				"not-present-parallelism": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Salt:        []byte("Hello world!"),
+						Memory:      MinimumMemory,
+						Iterations:  DefaultIterations,
+						Parallelism: 0,
+						KeyLength:   32,
+					},
+					IsPresent: false,
+				},
+				"present-valid": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Salt:        []byte("Hello world!"),
+						Memory:      MinimumMemory,
+						Iterations:  DefaultIterations,
+						Parallelism: DefaultParallelism,
+						KeyLength:   32,
+					},
+					IsPresent: true,
+					IsValid:   true,
+				},
+				"invalid-iterations": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Salt:        []byte("Hello world!"),
+						Memory:      MinimumMemory,
+						Iterations:  -1,
+						Parallelism: DefaultParallelism,
+						KeyLength:   32,
+					},
+					IsPresent: true,
+					IsValid:   false,
+				},
+				"invalid-parallelism": {
+					ValidConfig: validConfig,
+					Meta: &Metadata{
+						Salt:        []byte("Hello world!"),
+						Memory:      MinimumMemory,
+						Iterations:  DefaultIterations,
+						Parallelism: MaximumParallelism + 1,
+						KeyLength:   32,
+					},
+					IsPresent: true,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *Metadata]{
+				ValidConfig: &Config{
+					randomSource: &testRandomSource{t: t},
+					Passphrase:   "Hello world! 123",
+					KeyLength:    DefaultKeyLength,
+					Memory:       MinimumMemory,
+					Iterations:   DefaultIterations,
+					Parallelism:  DefaultParallelism,
+					SaltLength:   DefaultSaltLength,
+				},
+				ValidateKeys: nil,
+				ValidateMetadata: func(meta *Metadata) error {
+					if !meta.isPresent() {
+						return fmt.Errorf("output metadata is not present")
+					}
+					if err := meta.validate(); err != nil {
+						return err
+					}
+					if meta.KeyLength != DefaultKeyLength {
+						return fmt.Errorf("incorrect output metadata key length: %d", meta.KeyLength)
+					}
+					if meta.Memory != MinimumMemory {
+						return fmt.Errorf("incorrect output metadata memory: %d", meta.Memory)
+					}
+					if meta.Iterations != DefaultIterations {
+						return fmt.Errorf("incorrect output metadata iterations: %d", meta.Iterations)
+					}
+					if meta.Parallelism != DefaultParallelism {
+						return fmt.Errorf("incorrect output metadata parallelism: %d", meta.Parallelism)
+					}
+					if len(meta.Salt) != DefaultSaltLength {
+						return fmt.Errorf("incorrect output salt length: %d", len(meta.Salt))
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestKeyProviderCompliance(t *testing.T) {
+	compliancetest.KeyProviderComplianceTest(t, func() keyprovider.Descriptor { return New() }, func() keyprovider.Config {
+		return &Config{
+			randomSource: rand.Reader,
+			Passphrase:   "Hello world! 123",
+			KeyLength:    DefaultKeyLength,
+			Memory:       MinimumMemory,
+			Iterations:   DefaultIterations,
+			Parallelism:  DefaultParallelism,
+			SaltLength:   DefaultSaltLength,
+		}
+	})
+}
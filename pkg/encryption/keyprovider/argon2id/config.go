@@ -0,0 +1,118 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+type Config struct {
+	// Set by the descriptor.
+	randomSource io.Reader
+
+	Passphrase  string `hcl:"passphrase"`
+	KeyLength   int    `hcl:"key_length,optional"`
+	Memory      int    `hcl:"memory,optional"`
+	Iterations  int    `hcl:"iterations,optional"`
+	Parallelism int    `hcl:"parallelism,optional"`
+	SaltLength  int    `hcl:"salt_length,optional"`
+}
+
+// WithPassphrase adds the passphrase and returns the same config for chaining.
+func (c *Config) WithPassphrase(passphrase string) *Config {
+	c.Passphrase = passphrase
+	return c
+}
+
+// WithKeyLength sets the key length and returns the same config for chaining
+func (c *Config) WithKeyLength(length int) *Config {
+	c.KeyLength = length
+	return c
+}
+
+// WithMemory sets the memory in KiB and returns the same config for chaining
+func (c *Config) WithMemory(memory int) *Config {
+	c.Memory = memory
+	return c
+}
+
+// WithIterations sets the iterations and returns the same config for chaining
+func (c *Config) WithIterations(iterations int) *Config {
+	c.Iterations = iterations
+	return c
+}
+
+// WithParallelism sets the parallelism and returns the same config for chaining
+func (c *Config) WithParallelism(parallelism int) *Config {
+	c.Parallelism = parallelism
+	return c
+}
+
+// WithSaltLength sets the salt length and returns the same config for chaining
+func (c *Config) WithSaltLength(length int) *Config {
+	c.SaltLength = length
+	return c
+}
+
+func (c *Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.randomSource == nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "missing randomness source (please don't initialize the Config struct directly, use the descriptor)",
+		}
+	}
+
+	if c.Passphrase == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "no passphrase provided",
+		}
+	}
+
+	if len(c.Passphrase) < MinimumPassphraseLength {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("passphrase is too short (minimum %d characters)", MinimumPassphraseLength),
+		}
+	}
+
+	if c.KeyLength <= 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the key length must be larger than zero",
+		}
+	}
+
+	if c.Memory <= 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the memory must be larger than zero",
+		}
+	}
+	if c.Memory < MinimumMemory {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the memory is dangerously low (<%d KiB), refusing to generate key", MinimumMemory),
+		}
+	}
+
+	if c.Iterations <= 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the number of iterations must be larger than zero",
+		}
+	}
+
+	if c.Parallelism <= 0 || c.Parallelism > MaximumParallelism {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the parallelism must be between 1 and %d", MaximumParallelism),
+		}
+	}
+
+	if c.SaltLength <= 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the salt length must be larger than zero",
+		}
+	}
+
+	return &argon2idKeyProvider{*c}, new(Metadata), nil
+}
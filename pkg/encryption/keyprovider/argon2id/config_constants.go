@@ -0,0 +1,16 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+const (
+	// MinimumMemory is the smallest amount of memory in KiB accepted for new keys, matching the OWASP recommendation:
+	// https://cheatsheetseries.owasp.org/cheatsheets/Password_Storage_Cheat_Sheet.html#argon2id
+	MinimumMemory int = 19 * 1024
+	// MaximumParallelism is the largest number of lanes supported by the Argon2 implementation.
+	MaximumParallelism int = 255
+	// MinimumPassphraseLength is the shortest passphrase accepted.
+	MinimumPassphraseLength int = 16
+)
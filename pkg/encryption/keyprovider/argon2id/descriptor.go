@@ -0,0 +1,66 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+const (
+	// DefaultMemory is the default amount of memory in KiB used for the derivation. The defaults follow the second
+	// recommended option of RFC 9106:
+	// https://www.rfc-editor.org/rfc/rfc9106.html#name-parameter-choice
+	DefaultMemory int = 64 * 1024
+	// DefaultIterations is the default number of passes over the memory.
+	DefaultIterations int = 3
+	// DefaultParallelism is the default number of lanes used for the derivation.
+	DefaultParallelism int = 4
+	// DefaultSaltLength specifies the default salt length in bytes.
+	DefaultSaltLength int = 32
+	// DefaultKeyLength is the default output length. We set it to the key length required by AES-GCM 256
+	DefaultKeyLength int = 32
+)
+
+// New creates a new Argon2id key provider descriptor.
+func New() Descriptor {
+	return &descriptor{
+		randomSource: rand.Reader,
+	}
+}
+
+// Descriptor provides TypedConfig on top of keyprovider.Descriptor.
+type Descriptor interface {
+	keyprovider.Descriptor
+
+	TypedConfig() *Config
+}
+
+type descriptor struct {
+	randomSource io.Reader
+}
+
+func (f descriptor) ID() keyprovider.ID {
+	return "argon2id"
+}
+
+func (f descriptor) TypedConfig() *Config {
+	return &Config{
+		randomSource: f.randomSource,
+		Passphrase:   "",
+		KeyLength:    DefaultKeyLength,
+		Memory:       DefaultMemory,
+		Iterations:   DefaultIterations,
+		Parallelism:  DefaultParallelism,
+		SaltLength:   DefaultSaltLength,
+	}
+}
+
+func (f descriptor) ConfigStruct() keyprovider.Config {
+	return f.TypedConfig()
+}
@@ -0,0 +1,18 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id_test
+
+import (
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/argon2id"
+)
+
+func TestDescriptor_ID(t *testing.T) {
+	if id := argon2id.New().ID(); id != "argon2id" {
+		t.Fatalf("incorrect ID: %s", id)
+	}
+}
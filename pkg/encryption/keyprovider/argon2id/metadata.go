@@ -0,0 +1,50 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"fmt"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+// Metadata describes the metadata to be stored alongside the encrypted form. It records all derivation parameters, so
+// the decryption key can be derived even after the configuration changed.
+type Metadata struct {
+	Salt        []byte `json:"salt"`
+	Memory      int    `json:"memory"`
+	Iterations  int    `json:"iterations"`
+	Parallelism int    `json:"parallelism"`
+	KeyLength   int    `json:"key_length"`
+}
+
+func (m Metadata) isPresent() bool {
+	return len(m.Salt) != 0 && m.Memory != 0 && m.Iterations != 0 && m.Parallelism != 0 && m.KeyLength != 0
+}
+
+func (m Metadata) validate() error {
+	if m.Memory < 0 {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid memory (%d)", m.Memory),
+		}
+	}
+	if m.Iterations < 0 {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid number of iterations (%d)", m.Iterations),
+		}
+	}
+	if m.Parallelism < 0 || m.Parallelism > MaximumParallelism {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid parallelism (%d)", m.Parallelism),
+		}
+	}
+	if m.KeyLength < 0 {
+		return &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("invalid key length (%d)", m.KeyLength),
+		}
+	}
+	return nil
+}
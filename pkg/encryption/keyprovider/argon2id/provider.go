@@ -0,0 +1,83 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package argon2id contains a key provider that takes a passphrase and emits an Argon2id hash of the configured length.
+// Unlike PBKDF2, Argon2id is memory-hard, which makes brute-forcing the passphrase on GPUs considerably more expensive.
+package argon2id
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+
+	"golang.org/x/crypto/argon2"
+)
+
+type argon2idKeyProvider struct {
+	Config
+}
+
+func (p argon2idKeyProvider) generateMetadata() (*Metadata, error) {
+	// Build outMeta based on current configuration
+	outMeta := &Metadata{
+		Memory:      p.Memory,
+		Iterations:  p.Iterations,
+		Parallelism: p.Parallelism,
+		Salt:        make([]byte, p.SaltLength),
+		KeyLength:   p.KeyLength,
+	}
+	// Generate new salt
+	if _, err := io.ReadFull(p.randomSource, outMeta.Salt); err != nil {
+		return nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("failed to obtain %d bytes of random data", p.SaltLength),
+			Cause:   err,
+		}
+	}
+	return outMeta, nil
+}
+
+func (p argon2idKeyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
+	}
+	inMeta, ok := rawMeta.(*Metadata)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: incorrect metadata type of %T provided", rawMeta),
+		}
+	}
+
+	outMeta, err := p.generateMetadata()
+	if err != nil {
+		return keyprovider.Output{}, nil, err
+	}
+
+	var decryptionKey []byte
+	if inMeta.isPresent() {
+		if err := inMeta.validate(); err != nil {
+			return keyprovider.Output{}, nil, err
+		}
+		// The stored parameters are used instead of the configured ones, so that tuning the configuration does not
+		// prevent decrypting existing data.
+		decryptionKey = deriveKey(p.Passphrase, inMeta)
+	}
+
+	return keyprovider.Output{
+		EncryptionKey: deriveKey(p.Passphrase, outMeta),
+		DecryptionKey: decryptionKey,
+	}, outMeta, nil
+}
+
+func deriveKey(passphrase string, meta *Metadata) []byte {
+	return argon2.IDKey(
+		[]byte(passphrase),
+		meta.Salt,
+		uint32(meta.Iterations),
+		uint32(meta.Memory),
+		uint8(meta.Parallelism),
+		uint32(meta.KeyLength),
+	)
+}
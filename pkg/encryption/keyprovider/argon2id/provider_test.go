@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package argon2id
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testRandomSource is a predictable reader that outputs the test name as a source of randomness.
+type testRandomSource struct {
+	t *testing.T
+}
+
+func (t testRandomSource) Read(target []byte) (int, error) {
+	name := t.t.Name()
+	for i := 0; i < len(target); i++ {
+		target[i] = name[i%len(name)]
+	}
+	return len(target), nil
+}
+
+func testConfig(t *testing.T) Config {
+	return Config{
+		randomSource: testRandomSource{t},
+		Passphrase:   "Hello world! 123",
+		KeyLength:    32,
+		Memory:       MinimumMemory,
+		Iterations:   1,
+		Parallelism:  1,
+		SaltLength:   16,
+	}
+}
+
+func TestArgon2idKeyProvider_parametersChangeKey(t *testing.T) {
+	base := testConfig(t)
+	baseKeys, _, err := argon2idKeyProvider{base}.Provide(&Metadata{})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := map[string]func(c *Config){
+		"passphrase":  func(c *Config) { c.Passphrase = "Hello world! 456" },
+		"memory":      func(c *Config) { c.Memory++ },
+		"iterations":  func(c *Config) { c.Iterations++ },
+		"parallelism": func(c *Config) { c.Parallelism++ },
+		"salt_length": func(c *Config) { c.SaltLength++ },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := base
+			modify(&cfg)
+			keys, _, err := argon2idKeyProvider{cfg}.Provide(&Metadata{})
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if bytes.Equal(keys.EncryptionKey, baseKeys.EncryptionKey) {
+				t.Fatalf("changing %s did not change the derived key", name)
+			}
+		})
+	}
+}
+
+func TestArgon2idKeyProvider_decryptionUsesStoredParameters(t *testing.T) {
+	original := testConfig(t)
+	originalKeys, meta, err := argon2idKeyProvider{original}.Provide(&Metadata{})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// Tune every parameter after the data was encrypted.
+	changed := original
+	changed.KeyLength = 64
+	changed.Memory = MinimumMemory * 2
+	changed.Iterations = 2
+	changed.Parallelism = 2
+	changed.SaltLength = 32
+
+	keys, outMeta, err := argon2idKeyProvider{changed}.Provide(meta)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !bytes.Equal(keys.DecryptionKey, originalKeys.EncryptionKey) {
+		t.Fatalf("the decryption key was not derived from the stored parameters")
+	}
+	if len(keys.EncryptionKey) != 64 {
+		t.Fatalf("the encryption key was not derived from the current configuration: %d bytes", len(keys.EncryptionKey))
+	}
+	typedMeta, ok := outMeta.(*Metadata)
+	if !ok {
+		t.Fatalf("incorrect metadata type: %T", outMeta)
+	}
+	if typedMeta.Memory != changed.Memory || typedMeta.Iterations != changed.Iterations || typedMeta.Parallelism != changed.Parallelism {
+		t.Fatalf("the output metadata does not match the current configuration: %#v", typedMeta)
+	}
+}
+
+func TestArgon2idKeyProvider_badMetadata(t *testing.T) {
+	_, _, err := argon2idKeyProvider{testConfig(t)}.Provide(&Metadata{
+		Salt:        []byte("Hello world!"),
+		Memory:      MinimumMemory,
+		Iterations:  1,
+		Parallelism: MaximumParallelism + 1,
+		KeyLength:   32,
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
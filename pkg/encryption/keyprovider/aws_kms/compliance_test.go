@@ -44,6 +44,24 @@ func TestKeyProvider(t *testing.T) {
 						return nil
 					},
 				},
+				"encryption-context": {
+					HCL: fmt.Sprintf(`key_provider "aws_kms" "foo" {
+							kms_key_id = "%s"
+							key_spec = "AES_256"
+							encryption_context = {
+								project = "opentofu"
+							}
+							skip_credentials_validation = true // required for mocking
+						}`, testKeyId),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if config.EncryptionContext["project"] != "opentofu" {
+							return fmt.Errorf("incorrect encryption context returned")
+						}
+						return nil
+					},
+				},
 				"empty": {
 					HCL:        `key_provider "aws_kms" "foo" {}`,
 					ValidHCL:   false,
@@ -29,8 +29,9 @@ var newKMSFromConfig func(aws.Config) kmsClient = func(cfg aws.Config) kmsClient
 
 type Config struct {
 	// KeyProvider Config
-	KMSKeyID string `hcl:"kms_key_id"`
-	KeySpec  string `hcl:"key_spec"`
+	KMSKeyID          string            `hcl:"kms_key_id"`
+	KeySpec           string            `hcl:"key_spec"`
+	EncryptionContext map[string]string `hcl:"encryption_context,optional"`
 
 	// Mirrored S3 Backend Config, mirror any changes
 	AccessKey                      string                     `hcl:"access_key,optional"`
@@ -232,6 +233,14 @@ func (c Config) validate() (err error) {
 		}
 	}
 
+	for k := range c.EncryptionContext {
+		if k == "" {
+			return &keyprovider.ErrInvalidConfiguration{
+				Message: "encryption_context keys must not be empty",
+			}
+		}
+	}
+
 	return nil
 }
 
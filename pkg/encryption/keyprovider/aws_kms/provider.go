@@ -16,6 +16,9 @@ import (
 
 type keyMeta struct {
 	CiphertextBlob []byte `json:"ciphertext_blob"`
+	// EncryptionContext is the KMS encryption context the data key was
+	// encrypted with, which must be supplied again to decrypt it.
+	EncryptionContext map[string]string `json:"encryption_context,omitempty"`
 }
 
 func (m keyMeta) isPresent() bool {
@@ -49,8 +52,9 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 	spec := types.DataKeySpec(p.KeySpec)
 
 	generatedKeyData, err := p.svc.GenerateDataKey(p.ctx, &kms.GenerateDataKeyInput{
-		KeyId:   aws.String(p.KMSKeyID),
-		KeySpec: spec,
+		KeyId:             aws.String(p.KMSKeyID),
+		KeySpec:           spec,
+		EncryptionContext: p.EncryptionContext,
 	})
 
 	if err != nil {
@@ -63,19 +67,31 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 	// Set initial outputs that are always set
 	out.EncryptionKey = generatedKeyData.Plaintext
 	outMeta.CiphertextBlob = generatedKeyData.CiphertextBlob
+	outMeta.EncryptionContext = p.EncryptionContext
 
 	// We do not set the DecryptionKey here as we should only be setting the decryption key if we are decrypting
 	// and that is handled below when we check if the inMeta has a CiphertextBlob
 
 	if inMeta.isPresent() {
 		// We have an existing decryption key to decrypt, so we should now populate the DecryptionKey
+		// The data key must be decrypted with the encryption context it was
+		// encrypted with, which may differ from the configured one if the
+		// configuration has changed since.
 		decryptedKeyData, decryptErr := p.svc.Decrypt(p.ctx, &kms.DecryptInput{
-			KeyId:          aws.String(p.KMSKeyID),
-			CiphertextBlob: inMeta.CiphertextBlob,
+			KeyId:             aws.String(p.KMSKeyID),
+			CiphertextBlob:    inMeta.CiphertextBlob,
+			EncryptionContext: inMeta.EncryptionContext,
 		})
 
 		if decryptErr != nil {
-			return out, outMeta, &keyprovider.ErrKeyProviderFailure{Cause: decryptErr}
+			message := "failed to decrypt key"
+			if len(inMeta.EncryptionContext) != 0 {
+				message += " with the stored encryption context"
+			}
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: message,
+				Cause:   decryptErr,
+			}
 		}
 
 		// Set decryption key on the output
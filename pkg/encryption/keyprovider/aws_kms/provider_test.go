@@ -6,8 +6,13 @@
 package aws_kms
 
 import (
+	"errors"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 )
 
 func getKey(t *testing.T) string {
@@ -80,3 +85,76 @@ func TestKMSProvider_Simple(t *testing.T) {
 		t.Fatalf("No ciphertext blob provided")
 	}
 }
+
+func TestKMSProvider_EncryptionContext(t *testing.T) {
+	encryptionContext := map[string]string{"project": "opentofu"}
+
+	var genkeyContext, decryptContext map[string]string
+	injectMock(&mockKMS{
+		genkey: func(params *kms.GenerateDataKeyInput) (*kms.GenerateDataKeyOutput, error) {
+			genkeyContext = params.EncryptionContext
+			return &kms.GenerateDataKeyOutput{
+				CiphertextBlob: []byte("ciphertext"),
+				Plaintext:      []byte("plaintext"),
+			}, nil
+		},
+		decrypt: func(params *kms.DecryptInput) (*kms.DecryptOutput, error) {
+			decryptContext = params.EncryptionContext
+			if !reflect.DeepEqual(params.EncryptionContext, encryptionContext) {
+				return nil, errors.New("InvalidCiphertextException")
+			}
+			return &kms.DecryptOutput{
+				Plaintext: []byte("plaintext"),
+			}, nil
+		},
+	})
+
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "accesskey")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secretkey")
+
+	providerConfig := Config{
+		KMSKeyID:          "alias/my-mock-key",
+		KeySpec:           "AES_256",
+		EncryptionContext: encryptionContext,
+
+		SkipCredsValidation: true, // Required for mocking
+	}
+
+	provider, metaIn, err := providerConfig.Build()
+	if err != nil {
+		t.Fatalf("Error building provider: %s", err)
+	}
+
+	_, meta, err := provider.Provide(metaIn)
+	if err != nil {
+		t.Fatalf("Error providing keys: %s", err)
+	}
+	if !reflect.DeepEqual(genkeyContext, encryptionContext) {
+		t.Fatalf("Incorrect encryption context for GenerateDataKey: %v", genkeyContext)
+	}
+	if !reflect.DeepEqual(meta.(*keyMeta).EncryptionContext, encryptionContext) {
+		t.Fatalf("Encryption context not stored in the metadata: %v", meta.(*keyMeta).EncryptionContext)
+	}
+
+	output, _, err := provider.Provide(meta)
+	if err != nil {
+		t.Fatalf("Error providing keys: %s", err)
+	}
+	if !reflect.DeepEqual(decryptContext, encryptionContext) {
+		t.Fatalf("Incorrect encryption context for Decrypt: %v", decryptContext)
+	}
+	if len(output.DecryptionKey) == 0 {
+		t.Fatalf("No decryption key provided")
+	}
+
+	// A different encryption context must fail with the KMS error.
+	meta.(*keyMeta).EncryptionContext = map[string]string{"project": "other"}
+	_, _, err = provider.Provide(meta)
+	if err == nil {
+		t.Fatalf("Expected an error for a mismatched encryption context")
+	}
+	if !strings.Contains(err.Error(), "encryption context") || !strings.Contains(err.Error(), "InvalidCiphertextException") {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
+)
+
+func getVault(t *testing.T) (string, string) {
+	if os.Getenv("TF_ACC") == "" && os.Getenv("TF_KMS_TEST") == "" {
+		return "", ""
+	}
+	return os.Getenv("TF_AZURE_KEYVAULT_URL"), os.Getenv("TF_AZURE_KEYVAULT_KEY_NAME")
+}
+
+func TestKeyProvider(t *testing.T) {
+	testVaultURL, testKeyName := getVault(t)
+
+	if testVaultURL == "" || testKeyName == "" {
+		testVaultURL = "https://tofu-test.vault.azure.net/"
+		testKeyName = "tofu-test-key"
+		injectMock(&mockKeyVault{
+			keyName:        testKeyName,
+			currentVersion: "v1",
+		})
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_url = "%s"
+							key_name = "%s"
+						}`, testVaultURL, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if config.KeyName != testKeyName {
+							return fmt.Errorf("incorrect key name returned")
+						}
+						if keyProvider.algorithm != azkeys.EncryptionAlgorithmRSAOAEP256 {
+							return fmt.Errorf("incorrect default algorithm: %s", keyProvider.algorithm)
+						}
+						return nil
+					},
+				},
+				"success-full": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_url = "%s"
+							key_name = "%s"
+							key_version = "v1"
+							algorithm = "RSA-OAEP"
+							key_length = 16
+						}`, testVaultURL, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: true,
+				},
+				"empty": {
+					HCL:        `key_provider "azure_keyvault" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"empty-vault-url": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_url = ""
+							key_name = "%s"
+						}`, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-vault-url": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_url = "http://tofu-test.vault.azure.net/"
+							key_name = "%s"
+						}`, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"empty-key-name": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_url = "%s"
+							key_name = ""
+						}`, testVaultURL),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-algorithm": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_url = "%s"
+							key_name = "%s"
+							algorithm = "RSA1_5"
+						}`, testVaultURL, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_url = "%s"
+							key_name = "%s"
+							key_length = 17
+						}`, testVaultURL, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_url = "%s"
+							key_name = "%s"
+							unknown_property = "foo"
+						}`, testVaultURL, testKeyName),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config: &Config{
+						VaultURL: testVaultURL,
+						KeyName:  testKeyName,
+					},
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if p.keyLength != defaultKeyLength {
+							return fmt.Errorf("invalid default key length: %v", p.keyLength)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+					Validate:   nil,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: &Config{
+						VaultURL: testVaultURL,
+						KeyName:  testKeyName,
+					},
+					Meta:      &keyMeta{},
+					IsPresent: false,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: &Config{
+					VaultURL: testVaultURL,
+					KeyName:  testKeyName,
+				},
+				ValidateKeys: func(dec []byte, enc []byte) error {
+					if len(dec) == 0 {
+						return fmt.Errorf("decryption key is empty")
+					}
+					if len(enc) == 0 {
+						return fmt.Errorf("encryption key is empty")
+					}
+					return nil
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if len(meta.Ciphertext) == 0 {
+						return fmt.Errorf("ciphertext is empty")
+					}
+					if meta.Version == "" {
+						return fmt.Errorf("key version was not recorded")
+					}
+					return nil
+				},
+			},
+		})
+}
+
+func TestRotatedKeyVersion(t *testing.T) {
+	mock := &mockKeyVault{
+		keyName:        "tofu-test-key",
+		currentVersion: "v1",
+	}
+	injectMock(mock)
+
+	cfg := Config{
+		VaultURL: "https://tofu-test.vault.azure.net/",
+		KeyName:  "tofu-test-key",
+	}
+
+	provider, meta, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, meta, err := provider.Provide(meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := meta.(*keyMeta).Version; v != "v1" {
+		t.Fatalf("expected key version v1 to be recorded, got %q", v)
+	}
+
+	// Rotate the key, the data key wrapped with the previous version must still be unwrapped with that version.
+	mock.currentVersion = "v2"
+
+	provider, _, err = cfg.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out2, meta2, err := provider.Provide(meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(out.EncryptionKey, out2.DecryptionKey) {
+		t.Fatalf("the decryption key does not match the previous encryption key")
+	}
+	if v := meta2.(*keyMeta).Version; v != "v2" {
+		t.Fatalf("expected key version v2 to be recorded after rotation, got %q", v)
+	}
+}
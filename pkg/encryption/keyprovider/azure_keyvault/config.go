@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+type keyVaultClientInit func(vaultURL string) (keyVaultClient, error)
+
+// newKeyVaultClient can be overridden for test mocking.
+var newKeyVaultClient keyVaultClientInit = func(vaultURL string) (keyVaultClient, error) {
+	// The default credential chain tries the environment, workload identity, managed identity and the Azure CLI, in
+	// that order, so the same configuration works both locally and in pipelines.
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return azkeys.NewClient(vaultURL, cred, nil)
+}
+
+type Config struct {
+	VaultURL   string `hcl:"vault_url"`
+	KeyName    string `hcl:"key_name"`
+	KeyVersion string `hcl:"key_version,optional"`
+
+	Algorithm string `hcl:"algorithm,optional"`
+	KeyLength int    `hcl:"key_length,optional"`
+}
+
+const (
+	defaultKeyLength = 32
+	defaultAlgorithm = azkeys.EncryptionAlgorithmRSAOAEP256
+)
+
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.VaultURL == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "vault_url must be provided"}
+	}
+	if u, err := url.Parse(c.VaultURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("vault_url must be a valid https URL, such as https://myvault.vault.azure.net/: got %q", c.VaultURL),
+		}
+	}
+	if c.KeyName == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_name must be provided"}
+	}
+
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultKeyLength
+	}
+	switch c.KeyLength {
+	case 16, 32, 64:
+	default:
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("data key length should one of 16, 32 or 64 bytes: got %v", c.KeyLength),
+		}
+	}
+
+	algorithm := defaultAlgorithm
+	if c.Algorithm != "" {
+		algorithm = azkeys.EncryptionAlgorithm(c.Algorithm)
+		if !isSupportedAlgorithm(algorithm) {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("unsupported key wrapping algorithm %q, expected one of %v", c.Algorithm, supportedAlgorithms),
+			}
+		}
+	}
+
+	svc, err := newKeyVaultClient(c.VaultURL)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "failed to create Azure Key Vault client",
+			Cause:   err,
+		}
+	}
+
+	return &keyProvider{
+		svc:        svc,
+		ctx:        context.Background(),
+		keyName:    c.KeyName,
+		keyVersion: c.KeyVersion,
+		algorithm:  algorithm,
+		keyLength:  c.KeyLength,
+	}, new(keyMeta), nil
+}
+
+// supportedAlgorithms are the Key Vault wrapping algorithms suitable for protecting a data key.
+var supportedAlgorithms = []azkeys.EncryptionAlgorithm{
+	azkeys.EncryptionAlgorithmRSAOAEP256,
+	azkeys.EncryptionAlgorithmRSAOAEP,
+	azkeys.EncryptionAlgorithmA256KW,
+}
+
+func isSupportedAlgorithm(algorithm azkeys.EncryptionAlgorithm) bool {
+	for _, a := range supportedAlgorithms {
+		if a == algorithm {
+			return true
+		}
+	}
+	return false
+}
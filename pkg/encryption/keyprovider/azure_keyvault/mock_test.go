@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+)
+
+// mockKeyVault emulates the wrap and unwrap operations of a Key Vault with a single key that can have multiple
+// versions.
+type mockKeyVault struct {
+	vaultURL       string
+	keyName        string
+	currentVersion string
+}
+
+func (m *mockKeyVault) WrapKey(_ context.Context, name string, version string, parameters azkeys.KeyOperationParameters, _ *azkeys.WrapKeyOptions) (azkeys.WrapKeyResponse, error) {
+	if name != m.keyName {
+		return azkeys.WrapKeyResponse{}, fmt.Errorf("key not found: %s", name)
+	}
+	if version == "" {
+		version = m.currentVersion
+	}
+	kid := azkeys.ID(fmt.Sprintf("%skeys/%s/%s", m.vaultURL, name, version))
+	return azkeys.WrapKeyResponse{
+		KeyOperationResult: azkeys.KeyOperationResult{
+			KID:    &kid,
+			Result: append([]byte(version+":"), parameters.Value...),
+		},
+	}, nil
+}
+
+func (m *mockKeyVault) UnwrapKey(_ context.Context, name string, version string, parameters azkeys.KeyOperationParameters, _ *azkeys.UnwrapKeyOptions) (azkeys.UnwrapKeyResponse, error) {
+	if name != m.keyName {
+		return azkeys.UnwrapKeyResponse{}, fmt.Errorf("key not found: %s", name)
+	}
+	prefix := []byte(version + ":")
+	if len(parameters.Value) < len(prefix) || string(parameters.Value[:len(prefix)]) != string(prefix) {
+		return azkeys.UnwrapKeyResponse{}, fmt.Errorf("ciphertext was not wrapped with version %q of key %s", version, name)
+	}
+	return azkeys.UnwrapKeyResponse{
+		KeyOperationResult: azkeys.KeyOperationResult{
+			Result: parameters.Value[len(prefix):],
+		},
+	}, nil
+}
+
+func injectMock(m *mockKeyVault) {
+	newKeyVaultClient = func(vaultURL string) (keyVaultClient, error) {
+		m.vaultURL = vaultURL
+		return m, nil
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"context"
+	"crypto/rand"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+type keyMeta struct {
+	Ciphertext []byte `json:"ciphertext"`
+	// Version is the version of the Key Vault key that wrapped the data key. It is recorded so that decryption
+	// keeps working after the key is rotated.
+	Version   string                     `json:"key_version,omitempty"`
+	Algorithm azkeys.EncryptionAlgorithm `json:"algorithm,omitempty"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.Ciphertext) != 0
+}
+
+// KeyVersion implements keyprovider.VersionedKeyMeta.
+func (m keyMeta) KeyVersion() string {
+	return m.Version
+}
+
+type keyVaultClient interface {
+	WrapKey(ctx context.Context, name string, version string, parameters azkeys.KeyOperationParameters, options *azkeys.WrapKeyOptions) (azkeys.WrapKeyResponse, error)
+	UnwrapKey(ctx context.Context, name string, version string, parameters azkeys.KeyOperationParameters, options *azkeys.UnwrapKeyOptions) (azkeys.UnwrapKeyResponse, error)
+}
+
+type keyProvider struct {
+	svc        keyVaultClient
+	ctx        context.Context
+	keyName    string
+	keyVersion string
+	algorithm  azkeys.EncryptionAlgorithm
+	keyLength  int
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
+	}
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: invalid metadata struct type"}
+	}
+
+	outMeta := &keyMeta{}
+	out := keyprovider.Output{}
+
+	// Generate new key
+	out.EncryptionKey = make([]byte, p.keyLength)
+	_, err := rand.Read(out.EncryptionKey)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate key",
+			Cause:   err,
+		}
+	}
+
+	// Wrap the new encryption key using the Key Vault key
+	algorithm := p.algorithm
+	wrapped, err := p.svc.WrapKey(p.ctx, p.keyName, p.keyVersion, azkeys.KeyOperationParameters{
+		Algorithm: &algorithm,
+		Value:     out.EncryptionKey,
+	}, nil)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to wrap key with Azure Key Vault (check if the configuration is valid and the vault is accessible)",
+			Cause:   err,
+		}
+	}
+
+	outMeta.Ciphertext = wrapped.Result
+	outMeta.Algorithm = p.algorithm
+	outMeta.Version = p.keyVersion
+	if wrapped.KID != nil && wrapped.KID.Version() != "" {
+		outMeta.Version = wrapped.KID.Version()
+	}
+
+	if inMeta.isPresent() {
+		unwrapAlgorithm := inMeta.Algorithm
+		if unwrapAlgorithm == "" {
+			unwrapAlgorithm = p.algorithm
+		}
+
+		// We have an existing decryption key to unwrap, so we should now populate the DecryptionKey
+		unwrapped, unwrapErr := p.svc.UnwrapKey(p.ctx, p.keyName, inMeta.Version, azkeys.KeyOperationParameters{
+			Algorithm: &unwrapAlgorithm,
+			Value:     inMeta.Ciphertext,
+		}, nil)
+		if unwrapErr != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to unwrap key with Azure Key Vault",
+				Cause:   unwrapErr,
+			}
+		}
+
+		// Set decryption key on the output
+		out.DecryptionKey = unwrapped.Result
+	}
+
+	return out, outMeta, nil
+}
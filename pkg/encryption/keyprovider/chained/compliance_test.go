@@ -0,0 +1,176 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package chained
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
+)
+
+func TestKeyProvider(t *testing.T) {
+	keyA := bytes.Repeat([]byte{1}, 32)
+	keyB := bytes.Repeat([]byte{2}, 32)
+
+	expected, err := combine(keyA, keyB, defaultKeyLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, keyprovider.KeyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: `key_provider "chained" "foo" {
+							a = { encryption_key = [1, 1, 1, 1], decryption_key = [1, 1, 1, 1] }
+							b = { encryption_key = [2, 2], decryption_key = [2, 2] }
+						}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(_ *Config, p *keyProvider) error {
+						if len(p.key.EncryptionKey) != defaultKeyLength {
+							return fmt.Errorf("incorrect encryption key length: %d", len(p.key.EncryptionKey))
+						}
+						if !bytes.Equal(p.key.EncryptionKey, p.key.DecryptionKey) {
+							return fmt.Errorf("the encryption and decryption keys do not match")
+						}
+						return nil
+					},
+				},
+				"key-length": {
+					HCL: `key_provider "chained" "foo" {
+							a          = { encryption_key = [1], decryption_key = null }
+							b          = { encryption_key = [2], decryption_key = null }
+							key_length = 16
+						}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(_ *Config, p *keyProvider) error {
+						if len(p.key.EncryptionKey) != 16 {
+							return fmt.Errorf("incorrect encryption key length: %d", len(p.key.EncryptionKey))
+						}
+						return nil
+					},
+				},
+				"missing-b-decryption-key": {
+					HCL: `key_provider "chained" "foo" {
+							a = { encryption_key = [1], decryption_key = [1] }
+							b = { encryption_key = [2], decryption_key = null }
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: `key_provider "chained" "foo" {
+							a          = { encryption_key = [1], decryption_key = null }
+							b          = { encryption_key = [2], decryption_key = null }
+							key_length = 17
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"missing-b": {
+					HCL: `key_provider "chained" "foo" {
+							a = { encryption_key = [1], decryption_key = null }
+						}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config: &Config{
+						A: keyprovider.Output{EncryptionKey: keyA},
+						B: keyprovider.Output{EncryptionKey: keyB},
+					},
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if !bytes.Equal(p.key.EncryptionKey, expected) {
+							return fmt.Errorf("incorrect encryption key")
+						}
+						if len(p.key.DecryptionKey) != 0 {
+							return fmt.Errorf("unexpected decryption key")
+						}
+						return nil
+					},
+				},
+				"missing-a-encryption-key": {
+					Config: &Config{
+						B: keyprovider.Output{EncryptionKey: keyB},
+					},
+					ValidBuild: false,
+				},
+				"missing-a-decryption-key": {
+					Config: &Config{
+						A: keyprovider.Output{EncryptionKey: keyA},
+						B: keyprovider.Output{EncryptionKey: keyB, DecryptionKey: keyB},
+					},
+					ValidBuild: false,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, keyprovider.KeyMeta]{
+				"empty": {
+					ValidConfig: &Config{
+						A: keyprovider.Output{EncryptionKey: keyA},
+						B: keyprovider.Output{EncryptionKey: keyB},
+					},
+					Meta:      nil,
+					IsPresent: false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, keyprovider.KeyMeta]{
+				ValidConfig: &Config{
+					A: keyprovider.Output{EncryptionKey: keyA, DecryptionKey: keyA},
+					B: keyprovider.Output{EncryptionKey: keyB, DecryptionKey: keyB},
+				},
+				ExpectedOutput: &keyprovider.Output{
+					EncryptionKey: expected,
+					DecryptionKey: expected,
+				},
+			},
+		},
+	)
+}
+
+func TestCombineDependsOnBothKeys(t *testing.T) {
+	keyA := []byte{1, 2, 3, 4}
+	keyB := []byte{5, 6, 7, 8}
+
+	base, err := combine(keyA, keyB, defaultKeyLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, inputs := range map[string][2][]byte{
+		"a-changed": {{1, 2, 3, 5}, keyB},
+		"b-changed": {keyA, {5, 6, 7, 9}},
+		"swapped":   {keyB, keyA},
+		"shifted":   {{1, 2, 3}, {4, 5, 6, 7, 8}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			key, err := combine(inputs[0], inputs[1], defaultKeyLength)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(key, base) {
+				t.Fatalf("the combined key did not change")
+			}
+		})
+	}
+}
+
+func TestKeyProviderCompliance(t *testing.T) {
+	compliancetest.KeyProviderComplianceTest(t, func() keyprovider.Descriptor { return New() }, func() keyprovider.Config {
+		return &Config{
+			A: keyprovider.Output{EncryptionKey: bytes.Repeat([]byte{1}, 32)},
+			B: keyprovider.Output{EncryptionKey: bytes.Repeat([]byte{2}, 32)},
+		}
+	})
+}
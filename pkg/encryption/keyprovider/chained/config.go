@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package chained
+
+import (
+	"fmt"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+const defaultKeyLength = 32
+
+// Config contains the configuration for this key provider supplied by the user. This struct must have hcl tags in order
+// to function.
+type Config struct {
+	// A is the output of the first key provider, typically referenced as key_provider.<type>.<name>.
+	A keyprovider.Output `hcl:"a"`
+	// B is the output of the second key provider.
+	B keyprovider.Output `hcl:"b"`
+	// KeyLength is the length of the combined key in bytes.
+	KeyLength int `hcl:"key_length,optional"`
+}
+
+// Build will create the usable key provider.
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultKeyLength
+	}
+	switch c.KeyLength {
+	case 16, 32, 64:
+	default:
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("key length should be one of 16, 32 or 64 bytes: got %v", c.KeyLength),
+		}
+	}
+
+	if len(c.A.EncryptionKey) == 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "missing encryption key from key provider a",
+		}
+	}
+	if len(c.B.EncryptionKey) == 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "missing encryption key from key provider b",
+		}
+	}
+
+	// Both key providers must be able to decrypt, otherwise we would silently fall back to a key derived from only
+	// one of them.
+	switch {
+	case len(c.A.DecryptionKey) != 0 && len(c.B.DecryptionKey) == 0:
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "key provider b did not return a decryption key, but key provider a did; both key providers must be able to decrypt",
+		}
+	case len(c.A.DecryptionKey) == 0 && len(c.B.DecryptionKey) != 0:
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "key provider a did not return a decryption key, but key provider b did; both key providers must be able to decrypt",
+		}
+	}
+
+	encryptionKey, err := combine(c.A.EncryptionKey, c.B.EncryptionKey, c.KeyLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	var decryptionKey []byte
+	if len(c.A.DecryptionKey) != 0 {
+		decryptionKey, err = combine(c.A.DecryptionKey, c.B.DecryptionKey, c.KeyLength)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &keyProvider{keyprovider.Output{
+		EncryptionKey: encryptionKey,
+		DecryptionKey: decryptionKey,
+	}}, nil, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package chained contains a key provider that combines the keys of two other key providers.
+package chained
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+// hkdfInfo binds the derived keys to this key provider, so the same inputs used elsewhere yield unrelated keys.
+const hkdfInfo = "opentofu chained key provider"
+
+type keyProvider struct {
+	key keyprovider.Output
+}
+
+func (p keyProvider) Provide(meta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if meta != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: metadata provided despite none being required: %T", meta),
+		}
+	}
+
+	return p.key, nil, nil
+}
+
+// combine derives a key of the given length from the two input keys. The length of the first key is prefixed to the
+// input keying material so that the boundary between the two keys is unambiguous.
+func combine(a []byte, b []byte, length int) ([]byte, error) {
+	ikm := make([]byte, 0, 4+len(a)+len(b))
+	ikm = binary.BigEndian.AppendUint32(ikm, uint32(len(a)))
+	ikm = append(ikm, a...)
+	ikm = append(ikm, b...)
+
+	key := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, nil, []byte(hkdfInfo)), key); err != nil {
+		return nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to combine the keys",
+			Cause:   err,
+		}
+	}
+	return key, nil
+}
@@ -29,7 +29,7 @@ func ComplianceTest[TDescriptor keyprovider.Descriptor, TConfig keyprovider.Conf
 	}
 
 	var meta TMeta
-	metaType := reflect.TypeOf(cfg)
+	metaType := reflect.TypeOf((*TMeta)(nil)).Elem()
 	if metaType.Kind() != reflect.Interface {
 		if metaType.Kind() != reflect.Ptr || metaType.Elem().Kind() != reflect.Struct {
 			compliancetest.Log(t, "You declared a metadata type as %T, but it should be a pointer to a struct. Please fix your call to ComplianceTest().", meta)
@@ -151,7 +151,7 @@ func complianceTestProvide[TDescriptor keyprovider.Descriptor, TConfig keyprovid
 	t.Run("nil-metadata", func(t *testing.T) {
 		keyProvider, inMeta := complianceTestBuildConfigAndValidate[TKeyProvider, TMeta](t, keyProviderConfig, true)
 
-		if reflect.ValueOf(inMeta).IsNil() {
+		if isNilMeta(inMeta) {
 			compliancetest.Skip(t, "The key provider does not have metadata (no metadata returned from Build()).")
 			return
 		}
@@ -170,7 +170,7 @@ func complianceTestProvide[TDescriptor keyprovider.Descriptor, TConfig keyprovid
 	})
 	t.Run("incorrect-metadata-type", func(t *testing.T) {
 		keyProvider, inMeta := complianceTestBuildConfigAndValidate[TKeyProvider, TMeta](t, keyProviderConfig, true)
-		if reflect.ValueOf(inMeta).IsNil() {
+		if isNilMeta(inMeta) {
 			compliancetest.Skip(t, "The key provider does not have metadata (no metadata returned from Build()).")
 			return
 		}
@@ -389,7 +389,7 @@ func complianceTestBuildConfigAndValidate[TKeyProvider keyprovider.KeyProvider,
 			compliancetest.Log(t, "Build() returned the correct key provider type of %T.", typedKeyProvider)
 		}
 
-		metaType := reflect.TypeOf(typedMeta)
+		metaType := reflect.TypeOf((*TMeta)(nil)).Elem()
 		if meta == nil {
 			if metaType.Kind() != reflect.Interface {
 				compliancetest.Fail(t, "Build() did not return a metadata, but you declared a metadata type. Please make sure that you always return the same metadata type.")
@@ -499,3 +499,9 @@ func complianceTestMetadataTestCase[TConfig keyprovider.Config, TKeyProvider key
 		}
 	}
 }
+
+// isNilMeta returns true if the metadata is nil, including when the declared metadata type is an interface.
+func isNilMeta[TMeta keyprovider.KeyMeta](meta TMeta) bool {
+	v := reflect.ValueOf(meta)
+	return !v.IsValid() || v.IsNil()
+}
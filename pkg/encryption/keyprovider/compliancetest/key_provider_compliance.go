@@ -0,0 +1,214 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compliancetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/we-dcode/opentofu/pkg/encryption/compliancetest"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+// KeyProviderComplianceTest runs a standard set of behavioral tests against a key provider. Unlike ComplianceTest, it
+// needs no provider-specific test cases: descriptorFactory returns the descriptor of the key provider and cfgFactory
+// returns a valid configuration for it, which must describe the same key on every call. The key provider is used the
+// same way the encryption layer uses it, including storing its metadata as JSON between runs:
+//
+//   - config-struct: the descriptor returns a new, properly tagged configuration struct on every call.
+//   - missing-config: an empty configuration block is rejected with diagnostics or a
+//     keyprovider.ErrInvalidConfiguration, and doesn't panic.
+//   - no-metadata: without stored metadata, the key provider returns an encryption key, but no decryption key.
+//   - metadata-round-trip: the metadata stored after one run lets a newly built key provider return the previous
+//     encryption key as its decryption key. Key providers without metadata must return the same encryption key on
+//     every run instead, as nothing else would let them decrypt the data again.
+func KeyProviderComplianceTest(
+	t *testing.T,
+	descriptorFactory func() keyprovider.Descriptor,
+	cfgFactory func() keyprovider.Config,
+) {
+	if descriptorFactory == nil || cfgFactory == nil {
+		compliancetest.Fail(t, "Please provide a descriptorFactory and a cfgFactory to KeyProviderComplianceTest().")
+	}
+
+	t.Run("config-struct", func(t *testing.T) {
+		keyProviderComplianceConfigStruct(t, descriptorFactory)
+	})
+	t.Run("missing-config", func(t *testing.T) {
+		keyProviderComplianceMissingConfig(t, descriptorFactory)
+	})
+	t.Run("no-metadata", func(t *testing.T) {
+		keyProviderComplianceNoMetadata(t, cfgFactory)
+	})
+	t.Run("metadata-round-trip", func(t *testing.T) {
+		keyProviderComplianceMetadataRoundTrip(t, cfgFactory)
+	})
+}
+
+func keyProviderComplianceConfigStruct(t *testing.T, descriptorFactory func() keyprovider.Descriptor) {
+	descriptor := descriptorFactory()
+	if err := descriptor.ID().Validate(); err != nil {
+		compliancetest.Fail(t, "ID failed validation: %s (%v)", descriptor.ID(), err)
+	} else {
+		compliancetest.Log(t, "ID passed validation.")
+	}
+
+	first := descriptor.ConfigStruct()
+	compliancetest.ConfigStruct[keyprovider.Config](t, first)
+
+	second := descriptor.ConfigStruct()
+	// Pointers to zero-sized structs may legitimately be equal.
+	if reflect.TypeOf(first).Elem().Size() > 0 && reflect.ValueOf(first).Pointer() == reflect.ValueOf(second).Pointer() {
+		compliancetest.Fail(t, "The ConfigStruct() method returned the same struct twice. Please return a new struct on every call, as each key provider block is decoded into its own struct.")
+	} else {
+		compliancetest.Log(t, "The ConfigStruct() method returns a new struct on every call.")
+	}
+}
+
+func keyProviderComplianceMissingConfig(t *testing.T, descriptorFactory func() keyprovider.Descriptor) {
+	descriptor := descriptorFactory()
+	parsedConfig, diags := config.LoadConfigFromString("config.hcl", fmt.Sprintf(`key_provider %q "test" {}`, descriptor.ID()))
+	if diags.HasErrors() {
+		compliancetest.Fail(t, "Unexpected HCL error (%v).", diags)
+	}
+
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				compliancetest.Fail(t, "Loading or building an empty configuration panicked: %v", r)
+			}
+		}()
+		configStruct := descriptor.ConfigStruct()
+		diags = gohcl.DecodeBody(parsedConfig.KeyProviderConfigs[0].Body, nil, configStruct)
+		if !diags.HasErrors() {
+			_, _, err = configStruct.Build()
+		}
+	}()
+
+	switch {
+	case diags.HasErrors():
+		for _, diag := range diags {
+			if diag.Severity == hcl.DiagError && diag.Summary == "" {
+				compliancetest.Fail(t, "Decoding an empty configuration returned an error diagnostic without a summary.")
+			}
+		}
+		compliancetest.Log(t, "Decoding an empty configuration correctly returned diagnostics (%v).", diags)
+	case err != nil:
+		var typedError *keyprovider.ErrInvalidConfiguration
+		if !errors.As(err, &typedError) {
+			compliancetest.Fail(t, "Build() returned a %T instead of a %T for an empty configuration. Please use the correct typed errors.", err, typedError)
+		} else {
+			compliancetest.Log(t, "Build() correctly returned a %T for an empty configuration (%v).", typedError, err)
+		}
+	default:
+		compliancetest.Log(t, "The key provider can be built without any configuration.")
+	}
+}
+
+func keyProviderComplianceNoMetadata(t *testing.T, cfgFactory func() keyprovider.Config) {
+	keyProvider, meta := keyProviderComplianceBuild(t, cfgFactory)
+
+	output, _, err := keyProvider.Provide(meta)
+	if err != nil {
+		compliancetest.Fail(t, "Provide() failed without stored metadata (%v).", err)
+	}
+	if len(output.EncryptionKey) == 0 {
+		compliancetest.Fail(t, "Provide() did not return an encryption key.")
+	} else {
+		compliancetest.Log(t, "Provide() returned an encryption key.")
+	}
+	if meta != nil && len(output.DecryptionKey) != 0 {
+		compliancetest.Fail(t, "Provide() returned a decryption key without stored metadata. Please don't return a decryption key unless you receive the stored metadata.")
+	} else {
+		compliancetest.Log(t, "Provide() correctly did not return a decryption key without stored metadata.")
+	}
+}
+
+func keyProviderComplianceMetadataRoundTrip(t *testing.T, cfgFactory func() keyprovider.Config) {
+	keyProvider, meta := keyProviderComplianceBuild(t, cfgFactory)
+	output, outMeta, err := keyProvider.Provide(meta)
+	if err != nil {
+		compliancetest.Fail(t, "Provide() failed (%v).", err)
+	}
+
+	// The second run uses a newly built key provider, so no state is carried over other than the stored metadata.
+	keyProvider2, meta2 := keyProviderComplianceBuild(t, cfgFactory)
+
+	if outMeta == nil {
+		output2, _, err := keyProvider2.Provide(meta2)
+		if err != nil {
+			compliancetest.Fail(t, "Provide() on the subsequent run failed (%v).", err)
+		}
+		if !bytes.Equal(output.EncryptionKey, output2.EncryptionKey) {
+			compliancetest.Fail(t, "The key provider returned no metadata, but a different encryption key on the subsequent run. Key providers without metadata must return the same key on every run.")
+		} else {
+			compliancetest.Log(t, "The key provider without metadata returned the same encryption key on every run.")
+		}
+		return
+	}
+
+	storedMeta, err := json.Marshal(outMeta)
+	if err != nil {
+		compliancetest.Fail(t, "JSON-marshalling the output metadata failed (%v).", err)
+	} else {
+		compliancetest.Log(t, "JSON-marshalling the output metadata succeeded: %s", storedMeta)
+	}
+	if meta2 == nil {
+		compliancetest.Fail(t, "Provide() returned metadata, but Build() did not return a metadata struct to load it into. Please always return the metadata struct from Build().")
+	}
+	if err := json.Unmarshal(storedMeta, meta2); err != nil {
+		compliancetest.Fail(t, "JSON-unmarshalling the stored metadata failed (%v).", err)
+	} else {
+		compliancetest.Log(t, "JSON-unmarshalling the stored metadata succeeded.")
+	}
+
+	output2, outMeta2, err := keyProvider2.Provide(meta2)
+	if err != nil {
+		compliancetest.Fail(t, "Provide() with the stored metadata failed (%v).", err)
+	}
+	if !bytes.Equal(output.EncryptionKey, output2.DecryptionKey) {
+		compliancetest.Fail(t, "The decryption key returned with the stored metadata does not match the previous encryption key.")
+	} else {
+		compliancetest.Log(t, "The decryption key returned with the stored metadata matches the previous encryption key.")
+	}
+	if len(output2.EncryptionKey) == 0 {
+		compliancetest.Fail(t, "Provide() with the stored metadata did not return an encryption key.")
+	}
+	if outMeta2 == nil {
+		compliancetest.Fail(t, "Provide() returned metadata on the first run, but not on the subsequent run.")
+	} else if _, err := json.Marshal(outMeta2); err != nil {
+		compliancetest.Fail(t, "JSON-marshalling the output metadata of the subsequent run failed (%v).", err)
+	}
+}
+
+func keyProviderComplianceBuild(t *testing.T, cfgFactory func() keyprovider.Config) (keyprovider.KeyProvider, keyprovider.KeyMeta) {
+	t.Helper()
+
+	cfg := cfgFactory()
+	if cfg == nil {
+		compliancetest.Fail(t, "The cfgFactory returned a nil configuration.")
+	}
+	keyProvider, meta, err := cfg.Build()
+	if err != nil {
+		compliancetest.Fail(t, "Build() returned an unexpected error for the configuration from cfgFactory (%v).", err)
+	}
+	if keyProvider == nil {
+		compliancetest.Fail(t, "Build() returned a nil key provider.")
+	}
+	if v := reflect.ValueOf(meta); meta != nil && v.Kind() == reflect.Ptr && v.IsNil() {
+		// A typed nil pointer can't be loaded from JSON, so treat it the same as no metadata.
+		meta = nil
+	}
+	return keyProvider, meta
+}
@@ -0,0 +1,245 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package external
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
+)
+
+// helperEnv selects the behavior of the test binary when it is run as the external command.
+const helperEnv = "TF_EXTERNAL_KEY_PROVIDER_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(helperEnv); mode != "" {
+		os.Exit(runHelper(mode))
+	}
+	os.Exit(m.Run())
+}
+
+// runHelper emulates an external key command. It is not secure, the key is stored in the external data in plain text.
+func runHelper(mode string) int {
+	switch mode {
+	case "fail":
+		_, _ = fmt.Fprintln(os.Stderr, "secret store unavailable")
+		return 3
+	case "sleep":
+		time.Sleep(10 * time.Second)
+		return 0
+	case "garbage":
+		_, _ = fmt.Fprintln(os.Stdout, "this is not JSON")
+		return 0
+	}
+
+	stdin, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return 1
+	}
+	var input struct {
+		ExternalData *struct {
+			Key []byte `json:"key"`
+		} `json:"external_data"`
+	}
+	if err := json.Unmarshal(stdin, &input); err != nil {
+		return 1
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return 1
+	}
+	output := map[string]any{
+		"keys": map[string][]byte{
+			"encryption_key": key,
+		},
+		"external_data": map[string][]byte{
+			"key": key,
+		},
+	}
+	if input.ExternalData != nil {
+		output["keys"].(map[string][]byte)["decryption_key"] = input.ExternalData.Key
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(output); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func helperConfig(mode string) *Config {
+	return &Config{
+		Command: []string{os.Args[0]},
+		Env: map[string]string{
+			helperEnv: mode,
+		},
+	}
+}
+
+func TestKeyProvider(t *testing.T) {
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "external" "foo" {
+							command = [%q, "--flag"]
+							env = {
+								FOO = "bar"
+							}
+							timeout = "1m"
+						}`, os.Args[0]),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if len(keyProvider.command) != 2 || keyProvider.command[1] != "--flag" {
+							return fmt.Errorf("incorrect command: %v", keyProvider.command)
+						}
+						if len(keyProvider.env) != 1 || keyProvider.env[0] != "FOO=bar" {
+							return fmt.Errorf("incorrect environment: %v", keyProvider.env)
+						}
+						if keyProvider.timeout != time.Minute {
+							return fmt.Errorf("incorrect timeout: %s", keyProvider.timeout)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					HCL:        `key_provider "external" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"empty-command": {
+					HCL: `key_provider "external" "foo" {
+							command = []
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-timeout": {
+					HCL: `key_provider "external" "foo" {
+							command = ["true"]
+							timeout = "soon"
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"negative-timeout": {
+					HCL: `key_provider "external" "foo" {
+							command = ["true"]
+							timeout = "-1s"
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: `key_provider "external" "foo" {
+							command = ["true"]
+							unknown_property = "foo"
+						}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config:     helperConfig("ok"),
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if p.timeout != defaultTimeout {
+							return fmt.Errorf("incorrect default timeout: %s", p.timeout)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+					Validate:   nil,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: helperConfig("ok"),
+					Meta:        &keyMeta{},
+					IsPresent:   false,
+					IsValid:     false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: helperConfig("ok"),
+				ValidateKeys: func(dec []byte, enc []byte) error {
+					if len(dec) == 0 {
+						return fmt.Errorf("decryption key is empty")
+					}
+					if len(enc) == 0 {
+						return fmt.Errorf("encryption key is empty")
+					}
+					return nil
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if !meta.isPresent() {
+						return fmt.Errorf("external data is empty")
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestCommandFailure(t *testing.T) {
+	testCases := map[string]struct {
+		config      *Config
+		wantMessage string
+	}{
+		"non-zero-exit": {
+			config:      helperConfig("fail"),
+			wantMessage: "exited with code 3: secret store unavailable",
+		},
+		"timeout": {
+			config: &Config{
+				Command: []string{os.Args[0]},
+				Env:     map[string]string{helperEnv: "sleep"},
+				Timeout: "100ms",
+			},
+			wantMessage: "did not finish within 100ms",
+		},
+		"invalid-output": {
+			config:      helperConfig("garbage"),
+			wantMessage: "returned invalid JSON",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			provider, meta, err := tc.config.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, _, err = provider.Provide(meta)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			var typedErr *keyprovider.ErrKeyProviderFailure
+			if !errors.As(err, &typedErr) {
+				t.Fatalf("expected %T, got %T: %v", typedErr, err, err)
+			}
+			if !strings.Contains(err.Error(), tc.wantMessage) {
+				t.Fatalf("expected the error to contain %q, got: %v", tc.wantMessage, err)
+			}
+		})
+	}
+}
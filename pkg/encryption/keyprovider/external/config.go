@@ -0,0 +1,63 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package external
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// Config contains the configuration for the external key provider supplied by the user.
+type Config struct {
+	// Command is the program to run, followed by its arguments.
+	Command []string `hcl:"command"`
+	// Env contains additional environment variables for the command. The command inherits the environment of
+	// OpenTofu.
+	Env map[string]string `hcl:"env,optional"`
+	// Timeout is the maximum time the command may run for, as a Go duration string. Defaults to 30s.
+	Timeout string `hcl:"timeout,optional"`
+}
+
+// Build will create the usable key provider.
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if len(c.Command) == 0 || c.Command[0] == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the command must contain at least the program to run",
+		}
+	}
+
+	timeout := defaultTimeout
+	if c.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(c.Timeout)
+		if err != nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("invalid timeout %q", c.Timeout),
+				Cause:   err,
+			}
+		}
+		if timeout <= 0 {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("the timeout must be positive: got %s", c.Timeout),
+			}
+		}
+	}
+
+	env := make([]string, 0, len(c.Env))
+	for name, value := range c.Env {
+		env = append(env, name+"="+value)
+	}
+
+	return &keyProvider{
+		command: c.Command,
+		env:     env,
+		timeout: timeout,
+	}, new(keyMeta), nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package external contains a key provider that obtains keys by running a user-specified command.
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+// keyMeta holds the opaque metadata returned by the command, which is passed back to it on the next run so it can
+// provide the decryption key.
+type keyMeta struct {
+	ExternalData json.RawMessage `json:"external_data,omitempty"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.ExternalData) != 0 && string(m.ExternalData) != "null"
+}
+
+// commandInput is written to the standard input of the command as JSON.
+type commandInput struct {
+	// ExternalData is the metadata the command returned when the data was encrypted, or null if nothing is being
+	// decrypted.
+	ExternalData json.RawMessage `json:"external_data"`
+}
+
+// commandOutput is read from the standard output of the command as JSON. The keys are base64-encoded. The
+// ExternalData is optional and is stored alongside the encrypted data.
+type commandOutput struct {
+	Keys         keyprovider.Output `json:"keys"`
+	ExternalData json.RawMessage    `json:"external_data,omitempty"`
+}
+
+type keyProvider struct {
+	command []string
+	env     []string
+	timeout time.Duration
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: invalid metadata type received: %T", rawMeta),
+		}
+	}
+
+	input := commandInput{
+		ExternalData: json.RawMessage("null"),
+	}
+	if inMeta.isPresent() {
+		input.ExternalData = inMeta.ExternalData
+	}
+	stdin, err := json.Marshal(input)
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "failed to encode the metadata for the external command",
+			Cause:   err,
+		}
+	}
+
+	stdout, err := p.run(stdin)
+	if err != nil {
+		return keyprovider.Output{}, nil, err
+	}
+
+	var result commandOutput
+	if err := json.Unmarshal(stdout, &result); err != nil {
+		// The output may contain key material, so it must not be included in the error.
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("the external command %s returned invalid JSON on its standard output", p.command[0]),
+		}
+	}
+
+	if len(result.Keys.EncryptionKey) == 0 {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("the external command %s did not return an encryption key", p.command[0]),
+		}
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: result.Keys.EncryptionKey,
+	}
+	if inMeta.isPresent() {
+		if len(result.Keys.DecryptionKey) == 0 {
+			return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+				Message: fmt.Sprintf("the external command %s did not return a decryption key for the stored metadata", p.command[0]),
+			}
+		}
+		out.DecryptionKey = result.Keys.DecryptionKey
+	}
+
+	// The metadata must be present even if the command doesn't need any, otherwise the command would not be asked for
+	// the decryption key on the next run.
+	outMeta := &keyMeta{ExternalData: result.ExternalData}
+	if !outMeta.isPresent() {
+		outMeta.ExternalData = json.RawMessage("{}")
+	}
+
+	return out, outMeta, nil
+}
+
+// run executes the command with the given standard input and returns its standard output.
+func (p keyProvider) run(stdin []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	//nolint:gosec // Running a user-specified command is the purpose of this key provider.
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Env = append(os.Environ(), p.env...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// Note: the standard output contains the keys, never log it.
+	log.Printf("[TRACE] external key provider: running %s", p.command[0])
+	err := cmd.Run()
+	if err == nil {
+		return stdout.Bytes(), nil
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, &keyprovider.ErrKeyProviderFailure{
+			Message: fmt.Sprintf("the external command %s did not finish within %s", p.command[0], p.timeout),
+		}
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		message := fmt.Sprintf("the external command %s exited with code %d", p.command[0], exitErr.ExitCode())
+		if errOutput := strings.TrimSpace(stderr.String()); errOutput != "" {
+			message += ": " + errOutput
+		}
+		return nil, &keyprovider.ErrKeyProviderFailure{
+			Message: message,
+		}
+	}
+	return nil, &keyprovider.ErrKeyProviderFailure{
+		Message: fmt.Sprintf("failed to run the external command %s", p.command[0]),
+		Cause:   err,
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gcp_kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+)
+
+// symmetricAlgorithm is the key_algorithm for symmetric crypto keys, which is the default. It is not recorded in the
+// metadata, so metadata written before asymmetric keys were supported is treated as symmetric.
+const symmetricAlgorithm = "GOOGLE_SYMMETRIC_ENCRYPTION"
+
+// asymmetricAlgorithms maps the supported asymmetric crypto key version algorithms to the hash used for OAEP padding.
+var asymmetricAlgorithms = map[kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm]crypto.Hash{
+	kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_2048_SHA256: crypto.SHA256,
+	kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_3072_SHA256: crypto.SHA256,
+	kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA256: crypto.SHA256,
+	kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA512: crypto.SHA512,
+	kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_2048_SHA1:   crypto.SHA1,
+	kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_3072_SHA1:   crypto.SHA1,
+	kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA1:   crypto.SHA1,
+}
+
+// parseAsymmetricAlgorithm returns the asymmetric algorithm with the given name, or false if it is not supported.
+func parseAsymmetricAlgorithm(name string) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, bool) {
+	value, ok := kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm_value[name]
+	if !ok {
+		return 0, false
+	}
+	algorithm := kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm(value)
+	_, ok = asymmetricAlgorithms[algorithm]
+	return algorithm, ok
+}
+
+// supportedAlgorithms returns the names of all supported key algorithms for error messages.
+func supportedAlgorithms() string {
+	names := []string{symmetricAlgorithm}
+	for algorithm := range asymmetricAlgorithms {
+		names = append(names, algorithm.String())
+	}
+	sort.Strings(names[1:])
+	return strings.Join(names, ", ")
+}
+
+// asymmetricKey is the public key of an asymmetric crypto key version, which is used to wrap data keys locally.
+type asymmetricKey struct {
+	algorithm kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+	publicKey *rsa.PublicKey
+}
+
+// fetchAsymmetricKey fetches and parses the public key of the given crypto key version, checking that it uses the
+// expected algorithm.
+func fetchAsymmetricKey(ctx context.Context, svc keyManagementClient, keyVersion string, algorithm kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm) (*asymmetricKey, error) {
+	resp, err := svc.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: keyVersion})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the public key of %s: %w", keyVersion, err)
+	}
+	if resp.Algorithm != algorithm {
+		return nil, fmt.Errorf("the key %s uses the %s algorithm, but key_algorithm is %s", keyVersion, resp.Algorithm, algorithm)
+	}
+
+	block, _ := pem.Decode([]byte(resp.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("the public key of %s is not PEM-encoded", keyVersion)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key of %s: %w", keyVersion, err)
+	}
+	publicKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("the public key of %s is not an RSA key", keyVersion)
+	}
+
+	return &asymmetricKey{
+		algorithm: algorithm,
+		publicKey: publicKey,
+	}, nil
+}
+
+// maxKeyLength returns the longest data key that can be wrapped with this key.
+func (k *asymmetricKey) maxKeyLength() int {
+	return k.publicKey.Size() - 2*asymmetricAlgorithms[k.algorithm].Size() - 2
+}
+
+// wrap encrypts the given data key with the public key, as AsymmetricDecrypt expects it.
+func (k *asymmetricKey) wrap(data []byte) ([]byte, error) {
+	var h = sha256.New()
+	switch asymmetricAlgorithms[k.algorithm] {
+	case crypto.SHA512:
+		h = sha512.New()
+	case crypto.SHA1:
+		h = sha1.New()
+	}
+	return rsa.EncryptOAEP(h, rand.Reader, k.publicKey, data, nil)
+}
@@ -8,7 +8,9 @@ package gcp_kms
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
@@ -35,8 +37,9 @@ type Config struct {
 	ImpersonateServiceAccount          string   `hcl:"impersonate_service_account,optional"`
 	ImpersonateServiceAccountDelegates []string `hcl:"impersonate_service_account_delegates,optional"`
 
-	KMSKeyName string `hcl:"kms_encryption_key"`
-	KeyLength  int    `hcl:"key_length"`
+	KMSKeyName   string `hcl:"kms_encryption_key"`
+	KeyLength    int    `hcl:"key_length"`
+	KeyAlgorithm string `hcl:"key_algorithm,optional"`
 }
 
 func stringAttrEnvFallback(val string, env string) string {
@@ -144,10 +147,36 @@ func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
 		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_length must be less than the GCP limit of 1024"}
 	}
 
+	var asymmetric *asymmetricKey
+	if c.KeyAlgorithm != "" && c.KeyAlgorithm != symmetricAlgorithm {
+		algorithm, ok := parseAsymmetricAlgorithm(c.KeyAlgorithm)
+		if !ok {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("unsupported key_algorithm %s, expected one of %s", c.KeyAlgorithm, supportedAlgorithms()),
+			}
+		}
+		// Asymmetric keys have no primary version, so the version must be given.
+		if !strings.Contains(c.KMSKeyName, cryptoKeyVersionSeparator) {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: "kms_encryption_key must be a crypto key version for asymmetric keys",
+			}
+		}
+		asymmetric, err = fetchAsymmetricKey(ctx, svc, c.KMSKeyName, algorithm)
+		if err != nil {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{Cause: err}
+		}
+		if maxLength := asymmetric.maxKeyLength(); c.KeyLength > maxLength {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("key_length must be at most %d for the %s algorithm", maxLength, c.KeyAlgorithm),
+			}
+		}
+	}
+
 	return &keyProvider{
-		svc:       svc,
-		ctx:       ctx,
-		keyName:   c.KMSKeyName,
-		keyLength: c.KeyLength,
+		svc:        svc,
+		ctx:        ctx,
+		keyName:    c.KMSKeyName,
+		keyLength:  c.KeyLength,
+		asymmetric: asymmetric,
 	}, new(keyMeta), nil
 }
@@ -11,6 +11,9 @@ import (
 type mockKMC struct {
 	encrypt func(*kmspb.EncryptRequest) (*kmspb.EncryptResponse, error)
 	decrypt func(*kmspb.DecryptRequest) (*kmspb.DecryptResponse, error)
+
+	getPublicKey      func(*kmspb.GetPublicKeyRequest) (*kmspb.PublicKey, error)
+	asymmetricDecrypt func(*kmspb.AsymmetricDecryptRequest) (*kmspb.AsymmetricDecryptResponse, error)
 }
 
 func (m *mockKMC) Encrypt(ctx context.Context, req *kmspb.EncryptRequest, opts ...gax.CallOption) (*kmspb.EncryptResponse, error) {
@@ -19,6 +22,12 @@ func (m *mockKMC) Encrypt(ctx context.Context, req *kmspb.EncryptRequest, opts .
 func (m *mockKMC) Decrypt(ctx context.Context, req *kmspb.DecryptRequest, opts ...gax.CallOption) (*kmspb.DecryptResponse, error) {
 	return m.decrypt(req)
 }
+func (m *mockKMC) GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, opts ...gax.CallOption) (*kmspb.PublicKey, error) {
+	return m.getPublicKey(req)
+}
+func (m *mockKMC) AsymmetricDecrypt(ctx context.Context, req *kmspb.AsymmetricDecryptRequest, opts ...gax.CallOption) (*kmspb.AsymmetricDecryptResponse, error) {
+	return m.asymmetricDecrypt(req)
+}
 
 func injectMock(m *mockKMC) {
 	newKeyManagementClient = func(ctx context.Context, opts ...option.ClientOption) (keyManagementClient, error) {
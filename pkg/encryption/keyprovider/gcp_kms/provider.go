@@ -8,6 +8,7 @@ package gcp_kms
 import (
 	"context"
 	"crypto/rand"
+	"strings"
 
 	"cloud.google.com/go/kms/apiv1/kmspb"
 	"github.com/googleapis/gax-go/v2"
@@ -16,6 +17,12 @@ import (
 
 type keyMeta struct {
 	Ciphertext []byte `json:"ciphertext"`
+	// KeyVersion is the resource name of the crypto key version the ciphertext was encrypted with. It is empty for
+	// metadata written before the key version was recorded.
+	KeyVersion string `json:"key_version,omitempty"`
+	// Algorithm is the asymmetric algorithm the ciphertext was encrypted with. It is empty if the ciphertext was
+	// encrypted with a symmetric key.
+	Algorithm string `json:"algorithm,omitempty"`
 }
 
 func (m keyMeta) isPresent() bool {
@@ -25,6 +32,19 @@ func (m keyMeta) isPresent() bool {
 type keyManagementClient interface {
 	Encrypt(ctx context.Context, req *kmspb.EncryptRequest, opts ...gax.CallOption) (*kmspb.EncryptResponse, error)
 	Decrypt(ctx context.Context, req *kmspb.DecryptRequest, opts ...gax.CallOption) (*kmspb.DecryptResponse, error)
+	GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, opts ...gax.CallOption) (*kmspb.PublicKey, error)
+	AsymmetricDecrypt(ctx context.Context, req *kmspb.AsymmetricDecryptRequest, opts ...gax.CallOption) (*kmspb.AsymmetricDecryptResponse, error)
+}
+
+// cryptoKeyVersionSeparator separates the crypto key name from the version in a crypto key version resource name.
+const cryptoKeyVersionSeparator = "/cryptoKeyVersions/"
+
+// cryptoKeyName returns the crypto key a crypto key (version) resource name refers to.
+func cryptoKeyName(name string) string {
+	if i := strings.Index(name, cryptoKeyVersionSeparator); i != -1 {
+		return name[:i]
+	}
+	return name
 }
 
 type keyProvider struct {
@@ -32,6 +52,9 @@ type keyProvider struct {
 	ctx       context.Context
 	keyName   string
 	keyLength int
+	// asymmetric is set if the key is an asymmetric key, in which case data keys are wrapped locally with its public
+	// key.
+	asymmetric *asymmetricKey
 }
 
 func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
@@ -56,27 +79,63 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 		}
 	}
 
-	// Encrypt new encryption key using kms
-	encryptedKeyData, err := p.svc.Encrypt(p.ctx, &kmspb.EncryptRequest{
-		Name:      p.keyName,
-		Plaintext: out.EncryptionKey,
-	})
-	if err != nil {
-		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
-			Message: "failed to encrypt key",
-			Cause:   err,
+	if p.asymmetric != nil {
+		// Asymmetric keys are used to encrypt locally with the public key, only decryption goes through kms.
+		outMeta.Ciphertext, err = p.asymmetric.wrap(out.EncryptionKey)
+		if err != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to encrypt key",
+				Cause:   err,
+			}
+		}
+		outMeta.KeyVersion = p.keyName
+		outMeta.Algorithm = p.asymmetric.algorithm.String()
+	} else {
+		// Encrypt new encryption key using kms. We always pass the crypto key rather than a specific version, so that
+		// GCP uses the current primary version and the key can be rotated without changing the configuration.
+		encryptedKeyData, err := p.svc.Encrypt(p.ctx, &kmspb.EncryptRequest{
+			Name:      cryptoKeyName(p.keyName),
+			Plaintext: out.EncryptionKey,
+		})
+		if err != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to encrypt key",
+				Cause:   err,
+			}
 		}
-	}
 
-	outMeta.Ciphertext = encryptedKeyData.Ciphertext
+		outMeta.Ciphertext = encryptedKeyData.Ciphertext
+		// The response names the primary version which was actually used, which we record for decryption.
+		outMeta.KeyVersion = encryptedKeyData.Name
+	}
 
 	// We do not set the DecryptionKey here as we should only be setting the decryption key if we are decrypting
 	// and that is handled below when we check if the inMeta has a CiphertextBlob
 
-	if inMeta.isPresent() {
-		// We have an existing decryption key to decrypt, so we should now populate the DecryptionKey
+	if inMeta.isPresent() && inMeta.Algorithm != "" {
+		// The ciphertext was encrypted with an asymmetric key, which is always recorded as a crypto key version.
+		if inMeta.KeyVersion == "" {
+			return out, outMeta, &keyprovider.ErrInvalidMetadata{Message: "no key version recorded for asymmetric ciphertext"}
+		}
+		decryptedKeyData, decryptErr := p.svc.AsymmetricDecrypt(p.ctx, &kmspb.AsymmetricDecryptRequest{
+			Name:       inMeta.KeyVersion,
+			Ciphertext: inMeta.Ciphertext,
+		})
+		if decryptErr != nil {
+			return out, outMeta, decryptErr
+		}
+		out.DecryptionKey = decryptedKeyData.Plaintext
+	} else if inMeta.isPresent() {
+		// We have an existing decryption key to decrypt, so we should now populate the DecryptionKey. If the metadata
+		// records the key version used for encryption, we decrypt using the crypto key that version belongs to, since
+		// the primary version of the configured key may have been rotated since. GCP only accepts crypto key names
+		// for decryption and picks the version from the ciphertext itself.
+		keyName := p.keyName
+		if inMeta.KeyVersion != "" {
+			keyName = inMeta.KeyVersion
+		}
 		decryptedKeyData, decryptErr := p.svc.Decrypt(p.ctx, &kmspb.DecryptRequest{
-			Name:       p.keyName,
+			Name:       cryptoKeyName(keyName),
 			Ciphertext: inMeta.Ciphertext,
 		})
 
@@ -0,0 +1,255 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gcp_kms
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+const testRotatingKey = "projects/local-vehicle-id/locations/global/keyRings/ringid/cryptoKeys/keyid"
+
+// rotatingKMS simulates a crypto key with several versions, of which only the primary one is used for encryption.
+// The ciphertext is prefixed with the name of the version that produced it, like GCP does internally.
+type rotatingKMS struct {
+	primary int
+}
+
+func (r *rotatingKMS) versionName(version int) string {
+	return fmt.Sprintf("%s/cryptoKeyVersions/%d", testRotatingKey, version)
+}
+
+func (r *rotatingKMS) mock() *mockKMC {
+	return &mockKMC{
+		encrypt: func(req *kmspb.EncryptRequest) (*kmspb.EncryptResponse, error) {
+			if req.Name != testRotatingKey {
+				return nil, fmt.Errorf("unexpected key name for encryption: %s", req.Name)
+			}
+			version := r.versionName(r.primary)
+			return &kmspb.EncryptResponse{
+				Name:       version,
+				Ciphertext: append([]byte(version+":"), req.Plaintext...),
+			}, nil
+		},
+		decrypt: func(req *kmspb.DecryptRequest) (*kmspb.DecryptResponse, error) {
+			if req.Name != testRotatingKey {
+				return nil, fmt.Errorf("unexpected key name for decryption: %s", req.Name)
+			}
+			version, plaintext, ok := bytes.Cut(req.Ciphertext, []byte(":"))
+			if !ok || !strings.HasPrefix(string(version), testRotatingKey+cryptoKeyVersionSeparator) {
+				return nil, fmt.Errorf("invalid ciphertext")
+			}
+			return &kmspb.DecryptResponse{
+				Plaintext: plaintext,
+			}, nil
+		},
+	}
+}
+
+func TestKeyProvider_rotation(t *testing.T) {
+	kms := &rotatingKMS{primary: 1}
+	injectMock(kms.mock())
+
+	for name, keyName := range map[string]string{
+		"crypto-key":         testRotatingKey,
+		"crypto-key-version": kms.versionName(1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			kms.primary = 1
+
+			provider, metaIn, err := Config{KMSKeyName: keyName, KeyLength: 32}.Build()
+			if err != nil {
+				t.Fatalf("Error building provider: %s", err)
+			}
+
+			// Encrypt with version 1
+			output, meta, err := provider.Provide(metaIn)
+			if err != nil {
+				t.Fatalf("Error providing keys: %s", err)
+			}
+			v1Key := output.EncryptionKey
+			v1Meta := meta.(*keyMeta)
+			if v1Meta.KeyVersion != kms.versionName(1) {
+				t.Fatalf("Incorrect key version recorded: %s", v1Meta.KeyVersion)
+			}
+
+			// Rotate to version 2
+			kms.primary = 2
+
+			output, meta, err = provider.Provide(v1Meta)
+			if err != nil {
+				t.Fatalf("Error providing keys after rotation: %s", err)
+			}
+			if !bytes.Equal(output.DecryptionKey, v1Key) {
+				t.Fatalf("Version 1 ciphertext did not decrypt to the original key")
+			}
+			if v2Meta := meta.(*keyMeta); v2Meta.KeyVersion != kms.versionName(2) {
+				t.Fatalf("New encryptions did not use the primary version: %s", v2Meta.KeyVersion)
+			}
+		})
+	}
+}
+
+func TestKeyProvider_legacyMetadata(t *testing.T) {
+	kms := &rotatingKMS{primary: 2}
+	injectMock(kms.mock())
+
+	provider, _, err := Config{KMSKeyName: testRotatingKey, KeyLength: 32}.Build()
+	if err != nil {
+		t.Fatalf("Error building provider: %s", err)
+	}
+
+	// Metadata written before the key version was recorded only has the ciphertext.
+	key := []byte("0123456789abcdef0123456789abcdef")
+	legacyMeta := &keyMeta{
+		Ciphertext: append([]byte(kms.versionName(1)+":"), key...),
+	}
+
+	output, _, err := provider.Provide(legacyMeta)
+	if err != nil {
+		t.Fatalf("Error providing keys: %s", err)
+	}
+	if !bytes.Equal(output.DecryptionKey, key) {
+		t.Fatalf("Legacy ciphertext did not decrypt to the original key")
+	}
+}
+
+const testAsymmetricKey = "projects/local-vehicle-id/locations/global/keyRings/ringid/cryptoKeys/asymmetric/cryptoKeyVersions/1"
+
+// withAsymmetricKey adds an RSA_DECRYPT_OAEP_2048_SHA256 crypto key version to the given mock.
+func withAsymmetricKey(t *testing.T, m *mockKMC) *mockKMC {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.getPublicKey = func(req *kmspb.GetPublicKeyRequest) (*kmspb.PublicKey, error) {
+		if req.Name != testAsymmetricKey {
+			return nil, fmt.Errorf("unexpected key name for public key: %s", req.Name)
+		}
+		return &kmspb.PublicKey{
+			Pem:       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			Algorithm: kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_2048_SHA256,
+			Name:      req.Name,
+		}, nil
+	}
+	m.asymmetricDecrypt = func(req *kmspb.AsymmetricDecryptRequest) (*kmspb.AsymmetricDecryptResponse, error) {
+		if req.Name != testAsymmetricKey {
+			return nil, fmt.Errorf("unexpected key name for asymmetric decryption: %s", req.Name)
+		}
+		plaintext, err := rsa.DecryptOAEP(sha256.New(), nil, privateKey, req.Ciphertext, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &kmspb.AsymmetricDecryptResponse{Plaintext: plaintext}, nil
+	}
+	return m
+}
+
+func TestKeyProvider_asymmetric(t *testing.T) {
+	kms := &rotatingKMS{primary: 1}
+	injectMock(withAsymmetricKey(t, kms.mock()))
+
+	symmetric, metaIn, err := Config{KMSKeyName: testRotatingKey, KeyLength: 32}.Build()
+	if err != nil {
+		t.Fatalf("Error building symmetric provider: %s", err)
+	}
+	asymmetric, _, err := Config{
+		KMSKeyName:   testAsymmetricKey,
+		KeyLength:    32,
+		KeyAlgorithm: "RSA_DECRYPT_OAEP_2048_SHA256",
+	}.Build()
+	if err != nil {
+		t.Fatalf("Error building asymmetric provider: %s", err)
+	}
+
+	// Symmetric encryption doesn't record an algorithm.
+	output, meta, err := symmetric.Provide(metaIn)
+	if err != nil {
+		t.Fatalf("Error providing keys: %s", err)
+	}
+	symmetricKey := output.EncryptionKey
+	symmetricMeta := meta.(*keyMeta)
+	if symmetricMeta.Algorithm != "" {
+		t.Fatalf("Unexpected algorithm recorded for a symmetric key: %s", symmetricMeta.Algorithm)
+	}
+
+	// Switching to the asymmetric key still decrypts the symmetric history.
+	output, meta, err = asymmetric.Provide(symmetricMeta)
+	if err != nil {
+		t.Fatalf("Error providing keys: %s", err)
+	}
+	if !bytes.Equal(output.DecryptionKey, symmetricKey) {
+		t.Fatalf("Symmetric ciphertext did not decrypt to the original key")
+	}
+	asymmetricKey := output.EncryptionKey
+	asymmetricMeta := meta.(*keyMeta)
+	if asymmetricMeta.Algorithm != "RSA_DECRYPT_OAEP_2048_SHA256" || asymmetricMeta.KeyVersion != testAsymmetricKey {
+		t.Fatalf("Incorrect asymmetric metadata recorded: %s, %s", asymmetricMeta.Algorithm, asymmetricMeta.KeyVersion)
+	}
+
+	// Both providers decrypt the asymmetric ciphertext based on the recorded algorithm.
+	for name, provider := range map[string]keyprovider.KeyProvider{"asymmetric": asymmetric, "symmetric": symmetric} {
+		output, _, err = provider.Provide(asymmetricMeta)
+		if err != nil {
+			t.Fatalf("Error providing keys with the %s provider: %s", name, err)
+		}
+		if !bytes.Equal(output.DecryptionKey, asymmetricKey) {
+			t.Fatalf("Asymmetric ciphertext did not decrypt to the original key with the %s provider", name)
+		}
+	}
+}
+
+func TestKeyProvider_asymmetricInvalid(t *testing.T) {
+	kms := &rotatingKMS{primary: 1}
+	injectMock(withAsymmetricKey(t, kms.mock()))
+
+	testCases := map[string]Config{
+		"unknown-algorithm": {
+			KMSKeyName:   testAsymmetricKey,
+			KeyLength:    32,
+			KeyAlgorithm: "RSA_SIGN_PSS_2048_SHA256",
+		},
+		"no-key-version": {
+			KMSKeyName:   cryptoKeyName(testAsymmetricKey),
+			KeyLength:    32,
+			KeyAlgorithm: "RSA_DECRYPT_OAEP_2048_SHA256",
+		},
+		"algorithm-mismatch": {
+			KMSKeyName:   testAsymmetricKey,
+			KeyLength:    32,
+			KeyAlgorithm: "RSA_DECRYPT_OAEP_4096_SHA256",
+		},
+		"key-too-long": {
+			KMSKeyName:   testAsymmetricKey,
+			KeyLength:    512,
+			KeyAlgorithm: "RSA_DECRYPT_OAEP_2048_SHA256",
+		},
+	}
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := config.Build(); err == nil {
+				t.Fatalf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package openbaoclient contains the OpenBao client code shared by the openbao and openbao_transit key providers.
+package openbaoclient
+
+import (
+	"context"
+	"fmt"
+
+	openbao "github.com/openbao/openbao/api"
+)
+
+// Client is the part of the openbao/api logical client the key providers use.
+type Client interface {
+	WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*openbao.Secret, error)
+}
+
+// Constructor creates a Client. The key providers keep it in a variable so that tests can inject a mock client.
+// In order to keep the client interface simple, token setting is in this function as well. It's not possible to pass
+// the token in the config.
+type Constructor func(config *openbao.Config, token string) (Client, error)
+
+// NewConfig returns the client configuration read from BAO_ADDR and some other optional env variables. A non-empty
+// address supersedes BAO_ADDR.
+func NewConfig(address string) (*openbao.Config, error) {
+	config := openbao.DefaultConfig()
+	if config.Error != nil {
+		return nil, config.Error
+	}
+
+	if address != "" {
+		config.Address = address
+	}
+
+	return config, nil
+}
+
+// New creates a Client for the given configuration. A non-empty token supersedes BAO_TOKEN.
+func New(config *openbao.Config, token string) (Client, error) {
+	// NewClient reads BAO_TOKEN and some other optional env variables.
+	c, err := openbao.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OpenBao client: %w", err)
+	}
+
+	if token != "" {
+		c.SetToken(token)
+	}
+
+	return c.Logical(), nil
+}
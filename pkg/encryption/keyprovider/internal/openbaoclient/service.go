@@ -0,0 +1,173 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openbaoclient
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	openbao "github.com/openbao/openbao/api"
+)
+
+// Service implements the Transit engine calls missing from openbao/api, such as routing and serialization.
+type Service struct {
+	Client      Client
+	TransitPath string
+}
+
+// DataKey is a data key generated by the Transit engine, in plaintext and in the form wrapped by the Transit key.
+type DataKey struct {
+	Plaintext  []byte
+	Ciphertext []byte
+}
+
+// GenerateDataKey asks the Transit engine to generate a data key of the given size, wrapped with the named key.
+func (s Service) GenerateDataKey(ctx context.Context, keyName string, bitSize int) (DataKey, error) {
+	path := path.Join(s.TransitPath, "datakey/plaintext", url.PathEscape(keyName))
+
+	secret, err := s.Client.WriteWithContext(ctx, path, map[string]interface{}{
+		"bits": bitSize,
+	})
+	if err != nil {
+		return DataKey{}, fmt.Errorf("error sending datakey request to OpenBao: %w", err)
+	}
+
+	key := DataKey{}
+
+	key.Ciphertext, err = retrieveCiphertext(secret)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	key.Plaintext, err = retrievePlaintext(secret)
+	if err != nil {
+		return DataKey{}, err
+	}
+
+	return key, nil
+}
+
+// EncryptData wraps the plaintext with the named key. A keyVersion of 0 uses the latest version of the key.
+func (s Service) EncryptData(ctx context.Context, keyName string, keyVersion int, plaintext []byte) ([]byte, error) {
+	path := path.Join(s.TransitPath, "encrypt", url.PathEscape(keyName))
+
+	data := map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	}
+	if keyVersion != 0 {
+		data["key_version"] = keyVersion
+	}
+
+	secret, err := s.Client.WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, fmt.Errorf("error sending encryption request to OpenBao: %w", err)
+	}
+
+	return retrieveCiphertext(secret)
+}
+
+// DecryptData unwraps the ciphertext with the named key. If the ciphertext was wrapped with a key version that is no
+// longer allowed for decryption, it returns an *ErrKeyVersionDisallowed.
+func (s Service) DecryptData(ctx context.Context, keyName string, ciphertext []byte) ([]byte, error) {
+	path := path.Join(s.TransitPath, "decrypt", url.PathEscape(keyName))
+
+	secret, err := s.Client.WriteWithContext(ctx, path, map[string]interface{}{
+		"ciphertext": string(ciphertext),
+	})
+	if err != nil {
+		if isDisallowedVersionError(err) {
+			return nil, &ErrKeyVersionDisallowed{
+				KeyName: keyName,
+				Version: CiphertextVersion(ciphertext),
+				Cause:   err,
+			}
+		}
+		return nil, fmt.Errorf("error sending decryption request to OpenBao: %w", err)
+	}
+
+	return retrievePlaintext(secret)
+}
+
+// ErrKeyVersionDisallowed indicates that the stored ciphertext was wrapped with a key version that the Transit engine
+// no longer allows for decryption, typically because the key was rotated and its min_decryption_version was raised.
+type ErrKeyVersionDisallowed struct {
+	KeyName string
+	// Version is the key version the ciphertext was wrapped with, or 0 if it could not be determined.
+	Version int
+	Cause   error
+}
+
+func (e ErrKeyVersionDisallowed) Error() string {
+	version := "an older version"
+	if e.Version != 0 {
+		version = fmt.Sprintf("version %d", e.Version)
+	}
+	return fmt.Sprintf(
+		"the stored data key was encrypted with %s of the Transit key %q, which is no longer allowed for decryption (the key was likely rotated and its min_decryption_version raised): %v",
+		version,
+		e.KeyName,
+		e.Cause,
+	)
+}
+
+func (e ErrKeyVersionDisallowed) Unwrap() error {
+	return e.Cause
+}
+
+// isDisallowedVersionError checks if the error returned by OpenBao indicates that the ciphertext version is below
+// the minimum decryption version of the key.
+func isDisallowedVersionError(err error) bool {
+	return strings.Contains(err.Error(), "ciphertext version is disallowed by policy")
+}
+
+// CiphertextVersion extracts the key version from a Transit ciphertext in the form of "vault:v1:...". It returns 0
+// if the version cannot be determined.
+func CiphertextVersion(ciphertext []byte) int {
+	parts := strings.SplitN(string(ciphertext), ":", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[1], "v") {
+		return 0
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+func retrievePlaintext(s *openbao.Secret) ([]byte, error) {
+	if s == nil {
+		return nil, errors.New("empty response from OpenBao (it's either OpenTofu bug or incompatible OpenBao version)")
+	}
+	base64Plaintext, ok := s.Data["plaintext"].(string)
+	if !ok {
+		return nil, errors.New("failed to deserialize 'plaintext' (it's either OpenTofu bug or incompatible OpenBao version)")
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(base64Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("base64 decoding 'plaintext' (it's either OpenTofu bug or incompatible OpenBao version): %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func retrieveCiphertext(s *openbao.Secret) ([]byte, error) {
+	if s == nil {
+		return nil, errors.New("empty response from OpenBao (it's either OpenTofu bug or incompatible OpenBao version)")
+	}
+	ciphertext, ok := s.Data["ciphertext"].(string)
+	if !ok {
+		return nil, errors.New("failed to deserialize 'ciphertext' (it's either OpenTofu bug or incompatible OpenBao version)")
+	}
+
+	return []byte(ciphertext), nil
+}
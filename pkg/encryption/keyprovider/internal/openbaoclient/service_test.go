@@ -0,0 +1,70 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openbaoclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	openbao "github.com/openbao/openbao/api"
+)
+
+type mockClientFunc func(ctx context.Context, path string, data map[string]interface{}) (*openbao.Secret, error)
+
+func (f mockClientFunc) WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*openbao.Secret, error) {
+	return f(ctx, path, data)
+}
+
+func TestCiphertextVersion(t *testing.T) {
+	testCases := map[string]int{
+		"vault:v1:YWJj":  1,
+		"vault:v12:YWJj": 12,
+		"vault:vx:YWJj":  0,
+		"vault:1:YWJj":   0,
+		"YWJj":           0,
+	}
+	for ciphertext, want := range testCases {
+		if got := CiphertextVersion([]byte(ciphertext)); got != want {
+			t.Errorf("incorrect version for %q: got %d, want %d", ciphertext, got, want)
+		}
+	}
+}
+
+func TestDecryptData_disallowedVersion(t *testing.T) {
+	svc := Service{
+		Client: mockClientFunc(func(_ context.Context, path string, _ map[string]interface{}) (*openbao.Secret, error) {
+			if path != "/transit/decrypt/test-key" {
+				t.Fatalf("incorrect path: %s", path)
+			}
+			return nil, errors.New("Error making API request.\n\nCode: 400. Errors:\n\n* ciphertext version is disallowed by policy (too old)")
+		}),
+		TransitPath: "/transit",
+	}
+
+	_, err := svc.DecryptData(context.Background(), "test-key", []byte("vault:v3:YWJj"))
+
+	var versionErr *ErrKeyVersionDisallowed
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("incorrect error type returned: %T (%v)", err, err)
+	}
+	if versionErr.KeyName != "test-key" || versionErr.Version != 3 {
+		t.Fatalf("incorrect error: %v", versionErr)
+	}
+}
+
+func TestEncryptData_emptyResponse(t *testing.T) {
+	svc := Service{
+		Client: mockClientFunc(func(context.Context, string, map[string]interface{}) (*openbao.Secret, error) {
+			return nil, nil
+		}),
+		TransitPath: "/transit",
+	}
+
+	if _, err := svc.EncryptData(context.Background(), "test-key", 0, []byte("data key")); err == nil {
+		t.Fatalf("expected error, none returned")
+	}
+}
@@ -14,3 +14,13 @@ type KeyMeta any
 
 // MetaStorageKey signals the key under which the metadata for a specific key provider is stored.
 type MetaStorageKey string
+
+// VersionedKeyMeta can optionally be implemented by the metadata of key providers which support key rotation. It
+// returns the identifier of the key version the metadata was created with.
+//
+// When the version in the stored metadata differs from the version in the metadata returned by Provide(), the
+// encryption layer treats the key as rotated: only the new key is used for encryption, while the previous key remains
+// available for decryption until the data has been re-encrypted.
+type VersionedKeyMeta interface {
+	KeyVersion() string
+}
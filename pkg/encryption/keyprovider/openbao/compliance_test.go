@@ -125,8 +125,8 @@ func TestKeyProvider(t *testing.T) {
 						if p.keyLength != 16 {
 							return fmt.Errorf("invalid key length: %v", p.keyLength)
 						}
-						if p.svc.transitPath != "/pki" {
-							return fmt.Errorf("invalid transit path: %v", p.svc.transitPath)
+						if p.svc.TransitPath != "/pki" {
+							return fmt.Errorf("invalid transit path: %v", p.svc.TransitPath)
 						}
 						return nil
 					},
@@ -143,8 +143,8 @@ func TestKeyProvider(t *testing.T) {
 						if p.keyLength != 32 {
 							return fmt.Errorf("invalid default key length: %v", p.keyLength)
 						}
-						if p.svc.transitPath != "/transit" {
-							return fmt.Errorf("invalid default transit path: %v; expected: '/transit'", p.svc.transitPath)
+						if p.svc.TransitPath != "/transit" {
+							return fmt.Errorf("invalid default transit path: %v; expected: '/transit'", p.svc.TransitPath)
 						}
 						return nil
 					},
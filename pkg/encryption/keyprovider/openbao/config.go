@@ -8,8 +8,8 @@ package openbao
 import (
 	"fmt"
 
-	openbao "github.com/openbao/openbao/api"
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/internal/openbaoclient"
 )
 
 type Config struct {
@@ -47,19 +47,13 @@ func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
 		c.TransitEnginePath = defaultTransitEnginePath
 	}
 
-	// DefaultConfig reads BAO_ADDR and some other optional env variables.
-	config := openbao.DefaultConfig()
-	if config.Error != nil {
+	config, err := openbaoclient.NewConfig(c.Address)
+	if err != nil {
 		return nil, nil, &keyprovider.ErrInvalidConfiguration{
-			Cause: config.Error,
+			Cause: err,
 		}
 	}
 
-	// Address from HCL supersedes BAO_ADDR.
-	if c.Address != "" {
-		config.Address = c.Address
-	}
-
 	client, err := newClient(config, c.Token)
 	if err != nil {
 		return nil, nil, &keyprovider.ErrInvalidConfiguration{
@@ -68,9 +62,9 @@ func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
 	}
 
 	return &keyProvider{
-		svc: service{
-			c:           client,
-			transitPath: c.TransitEnginePath,
+		svc: openbaoclient.Service{
+			Client:      client,
+			TransitPath: c.TransitEnginePath,
 		},
 		keyName:   c.KeyName,
 		keyLength: c.KeyLength,
@@ -92,24 +86,5 @@ func (l DataKeyLength) Bits() int {
 	return int(l) * 8
 }
 
-type clientConstructor func(config *openbao.Config, token string) (client, error)
-
 // newClient variable allows to inject different client implementations.
-// In order to keep client interface simple, token setting is in this function as well.
-// It's not possible to pass token in config.
-var newClient clientConstructor = newOpenBaoClient
-
-func newOpenBaoClient(config *openbao.Config, token string) (client, error) {
-	// NewClient reads BAO_TOKEN and some other optional env variables.
-	c, err := openbao.NewClient(config)
-	if err != nil {
-		return nil, fmt.Errorf("error creating OpenBao client: %w", err)
-	}
-
-	// Token from HCL supersedes BAO_TOKEN.
-	if token != "" {
-		c.SetToken(token)
-	}
-
-	return c.Logical(), nil
-}
+var newClient openbaoclient.Constructor = openbaoclient.New
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	openbao "github.com/openbao/openbao/api"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/internal/openbaoclient"
 )
 
 type mockClientFunc func(ctx context.Context, path string, data map[string]interface{}) (*openbao.Secret, error)
@@ -13,11 +15,11 @@ func (f mockClientFunc) WriteWithContext(ctx context.Context, path string, data
 }
 
 func injectMock(m mockClientFunc) {
-	newClient = func(_ *openbao.Config, _ string) (client, error) {
+	newClient = func(_ *openbao.Config, _ string) (openbaoclient.Client, error) {
 		return m, nil
 	}
 }
 
 func injectDefaultClient() {
-	newClient = newOpenBaoClient
+	newClient = openbaoclient.New
 }
@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/internal/openbaoclient"
 )
 
 type keyMeta struct {
@@ -20,7 +21,7 @@ func (m keyMeta) isPresent() bool {
 }
 
 type keyProvider struct {
-	svc       service
+	svc       openbaoclient.Service
 	keyName   string
 	keyLength DataKeyLength
 }
@@ -41,7 +42,7 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 
 	ctx := context.Background()
 
-	dataKey, err := p.svc.generateDataKey(ctx, p.keyName, p.keyLength.Bits())
+	dataKey, err := p.svc.GenerateDataKey(ctx, p.keyName, p.keyLength.Bits())
 	if err != nil {
 		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
 			Message: "failed to generate OpenBao data key (check if the configuration valid and OpenBao server accessible)",
@@ -58,7 +59,7 @@ func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, k
 	}
 
 	if inMeta.isPresent() {
-		out.DecryptionKey, err = p.svc.decryptData(ctx, p.keyName, inMeta.Ciphertext)
+		out.DecryptionKey, err = p.svc.DecryptData(ctx, p.keyName, inMeta.Ciphertext)
 		if err != nil {
 			return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
 				Message: "failed to decrypt ciphertext (check if the configuration valid and OpenBao server accessible)",
@@ -0,0 +1,270 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openbao_transit
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	openbao "github.com/openbao/openbao/api"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/internal/openbaoclient"
+)
+
+func getBaoKeyName() string {
+	// Acceptance tests are disabled, running with mock.
+	if os.Getenv("TF_ACC") == "" {
+		return ""
+	}
+	return os.Getenv("TF_ACC_BAO_TRANSIT_KEY_NAME")
+}
+
+const defaultTestKeyName = "test-key"
+
+func TestKeyProvider(t *testing.T) {
+	testKeyName := getBaoKeyName()
+
+	if testKeyName == "" {
+		testKeyName = defaultTestKeyName
+
+		mock := prepareClientMockForKeyProviderTest(t, testKeyName, 1)
+
+		injectMock(mock)
+
+		t.Cleanup(func() {
+			injectDefaultClient()
+		})
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "openbao_transit" "foo" {
+							key_name = "%s"
+						}`, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: true,
+				},
+				"success-full-creds": {
+					HCL: fmt.Sprintf(`key_provider "openbao_transit" "foo" {
+							token = "s.dummytoken"
+							address = "http://127.0.0.1:8201"
+							key_name = "%s"
+							key_version = 1
+						}`, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: true,
+				},
+				"empty": {
+					HCL:        `key_provider "openbao_transit" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"empty-key-name": {
+					HCL: `key_provider "openbao_transit" "foo" {
+							key_name = ""
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"negative-key-version": {
+					HCL: fmt.Sprintf(`key_provider "openbao_transit" "foo" {
+							key_name = "%s"
+							key_version = -1
+						}`, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: fmt.Sprintf(`key_provider "openbao_transit" "foo" {
+							key_name = "%s"
+							key_length = 17
+						}`, testKeyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: fmt.Sprintf(`key_provider "openbao_transit" "foo" {
+							key_name = "%s"
+							unknown_property = "foo"
+						}`, testKeyName),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config: &Config{
+						KeyName:           testKeyName,
+						KeyVersion:        2,
+						KeyLength:         16,
+						TransitEnginePath: "/secrets",
+					},
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if p.keyName != testKeyName {
+							return fmt.Errorf("key names don't match: %v and %v", p.keyName, testKeyName)
+						}
+						if p.keyVersion != 2 {
+							return fmt.Errorf("invalid key version: %v", p.keyVersion)
+						}
+						if p.keyLength != 16 {
+							return fmt.Errorf("invalid key length: %v", p.keyLength)
+						}
+						if p.svc.TransitPath != "/secrets" {
+							return fmt.Errorf("invalid transit path: %v", p.svc.TransitPath)
+						}
+						return nil
+					},
+				},
+				"success-default-values": {
+					Config: &Config{
+						KeyName: testKeyName,
+					},
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if p.keyVersion != 0 {
+							return fmt.Errorf("invalid default key version: %v", p.keyVersion)
+						}
+						if p.keyLength != 32 {
+							return fmt.Errorf("invalid default key length: %v", p.keyLength)
+						}
+						if p.svc.TransitPath != "/transit" {
+							return fmt.Errorf("invalid default transit path: %v; expected: '/transit'", p.svc.TransitPath)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+					Validate:   nil,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: &Config{
+						KeyName: testKeyName,
+					},
+					Meta:      &keyMeta{},
+					IsPresent: false,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: &Config{
+					KeyName: testKeyName,
+				},
+				ValidateKeys: func(dec []byte, enc []byte) error {
+					if len(dec) == 0 {
+						return fmt.Errorf("decryption key is empty")
+					}
+					if len(enc) == 0 {
+						return fmt.Errorf("encryption key is empty")
+					}
+					return nil
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if len(meta.Ciphertext) == 0 {
+						return fmt.Errorf("ciphertext is empty")
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestRotatedKeyVersion(t *testing.T) {
+	injectMock(prepareClientMockForKeyProviderTest(t, defaultTestKeyName, 2))
+	t.Cleanup(func() {
+		injectDefaultClient()
+	})
+
+	provider, meta, err := Config{KeyName: defaultTestKeyName}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	typedMeta := meta.(*keyMeta)
+	typedMeta.Ciphertext = []byte("vault:v1:" + base64.StdEncoding.EncodeToString([]byte("old-data-key")))
+
+	_, _, err = provider.Provide(typedMeta)
+	if err == nil {
+		t.Fatalf("expected error, none returned")
+	}
+
+	var versionErr *ErrKeyVersionDisallowed
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("incorrect error type returned: %T (%v)", err, err)
+	}
+	if versionErr.Version != 1 {
+		t.Fatalf("incorrect key version in error: %d", versionErr.Version)
+	}
+}
+
+// prepareClientMockForKeyProviderTest returns a mock Transit engine. The mock refuses to decrypt ciphertexts below
+// minDecryptionVersion and always encrypts with the latest key version.
+func prepareClientMockForKeyProviderTest(t *testing.T, testKeyName string, minDecryptionVersion int) mockClientFunc {
+	escapedTestKeyName := url.PathEscape(testKeyName)
+
+	// Mock uses default transit engine path: "/transit".
+	encryptPath := fmt.Sprintf("/transit/encrypt/%s", escapedTestKeyName)
+	decryptPath := fmt.Sprintf("/transit/decrypt/%s", escapedTestKeyName)
+
+	return func(ctx context.Context, path string, data map[string]interface{}) (*openbao.Secret, error) {
+		switch path {
+		case encryptPath:
+			plaintext, ok := data["plaintext"].(string)
+			if !ok {
+				t.Fatalf("Invalid plaintext in data supplied to mock: not an string")
+			}
+
+			s := &openbao.Secret{
+				Data: map[string]interface{}{
+					"ciphertext": fmt.Sprintf("vault:v%d:%s", minDecryptionVersion, plaintext),
+				},
+			}
+
+			return s, nil
+
+		case decryptPath:
+			ciphertext, ok := data["ciphertext"].(string)
+			if !ok {
+				t.Fatalf("Invalid ciphertext in data supplied to mock: not an string")
+			}
+
+			if openbaoclient.CiphertextVersion([]byte(ciphertext)) < minDecryptionVersion {
+				return nil, errors.New("Error making API request.\n\nCode: 400. Errors:\n\n* ciphertext version is disallowed by policy (too old)")
+			}
+
+			parts := strings.SplitN(ciphertext, ":", 3)
+
+			s := &openbao.Secret{
+				Data: map[string]interface{}{
+					"plaintext": parts[2],
+				},
+			}
+
+			return s, nil
+
+		default:
+			t.Fatalf("Invalid path supplied to mock: %s", path)
+		}
+
+		// unreachable code
+		return nil, nil
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openbao_transit
+
+import (
+	"fmt"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/internal/openbaoclient"
+)
+
+type Config struct {
+	Address string `hcl:"address,optional"`
+	Token   string `hcl:"token,optional"`
+
+	KeyName           string `hcl:"key_name"`
+	KeyVersion        int    `hcl:"key_version,optional"`
+	KeyLength         int    `hcl:"key_length,optional"`
+	TransitEnginePath string `hcl:"transit_engine_path,optional"`
+}
+
+const (
+	defaultKeyLength         int    = 32
+	defaultTransitEnginePath string = "/transit"
+)
+
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.KeyName == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "no key name found",
+		}
+	}
+
+	if c.KeyVersion < 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("key version must be a positive number: got %d", c.KeyVersion),
+		}
+	}
+
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultKeyLength
+	}
+
+	switch c.KeyLength {
+	case 16, 32, 64:
+	default:
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("data key length should one of 16, 32 or 64 bytes: got %v", c.KeyLength),
+		}
+	}
+
+	if c.TransitEnginePath == "" {
+		c.TransitEnginePath = defaultTransitEnginePath
+	}
+
+	config, err := openbaoclient.NewConfig(c.Address)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Cause: err,
+		}
+	}
+
+	client, err := newClient(config, c.Token)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Cause: err,
+		}
+	}
+
+	return &keyProvider{
+		svc: openbaoclient.Service{
+			Client:      client,
+			TransitPath: c.TransitEnginePath,
+		},
+		keyName:    c.KeyName,
+		keyVersion: c.KeyVersion,
+		keyLength:  c.KeyLength,
+	}, new(keyMeta), nil
+}
+
+// newClient variable allows to inject different client implementations.
+var newClient openbaoclient.Constructor = openbaoclient.New
@@ -0,0 +1,25 @@
+package openbao_transit
+
+import (
+	"context"
+
+	openbao "github.com/openbao/openbao/api"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/internal/openbaoclient"
+)
+
+type mockClientFunc func(ctx context.Context, path string, data map[string]interface{}) (*openbao.Secret, error)
+
+func (f mockClientFunc) WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*openbao.Secret, error) {
+	return f(ctx, path, data)
+}
+
+func injectMock(m mockClientFunc) {
+	newClient = func(_ *openbao.Config, _ string) (openbaoclient.Client, error) {
+		return m, nil
+	}
+}
+
+func injectDefaultClient() {
+	newClient = openbaoclient.New
+}
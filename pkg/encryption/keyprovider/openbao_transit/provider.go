@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package openbao_transit
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/internal/openbaoclient"
+)
+
+// ErrKeyVersionDisallowed indicates that the stored data key was wrapped with a Transit key version that is no longer
+// allowed for decryption.
+type ErrKeyVersionDisallowed = openbaoclient.ErrKeyVersionDisallowed
+
+type keyMeta struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.Ciphertext) != 0
+}
+
+type keyProvider struct {
+	svc        openbaoclient.Service
+	keyName    string
+	keyVersion int
+	keyLength  int
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: invalid metadata struct type",
+		}
+	}
+
+	ctx := context.Background()
+
+	// The data key is generated locally and only its wrapped form leaves this process. The Transit key itself
+	// never leaves the OpenBao server.
+	encryptionKey := make([]byte, p.keyLength)
+	if _, err := rand.Read(encryptionKey); err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate data key",
+			Cause:   err,
+		}
+	}
+
+	ciphertext, err := p.svc.EncryptData(ctx, p.keyName, p.keyVersion, encryptionKey)
+	if err != nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to encrypt data key with OpenBao Transit (check if the configuration valid and OpenBao server accessible)",
+			Cause:   err,
+		}
+	}
+
+	outMeta := &keyMeta{
+		Ciphertext: ciphertext,
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: encryptionKey,
+	}
+
+	if inMeta.isPresent() {
+		out.DecryptionKey, err = p.svc.DecryptData(ctx, p.keyName, inMeta.Ciphertext)
+		if err != nil {
+			var versionErr *ErrKeyVersionDisallowed
+			if errors.As(err, &versionErr) {
+				return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+					Message: "failed to decrypt data key because the Transit key version it was encrypted with has been retired",
+					Cause:   err,
+				}
+			}
+			return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to decrypt ciphertext (check if the configuration valid and OpenBao server accessible)",
+				Cause:   err,
+			}
+		}
+	}
+
+	return out, outMeta, nil
+}
@@ -227,3 +227,16 @@ func TestCompliance(t *testing.T) {
 		},
 	)
 }
+
+func TestKeyProviderCompliance(t *testing.T) {
+	compliancetest.KeyProviderComplianceTest(t, func() keyprovider.Descriptor { return New() }, func() keyprovider.Config {
+		return &Config{
+			randomSource: rand.Reader,
+			Passphrase:   "Hello world! 123",
+			KeyLength:    DefaultKeyLength,
+			Iterations:   DefaultIterations,
+			HashFunction: SHA256HashFunctionName,
+			SaltLength:   DefaultSaltLength,
+		}
+	})
+}
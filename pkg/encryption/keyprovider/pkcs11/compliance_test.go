@@ -0,0 +1,290 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
+)
+
+func getToken(t *testing.T) (libraryPath string, tokenLabel string, keyLabel string) {
+	if os.Getenv("TF_ACC") == "" && os.Getenv("TF_KMS_TEST") == "" {
+		return "", "", ""
+	}
+	return os.Getenv("TF_PKCS11_LIBRARY_PATH"), os.Getenv("TF_PKCS11_TOKEN_LABEL"), os.Getenv("TF_PKCS11_KEY_LABEL")
+}
+
+func TestKeyProvider(t *testing.T) {
+	testLibraryPath, testTokenLabel, testKeyLabel := getToken(t)
+	testPin := os.Getenv("TF_PKCS11_PIN")
+
+	if testLibraryPath == "" || testTokenLabel == "" || testKeyLabel == "" {
+		testLibraryPath = "/usr/lib/softhsm/libsofthsm2.so"
+		testTokenLabel = "tofu-test-token"
+		testKeyLabel = "tofu-test-key"
+		testPin = "1234"
+		injectMock(&mockToken{
+			tokenLabel: testTokenLabel,
+			keyLabel:   testKeyLabel,
+			pin:        testPin,
+		})
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							token_label = "%s"
+							pin = "%s"
+							key_label = "%s"
+						}`, testLibraryPath, testTokenLabel, testPin, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if config.KeyLabel != testKeyLabel {
+							return fmt.Errorf("incorrect key label returned")
+						}
+						if keyProvider.mechanism != defaultMechanism {
+							return fmt.Errorf("incorrect default mechanism: %s", keyProvider.mechanism)
+						}
+						return nil
+					},
+				},
+				"success-slot": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							slot = 0
+							pin = "%s"
+							key_label = "%s"
+							mechanism = "CKM_AES_KEY_WRAP"
+							key_length = 16
+						}`, testLibraryPath, testPin, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(_ *Config, keyProvider *keyProvider) error {
+						if keyProvider.session.slot == nil || *keyProvider.session.slot != 0 {
+							return fmt.Errorf("incorrect slot")
+						}
+						return nil
+					},
+				},
+				"empty": {
+					HCL:        `key_provider "pkcs11" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"empty-library-path": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = ""
+							token_label = "%s"
+							key_label = "%s"
+						}`, testTokenLabel, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"slot-and-token-label": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							slot = 0
+							token_label = "%s"
+							key_label = "%s"
+						}`, testLibraryPath, testTokenLabel, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"no-slot-or-token-label": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							key_label = "%s"
+						}`, testLibraryPath, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"pin-and-pin-env": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							token_label = "%s"
+							pin = "1234"
+							pin_env = "TF_PKCS11_PIN"
+							key_label = "%s"
+						}`, testLibraryPath, testTokenLabel, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unset-pin-env": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							token_label = "%s"
+							pin_env = "TF_PKCS11_TEST_UNSET_PIN"
+							key_label = "%s"
+						}`, testLibraryPath, testTokenLabel, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-mechanism": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							token_label = "%s"
+							key_label = "%s"
+							mechanism = "CKM_DES3_ECB"
+						}`, testLibraryPath, testTokenLabel, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							token_label = "%s"
+							key_label = "%s"
+							key_length = 17
+						}`, testLibraryPath, testTokenLabel, testKeyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library_path = "%s"
+							token_label = "%s"
+							key_label = "%s"
+							unknown_property = "foo"
+						}`, testLibraryPath, testTokenLabel, testKeyLabel),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config: &Config{
+						LibraryPath: testLibraryPath,
+						TokenLabel:  testTokenLabel,
+						Pin:         testPin,
+						KeyLabel:    testKeyLabel,
+					},
+					ValidBuild: true,
+					Validate: func(p *keyProvider) error {
+						if p.keyLength != defaultKeyLength {
+							return fmt.Errorf("invalid default key length: %v", p.keyLength)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+					Validate:   nil,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: &Config{
+						LibraryPath: testLibraryPath,
+						TokenLabel:  testTokenLabel,
+						Pin:         testPin,
+						KeyLabel:    testKeyLabel,
+					},
+					Meta:      &keyMeta{},
+					IsPresent: false,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: &Config{
+					LibraryPath: testLibraryPath,
+					TokenLabel:  testTokenLabel,
+					Pin:         testPin,
+					KeyLabel:    testKeyLabel,
+				},
+				ValidateKeys: func(dec []byte, enc []byte) error {
+					if len(dec) == 0 {
+						return fmt.Errorf("decryption key is empty")
+					}
+					if len(enc) == 0 {
+						return fmt.Errorf("encryption key is empty")
+					}
+					return nil
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if len(meta.Ciphertext) == 0 {
+						return fmt.Errorf("ciphertext is empty")
+					}
+					if meta.Mechanism == "" {
+						return fmt.Errorf("mechanism was not recorded")
+					}
+					return nil
+				},
+			},
+		})
+}
+
+func TestSessionPerProvide(t *testing.T) {
+	mock := &mockToken{
+		tokenLabel: "tofu-test-token",
+		keyLabel:   "tofu-test-key",
+		pin:        "1234",
+	}
+	injectMock(mock)
+
+	cfg := Config{
+		LibraryPath: "/usr/lib/softhsm/libsofthsm2.so",
+		TokenLabel:  "tofu-test-token",
+		Pin:         "1234",
+		KeyLabel:    "tofu-test-key",
+	}
+	provider, meta, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.openSessions != 0 {
+		t.Fatalf("Build must not open a session")
+	}
+
+	for i := 0; i < 2; i++ {
+		_, meta, err = provider.Provide(meta)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mock.openSessions != 0 {
+			t.Fatalf("the session was not closed after Provide")
+		}
+	}
+}
+
+func TestOpenSessionFailure(t *testing.T) {
+	injectMock(&mockToken{
+		tokenLabel: "tofu-test-token",
+		keyLabel:   "tofu-test-key",
+		pin:        "1234",
+	})
+
+	cfg := Config{
+		LibraryPath: "/usr/lib/softhsm/libsofthsm2.so",
+		TokenLabel:  "missing-token",
+		Pin:         "1234",
+		KeyLabel:    "tofu-test-key",
+	}
+	provider, meta, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, _, err = provider.Provide(meta)
+	if err == nil {
+		t.Fatalf("expected an error for a missing token")
+	}
+	var failure *keyprovider.ErrKeyProviderFailure
+	if !errors.As(err, &failure) {
+		t.Fatalf("expected a key provider failure, got %T: %v", err, err)
+	}
+}
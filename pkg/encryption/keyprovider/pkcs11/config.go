@@ -0,0 +1,114 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+type Config struct {
+	LibraryPath string `hcl:"library_path"`
+	Slot        *int   `hcl:"slot,optional"`
+	TokenLabel  string `hcl:"token_label,optional"`
+	Pin         string `hcl:"pin,optional"`
+	PinEnv      string `hcl:"pin_env,optional"`
+	KeyLabel    string `hcl:"key_label"`
+
+	Mechanism string `hcl:"mechanism,optional"`
+	KeyLength int    `hcl:"key_length,optional"`
+}
+
+const (
+	defaultKeyLength = 32
+	defaultMechanism = "CKM_AES_KEY_WRAP_PAD"
+)
+
+// supportedMechanisms are the PKCS#11 wrapping mechanisms suitable for protecting a data key.
+var supportedMechanisms = []string{
+	"CKM_AES_KEY_WRAP_PAD",
+	"CKM_AES_KEY_WRAP",
+}
+
+func isSupportedMechanism(mechanism string) bool {
+	for _, m := range supportedMechanisms {
+		if m == mechanism {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.LibraryPath == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "library_path must be provided"}
+	}
+	if (c.Slot == nil) == (c.TokenLabel == "") {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "exactly one of slot or token_label must be provided"}
+	}
+	if c.Slot != nil && *c.Slot < 0 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: fmt.Sprintf("slot must not be negative: got %d", *c.Slot)}
+	}
+	if c.KeyLabel == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_label must be provided"}
+	}
+
+	pin := c.Pin
+	if c.PinEnv != "" {
+		if c.Pin != "" {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "only one of pin or pin_env may be provided"}
+		}
+		var ok bool
+		pin, ok = os.LookupEnv(c.PinEnv)
+		if !ok {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("the environment variable %s configured in pin_env is not set", c.PinEnv),
+			}
+		}
+	}
+
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultKeyLength
+	}
+	switch c.KeyLength {
+	case 16, 32, 64:
+	default:
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("data key length should one of 16, 32 or 64 bytes: got %v", c.KeyLength),
+		}
+	}
+
+	mechanism := defaultMechanism
+	if c.Mechanism != "" {
+		mechanism = c.Mechanism
+		if !isSupportedMechanism(mechanism) {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("unsupported key wrapping mechanism %q, expected one of %v", c.Mechanism, supportedMechanisms),
+			}
+		}
+	}
+
+	// The HSM session is not opened here, since session handles can't outlive a single operation. The provider opens
+	// a new session every time it provides a key.
+	session := sessionConfig{
+		libraryPath: c.LibraryPath,
+		tokenLabel:  c.TokenLabel,
+		pin:         pin,
+		keyLabel:    c.KeyLabel,
+	}
+	if c.Slot != nil {
+		slot := uint(*c.Slot)
+		session.slot = &slot
+	}
+
+	return &keyProvider{
+		session:   session,
+		mechanism: mechanism,
+		keyLength: c.KeyLength,
+	}, new(keyMeta), nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// mockToken emulates a token holding a single wrapping key. It counts the open sessions, so tests can check that
+// every session is closed again.
+type mockToken struct {
+	tokenLabel   string
+	keyLabel     string
+	pin          string
+	openSessions int
+}
+
+type mockSession struct {
+	token *mockToken
+}
+
+func (m *mockToken) open(cfg sessionConfig) (hsmSession, error) {
+	if cfg.slot == nil && cfg.tokenLabel != m.tokenLabel {
+		return nil, fmt.Errorf("no token with the label %q found", cfg.tokenLabel)
+	}
+	if cfg.pin != m.pin {
+		return nil, fmt.Errorf("incorrect PIN")
+	}
+	if cfg.keyLabel != m.keyLabel {
+		return nil, fmt.Errorf("no secret key with the label %q found", cfg.keyLabel)
+	}
+	m.openSessions++
+	return &mockSession{token: m}, nil
+}
+
+func (s *mockSession) WrapKey(mechanism string, key []byte) ([]byte, error) {
+	return append([]byte(mechanism+":"+s.token.keyLabel+":"), key...), nil
+}
+
+func (s *mockSession) UnwrapKey(mechanism string, wrapped []byte) ([]byte, error) {
+	prefix := []byte(mechanism + ":" + s.token.keyLabel + ":")
+	if !bytes.HasPrefix(wrapped, prefix) {
+		return nil, fmt.Errorf("the data key was not wrapped with %s using the key %s", mechanism, s.token.keyLabel)
+	}
+	return wrapped[len(prefix):], nil
+}
+
+func (s *mockSession) Close() error {
+	s.token.openSessions--
+	return nil
+}
+
+func injectMock(m *mockToken) {
+	openSession = m.open
+}
@@ -0,0 +1,107 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"crypto/rand"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+type keyMeta struct {
+	Ciphertext []byte `json:"ciphertext"`
+	Mechanism  string `json:"mechanism,omitempty"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.Ciphertext) != 0
+}
+
+// sessionConfig holds everything needed to open a session with the HSM and locate the wrapping key.
+type sessionConfig struct {
+	libraryPath string
+	// Exactly one of slot and tokenLabel is set.
+	slot       *uint
+	tokenLabel string
+	pin        string
+	keyLabel   string
+}
+
+// hsmSession is an open session with the HSM, bound to the wrapping key.
+type hsmSession interface {
+	WrapKey(mechanism string, key []byte) ([]byte, error)
+	UnwrapKey(mechanism string, wrapped []byte) ([]byte, error)
+	Close() error
+}
+
+// openSession can be overridden for test mocking.
+var openSession = openPKCS11Session
+
+type keyProvider struct {
+	session   sessionConfig
+	mechanism string
+	keyLength int
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
+	}
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: invalid metadata struct type"}
+	}
+
+	outMeta := &keyMeta{}
+	out := keyprovider.Output{}
+
+	session, err := openSession(p.session)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to open a session with the PKCS#11 token (check the library path, slot or token label, and PIN)",
+			Cause:   err,
+		}
+	}
+	defer session.Close()
+
+	// Generate new key
+	out.EncryptionKey = make([]byte, p.keyLength)
+	_, err = rand.Read(out.EncryptionKey)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate key",
+			Cause:   err,
+		}
+	}
+
+	// Wrap the new encryption key using the HSM key
+	outMeta.Ciphertext, err = session.WrapKey(p.mechanism, out.EncryptionKey)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to wrap key with the PKCS#11 token",
+			Cause:   err,
+		}
+	}
+	outMeta.Mechanism = p.mechanism
+
+	if inMeta.isPresent() {
+		mechanism := inMeta.Mechanism
+		if mechanism == "" {
+			mechanism = p.mechanism
+		}
+
+		// We have an existing decryption key to unwrap, so we should now populate the DecryptionKey
+		out.DecryptionKey, err = session.UnwrapKey(mechanism, inMeta.Ciphertext)
+		if err != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to unwrap key with the PKCS#11 token",
+				Cause:   err,
+			}
+		}
+	}
+
+	return out, outMeta, nil
+}
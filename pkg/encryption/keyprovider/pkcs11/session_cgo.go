@@ -0,0 +1,204 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build cgo
+
+package pkcs11
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	p11 "github.com/miekg/pkcs11"
+)
+
+var mechanisms = map[string]uint{
+	"CKM_AES_KEY_WRAP":     p11.CKM_AES_KEY_WRAP,
+	"CKM_AES_KEY_WRAP_PAD": p11.CKM_AES_KEY_WRAP_PAD,
+}
+
+type pkcs11Session struct {
+	ctx        *p11.Ctx
+	handle     p11.SessionHandle
+	loggedIn   bool
+	wrapKey    p11.ObjectHandle
+	hasSession bool
+}
+
+// openPKCS11Session loads the PKCS#11 library, opens a session with the configured token, logs in and locates the
+// wrapping key. The session must be closed by the caller.
+func openPKCS11Session(cfg sessionConfig) (_ hsmSession, err error) {
+	ctx := p11.New(cfg.libraryPath)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load the PKCS#11 library %s", cfg.libraryPath)
+	}
+	if err := ctx.Initialize(); err != nil && !isError(err, p11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize the PKCS#11 library %s: %w", cfg.libraryPath, err)
+	}
+
+	s := &pkcs11Session{ctx: ctx}
+	defer func() {
+		if err != nil {
+			_ = s.Close()
+		}
+	}()
+
+	slot, err := s.findSlot(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.handle, err = ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a session on slot %d: %w", slot, err)
+	}
+	s.hasSession = true
+
+	if err := ctx.Login(s.handle, p11.CKU_USER, cfg.pin); err != nil && !isError(err, p11.CKR_USER_ALREADY_LOGGED_IN) {
+		return nil, fmt.Errorf("failed to log in to slot %d: %w", slot, err)
+	}
+	s.loggedIn = true
+
+	s.wrapKey, err = s.findKey(cfg.keyLabel)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *pkcs11Session) findSlot(cfg sessionConfig) (uint, error) {
+	if cfg.slot != nil {
+		return *cfg.slot, nil
+	}
+
+	slots, err := s.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the PKCS#11 slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := s.ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		// Token labels are padded with spaces to a fixed length.
+		if strings.TrimRight(info.Label, " \x00") == cfg.tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no token with the label %q found", cfg.tokenLabel)
+}
+
+func (s *pkcs11Session) findKey(label string) (p11.ObjectHandle, error) {
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_SECRET_KEY),
+		p11.NewAttribute(p11.CKA_LABEL, label),
+	}
+	if err := s.ctx.FindObjectsInit(s.handle, template); err != nil {
+		return 0, fmt.Errorf("failed to search for the key %q: %w", label, err)
+	}
+	objects, _, err := s.ctx.FindObjects(s.handle, 2)
+	if finalErr := s.ctx.FindObjectsFinal(s.handle); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to search for the key %q: %w", label, err)
+	}
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("no secret key with the label %q found", label)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("more than one secret key with the label %q found", label)
+	}
+}
+
+// dataKeyTemplate describes the temporary session objects holding the data key while it is wrapped or unwrapped.
+func dataKeyTemplate() []*p11.Attribute {
+	return []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_SECRET_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_GENERIC_SECRET),
+		p11.NewAttribute(p11.CKA_TOKEN, false),
+		p11.NewAttribute(p11.CKA_SENSITIVE, false),
+		p11.NewAttribute(p11.CKA_EXTRACTABLE, true),
+	}
+}
+
+func mechanismFor(name string) ([]*p11.Mechanism, error) {
+	mechanism, ok := mechanisms[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported key wrapping mechanism %q", name)
+	}
+	return []*p11.Mechanism{p11.NewMechanism(mechanism, nil)}, nil
+}
+
+func (s *pkcs11Session) WrapKey(mechanism string, key []byte) ([]byte, error) {
+	m, err := mechanismFor(mechanism)
+	if err != nil {
+		return nil, err
+	}
+
+	// The data key is imported as a temporary session object, since PKCS#11 only wraps key objects.
+	template := append(dataKeyTemplate(), p11.NewAttribute(p11.CKA_VALUE, key))
+	obj, err := s.ctx.CreateObject(s.handle, template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import the data key: %w", err)
+	}
+	defer func() {
+		_ = s.ctx.DestroyObject(s.handle, obj)
+	}()
+
+	return s.ctx.WrapKey(s.handle, m, s.wrapKey, obj)
+}
+
+func (s *pkcs11Session) UnwrapKey(mechanism string, wrapped []byte) ([]byte, error) {
+	m, err := mechanismFor(mechanism)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := s.ctx.UnwrapKey(s.handle, m, s.wrapKey, wrapped, dataKeyTemplate())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = s.ctx.DestroyObject(s.handle, obj)
+	}()
+
+	attrs, err := s.ctx.GetAttributeValue(s.handle, obj, []*p11.Attribute{p11.NewAttribute(p11.CKA_VALUE, nil)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the unwrapped data key: %w", err)
+	}
+	if len(attrs) != 1 || len(attrs[0].Value) == 0 {
+		return nil, errors.New("the token returned an empty data key")
+	}
+	return attrs[0].Value, nil
+}
+
+func (s *pkcs11Session) Close() error {
+	var errs []error
+	if s.loggedIn {
+		if err := s.ctx.Logout(s.handle); err != nil && !isError(err, p11.CKR_USER_NOT_LOGGED_IN) {
+			errs = append(errs, err)
+		}
+	}
+	if s.hasSession {
+		if err := s.ctx.CloseSession(s.handle); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := s.ctx.Finalize(); err != nil {
+		errs = append(errs, err)
+	}
+	s.ctx.Destroy()
+	return errors.Join(errs...)
+}
+
+func isError(err error, code uint) bool {
+	var p11Err p11.Error
+	return errors.As(err, &p11Err) && uint(p11Err) == code
+}
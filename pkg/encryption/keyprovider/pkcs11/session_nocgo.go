@@ -0,0 +1,17 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !cgo
+
+package pkcs11
+
+import (
+	"errors"
+)
+
+// openPKCS11Session always fails, since loading a PKCS#11 library requires cgo.
+func openPKCS11Session(_ sessionConfig) (hsmSession, error) {
+	return nil, errors.New("the PKCS#11 key provider is not available because this build of OpenTofu does not support cgo")
+}
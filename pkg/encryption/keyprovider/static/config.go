@@ -6,19 +6,57 @@
 package static
 
 import (
+	"encoding/base64"
 	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
 )
 
+// Base64KeyLength is the length of the key expected in the key_base64 field after decoding.
+const Base64KeyLength = 32
+
 // Config contains the configuration for this key provider supplied by the user. This struct must have hcl tags in order
 // to function.
 type Config struct {
-	Key string `hcl:"key"`
+	// Key is the hex-encoded key.
+	Key string `hcl:"key,optional"`
+	// KeyBase64 is the standard base64-encoded key, which must decode to Base64KeyLength bytes. It is mutually
+	// exclusive with Key.
+	KeyBase64 string `hcl:"key_base64,optional"`
+	// KeyFile is the path to a file containing the key, either hex-encoded or standard base64-encoded. Surrounding
+	// whitespace is ignored, so files ending in a newline can be used. It is mutually exclusive with Key and KeyBase64.
+	KeyFile string `hcl:"key_file,optional"`
 }
 
 // Build will create the usable key provider.
 func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	set := 0
+	for _, value := range []string{c.Key, c.KeyBase64, c.KeyFile} {
+		if value != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "the key, key_base64 and key_file options are mutually exclusive, please specify only one of them",
+		}
+	}
+
+	if c.KeyFile != "" {
+		return c.buildFromFile()
+	}
+
+	if c.KeyBase64 != "" {
+		decodedData, err := decodeBase64Key(c.KeyBase64)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &staticKeyProvider{decodedData}, new(Metadata), nil
+	}
+
 	if c.Key == "" {
 		return nil, nil, &keyprovider.ErrInvalidConfiguration{
 			Message: "Missing key",
@@ -35,3 +73,50 @@ func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
 
 	return &staticKeyProvider{decodedData}, new(Metadata), nil
 }
+
+// buildFromFile reads the key from the file referenced in KeyFile. The contents are decoded as hex if possible and as
+// base64 otherwise.
+func (c Config) buildFromFile() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	contents, err := os.ReadFile(c.KeyFile)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("failed to read the key file %q", c.KeyFile),
+			Cause:   err,
+		}
+	}
+	encoded := strings.TrimSpace(string(contents))
+	if encoded == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the key file %q is empty", c.KeyFile),
+		}
+	}
+
+	if decodedData, err := hex.DecodeString(encoded); err == nil {
+		return &staticKeyProvider{decodedData}, new(Metadata), nil
+	}
+	decodedData, err := decodeBase64Key(encoded)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the key file %q must contain a hex-encoded or a base64-encoded key", c.KeyFile),
+			Cause:   err,
+		}
+	}
+	return &staticKeyProvider{decodedData}, new(Metadata), nil
+}
+
+// decodeBase64Key decodes a standard base64-encoded key and checks that it is Base64KeyLength bytes long.
+func decodeBase64Key(encoded string) ([]byte, error) {
+	decodedData, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "failed to base64-decode the provided key",
+			Cause:   err,
+		}
+	}
+	if len(decodedData) != Base64KeyLength {
+		return nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the base64-decoded key must be %d bytes long, got %d bytes", Base64KeyLength, len(decodedData)),
+		}
+	}
+	return decodedData, nil
+}
@@ -8,6 +8,9 @@ package static
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
@@ -39,7 +42,42 @@ func TestKeyProvider(t *testing.T) {
 				},
 				"empty": {
 					HCL:        `key_provider "static" "foo" {}`,
-					ValidHCL:   false,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"base64": {
+					HCL: `key_provider "static" "foo" {
+	key_base64 = "b29waDBlb2dob2g0YWhydW83UXVhZWhlZXlvb3JlMWk="
+}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *staticKeyProvider) error {
+						if !bytes.Equal(keyProvider.key, []byte("ooph0eoghoh4ahruo7Quaeheeyoore1i")) {
+							return fmt.Errorf("key provider contains invalid key")
+						}
+						return nil
+					},
+				},
+				"both-keys": {
+					HCL: `key_provider "static" "foo" {
+	key        = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+	key_base64 = "b29waDBlb2dob2g0YWhydW83UXVhZWhlZXlvb3JlMWk="
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"bad-base64": {
+					HCL: `key_provider "static" "foo" {
+	key_base64 = "not base64!"
+}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"base64-wrong-length": {
+					HCL: `key_provider "static" "foo" {
+	key_base64 = "SGVsbG8gd29ybGQh"
+}`,
+					ValidHCL:   true,
 					ValidBuild: false,
 				},
 				"bad-hex": {
@@ -115,3 +153,90 @@ func TestKeyProvider(t *testing.T) {
 		},
 	)
 }
+
+func TestConfig_Build(t *testing.T) {
+	dir := t.TempDir()
+	writeKeyFile := func(name string, contents string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	testCases := map[string]struct {
+		config  Config
+		wantKey []byte
+		wantErr string
+	}{
+		"base64": {
+			config:  Config{KeyBase64: "b29waDBlb2dob2g0YWhydW83UXVhZWhlZXlvb3JlMWk="},
+			wantKey: []byte("ooph0eoghoh4ahruo7Quaeheeyoore1i"),
+		},
+		"both": {
+			config: Config{
+				Key:       "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169",
+				KeyBase64: "b29waDBlb2dob2g0YWhydW83UXVhZWhlZXlvb3JlMWk=",
+			},
+			wantErr: "the key, key_base64 and key_file options are mutually exclusive",
+		},
+		"wrong-length": {
+			config:  Config{KeyBase64: "SGVsbG8gd29ybGQh"},
+			wantErr: "the base64-decoded key must be 32 bytes long, got 12 bytes",
+		},
+		"file-hex": {
+			config:  Config{KeyFile: writeKeyFile("hex.key", "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169\n")},
+			wantKey: []byte("ooph0eoghoh4ahruo7Quaeheeyoore1i"),
+		},
+		"file-base64": {
+			config:  Config{KeyFile: writeKeyFile("base64.key", "b29waDBlb2dob2g0YWhydW83UXVhZWhlZXlvb3JlMWk=\n")},
+			wantKey: []byte("ooph0eoghoh4ahruo7Quaeheeyoore1i"),
+		},
+		"file-missing": {
+			config:  Config{KeyFile: filepath.Join(dir, "missing.key")},
+			wantErr: "failed to read the key file",
+		},
+		"file-empty": {
+			config:  Config{KeyFile: writeKeyFile("empty.key", "\n")},
+			wantErr: "is empty",
+		},
+		"file-malformed": {
+			config:  Config{KeyFile: writeKeyFile("malformed.key", "not a key!")},
+			wantErr: "must contain a hex-encoded or a base64-encoded key",
+		},
+		"file-and-key": {
+			config: Config{
+				Key:     "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169",
+				KeyFile: writeKeyFile("both.key", "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"),
+			},
+			wantErr: "the key, key_base64 and key_file options are mutually exclusive",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keyProvider, _, err := tc.config.Build()
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected an error containing %q, got none", tc.wantErr)
+				}
+				if !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected an error containing %q, got: %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := keyProvider.(*staticKeyProvider).key; !bytes.Equal(got, tc.wantKey) {
+				t.Fatalf("incorrect key: %q", got)
+			}
+		})
+	}
+}
+
+func TestKeyProviderCompliance(t *testing.T) {
+	compliancetest.KeyProviderComplianceTest(t, func() keyprovider.Descriptor { return New() }, func() keyprovider.Config {
+		return &Config{Key: "48656c6c6f20776f726c6421"}
+	})
+}
@@ -0,0 +1,212 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package systemd_creds
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/compliancetest"
+)
+
+// testCredentialsDirectory sets CREDENTIALS_DIRECTORY to a new directory containing the given credentials.
+func testCredentialsDirectory(t *testing.T, credentials map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, contents := range credentials {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o400); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(CredentialsDirectoryEnv, dir)
+	return dir
+}
+
+func TestKeyProvider(t *testing.T) {
+	testCredentialsDirectory(t, map[string]string{
+		"hex":    "48656c6c6f20776f726c6421\n",
+		"base64": "SGVsbG8gd29ybGQh\n",
+	})
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"hex": {
+					HCL: `key_provider "systemd_creds" "foo" {
+							credential = "hex"
+						}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(_ *Config, p *keyProvider) error {
+						if !bytes.Equal(p.key, []byte("Hello world!")) {
+							return fmt.Errorf("key provider contains invalid key")
+						}
+						return nil
+					},
+				},
+				"base64": {
+					HCL: `key_provider "systemd_creds" "foo" {
+							credential = "base64"
+						}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(_ *Config, p *keyProvider) error {
+						if !bytes.Equal(p.key, []byte("Hello world!")) {
+							return fmt.Errorf("key provider contains invalid key")
+						}
+						return nil
+					},
+				},
+				"empty": {
+					HCL:        `key_provider "systemd_creds" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"missing-credential": {
+					HCL: `key_provider "systemd_creds" "foo" {
+							credential = "missing"
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"path-traversal": {
+					HCL: `key_provider "systemd_creds" "foo" {
+							credential = "../hex"
+						}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: `key_provider "systemd_creds" "foo" {
+							credential       = "hex"
+							unknown_property = "foo"
+						}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+					Validate:   nil,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: &Config{Credential: "hex"},
+					Meta:        &keyMeta{},
+					IsPresent:   false,
+					IsValid:     false,
+				},
+				"valid": {
+					ValidConfig: &Config{Credential: "hex"},
+					Meta:        &keyMeta{Credential: "hex"},
+					IsPresent:   true,
+					IsValid:     true,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: &Config{Credential: "hex"},
+				ExpectedOutput: &keyprovider.Output{
+					EncryptionKey: []byte("Hello world!"),
+					DecryptionKey: []byte("Hello world!"),
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if meta.Credential != "hex" {
+						return fmt.Errorf("incorrect credential in the metadata: %s", meta.Credential)
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
+
+func TestConfig_Build(t *testing.T) {
+	dir := testCredentialsDirectory(t, map[string]string{
+		"key":       "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169\n",
+		"empty":     "\n",
+		"malformed": "not a key!",
+	})
+
+	testCases := map[string]struct {
+		credential string
+		unsetEnv   bool
+		wantKey    []byte
+		wantErr    string
+	}{
+		"success": {
+			credential: "key",
+			wantKey:    []byte("ooph0eoghoh4ahruo7Quaeheeyoore1i"),
+		},
+		"missing-credential": {
+			credential: "missing",
+			wantErr:    fmt.Sprintf(`the credential "missing" was not passed to the service, there is no %s`, filepath.Join(dir, "missing")),
+		},
+		"missing-directory": {
+			credential: "key",
+			unsetEnv:   true,
+			wantErr:    "the CREDENTIALS_DIRECTORY environment variable is not set",
+		},
+		"empty": {
+			credential: "empty",
+			wantErr:    "the credential is empty",
+		},
+		"malformed": {
+			credential: "malformed",
+			wantErr:    `the credential "malformed" must contain a hex-encoded or a base64-encoded key`,
+		},
+		"path-separator": {
+			credential: "sub/key",
+			wantErr:    "credential names can't contain path separators",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.unsetEnv {
+				t.Setenv(CredentialsDirectoryEnv, "")
+			}
+
+			p, _, err := Config{Credential: tc.credential}.Build()
+			if tc.wantErr != "" {
+				var typedErr *keyprovider.ErrInvalidConfiguration
+				if !errors.As(err, &typedErr) {
+					t.Fatalf("expected %T, got %T: %v", typedErr, err, err)
+				}
+				if !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected an error containing %q, got: %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := p.(*keyProvider).key; !bytes.Equal(got, tc.wantKey) {
+				t.Fatalf("incorrect key: %q", got)
+			}
+		})
+	}
+}
+
+func TestKeyProviderCompliance(t *testing.T) {
+	testCredentialsDirectory(t, map[string]string{
+		"key": "48656c6c6f20776f726c6421",
+	})
+
+	compliancetest.KeyProviderComplianceTest(t, func() keyprovider.Descriptor { return New() }, func() keyprovider.Config {
+		return &Config{Credential: "key"}
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package systemd_creds
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+// CredentialsDirectoryEnv is the environment variable systemd sets to the directory containing the credentials passed
+// to a service with LoadCredential=, LoadCredentialEncrypted= or SetCredential=.
+const CredentialsDirectoryEnv = "CREDENTIALS_DIRECTORY"
+
+// Config contains the configuration for this key provider supplied by the user.
+type Config struct {
+	// Credential is the name of the systemd credential containing the key, either hex-encoded or standard
+	// base64-encoded. Surrounding whitespace is ignored.
+	Credential string `hcl:"credential"`
+}
+
+// Build will create the usable key provider.
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if c.Credential == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "Missing credential",
+		}
+	}
+	if c.Credential == "." || c.Credential == ".." || strings.ContainsAny(c.Credential, `/\`) {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("invalid credential name %q, credential names can't contain path separators", c.Credential),
+		}
+	}
+
+	dir := os.Getenv(CredentialsDirectoryEnv)
+	if dir == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf(
+				"the %s environment variable is not set, please run OpenTofu as a systemd service with the %q credential passed with LoadCredential= or SetCredential=",
+				CredentialsDirectoryEnv,
+				c.Credential,
+			),
+		}
+	}
+
+	path := filepath.Join(dir, c.Credential)
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{
+				Message: fmt.Sprintf("the credential %q was not passed to the service, there is no %s", c.Credential, path),
+			}
+		}
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("failed to read the credential %q", c.Credential),
+			Cause:   err,
+		}
+	}
+
+	key, err := decodeKey(string(contents))
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the credential %q must contain a hex-encoded or a base64-encoded key", c.Credential),
+			Cause:   err,
+		}
+	}
+
+	return &keyProvider{credential: c.Credential, key: key}, new(keyMeta), nil
+}
+
+// decodeKey decodes the given hex-encoded or standard base64-encoded key, ignoring surrounding whitespace.
+func decodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return nil, fmt.Errorf("the credential is empty")
+	}
+	if key, err := hex.DecodeString(encoded); err == nil {
+		return key, nil
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package systemd_creds contains a key provider that reads the key from a systemd credential.
+package systemd_creds
+
+import (
+	"fmt"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+type keyMeta struct {
+	// Credential is the name of the credential the data was encrypted with. It only marks that the data is
+	// encrypted, the key is always read from the configured credential.
+	Credential string `json:"credential"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return m.Credential != ""
+}
+
+type keyProvider struct {
+	credential string
+	key        []byte
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: "bug: no metadata struct provided",
+		}
+	}
+
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: invalid metadata type received: %T", rawMeta),
+		}
+	}
+
+	out := keyprovider.Output{
+		EncryptionKey: p.key,
+	}
+	if inMeta.isPresent() {
+		out.DecryptionKey = p.key
+	}
+
+	return out, &keyMeta{Credential: p.credential}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"errors"
+	"io"
 
 	"github.com/we-dcode/opentofu/pkg/encryption/method"
 )
@@ -19,6 +20,10 @@ type aesgcm struct {
 	encryptionKey []byte
 	decryptionKey []byte
 	aad           []byte
+
+	// randReader is the source of the nonces. It is always nil outside of tests, which means crypto/rand is used.
+	// It is deliberately not configurable, so the configuration can never select a weak source.
+	randReader io.Reader
 }
 
 // Encrypt encrypts the passed data with AES-GCM. If the data the encryption fails, it returns an error.
@@ -31,7 +36,7 @@ func (a aesgcm) Encrypt(data []byte) ([]byte, error) {
 			}
 
 			nonce := make([]byte, gcm.NonceSize())
-			if _, err := rand.Read(nonce); err != nil {
+			if _, err := io.ReadFull(a.random(), nonce); err != nil {
 				return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{
 					Message: "could not generate nonce",
 					Cause:   err,
@@ -105,6 +110,13 @@ func (a aesgcm) Decrypt(data []byte) ([]byte, error) {
 	return result, nil
 }
 
+func (a aesgcm) random() io.Reader {
+	if a.randReader != nil {
+		return a.randReader
+	}
+	return rand.Reader
+}
+
 func (a aesgcm) getGCM(key []byte) (cipher.AEAD, error) {
 	cipherBlock, err := aes.NewCipher(key)
 	if err != nil {
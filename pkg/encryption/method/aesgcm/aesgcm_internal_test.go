@@ -6,6 +6,8 @@
 package aesgcm
 
 import (
+	"encoding/hex"
+	"math/rand"
 	"testing"
 )
 
@@ -61,3 +63,34 @@ func TestInternalErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+// TestDeterministicNonce uses a seeded nonce source to check that the framing of the ciphertext (nonce followed by the
+// sealed data and tag) stays stable.
+func TestDeterministicNonce(t *testing.T) {
+	aes := &aesgcm{
+		encryptionKey: []byte("aeshi1quahb2Rua0ooquaiwahbonedoh"),
+		decryptionKey: []byte("aeshi1quahb2Rua0ooquaiwahbonedoh"),
+		randReader:    rand.New(rand.NewSource(1)),
+	}
+
+	expected := []string{
+		"52fdfc072182654f163f5f0f5c26b50d29fef312ff3f9bcf916e06a1a538d0e6dd72aaa580aef6ea",
+		"9a621d729566c74d10037c4d5d03c02c782c8ea684060769d07c94767fd0387a3c09c05ff1a937e7",
+	}
+	for i, want := range expected {
+		encrypted, err := aes.Encrypt([]byte("Hello world!"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := hex.EncodeToString(encrypted); got != want {
+			t.Fatalf("Incorrect ciphertext %d:\ngot:  %s\nwant: %s", i, got, want)
+		}
+		decrypted, err := aes.Decrypt(encrypted)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(decrypted) != "Hello world!" {
+			t.Fatalf("Incorrect decrypted string: %s", decrypted)
+		}
+	}
+}
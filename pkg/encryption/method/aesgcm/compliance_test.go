@@ -195,3 +195,10 @@ func TestCompliance(t *testing.T) {
 		},
 	})
 }
+
+func TestMethodCompliance(t *testing.T) {
+	compliancetest.MethodComplianceTest(t, compliancetest.MethodTestConfiguration{
+		Descriptor: New(),
+		KeyLength:  32,
+	})
+}
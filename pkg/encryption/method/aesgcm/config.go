@@ -29,6 +29,20 @@ type Config struct {
 	// otherwise the decryption will fail. (Note: this is Go-specific and differs from the NIST SP 800-38D description
 	// of the AAD.)
 	AAD []byte `hcl:"aad,optional" json:"aad,omitempty" yaml:"aad,omitempty"`
+
+	// BindToTarget appends the additional authenticated data supplied by the encryption layer, such as the target
+	// name, to the AAD. This prevents encrypted data from being swapped for data encrypted for a different target.
+	// Data encrypted without this setting cannot be decrypted with it enabled and vice versa.
+	BindToTarget bool `hcl:"bind_to_target,optional" json:"bind_to_target,omitempty" yaml:"bind_to_target,omitempty"`
+
+	// targetAAD is the additional authenticated data supplied by the encryption layer.
+	targetAAD []byte
+}
+
+// SetTargetAAD sets the additional authenticated data supplied by the encryption layer. It is only used if
+// BindToTarget is enabled.
+func (c *Config) SetTargetAAD(aad []byte) {
+	c.targetAAD = aad
 }
 
 // Build checks the validity of the configuration and returns a ready-to-use AES-GCM implementation.
@@ -58,9 +72,14 @@ func (c *Config) Build() (method.Method, error) {
 		}
 	}
 
+	aad := c.AAD
+	if c.BindToTarget && len(c.targetAAD) > 0 {
+		aad = append(append([]byte{}, c.AAD...), c.targetAAD...)
+	}
+
 	return &aesgcm{
-		encryptionKey,
-		decryptionKey,
-		c.AAD,
+		encryptionKey: encryptionKey,
+		decryptionKey: decryptionKey,
+		aad:           aad,
 	}, nil
 }
@@ -105,6 +105,40 @@ func TestConfig_Build(t *testing.T) {
 			},
 			errorType: nil,
 		},
+		{
+			name: "aad-bind-to-target",
+			config: &Config{
+				Keys: keyprovider.Output{
+					EncryptionKey: []byte("bohwu9zoo7Zool5olaileef1eibeathe"),
+					DecryptionKey: []byte("bohwu9zoo7Zool5olaileef1eibeathd"),
+				},
+				AAD:          []byte("foobar"),
+				BindToTarget: true,
+				targetAAD:    []byte("state"),
+			},
+			expected: aesgcm{
+				encryptionKey: []byte("bohwu9zoo7Zool5olaileef1eibeathe"),
+				decryptionKey: []byte("bohwu9zoo7Zool5olaileef1eibeathd"),
+				aad:           []byte("foobarstate"),
+			},
+			errorType: nil,
+		},
+		{
+			name: "aad-target-unbound",
+			config: &Config{
+				Keys: keyprovider.Output{
+					EncryptionKey: []byte("bohwu9zoo7Zool5olaileef1eibeathe"),
+					DecryptionKey: []byte("bohwu9zoo7Zool5olaileef1eibeathd"),
+				},
+				targetAAD: []byte("state"),
+			},
+			expected: aesgcm{
+				encryptionKey: []byte("bohwu9zoo7Zool5olaileef1eibeathe"),
+				decryptionKey: []byte("bohwu9zoo7Zool5olaileef1eibeathd"),
+				aad:           nil,
+			},
+			errorType: nil,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,92 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv
+
+import (
+	"github.com/google/tink/go/aead/subtle"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/method"
+)
+
+const (
+	// keySize is the size of the key-generating key of AES-256-GCM-SIV.
+	keySize = 32
+	// overhead is the size of the nonce and the authentication tag added to the encrypted data.
+	overhead = subtle.AESGCMSIVNonceSize + 16
+)
+
+// aesgcmsiv contains the encryption/decryption methods according to AES-GCM-SIV (RFC 8452).
+type aesgcmsiv struct {
+	encryptionKey []byte
+	decryptionKey []byte
+	aad           []byte
+}
+
+// Encrypt encrypts the passed data with AES-GCM-SIV. If the encryption fails, it returns an error.
+func (a aesgcmsiv) Encrypt(data []byte) ([]byte, error) {
+	aead, err := a.getAEAD(a.encryptionKey)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+
+	// The result contains the random nonce, followed by the encrypted data and the authentication tag.
+	encrypted, err := aead.Encrypt(data, a.aad)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+	return encrypted, nil
+}
+
+// Decrypt decrypts an AES-GCM-SIV-encrypted data set. If the data set fails decryption, it returns an error.
+func (a aesgcmsiv) Decrypt(data []byte) ([]byte, error) {
+	if len(a.decryptionKey) == 0 {
+		return nil, &method.ErrDecryptionKeyUnavailable{}
+	}
+	if len(data) == 0 {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt empty data",
+				Cause:   nil,
+			},
+		}
+	}
+	if len(data) < overhead {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt data because it is too small (likely data corruption)",
+				Cause:   nil,
+			},
+		}
+	}
+
+	aead, err := a.getAEAD(a.decryptionKey)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+
+	decrypted, err := aead.Decrypt(data, a.aad)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+	return decrypted, nil
+}
+
+func (a aesgcmsiv) getAEAD(key []byte) (*subtle.AESGCMSIV, error) {
+	aead, err := subtle.NewAESGCMSIV(key)
+	if err != nil {
+		return nil, &method.ErrCryptoFailure{
+			Message: "failed to create AES-GCM-SIV cipher",
+			Cause:   err,
+		}
+	}
+	return aead, nil
+}
+
+// Is returns true if the passed method is an AES-GCM-SIV method.
+func Is(m method.Method) bool {
+	_, ok := m.(*aesgcmsiv)
+	return ok
+}
@@ -0,0 +1,66 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestDecryptVectors checks that the method decrypts the AEAD_AES_256_GCM_SIV test vectors in appendix C.2 of
+// RFC 8452, stored with the nonce in front like the method does.
+func TestDecryptVectors(t *testing.T) {
+	testCases := map[string]struct {
+		plaintext string
+		result    string
+	}{
+		"empty": {
+			plaintext: "",
+			result:    "07f5f4169bbf55a8400cd47ea6fd400f",
+		},
+		"8-bytes": {
+			plaintext: "0100000000000000",
+			result:    "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28",
+		},
+	}
+
+	key := mustDecodeHex(t, "0100000000000000000000000000000000000000000000000000000000000000")
+	nonce := mustDecodeHex(t, "030000000000000000000000")
+	m := aesgcmsiv{
+		encryptionKey: key,
+		decryptionKey: key,
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plaintext := mustDecodeHex(t, tc.plaintext)
+			sealed := append(append([]byte{}, nonce...), mustDecodeHex(t, tc.result)...)
+
+			opened, err := m.Decrypt(sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(opened, plaintext) {
+				t.Fatalf("incorrect plaintext %x, expected %x", opened, plaintext)
+			}
+
+			sealed[len(nonce)] ^= 1
+			if _, err := m.Decrypt(sealed); err == nil {
+				t.Fatalf("decrypting a corrupted ciphertext succeeded")
+			}
+		})
+	}
+}
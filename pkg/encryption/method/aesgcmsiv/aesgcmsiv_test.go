@@ -0,0 +1,169 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/method"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcmsiv"
+)
+
+var config = &aesgcmsiv.Config{
+	Keys: keyprovider.Output{
+		EncryptionKey: []byte("eeNgahx7Ohgoh9aech1ieRaiphie3que"),
+		DecryptionKey: []byte("eeNgahx7Ohgoh9aech1ieRaiphie3que"),
+	},
+}
+
+func TestBuildWrongKeySize(t *testing.T) {
+	_, err := (&aesgcmsiv.Config{
+		Keys: keyprovider.Output{
+			EncryptionKey: []byte("Vaeb4eiquoo2ohW6"),
+			DecryptionKey: []byte("Vaeb4eiquoo2ohW6"),
+		},
+	}).Build()
+	if err == nil {
+		t.Fatalf("Expected error, none returned.")
+	}
+
+	var e *method.ErrInvalidConfiguration
+	if !errors.As(err, &e) {
+		t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+	}
+}
+
+func TestDecryptEmptyData(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	_, err = m.Decrypt(nil)
+	if err == nil {
+		t.Fatalf("Expected error, none returned.")
+	}
+
+	var e *method.ErrDecryptionFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+	}
+}
+
+func TestDecryptShortData(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	// Passing a non-empty, but shorter-than-nonce data
+	_, err = m.Decrypt([]byte("1"))
+	if err == nil {
+		t.Fatalf("Expected error, none returned.")
+	}
+
+	var e *method.ErrDecryptionFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+	}
+}
+
+func TestDecryptInvalidData(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	_, err = m.Decrypt([]byte("abcdefghijklmnopqrstuvwxyz0123456789"))
+	if err == nil {
+		t.Fatalf("Expected error, none returned.")
+	}
+
+	var e *method.ErrDecryptionFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+	}
+}
+
+func TestDecryptCorruptData(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	encrypted, err := m.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	encrypted = encrypted[:len(encrypted)-1]
+	decrypted, err := m.Decrypt(encrypted)
+	if err == nil {
+		t.Fatalf("Expected error, got: %v", decrypted)
+	}
+	var e *method.ErrDecryptionFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+	}
+}
+
+func TestEncryptTwice(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	plaintext := []byte("Hello world!")
+	first, err := m.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	second, err := m.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	// Each encryption uses a fresh nonce, so encrypting the same plaintext twice must not produce the same
+	// ciphertext.
+	if bytes.Equal(first, second) {
+		t.Fatalf("Encrypting the same plaintext twice produced the same ciphertext.")
+	}
+
+	for _, encrypted := range [][]byte{first, second} {
+		decrypted, err := m.Decrypt(encrypted)
+		if err != nil {
+			t.Fatalf("unexpected error (%v)", err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("Incorrect decrypted data: %s", decrypted)
+		}
+	}
+}
+
+// BenchmarkEncryptDecrypt measures the method on a state file of several megabytes.
+func BenchmarkEncryptDecrypt(b *testing.B) {
+	m, err := config.Build()
+	if err != nil {
+		b.Fatalf("unexpected error (%v)", err)
+	}
+	state := bytes.Repeat([]byte(`{"mode":"managed","type":"null_resource","name":"foo"},`), 8<<20/55)
+
+	b.SetBytes(int64(len(state)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encrypted, err := m.Encrypt(state)
+		if err != nil {
+			b.Fatalf("unexpected error (%v)", err)
+		}
+		if _, err := m.Decrypt(encrypted); err != nil {
+			b.Fatalf("unexpected error (%v)", err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv
+
+import (
+	"fmt"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/method"
+)
+
+// Config is the configuration for the AES-GCM-SIV method.
+type Config struct {
+	// Keys is the encryption and decryption key for the AES-GCM-SIV encryption. The keys have to be exactly 32 bytes
+	// long, as this method implements AES-256-GCM-SIV.
+	Keys keyprovider.Output `hcl:"keys" json:"keys" yaml:"keys"`
+
+	// AAD is the Additional Authenticated Data that is authenticated, but not encrypted. The AAD value on decryption
+	// must match this setting, otherwise the decryption will fail.
+	AAD []byte `hcl:"aad,optional" json:"aad,omitempty" yaml:"aad,omitempty"`
+}
+
+// Build checks the validity of the configuration and returns a ready-to-use AES-GCM-SIV implementation.
+func (c *Config) Build() (method.Method, error) {
+	encryptionKey := c.Keys.EncryptionKey
+	decryptionKey := c.Keys.DecryptionKey
+
+	if len(encryptionKey) != keySize {
+		return nil, &method.ErrInvalidConfiguration{
+			Cause: fmt.Errorf(
+				"AES-GCM-SIV requires the key length to be %d bytes, received %d bytes in the encryption key",
+				keySize,
+				len(encryptionKey),
+			),
+		}
+	}
+
+	if len(decryptionKey) > 0 && len(decryptionKey) != keySize {
+		return nil, &method.ErrInvalidConfiguration{
+			Cause: fmt.Errorf(
+				"AES-GCM-SIV requires the key length to be %d bytes, received %d bytes in the decryption key",
+				keySize,
+				len(decryptionKey),
+			),
+		}
+	}
+
+	return &aesgcmsiv{
+		encryptionKey,
+		decryptionKey,
+		c.AAD,
+	}, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/we-dcode/opentofu/pkg/configs"
+	"github.com/we-dcode/opentofu/pkg/encryption"
+	encconfig "github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/static"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcmsiv"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
+)
+
+var hclConfig = `key_provider "static" "foo" {
+  key = "5468616977656976616871756f6f3965696d6f6f5a65696b6f68633371756569"
+}
+
+method "aes_gcm_siv" "bar" {
+  keys = key_provider.static.foo
+}
+
+plan {
+  method = method.aes_gcm_siv.bar
+}
+`
+
+// Example is a full end-to-end example of encrypting and decrypting a plan file.
+func Example() {
+	registry := lockingencryptionregistry.New()
+	if err := registry.RegisterKeyProvider(static.New()); err != nil {
+		panic(err)
+	}
+	if err := registry.RegisterMethod(aesgcmsiv.New()); err != nil {
+		panic(err)
+	}
+
+	cfg, diags := encconfig.LoadConfigFromString("test.hcl", hclConfig)
+	if diags.HasErrors() {
+		panic(diags)
+	}
+
+	staticEvaluator := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+
+	enc, diags := encryption.New(registry, cfg, staticEvaluator)
+	if diags.HasErrors() {
+		panic(diags)
+	}
+
+	encryptor := enc.Plan()
+
+	encryptedPlan, err := encryptor.EncryptPlan([]byte("Hello world!"))
+	if err != nil {
+		panic(err)
+	}
+	if strings.Contains(string(encryptedPlan), "Hello world!") {
+		panic("The plan was not encrypted!")
+	}
+	decryptedPlan, err := encryptor.DecryptPlan(encryptedPlan)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%s", decryptedPlan)
+	// Output: Hello world!
+}
+
+func Example_config() {
+	// First, get the descriptor to make sure we always have the default values.
+	descriptor := aesgcmsiv.New()
+
+	// Obtain a modifiable, buildable config.
+	config := descriptor.TypedConfig()
+
+	// Set up a 32-byte encryption key:
+	config.Keys = keyprovider.Output{
+		EncryptionKey: []byte("Ohcheiph2quiephe1ahcoo5Aexe6ieNg"),
+		DecryptionKey: []byte("Ohcheiph2quiephe1ahcoo5Aexe6ieNg"),
+	}
+
+	// Now you can build a method:
+	method, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Encrypt something:
+	encrypted, err := method.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		panic(err)
+	}
+
+	// Decrypt it:
+	decrypted, err := method.Decrypt(encrypted)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%s", decrypted)
+	// Output: Hello world!
+}
@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package chacha20poly1305
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/method"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// chacha contains the encryption/decryption methods according to ChaCha20-Poly1305 (RFC 8439).
+type chacha struct {
+	encryptionKey []byte
+	decryptionKey []byte
+	aad           []byte
+}
+
+// Encrypt encrypts the passed data with ChaCha20-Poly1305. If the encryption fails, it returns an error.
+func (c chacha) Encrypt(data []byte) ([]byte, error) {
+	aead, err := c.getAEAD(c.encryptionKey)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{
+			Message: "could not generate nonce",
+			Cause:   err,
+		}}
+	}
+
+	encrypted := aead.Seal(nil, nonce, data, c.aad)
+
+	return append(nonce, encrypted...), nil
+}
+
+// Decrypt decrypts a ChaCha20-Poly1305-encrypted data set. If the data set fails decryption, it returns an error.
+func (c chacha) Decrypt(data []byte) ([]byte, error) {
+	if len(c.decryptionKey) == 0 {
+		return nil, &method.ErrDecryptionKeyUnavailable{}
+	}
+	if len(data) == 0 {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt empty data",
+				Cause:   nil,
+			},
+		}
+	}
+
+	aead, err := c.getAEAD(c.decryptionKey)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+
+	if len(data) < aead.NonceSize()+aead.Overhead() {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt data because it is too small (likely data corruption)",
+				Cause:   nil,
+			},
+		}
+	}
+
+	nonce := data[:aead.NonceSize()]
+	data = data[aead.NonceSize():]
+
+	decrypted, err := aead.Open(nil, nonce, data, c.aad)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+	return decrypted, nil
+}
+
+func (c chacha) getAEAD(key []byte) (cipher.AEAD, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, &method.ErrCryptoFailure{
+			Message: "failed to create ChaCha20-Poly1305 cipher",
+			Cause:   err,
+		}
+	}
+	return aead, nil
+}
+
+// Is returns true if the passed method is a ChaCha20-Poly1305 method.
+func Is(m method.Method) bool {
+	_, ok := m.(*chacha)
+	return ok
+}
@@ -0,0 +1,230 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compliancetest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/we-dcode/opentofu/pkg/encryption/compliancetest"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/method"
+)
+
+// MethodComplianceTest runs a standard set of behavioral tests against any method that accepts its keys in the
+// usual "keys" attribute. Unlike ComplianceTest, it needs no method-specific test cases: the methods are configured
+// from HCL through the descriptor, so a new method only needs a few lines to get full coverage of the encryption and
+// decryption behavior:
+//
+//   - round-trip: data of various sizes, including empty data, decrypts to the original data.
+//   - empty-input: decrypting empty data fails with a method.ErrDecryptionFailed.
+//   - wrong-key: data encrypted with one key can't be decrypted with another key, nor after it was modified.
+//   - stability: methods built from the same configuration are interchangeable, and encrypting the same data twice
+//     doesn't produce the same encrypted data.
+func MethodComplianceTest(t *testing.T, testConfig MethodTestConfiguration) {
+	testConfig.execute(t)
+}
+
+// MethodTestConfiguration describes the method tested by MethodComplianceTest.
+type MethodTestConfiguration struct {
+	// Descriptor is the descriptor of the method to test.
+	Descriptor method.Descriptor
+	// KeyLength is the length of the keys in bytes that the method accepts.
+	KeyLength int
+}
+
+func (cfg MethodTestConfiguration) execute(t *testing.T) {
+	if cfg.Descriptor == nil {
+		compliancetest.Fail(t, "Please provide a Descriptor to MethodTestConfiguration.")
+	}
+	if cfg.KeyLength <= 0 {
+		compliancetest.Fail(t, "Please provide a positive KeyLength to MethodTestConfiguration.")
+	}
+
+	t.Run("id", func(t *testing.T) {
+		id := cfg.Descriptor.ID()
+		if err := id.Validate(); err != nil {
+			compliancetest.Fail(t, "Invalid ID returned from method descriptor: %s (%v)", id, err)
+		} else {
+			compliancetest.Log(t, "The ID provided by the method descriptor is valid: %s", id)
+		}
+	})
+	t.Run("round-trip", func(t *testing.T) {
+		cfg.testRoundTrip(t)
+	})
+	t.Run("empty-input", func(t *testing.T) {
+		cfg.testEmptyInput(t)
+	})
+	t.Run("wrong-key", func(t *testing.T) {
+		cfg.testWrongKey(t)
+	})
+	t.Run("stability", func(t *testing.T) {
+		cfg.testStability(t)
+	})
+}
+
+func (cfg MethodTestConfiguration) testRoundTrip(t *testing.T) {
+	m := cfg.build(t, cfg.key(1), cfg.key(1))
+
+	for _, size := range []int{0, 1, 16, 1000, 64 * 1024} {
+		plainData := bytes.Repeat([]byte{'x'}, size)
+		encryptedData, err := m.Encrypt(plainData)
+		if err != nil {
+			compliancetest.Fail(t, "Encrypt() failed for %d bytes of data (%v).", size, err)
+		}
+		// Short data may appear in the encrypted data by chance.
+		if size >= 16 && bytes.Contains(encryptedData, plainData) {
+			compliancetest.Fail(t, "Encrypt() returned the plain text data as part of the encrypted data for %d bytes of data.", size)
+		}
+		decryptedData, err := m.Decrypt(encryptedData)
+		if err != nil {
+			compliancetest.Fail(t, "Decrypt() failed for %d bytes of data (%v).", size, err)
+		}
+		if !bytes.Equal(decryptedData, plainData) {
+			compliancetest.Fail(t, "Decrypt() returned incorrect plain text data for %d bytes of data.", size)
+		}
+		compliancetest.Log(t, "Successfully encrypted and decrypted %d bytes of data.", size)
+	}
+}
+
+func (cfg MethodTestConfiguration) testEmptyInput(t *testing.T) {
+	m := cfg.build(t, cfg.key(1), cfg.key(1))
+
+	for name, data := range map[string][]byte{"nil": nil, "empty": {}} {
+		_, err := m.Decrypt(data)
+		expectDecryptionFailed(t, err, fmt.Sprintf("decrypting %s data", name))
+	}
+}
+
+func (cfg MethodTestConfiguration) testWrongKey(t *testing.T) {
+	encryptor := cfg.build(t, cfg.key(1), nil)
+	wrongKey := cfg.build(t, cfg.key(2), cfg.key(2))
+	rightKey := cfg.build(t, cfg.key(2), cfg.key(1))
+
+	plainData := []byte("Hello world!")
+	encryptedData, err := encryptor.Encrypt(plainData)
+	if err != nil {
+		compliancetest.Fail(t, "Unexpected error after Encrypt() (%v).", err)
+	}
+
+	_, err = wrongKey.Decrypt(encryptedData)
+	expectDecryptionFailed(t, err, "decrypting with the wrong key")
+
+	tamperedData := bytes.Clone(encryptedData)
+	tamperedData[len(tamperedData)-1] ^= 0xff
+	_, err = rightKey.Decrypt(tamperedData)
+	expectDecryptionFailed(t, err, "decrypting modified data")
+
+	_, err = rightKey.Decrypt(encryptedData[:len(encryptedData)-1])
+	expectDecryptionFailed(t, err, "decrypting truncated data")
+
+	decryptedData, err := rightKey.Decrypt(encryptedData)
+	if err != nil {
+		compliancetest.Fail(t, "Decrypt() failed with the right decryption key (%v).", err)
+	}
+	if !bytes.Equal(decryptedData, plainData) {
+		compliancetest.Fail(t, "Decrypt() returned incorrect plain text data with the right decryption key.")
+	}
+	compliancetest.Log(t, "Decrypt() only succeeded with the right decryption key.")
+}
+
+func (cfg MethodTestConfiguration) testStability(t *testing.T) {
+	first := cfg.build(t, cfg.key(1), cfg.key(1))
+	second := cfg.build(t, cfg.key(1), cfg.key(1))
+
+	plainData := []byte("Hello world!")
+	firstData, err := first.Encrypt(plainData)
+	if err != nil {
+		compliancetest.Fail(t, "Unexpected error after Encrypt() (%v).", err)
+	}
+	secondData, err := first.Encrypt(plainData)
+	if err != nil {
+		compliancetest.Fail(t, "Unexpected error after Encrypt() (%v).", err)
+	}
+	if bytes.Equal(firstData, secondData) {
+		compliancetest.Fail(t, "Encrypt() returned the same encrypted data when called twice with the same data.")
+	}
+	compliancetest.Log(t, "Encrypt() returned different encrypted data for the same data.")
+
+	for i, data := range [][]byte{firstData, secondData} {
+		decryptedData, err := second.Decrypt(data)
+		if err != nil {
+			compliancetest.Fail(t, "A method built from the same configuration failed to decrypt data (%v).", err)
+		}
+		if !bytes.Equal(decryptedData, plainData) {
+			compliancetest.Fail(t, "A method built from the same configuration returned incorrect plain text data for encryption %d.", i+1)
+		}
+	}
+	compliancetest.Log(t, "Methods built from the same configuration are interchangeable.")
+}
+
+// key returns a key of the configured length which is different for each seed.
+func (cfg MethodTestConfiguration) key(seed byte) []byte {
+	key := make([]byte, cfg.KeyLength)
+	for i := range key {
+		key[i] = seed + byte(i)
+	}
+	return key
+}
+
+// build configures a method from HCL with the given keys, which is how the method is configured by the encryption
+// layer as well.
+func (cfg MethodTestConfiguration) build(t *testing.T, encryptionKey []byte, decryptionKey []byte) method.Method {
+	t.Helper()
+
+	hcl := fmt.Sprintf(
+		`method %q "test" {
+			keys = {
+				encryption_key = %s
+				decryption_key = %s
+			}
+		}`,
+		cfg.Descriptor.ID(),
+		hclBytes(encryptionKey),
+		hclBytes(decryptionKey),
+	)
+	parsedConfig, diags := config.LoadConfigFromString("config.hcl", hcl)
+	if diags.HasErrors() {
+		compliancetest.Fail(t, "Unexpected HCL error (%v).", diags)
+	}
+
+	configStruct := cfg.Descriptor.ConfigStruct()
+	diags = gohcl.DecodeBody(parsedConfig.MethodConfigs[0].Body, nil, configStruct)
+	if diags.HasErrors() {
+		compliancetest.Fail(t, "Failed to load the keys into the config struct (%v).", diags)
+	}
+
+	m, err := configStruct.Build()
+	if err != nil {
+		compliancetest.Fail(t, "Build() returned an unexpected error (%v).", err)
+	}
+	return m
+}
+
+func hclBytes(data []byte) string {
+	parts := make([]string, len(data))
+	for i, b := range data {
+		parts[i] = fmt.Sprintf("%d", b)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+func expectDecryptionFailed(t *testing.T, err error, action string) {
+	t.Helper()
+
+	if err == nil {
+		compliancetest.Fail(t, "Decrypt() did not return an error when %s.", action)
+	}
+	var typedDecryptError *method.ErrDecryptionFailed
+	if !errors.As(err, &typedDecryptError) {
+		compliancetest.Fail(t, "Decrypt() returned a %T instead of a %T when %s. Please use the correct typed errors.", err, typedDecryptError, action)
+	}
+	compliancetest.Log(t, "Decrypt() correctly returned a %T when %s.", typedDecryptError, action)
+}
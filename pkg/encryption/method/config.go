@@ -19,3 +19,13 @@ type Config interface {
 	// TODO this may be better changed to return hcl.Diagnostics so warnings can be issued?
 	Build() (Method, error)
 }
+
+// TargetAADConfig is an optional interface for method configurations that can authenticate data about the target
+// being encrypted, such as the name of the target. The encryption layer calls SetTargetAAD before Build. The
+// configuration decides if and how the data is used, so that data encrypted without it can still be decrypted.
+type TargetAADConfig interface {
+	Config
+
+	// SetTargetAAD passes the additional authenticated data identifying the target to the configuration.
+	SetTargetAAD(aad []byte)
+}
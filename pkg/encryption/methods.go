@@ -74,12 +74,30 @@ func (e *targetBuilder) setupMethod(cfg config.MethodConfig) hcl.Diagnostics {
 
 	// TODO: we could use varhcl here to provider better error messages
 	methodConfig := encryptionMethod.ConfigStruct()
+
+	// Report references to key provider instances which were not produced by for_each before decoding, since the
+	// generic HCL index error does not tell the user where the instance keys came from.
+	deps, varDiags := gohcl.VariablesInBody(cfg.Body, methodConfig)
+	diags = append(diags, varDiags...)
+	if e.dependsOnFailedKeyProvider(deps) {
+		// The errors of the key provider have already been reported.
+		return diags
+	}
+	diags = append(diags, e.keyProviderInstanceDiags(deps)...)
+	if diags.HasErrors() {
+		return diags
+	}
+
 	methodDiags := gohcl.DecodeBody(cfg.Body, e.ctx, methodConfig)
 	diags = append(diags, methodDiags...)
 	if diags.HasErrors() {
 		return diags
 	}
 
+	if aadConfig, ok := methodConfig.(method.TargetAADConfig); ok {
+		aadConfig.SetTargetAAD([]byte(e.targetName))
+	}
+
 	e.methodValues[cfg.Type][cfg.Name] = cty.StringVal(string(addr))
 	m, err := methodConfig.Build()
 	if err != nil {
@@ -88,6 +106,7 @@ func (e *targetBuilder) setupMethod(cfg config.MethodConfig) hcl.Diagnostics {
 			Severity: hcl.DiagError,
 			Summary:  "Encryption method configuration failed",
 			Detail:   err.Error(),
+			Subject:  cfg.Body.MissingItemRange().Ptr(),
 		})
 	}
 	e.methods[addr] = m
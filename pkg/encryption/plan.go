@@ -50,11 +50,15 @@ type planEncryption struct {
 	base *baseEncryption
 }
 
-func newPlanEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (PlanEncryption, hcl.Diagnostics) {
-	base, diags := newBaseEncryption(enc, target, enforced, name, staticEval)
+func newPlanEncryption(enc *encryption, target *config.TargetConfig, enforced bool, unencryptedReads bool, name string, staticEval *configs.StaticEvaluator) (PlanEncryption, hcl.Diagnostics) {
+	base, diags := newBaseEncryption(enc, target, enforced, unencryptedReads, name, staticEval)
 	return &planEncryption{base}, diags
 }
 
+func (p planEncryption) validate() hcl.Diagnostics {
+	return p.base.validate()
+}
+
 func (p planEncryption) EncryptPlan(data []byte) ([]byte, error) {
 	return p.base.encrypt(data, func(base basedata) interface{} { return base })
 }
@@ -23,6 +23,9 @@ func complianceTestKeyProviders(t *testing.T, factory func() registry.Registry)
 	t.Run("duplicate-registration", func(t *testing.T) {
 		complianceTestKeyProviderDuplicateRegistration(t, factory)
 	})
+	t.Run("list", func(t *testing.T) {
+		complianceTestKeyProviderList(t, factory)
+	})
 }
 
 func complianceTestKeyProviderRegistrationAndReturn(t *testing.T, factory func() registry.Registry) {
@@ -115,6 +118,25 @@ func complianceTestKeyProviderDuplicateRegistration(t *testing.T, factory func()
 	}
 }
 
+func complianceTestKeyProviderList(t *testing.T, factory func() registry.Registry) {
+	reg := factory()
+	if listed := reg.ListKeyProviderDescriptors(); len(listed) != 0 {
+		t.Fatalf("An empty registry returned %d key providers from ListKeyProviderDescriptors.", len(listed))
+	}
+	for _, id := range []string{"second", "first"} {
+		if err := reg.RegisterKeyProvider(&testKeyProviderDescriptor{keyprovider.ID(id)}); err != nil {
+			t.Fatalf("Failed to register test key provider with ID %s (%v)", id, err)
+		}
+	}
+	listed := reg.ListKeyProviderDescriptors()
+	if len(listed) != 2 {
+		t.Fatalf("ListKeyProviderDescriptors returned %d key providers instead of the 2 registered ones.", len(listed))
+	}
+	if listed[0].ID() != "first" || listed[1].ID() != "second" {
+		t.Fatalf("ListKeyProviderDescriptors returned the key providers %s and %s, which are not sorted by their ID.", listed[0].ID(), listed[1].ID())
+	}
+}
+
 type testKeyProviderDescriptor struct {
 	id keyprovider.ID
 }
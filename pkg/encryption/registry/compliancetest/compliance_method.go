@@ -24,6 +24,9 @@ func complianceTestMethods(t *testing.T, factory func() registry.Registry) {
 	t.Run("duplicate-registration", func(t *testing.T) {
 		complianceTestMethodDuplicateRegistration(t, factory)
 	})
+	t.Run("list", func(t *testing.T) {
+		complianceTestMethodList(t, factory)
+	})
 }
 
 func complianceTestMethodRegistrationAndReturn(t *testing.T, factory func() registry.Registry) {
@@ -116,6 +119,25 @@ func complianceTestMethodDuplicateRegistration(t *testing.T, factory func() regi
 	}
 }
 
+func complianceTestMethodList(t *testing.T, factory func() registry.Registry) {
+	reg := factory()
+	if listed := reg.ListMethodDescriptors(); len(listed) != 0 {
+		t.Fatalf("An empty registry returned %d methods from ListMethodDescriptors.", len(listed))
+	}
+	for _, id := range []string{"second", "first"} {
+		if err := reg.RegisterMethod(&testMethodDescriptor{method.ID(id)}); err != nil {
+			t.Fatalf("Failed to register test method with ID %s (%v)", id, err)
+		}
+	}
+	listed := reg.ListMethodDescriptors()
+	if len(listed) != 2 {
+		t.Fatalf("ListMethodDescriptors returned %d methods instead of the 2 registered ones.", len(listed))
+	}
+	if listed[0].ID() != "first" || listed[1].ID() != "second" {
+		t.Fatalf("ListMethodDescriptors returned the methods %s and %s, which are not sorted by their ID.", listed[0].ID(), listed[1].ID())
+	}
+}
+
 type testMethodDescriptor struct {
 	id method.ID
 }
@@ -6,6 +6,7 @@
 package lockingencryptionregistry
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
@@ -77,3 +78,29 @@ func (l *lockingRegistry) GetMethodDescriptor(id method.ID) (method.Descriptor,
 	}
 	return foundMethod, nil
 }
+
+func (l *lockingRegistry) ListKeyProviderDescriptors() []keyprovider.Descriptor {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	result := make([]keyprovider.Descriptor, 0, len(l.providers))
+	for _, provider := range l.providers {
+		result = append(result, provider)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
+
+func (l *lockingRegistry) ListMethodDescriptors() []method.Descriptor {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	result := make([]method.Descriptor, 0, len(l.methods))
+	for _, foundMethod := range l.methods {
+		result = append(result, foundMethod)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
@@ -28,4 +28,10 @@ type Registry interface {
 	// GetMethodDescriptor returns the method with the specified ID.
 	// If the method is not registered, it will return a *MethodNotFoundError.
 	GetMethodDescriptor(id method.ID) (method.Descriptor, error)
+
+	// ListKeyProviderDescriptors returns all registered key providers, sorted by their ID.
+	ListKeyProviderDescriptors() []keyprovider.Descriptor
+
+	// ListMethodDescriptors returns all registered methods, sorted by their ID.
+	ListMethodDescriptors() []method.Descriptor
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/we-dcode/opentofu/pkg/configs"
 	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/unencrypted"
 )
 
 // StateEncryption describes the interface for encrypting state files.
@@ -56,11 +57,18 @@ type StateEncryption interface {
 
 type stateEncryption struct {
 	base *baseEncryption
+
+	// sensitiveOnly encrypts only the sensitive values of the state, leaving the rest of it readable.
+	sensitiveOnly bool
+}
+
+func newStateEncryption(enc *encryption, target *config.TargetConfig, enforced bool, unencryptedReads bool, sensitiveOnly bool, name string, staticEval *configs.StaticEvaluator) (StateEncryption, hcl.Diagnostics) {
+	base, diags := newBaseEncryption(enc, target, enforced, unencryptedReads, name, staticEval)
+	return &stateEncryption{base, sensitiveOnly}, diags
 }
 
-func newStateEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (StateEncryption, hcl.Diagnostics) {
-	base, diags := newBaseEncryption(enc, target, enforced, name, staticEval)
-	return &stateEncryption{base}, diags
+func (s *stateEncryption) validate() hcl.Diagnostics {
+	return s.base.validate()
 }
 
 type statedata struct {
@@ -75,6 +83,10 @@ func (s *stateEncryption) EncryptState(plainState []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	if s.sensitiveOnly && !unencrypted.Is(s.base.encMethods[0]) {
+		return s.encryptSensitiveValues(plainState)
+	}
+
 	return s.base.encrypt(plainState, func(base basedata) interface{} {
 		// Merge together the base encryption data and the passthrough fields
 		return struct {
@@ -88,6 +100,12 @@ func (s *stateEncryption) EncryptState(plainState []byte) ([]byte, error) {
 }
 
 func (s *stateEncryption) DecryptState(encryptedState []byte) ([]byte, EncryptionStatus, error) {
+	// State files with only the sensitive values encrypted are read regardless of the current configuration, so
+	// the sensitive_only option can be turned off again.
+	if decryptedState, status, ok, err := s.decryptSensitiveValues(encryptedState); ok {
+		return decryptedState, status, err
+	}
+
 	decryptedState, status, err := s.base.decrypt(encryptedState, func(data []byte) error {
 		tmp := struct {
 			FormatVersion string `json:"terraform_version"`
@@ -107,6 +125,10 @@ func (s *stateEncryption) DecryptState(encryptedState []byte) ([]byte, Encryptio
 	if err != nil {
 		return nil, status, err
 	}
+	if s.sensitiveOnly && status == StatusSatisfied && !unencrypted.Is(s.base.encMethods[0]) {
+		// Only the sensitive values should be encrypted now.
+		status = StatusMigration
+	}
 
 	// Make sure that the state passthrough fields match
 	var encrypted statedata
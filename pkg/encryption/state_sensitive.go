@@ -0,0 +1,300 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// sensitiveValuesField is the field of a state file that holds the encrypted sensitive values when only the sensitive
+// values are encrypted. Its presence identifies such a state file.
+const sensitiveValuesField = "encrypted_sensitive_values"
+
+// sensitiveValue is a sensitive value removed from a state file, along with the path to restore it to. The path
+// steps are strings for object keys and numbers for array indices.
+type sensitiveValue struct {
+	Path  []interface{} `json:"path"`
+	Value interface{}   `json:"value"`
+}
+
+// encryptSensitiveValues encrypts only the sensitive output values and resource instance attributes of the given
+// state file. The sensitive values are replaced with null and stored in encrypted form in the sensitiveValuesField,
+// the rest of the state file stays readable.
+func (s *stateEncryption) encryptSensitiveValues(plainState []byte) ([]byte, error) {
+	state, err := decodeStateJSON(plainState)
+	if err != nil {
+		return nil, err
+	}
+
+	values := extractSensitiveValues(state)
+	valuesJSON, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode sensitive values as json: %w", err)
+	}
+
+	encrypted, err := s.base.encrypt(valuesJSON, func(data basedata) interface{} { return data })
+	if err != nil {
+		return nil, err
+	}
+	state[sensitiveValuesField] = json.RawMessage(encrypted)
+
+	return json.Marshal(state)
+}
+
+// decryptSensitiveValues restores the sensitive values of a state file written by encryptSensitiveValues. The
+// returned bool is false if the state file does not contain encrypted sensitive values.
+func (s *stateEncryption) decryptSensitiveValues(encryptedState []byte) ([]byte, EncryptionStatus, bool, error) {
+	// If the state is not a JSON object, the error handling is left to the regular decryption.
+	var marker map[string]json.RawMessage
+	if json.Unmarshal(encryptedState, &marker) != nil {
+		return nil, StatusUnknown, false, nil
+	}
+	encrypted, ok := marker[sensitiveValuesField]
+	if !ok {
+		return nil, StatusUnknown, false, nil
+	}
+
+	valuesJSON, status, err := s.base.decrypt(encrypted, func([]byte) error {
+		return errors.New("the sensitive values of the state file are not encrypted")
+	})
+	if err != nil {
+		return nil, status, true, err
+	}
+	if !s.sensitiveOnly && status == StatusSatisfied {
+		// The whole state file should be encrypted now.
+		status = StatusMigration
+	}
+
+	var values []sensitiveValue
+	dec := json.NewDecoder(bytes.NewReader(valuesJSON))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return nil, status, true, fmt.Errorf("invalid encrypted sensitive values: %w", err)
+	}
+
+	state, err := decodeStateJSON(encryptedState)
+	if err != nil {
+		return nil, status, true, err
+	}
+	delete(state, sensitiveValuesField)
+
+	// Values are restored in reverse, so values nested in other sensitive values end up in their parent.
+	for i := len(values) - 1; i >= 0; i-- {
+		if err := restoreSensitiveValue(state, values[i]); err != nil {
+			return nil, status, true, err
+		}
+	}
+
+	decrypted, err := json.Marshal(state)
+	if err != nil {
+		return nil, status, true, err
+	}
+	return decrypted, status, true, nil
+}
+
+// decodeStateJSON decodes a state file into generic JSON values, keeping numbers exactly as they were written.
+func decodeStateJSON(data []byte) (map[string]interface{}, error) {
+	var state map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// extractSensitiveValues removes all sensitive values from the given state file and returns them. It finds the
+// outputs marked sensitive and the resource instance attributes listed in sensitive_attributes.
+func extractSensitiveValues(state map[string]interface{}) []sensitiveValue {
+	var values []sensitiveValue
+
+	// extract removes the value at the given path. At least minDepth steps of the path must exist, so that a
+	// malformed state file never causes the structure around the value to be removed.
+	extract := func(path []interface{}, minDepth int) {
+		parent, depth, value := resolveSensitivePath(state, path)
+		if depth < minDepth || value == nil {
+			// Null values have nothing to hide.
+			return
+		}
+		values = append(values, sensitiveValue{Path: path[:depth], Value: value})
+		setJSONChild(parent, path[depth-1], nil)
+	}
+
+	if outputs, ok := state["outputs"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(outputs))
+		for name := range outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			output, ok := outputs[name].(map[string]interface{})
+			if !ok || output["sensitive"] != true {
+				continue
+			}
+			extract([]interface{}{"outputs", name, "value"}, 3)
+		}
+	}
+
+	resources, _ := state["resources"].([]interface{})
+	for i, r := range resources {
+		resource, _ := r.(map[string]interface{})
+		instances, _ := resource["instances"].([]interface{})
+		for j, inst := range instances {
+			instance, _ := inst.(map[string]interface{})
+			paths, _ := instance["sensitive_attributes"].([]interface{})
+			for _, p := range paths {
+				steps, _ := p.([]interface{})
+				path := []interface{}{"resources", i, "instances", j, "attributes"}
+				extract(append(path, attributePathSteps(steps)...), len(path))
+			}
+		}
+	}
+
+	return values
+}
+
+// attributePathSteps converts the steps of a path in sensitive_attributes to steps in the JSON attributes. Steps that
+// can't be represented, like set elements, end the path early, so the whole containing value is treated as
+// sensitive.
+func attributePathSteps(steps []interface{}) []interface{} {
+	var path []interface{}
+	for _, s := range steps {
+		step, _ := s.(map[string]interface{})
+		switch step["type"] {
+		case "get_attr":
+			name, ok := step["value"].(string)
+			if !ok {
+				return path
+			}
+			path = append(path, name)
+		case "index":
+			key, _ := step["value"].(map[string]interface{})
+			switch key["type"] {
+			case "string":
+				name, ok := key["value"].(string)
+				if !ok {
+					return path
+				}
+				path = append(path, name)
+			case "number":
+				num, ok := key["value"].(json.Number)
+				if !ok {
+					return path
+				}
+				idx, err := strconv.Atoi(num.String())
+				if err != nil {
+					return path
+				}
+				path = append(path, idx)
+			default:
+				return path
+			}
+		default:
+			return path
+		}
+	}
+	return path
+}
+
+// resolveSensitivePath follows the given path in the state file as far as possible. It returns the value reached, the
+// number of steps followed, and the container of the value. If a step can't be followed, the value it was applied to
+// is returned instead, so the whole containing value is treated as sensitive.
+func resolveSensitivePath(state map[string]interface{}, path []interface{}) (interface{}, int, interface{}) {
+	var parent interface{}
+	var current interface{} = state
+	depth := 0
+	for _, step := range path {
+		child, ok := getJSONChild(current, step)
+		if !ok {
+			break
+		}
+		parent, current = current, child
+		depth++
+	}
+	return parent, depth, current
+}
+
+// restoreSensitiveValue puts a sensitive value back into the state file.
+func restoreSensitiveValue(state map[string]interface{}, value sensitiveValue) error {
+	if len(value.Path) == 0 {
+		return fmt.Errorf("invalid encrypted sensitive value: empty path")
+	}
+	var current interface{} = state
+	for _, step := range value.Path[:len(value.Path)-1] {
+		child, ok := getJSONChild(current, step)
+		if !ok {
+			return fmt.Errorf("invalid encrypted sensitive value: path %v does not exist in the state file", value.Path)
+		}
+		current = child
+	}
+	if !setJSONChild(current, value.Path[len(value.Path)-1], value.Value) {
+		return fmt.Errorf("invalid encrypted sensitive value: path %v does not exist in the state file", value.Path)
+	}
+	return nil
+}
+
+func getJSONChild(container interface{}, step interface{}) (interface{}, bool) {
+	switch c := container.(type) {
+	case map[string]interface{}:
+		name, ok := step.(string)
+		if !ok {
+			return nil, false
+		}
+		child, ok := c[name]
+		return child, ok
+	case []interface{}:
+		idx, ok := jsonIndex(step)
+		if !ok || idx < 0 || idx >= len(c) {
+			return nil, false
+		}
+		return c[idx], true
+	default:
+		return nil, false
+	}
+}
+
+func setJSONChild(container interface{}, step interface{}, value interface{}) bool {
+	switch c := container.(type) {
+	case map[string]interface{}:
+		name, ok := step.(string)
+		if !ok {
+			return false
+		}
+		if _, ok := c[name]; !ok {
+			return false
+		}
+		c[name] = value
+		return true
+	case []interface{}:
+		idx, ok := jsonIndex(step)
+		if !ok || idx < 0 || idx >= len(c) {
+			return false
+		}
+		c[idx] = value
+		return true
+	default:
+		return false
+	}
+}
+
+// jsonIndex returns the array index of a path step, which is an int when extracting values and a json.Number after
+// the path has been decoded again.
+func jsonIndex(step interface{}) (int, bool) {
+	switch s := step.(type) {
+	case int:
+		return s, true
+	case json.Number:
+		idx, err := strconv.Atoi(s.String())
+		return idx, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/configs"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/static"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcm"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
+)
+
+const sensitiveTestState = `{
+  "version": 4,
+  "terraform_version": "1.8.0",
+  "serial": 3,
+  "lineage": "test",
+  "outputs": {
+    "password": {"value": "output-secret", "type": "string", "sensitive": true},
+    "nothing": {"value": null, "type": "string", "sensitive": true},
+    "name": {"value": "public-name", "type": "string"}
+  },
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "test_thing",
+      "name": "foo",
+      "provider": "provider[\"registry.opentofu.org/hashicorp/test\"]",
+      "instances": [
+        {
+          "schema_version": 0,
+          "attributes": {
+            "id": "public-id",
+            "password": "attribute-secret",
+            "tokens": ["public-token", "list-secret"],
+            "settings": {"big": 12345678901234567890.5, "key": "map-secret"},
+            "rules": [{"port": 443}]
+          },
+          "sensitive_attributes": [
+            [{"type": "get_attr", "value": "password"}],
+            [{"type": "get_attr", "value": "tokens"}, {"type": "index", "value": {"value": 1, "type": "number"}}],
+            [{"type": "get_attr", "value": "settings"}],
+            [{"type": "get_attr", "value": "settings"}, {"type": "index", "value": {"value": "key", "type": "string"}}],
+            [{"type": "get_attr", "value": "rules"}, {"type": "index", "value": {"value": [{"port": 443}], "type": ["set", ["object", {"port": "number"}]]}}]
+          ]
+        }
+      ]
+    }
+  ],
+  "check_results": null
+}`
+
+func newSensitiveTestEncryption(t *testing.T, stateBlock string) Encryption {
+	t.Helper()
+
+	rawConfig := `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+	` + stateBlock
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("Test Config Source", rawConfig)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	return enc
+}
+
+func TestStateEncryption_sensitiveOnly(t *testing.T) {
+	enc := newSensitiveTestEncryption(t, `
+		state {
+			method = method.aes_gcm.example
+			sensitive_only = true
+		}
+	`)
+
+	encrypted, err := enc.State().EncryptState([]byte(sensitiveTestState))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"output-secret", "attribute-secret", "list-secret", "map-secret", "12345678901234567890.5"} {
+		if strings.Contains(string(encrypted), secret) {
+			t.Errorf("encrypted state contains sensitive value %q:\n%s", secret, encrypted)
+		}
+	}
+	for _, public := range []string{"public-name", "public-id", "public-token", `"lineage":"test"`} {
+		if !strings.Contains(string(encrypted), public) {
+			t.Errorf("encrypted state is missing non-sensitive value %q:\n%s", public, encrypted)
+		}
+	}
+
+	status, err := InspectPayload(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Encrypted || !status.SensitiveOnly {
+		t.Fatalf("unexpected payload status: %#v", status)
+	}
+
+	decrypted, decStatus, err := enc.State().DecryptState(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decStatus != StatusSatisfied {
+		t.Errorf("unexpected status %v", decStatus)
+	}
+	assertJSONEqual(t, decrypted, []byte(sensitiveTestState))
+
+	// The state is also readable once the option is turned off, but needs to be migrated.
+	fullEnc := newSensitiveTestEncryption(t, `
+		state {
+			method = method.aes_gcm.example
+		}
+	`)
+	decrypted, decStatus, err = fullEnc.State().DecryptState(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decStatus != StatusMigration {
+		t.Errorf("unexpected status %v", decStatus)
+	}
+	assertJSONEqual(t, decrypted, []byte(sensitiveTestState))
+}
+
+func TestStateEncryption_sensitiveOnlyTampered(t *testing.T) {
+	enc := newSensitiveTestEncryption(t, `
+		state {
+			method = method.aes_gcm.example
+			sensitive_only = true
+		}
+	`)
+
+	encrypted, err := enc.State().EncryptState([]byte(sensitiveTestState))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing the encrypted values must not result in a state without its sensitive values.
+	var state map[string]json.RawMessage
+	if err := json.Unmarshal(encrypted, &state); err != nil {
+		t.Fatal(err)
+	}
+	state[sensitiveValuesField] = json.RawMessage(`{"not": "encrypted"}`)
+	tampered, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := enc.State().DecryptState(tampered); err == nil {
+		t.Fatal("expected an error, got none")
+	}
+}
+
+func TestEncryption_sensitiveOnlyPlan(t *testing.T) {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("Test Config Source", `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+		plan {
+			method = method.aes_gcm.example
+			sensitive_only = true
+		}
+	`)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	_, diags = New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if !diags.HasErrors() {
+		t.Fatal("expected an error, got none")
+	}
+	if got, want := diags[0].Summary, "Unsupported sensitive_only option"; got != want {
+		t.Fatalf("unexpected error %q, want %q", got, want)
+	}
+}
+
+func assertJSONEqual(t *testing.T, got, want []byte) {
+	t.Helper()
+
+	decode := func(data []byte) interface{} {
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	if !reflect.DeepEqual(decode(got), decode(want)) {
+		t.Fatalf("unexpected JSON\n got: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+)
+
+// PayloadStatus describes how a state or plan payload is encrypted, as far as this can be determined from the
+// encryption header without decrypting the payload.
+type PayloadStatus struct {
+	// Encrypted is false if the payload is plaintext.
+	Encrypted bool
+	// Version is the encryption format version of the payload.
+	Version string
+	// KeyProviders lists the key provider metadata stored in the payload, sorted by MetaKey.
+	KeyProviders []PayloadKeyProvider
+	// SensitiveOnly is true if only the sensitive values of a state file are encrypted.
+	SensitiveOnly bool
+}
+
+// PayloadKeyProvider describes the metadata a single key provider stored alongside an encrypted payload.
+type PayloadKeyProvider struct {
+	// MetaKey is the key under which the metadata is stored. This is the address of the key provider, unless the
+	// key provider is configured with an encrypted_metadata_alias.
+	MetaKey keyprovider.MetaStorageKey
+	// KeyVersion is the version of the key the payload was encrypted with, if the key provider records it.
+	KeyVersion string
+}
+
+// InspectPayload reads the encryption header of the given state or plan payload. It does not need access to any
+// keys. Plaintext payloads are reported as not encrypted.
+func InspectPayload(data []byte) (*PayloadStatus, error) {
+	var partial map[string]json.RawMessage
+	if err := json.Unmarshal(data, &partial); err != nil {
+		return nil, fmt.Errorf("unable to parse the payload as json: %w", err)
+	}
+	sensitiveOnly := false
+	if sensitiveValues, ok := partial[sensitiveValuesField]; ok {
+		// Only the sensitive values are encrypted, the encryption header is stored along with them.
+		data = sensitiveValues
+		sensitiveOnly = true
+	}
+
+	es := basedata{}
+	if err := json.Unmarshal(data, &es); err != nil {
+		return nil, fmt.Errorf("unable to parse the payload as json: %w", err)
+	}
+	if es.Version == "" {
+		return &PayloadStatus{}, nil
+	}
+
+	status := &PayloadStatus{
+		Encrypted:     true,
+		Version:       es.Version,
+		SensitiveOnly: sensitiveOnly,
+	}
+	for metaKey, meta := range es.Meta {
+		// Key providers which support rotation store the key version in their metadata.
+		var versioned struct {
+			KeyVersion string `json:"key_version"`
+		}
+		// The metadata format is up to the key provider, so we ignore metadata we can't read.
+		_ = json.Unmarshal(meta, &versioned)
+
+		status.KeyProviders = append(status.KeyProviders, PayloadKeyProvider{
+			MetaKey:    metaKey,
+			KeyVersion: versioned.KeyVersion,
+		})
+	}
+	sort.Slice(status.KeyProviders, func(i, j int) bool {
+		return status.KeyProviders[i].MetaKey < status.KeyProviders[j].MetaKey
+	})
+	return status, nil
+}
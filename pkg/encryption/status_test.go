@@ -0,0 +1,106 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/configs"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/pbkdf2"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcm"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
+)
+
+func TestInspectPayload(t *testing.T) {
+	sourceConfig := `key_provider "pbkdf2" "basic" {
+			passphrase = "Hello world! 123"
+		}
+		key_provider "pbkdf2" "aliased" {
+			encrypted_metadata_alias = "foo"
+			passphrase               = "Hello world! 456"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.pbkdf2.basic
+		}
+		method "aes_gcm" "fallback" {
+			keys = key_provider.pbkdf2.aliased
+		}
+		state {
+			method = method.aes_gcm.example
+			fallback {
+				method = method.aes_gcm.fallback
+			}
+		}`
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("source", sourceConfig)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := enc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := InspectPayload(encryptedState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &PayloadStatus{
+		Encrypted: true,
+		Version:   encryptionVersion,
+		KeyProviders: []PayloadKeyProvider{
+			{MetaKey: "foo"},
+			{MetaKey: "key_provider.pbkdf2.basic"},
+		},
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Fatalf("unexpected status\n got: %#v\nwant: %#v", status, want)
+	}
+
+	status, err = InspectPayload(testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Encrypted {
+		t.Fatalf("plaintext payload reported as encrypted: %#v", status)
+	}
+
+	if _, err := InspectPayload([]byte("not json")); err == nil {
+		t.Fatal("expected an error for an invalid payload")
+	}
+}
+
+func TestInspectPayload_keyVersion(t *testing.T) {
+	// The metadata is base64 encoded {"key_version":"v2"}.
+	payload := []byte(`{"meta":{"key_provider.test.basic":"eyJrZXlfdmVyc2lvbiI6InYyIn0="},"encrypted_data":"","encryption_version":"v0"}`)
+
+	status, err := InspectPayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PayloadKeyProvider{
+		{MetaKey: "key_provider.test.basic", KeyVersion: "v2"},
+	}
+	if !reflect.DeepEqual(status.KeyProviders, want) {
+		t.Fatalf("unexpected key providers\n got: %#v\nwant: %#v", status.KeyProviders, want)
+	}
+}
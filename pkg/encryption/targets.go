@@ -7,6 +7,7 @@ package encryption
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -17,6 +18,7 @@ import (
 	"github.com/we-dcode/opentofu/pkg/encryption/method/unencrypted"
 	"github.com/we-dcode/opentofu/pkg/encryption/registry"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
 )
 
 type targetBuilder struct {
@@ -34,11 +36,30 @@ type targetBuilder struct {
 	methodValues map[string]map[string]cty.Value
 	methods      map[method.Addr]method.Method
 	staticEval   *configs.StaticEvaluator
+
+	// rotatedKeyValues holds the previous keys of the key providers which reported a key rotation.
+	rotatedKeyValues map[string]map[string]cty.Value
+
+	// instanceKeys holds the sorted instance keys of the key providers expanded with for_each.
+	instanceKeys map[string]map[string][]string
+
+	// failedKeyProviders holds the type.name of the key providers which could not be set up, so that the key
+	// providers and methods depending on them can be skipped instead of reporting follow-up errors.
+	failedKeyProviders map[string]bool
+
+	// targetName is the name of the target the methods are built for. It is passed to methods which authenticate
+	// the target as additional data.
+	targetName string
 }
 
 func (base *baseEncryption) buildTargetMethods(inputMeta map[keyprovider.MetaStorageKey][]byte, outputMeta map[keyprovider.MetaStorageKey][]byte) ([]method.Method, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
+	lookupEnv := base.enc.lookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
 	builder := &targetBuilder{
 		cfg: base.enc.cfg,
 		reg: base.enc.reg,
@@ -46,19 +67,20 @@ func (base *baseEncryption) buildTargetMethods(inputMeta map[keyprovider.MetaSto
 		staticEval: base.staticEval,
 		ctx: &hcl.EvalContext{
 			Variables: map[string]cty.Value{},
+			Functions: map[string]function.Function{
+				"env": envFunc(lookupEnv),
+			},
 		},
 
 		inputKeyProviderMetadata:  inputMeta,
 		outputKeyProviderMetadata: outputMeta,
-	}
 
-	keyDiags := append(diags, builder.setupKeyProviders()...)
-	diags = append(diags, keyDiags...)
-	if diags.HasErrors() {
-		return nil, diags
+		targetName: base.name,
 	}
-	methodDiags := append(diags, builder.setupMethods()...)
-	diags = append(diags, methodDiags...)
+
+	// The methods are set up even if some key providers failed, so that all errors are reported at once.
+	diags = append(diags, builder.setupKeyProviders()...)
+	diags = append(diags, builder.setupMethods()...)
 	if diags.HasErrors() {
 		return nil, diags
 	}
@@ -66,15 +88,37 @@ func (base *baseEncryption) buildTargetMethods(inputMeta map[keyprovider.MetaSto
 	methods, targetDiags := builder.build(base.target, base.name)
 	diags = append(diags, targetDiags...)
 
+	// When a key was rotated, the methods are ordered newest-first: the primary method with the new key is used for
+	// encryption, followed by the same method with the previous key, which can only be used for decryption.
+	if len(builder.rotatedKeyValues) != 0 && len(methods) != 0 && !diags.HasErrors() {
+		rotatedMethods, rotatedDiags := builder.buildRotated(base.target, base.name)
+		diags = append(diags, rotatedDiags...)
+		if len(rotatedMethods) != 0 {
+			methods = append([]method.Method{methods[0], rotatedMethods[0]}, methods[1:]...)
+		}
+	}
+
 	if base.enforced {
-		for _, m := range methods {
-			if unencrypted.Is(m) {
+		for i, m := range methods {
+			if !unencrypted.Is(m) {
+				continue
+			}
+			// The first method is used for encryption, the unencrypted method may only be used for reading.
+			if i > 0 && base.unencryptedReads {
+				continue
+			}
+			if base.unencryptedReads {
 				return nil, append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "Unencrypted method is forbidden",
-					Detail:   "Unable to use `unencrypted` method since the `enforced` flag is used.",
+					Detail:   "Unable to use `unencrypted` method for writing since the `enforced` flag is used. With `allow_unencrypted_reads`, the `unencrypted` method may only be used in a `fallback` block.",
 				})
 			}
+			return nil, append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unencrypted method is forbidden",
+				Detail:   "Unable to use `unencrypted` method since the `enforced` flag is used.",
+			})
 		}
 	}
 
@@ -119,12 +163,49 @@ func (e *targetBuilder) build(target *config.TargetConfig, targetName string) (m
 		}
 	}
 
-	// Attempt to fetch the fallback method if it's been configured
-	if target.Fallback != nil {
-		fallback, fallbackDiags := e.build(target.Fallback, targetName+".fallback")
+	// Attempt to fetch the fallback methods if they've been configured. They are tried in declaration order.
+	for i, fallbackTarget := range target.Fallbacks {
+		fallbackName := targetName + ".fallback"
+		if len(target.Fallbacks) > 1 {
+			fallbackName = fmt.Sprintf("%s[%d]", fallbackName, i)
+		}
+		fallback, fallbackDiags := e.build(fallbackTarget, fallbackName)
 		diags = append(diags, fallbackDiags...)
 		methods = append(methods, fallback...)
 	}
 
 	return methods, diags
 }
+
+// buildRotated builds the target again with the keys of rotated key providers replaced by their previous keys. The
+// resulting methods are only suitable for decrypting data written before the rotation.
+func (e *targetBuilder) buildRotated(target *config.TargetConfig, targetName string) ([]method.Method, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	kpMap := make(map[string]cty.Value)
+	for kpType, kps := range e.keyValues {
+		values := make(map[string]cty.Value, len(kps))
+		for name, value := range kps {
+			values[name] = value
+		}
+		for name, value := range e.rotatedKeyValues[kpType] {
+			values[name] = value
+		}
+		kpMap[kpType] = cty.ObjectVal(values)
+	}
+	current := e.ctx.Variables["key_provider"]
+	e.ctx.Variables["key_provider"] = cty.ObjectVal(kpMap)
+	defer func() {
+		e.ctx.Variables["key_provider"] = current
+	}()
+
+	// Warnings have already been reported when building the target with the current keys.
+	methodDiags := e.setupMethods()
+	methods, buildDiags := e.build(target, targetName)
+	for _, diag := range append(methodDiags, buildDiags...) {
+		if diag.Severity == hcl.DiagError {
+			diags = append(diags, diag)
+		}
+	}
+	return methods, diags
+}
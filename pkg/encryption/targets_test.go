@@ -6,7 +6,12 @@
 package encryption
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
@@ -76,6 +81,90 @@ func TestBaseEncryption_buildTargetMethods(t *testing.T) {
 				unencrypted.Is,
 			},
 		},
+		"multiple-fallbacks": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				method "aes_gcm" "previous" {
+					keys = key_provider.static.basic
+				}
+				method "unencrypted" "example" {
+				}
+				state {
+					method = method.aes_gcm.example
+					fallback {
+						method = method.unencrypted.example
+					}
+					fallback {
+						method = method.aes_gcm.previous
+					}
+				}
+			`,
+			wantMethods: []func(method.Method) bool{
+				aesgcm.Is,
+				unencrypted.Is,
+				aesgcm.Is,
+			},
+		},
+		"multiple-fallbacks-order": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				method "aes_gcm" "previous" {
+					keys = key_provider.static.basic
+				}
+				method "unencrypted" "example" {
+				}
+				state {
+					method = method.aes_gcm.example
+					fallback {
+						method = method.aes_gcm.previous
+					}
+					fallback {
+						method = method.unencrypted.example
+					}
+				}
+			`,
+			wantMethods: []func(method.Method) bool{
+				aesgcm.Is,
+				aesgcm.Is,
+				unencrypted.Is,
+			},
+		},
+		"enforced-multiple-fallbacks": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				method "aes_gcm" "previous" {
+					keys = key_provider.static.basic
+				}
+				method "unencrypted" "example" {
+				}
+				state {
+					enforced = true
+					method   = method.aes_gcm.example
+					fallback {
+						method = method.aes_gcm.previous
+					}
+					fallback {
+						method = method.unencrypted.example
+					}
+				}
+			`,
+			wantErr: "<nil>: Unencrypted method is forbidden; Unable to use `unencrypted` method since the `enforced` flag is used.",
+		},
 		"enforced": {
 			rawConfig: `
 				key_provider "static" "basic" {
@@ -115,6 +204,169 @@ func TestBaseEncryption_buildTargetMethods(t *testing.T) {
 			`,
 			wantErr: "<nil>: Unencrypted method is forbidden; Unable to use `unencrypted` method since the `enforced` flag is used.",
 		},
+		"for-each": {
+			rawConfig: `
+				key_provider "static" "multi" {
+					for_each = {
+						a = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+						b = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+					}
+					key = each.value
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.multi["b"]
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			wantMethods: []func(method.Method) bool{
+				aesgcm.Is,
+			},
+		},
+		"for-each-missing-instance": {
+			rawConfig: `
+				key_provider "static" "multi" {
+					for_each = {
+						a = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+						b = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+					}
+					key = each.value
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.multi["c"]
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			wantErr: `Test Config Source:10,13-43: Undefined Key Provider instance; Key provider static.multi has no instance with the key "c"; the for_each argument produced the keys ["a" "b"].`,
+		},
+		"for-each-not-a-collection": {
+			rawConfig: `
+				key_provider "static" "multi" {
+					for_each = "a"
+					key = each.value
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.multi["a"]
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			wantErr: `Test Config Source:3,17-20: Invalid for_each argument; The given "for_each" argument value is unsuitable: the "for_each" argument must be a map, or set of strings, and you have provided a value of type string.`,
+		},
+		"rotated-key": {
+			rawConfig: `
+				key_provider "rotating" "basic" {
+					version = "v2"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.rotating.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			inputMeta: map[keyprovider.MetaStorageKey][]byte{
+				"key_provider.rotating.basic": []byte(`{"key_version":"v1"}`),
+			},
+			wantMethods: []func(method.Method) bool{
+				aesgcm.Is,
+				aesgcm.Is,
+			},
+		},
+		"rotated-key-with-fallback": {
+			rawConfig: `
+				key_provider "rotating" "basic" {
+					version = "v2"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.rotating.basic
+				}
+				method "unencrypted" "example" {
+				}
+				state {
+					method = method.aes_gcm.example
+					fallback {
+						method = method.unencrypted.example
+					}
+				}
+			`,
+			inputMeta: map[keyprovider.MetaStorageKey][]byte{
+				"key_provider.rotating.basic": []byte(`{"key_version":"v1"}`),
+			},
+			wantMethods: []func(method.Method) bool{
+				aesgcm.Is,
+				aesgcm.Is,
+				unencrypted.Is,
+			},
+		},
+		"same-key-version": {
+			rawConfig: `
+				key_provider "rotating" "basic" {
+					version = "v2"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.rotating.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			inputMeta: map[keyprovider.MetaStorageKey][]byte{
+				"key_provider.rotating.basic": []byte(`{"key_version":"v2"}`),
+			},
+			wantMethods: []func(method.Method) bool{
+				aesgcm.Is,
+			},
+		},
+		"enforced-with-unencrypted-reads": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				method "unencrypted" "example" {
+				}
+				state {
+					enforced                = true
+					allow_unencrypted_reads = true
+					method                  = method.aes_gcm.example
+					fallback {
+						method = method.unencrypted.example
+					}
+				}
+			`,
+			wantMethods: []func(method.Method) bool{
+				aesgcm.Is,
+				unencrypted.Is,
+			},
+		},
+		"enforced-with-unencrypted-reads-primary": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				method "unencrypted" "example" {
+				}
+				state {
+					enforced                = true
+					allow_unencrypted_reads = true
+					method                  = method.unencrypted.example
+					fallback {
+						method = method.aes_gcm.example
+					}
+				}
+			`,
+			wantErr: "<nil>: Unencrypted method is forbidden; Unable to use `unencrypted` method for writing since the `enforced` flag is used. With `allow_unencrypted_reads`, the `unencrypted` method may only be used in a `fallback` block.",
+		},
 		"key-from-vars": {
 			rawConfig: `
 				key_provider "static" "basic" {
@@ -161,6 +413,36 @@ func TestBaseEncryption_buildTargetMethods(t *testing.T) {
 			`,
 			wantErr: "Test Config Source:3,12-28: Undefined variable; Undefined variable var.undefinedkey",
 		},
+		"key-from-env": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = env("TF_TEST_STATE_KEY")
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			wantMethods: []func(method.Method) bool{
+				aesgcm.Is,
+			},
+		},
+		"undefined-key-from-env": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = env("TF_TEST_UNDEFINED_KEY")
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			wantErr: `Test Config Source:3,16-39: Invalid function argument; Invalid value for "name" parameter: Undefined environment variable TF_TEST_UNDEFINED_KEY.`,
+		},
 		"bad-keyprovider-format": {
 			rawConfig: `
 				key_provider "static" "basic" {
@@ -175,12 +457,35 @@ func TestBaseEncryption_buildTargetMethods(t *testing.T) {
 			`,
 			wantErr: "Test Config Source:3,12-34: Invalid Key Provider expression format; Expected key_provider.<type>.<name>",
 		},
+		"multiple-errors": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = var.undefinedkey
+				}
+				key_provider "static" "other" {
+					key = key_provider.static[0]
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			wantErrs: []string{
+				"Test Config Source:3,12-28: Undefined variable; Undefined variable var.undefinedkey",
+				"Test Config Source:6,12-34: Invalid Key Provider expression format; Expected key_provider.<type>.<name>",
+			},
+		},
 	}
 
 	reg := lockingencryptionregistry.New()
 	if err := reg.RegisterKeyProvider(static.New()); err != nil {
 		panic(err)
 	}
+	if err := reg.RegisterKeyProvider(rotatingKeyProviderDescriptor{}); err != nil {
+		panic(err)
+	}
 	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
 		panic(err)
 	}
@@ -215,10 +520,194 @@ func TestBaseEncryption_buildTargetMethods(t *testing.T) {
 	}
 }
 
+// testLookupEnv is a fake environment for the env function, so the tests can run in parallel.
+func testLookupEnv(name string) (string, bool) {
+	env := map[string]string{
+		"TF_TEST_STATE_KEY": "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169",
+	}
+	value, ok := env[name]
+	return value, ok
+}
+
+func TestBaseEncryption_buildTargetMethodsInvalidKeyLength(t *testing.T) {
+	t.Parallel()
+
+	// The key is 15 bytes long, which is not a valid AES key length.
+	rawConfig := `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+		state {
+			method = method.aes_gcm.example
+		}
+	`
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("Test Config Source", rawConfig)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	base := &baseEncryption{
+		enc: &encryption{
+			cfg: cfg,
+			reg: reg,
+		},
+		target:        cfg.State.AsTargetConfig(),
+		name:          "test",
+		inputEncMeta:  make(map[keyprovider.MetaStorageKey][]byte),
+		outputEncMeta: make(map[keyprovider.MetaStorageKey][]byte),
+		staticEval:    configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()),
+	}
+
+	// The key length must be rejected while building the methods, before anything is encrypted.
+	_, diags = base.buildTargetMethods(base.inputEncMeta, base.outputEncMeta)
+	if !diags.HasErrors() {
+		t.Fatal("expected an error, got none")
+	}
+	diag := diags[0]
+	if diag.Summary != "Encryption method configuration failed" {
+		t.Fatalf("unexpected error: %s", diag.Error())
+	}
+	if !strings.Contains(diag.Detail, "received 15 bytes in the encryption key") {
+		t.Fatalf("unexpected error detail: %s", diag.Detail)
+	}
+	if diag.Subject == nil {
+		t.Fatal("expected the error to point at the method block")
+	}
+	if got, want := diag.Subject.Start.Line, 5; got != want {
+		t.Fatalf("expected the error to point at line %d, got %d", want, got)
+	}
+}
+
+func TestBaseEncryption_buildTargetMethodsKeyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "state.key")
+	if err := os.WriteFile(keyFile, []byte("6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	malformedFile := filepath.Join(dir, "malformed.key")
+	if err := os.WriteFile(malformedFile, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		path        string
+		wantSummary string
+		wantDetail  string
+	}{
+		"present": {
+			path: keyFile,
+		},
+		"missing": {
+			path:        filepath.Join(dir, "missing.key"),
+			wantSummary: "Unable to build encryption key data",
+			wantDetail:  "failed to read the key file",
+		},
+		"malformed": {
+			path:        malformedFile,
+			wantSummary: "Unable to build encryption key data",
+			wantDetail:  "must contain a hex-encoded or a base64-encoded key",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, diags := config.LoadConfigFromString("Test Config Source", `
+				key_provider "static" "basic" {
+					key_file = var.key_file
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			// The path is passed through a variable to make sure the static evaluator resolves it before the file is read.
+			mod := &configs.Module{
+				Variables: map[string]*configs.Variable{
+					"key_file": {
+						Name:    "key_file",
+						Default: cty.StringVal(test.path),
+						Type:    cty.String,
+					},
+				},
+			}
+			getVars := func(v *configs.Variable) (cty.Value, hcl.Diagnostics) {
+				return v.Default, nil
+			}
+			modCall := configs.NewStaticModuleCall(addrs.RootModule, getVars, "<testing>", "")
+
+			base := &baseEncryption{
+				enc: &encryption{
+					cfg: cfg,
+					reg: reg,
+				},
+				target:        cfg.State.AsTargetConfig(),
+				name:          "test",
+				inputEncMeta:  make(map[keyprovider.MetaStorageKey][]byte),
+				outputEncMeta: make(map[keyprovider.MetaStorageKey][]byte),
+				staticEval:    configs.NewStaticEvaluator(mod, modCall),
+			}
+
+			methods, diags := base.buildTargetMethods(base.inputEncMeta, base.outputEncMeta)
+			if test.wantSummary == "" {
+				if diags.HasErrors() {
+					t.Fatalf("unexpected error: %v", diags.Error())
+				}
+				if len(methods) != 1 || !aesgcm.Is(methods[0]) {
+					t.Fatalf("expected a single aes_gcm method, got %v", methods)
+				}
+				return
+			}
+			if !diags.HasErrors() {
+				t.Fatal("expected an error, got none")
+			}
+			diag := diags[0]
+			if diag.Summary != test.wantSummary {
+				t.Fatalf("unexpected error: %s", diag.Error())
+			}
+			if !strings.Contains(diag.Detail, test.wantDetail) {
+				t.Fatalf("unexpected error detail: %s", diag.Detail)
+			}
+		})
+	}
+}
+
 type btmTestCase struct {
 	rawConfig   string // must contain state target
+	inputMeta   map[keyprovider.MetaStorageKey][]byte
 	wantMethods []func(method.Method) bool
 	wantErr     string
+	// wantErrs lists the errors which must all be reported if the config is broken in several places.
+	wantErrs []string
 }
 
 func (testCase btmTestCase) newTestRun(reg registry.Registry, staticEval *configs.StaticEvaluator) func(t *testing.T) {
@@ -232,8 +721,9 @@ func (testCase btmTestCase) newTestRun(reg registry.Registry, staticEval *config
 
 		base := &baseEncryption{
 			enc: &encryption{
-				cfg: cfg,
-				reg: reg,
+				cfg:       cfg,
+				reg:       reg,
+				lookupEnv: testLookupEnv,
 			},
 			target:        cfg.State.AsTargetConfig(),
 			enforced:      cfg.State.Enforced,
@@ -241,11 +731,23 @@ func (testCase btmTestCase) newTestRun(reg registry.Registry, staticEval *config
 			inputEncMeta:  make(map[keyprovider.MetaStorageKey][]byte),
 			outputEncMeta: make(map[keyprovider.MetaStorageKey][]byte),
 			staticEval:    staticEval,
+
+			unencryptedReads: cfg.State.AllowUnencryptedReads,
+		}
+
+		for key, meta := range testCase.inputMeta {
+			base.inputEncMeta[key] = meta
 		}
 
 		methods, diags := base.buildTargetMethods(base.inputEncMeta, base.outputEncMeta)
 
-		if diags.HasErrors() {
+		if testCase.wantErrs != nil {
+			for _, wantErr := range testCase.wantErrs {
+				if !hasDiagWithMsg(diags, wantErr) {
+					t.Fatalf("Expected error %q, got: %v", wantErr, diags.Error())
+				}
+			}
+		} else if diags.HasErrors() {
 			if !hasDiagWithMsg(diags, testCase.wantErr) {
 				t.Fatalf("Got unexpected error: %v", diags.Error())
 			}
@@ -275,3 +777,263 @@ func hasDiagWithMsg(diags hcl.Diagnostics, msg string) bool {
 	}
 	return false
 }
+
+func TestBaseEncryption_rotatedKeyDecrypts(t *testing.T) {
+	t.Parallel()
+
+	configTemplate := `
+		key_provider "rotating" "basic" {
+			version = %q
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.rotating.basic
+		}
+		state {
+			method = method.aes_gcm.example
+		}
+	`
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(rotatingKeyProviderDescriptor{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	staticEval := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+	stateEncryptionFor := func(version string) StateEncryption {
+		cfg, diags := config.LoadConfigFromString("Test Config Source", fmt.Sprintf(configTemplate, version))
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		enc, diags := New(reg, cfg, staticEval)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return enc.State()
+	}
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+
+	oldState, err := stateEncryptionFor("v1").EncryptState(testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After the rotation the data written with the previous key still decrypts, but needs to be migrated.
+	rotated := stateEncryptionFor("v2")
+	decrypted, status, err := rotated.DecryptState(oldState)
+	if err != nil {
+		t.Fatalf("failed to decrypt state written with the previous key: %v", err)
+	}
+	if string(decrypted) != string(testData) {
+		t.Fatalf("incorrect decrypted state: %s", decrypted)
+	}
+	if status != StatusMigration {
+		t.Fatalf("expected migration status after key rotation, got %v", status)
+	}
+
+	// Data written after the rotation uses the new key only.
+	newState, err := rotated.EncryptState(testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, status, err = rotated.DecryptState(newState)
+	if err != nil {
+		t.Fatalf("failed to decrypt state written with the new key: %v", err)
+	}
+	if string(decrypted) != string(testData) {
+		t.Fatalf("incorrect decrypted state: %s", decrypted)
+	}
+	if status != StatusSatisfied {
+		t.Fatalf("expected satisfied status, got %v", status)
+	}
+}
+
+func TestBaseEncryption_enforcedWithUnencryptedReads(t *testing.T) {
+	t.Parallel()
+
+	rawConfig := `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+		method "unencrypted" "example" {
+		}
+		state {
+			enforced                = true
+			allow_unencrypted_reads = true
+			method                  = method.aes_gcm.example
+			fallback {
+				method = method.unencrypted.example
+			}
+		}
+	`
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("Test Config Source", rawConfig)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	sfe := enc.State()
+
+	// The plaintext state can be read, but needs to be migrated.
+	plainState := []byte(`{"serial": 42, "lineage": "magic"}`)
+	decrypted, status, err := sfe.DecryptState(plainState)
+	if err != nil {
+		t.Fatalf("failed to read plaintext state: %v", err)
+	}
+	if string(decrypted) != string(plainState) {
+		t.Fatalf("incorrect decrypted state: %s", decrypted)
+	}
+	if status != StatusMigration {
+		t.Fatalf("expected migration status for plaintext state, got %v", status)
+	}
+
+	// The state is always written encrypted.
+	encrypted, err := sfe.EncryptState(plainState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	isEncrypted, err := IsEncryptionPayload(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted || strings.Contains(string(encrypted), "magic") {
+		t.Fatalf("the state was not encrypted: %s", encrypted)
+	}
+}
+
+func TestEncryption_defaults(t *testing.T) {
+	t.Parallel()
+
+	// The state uses the default method, while the plan overrides it.
+	rawConfig := `
+		key_provider "static" "basic" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.static.basic
+		}
+		method "unencrypted" "example" {
+		}
+		defaults {
+			method = method.aes_gcm.example
+		}
+		plan {
+			method = method.unencrypted.example
+		}
+	`
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, diags := config.LoadConfigFromString("Test Config Source", rawConfig)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := New(reg, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	plainState := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := enc.State().EncryptState(plainState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	isEncrypted, err := IsEncryptionPayload(encryptedState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted || strings.Contains(string(encryptedState), "magic") {
+		t.Fatalf("the state was not encrypted with the default method: %s", encryptedState)
+	}
+
+	plainPlan := []byte("magic plan")
+	encryptedPlan, err := enc.Plan().EncryptPlan(plainPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encryptedPlan) != string(plainPlan) {
+		t.Fatalf("the plan did not use its own unencrypted method: %q", encryptedPlan)
+	}
+}
+
+// rotatingKeyProviderDescriptor is a test key provider with a versioned key. The key is derived from the version, so
+// changing the version emulates a key rotation.
+type rotatingKeyProviderDescriptor struct{}
+
+func (rotatingKeyProviderDescriptor) ID() keyprovider.ID {
+	return "rotating"
+}
+
+func (rotatingKeyProviderDescriptor) ConfigStruct() keyprovider.Config {
+	return &rotatingKeyProviderConfig{}
+}
+
+type rotatingKeyProviderConfig struct {
+	Version string `hcl:"version"`
+}
+
+func (c rotatingKeyProviderConfig) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	return &rotatingKeyProvider{version: c.Version}, new(rotatingKeyMeta), nil
+}
+
+type rotatingKeyMeta struct {
+	Version string `json:"key_version"`
+}
+
+func (m rotatingKeyMeta) KeyVersion() string {
+	return m.Version
+}
+
+type rotatingKeyProvider struct {
+	version string
+}
+
+func (p rotatingKeyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	inMeta, ok := rawMeta.(*rotatingKeyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{
+			Message: fmt.Sprintf("bug: invalid metadata type received: %T", rawMeta),
+		}
+	}
+	out := keyprovider.Output{
+		EncryptionKey: rotatingKey(p.version),
+	}
+	if inMeta.Version != "" {
+		out.DecryptionKey = rotatingKey(inMeta.Version)
+	}
+	return out, &rotatingKeyMeta{Version: p.version}, nil
+}
+
+func rotatingKey(version string) []byte {
+	key := sha256.Sum256([]byte(version))
+	return key[:]
+}
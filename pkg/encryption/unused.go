@@ -0,0 +1,156 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider"
+	"github.com/we-dcode/opentofu/pkg/encryption/method"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry"
+)
+
+// referenceTracker follows the references from the encryption targets to the methods they use, and from there to the
+// key providers, so that we can warn about the ones which are declared but never used.
+type referenceTracker struct {
+	cfg *config.EncryptionConfig
+	reg registry.Registry
+
+	methods      map[method.Addr]bool
+	keyProviders map[keyprovider.Addr]bool
+}
+
+// unusedDiags returns a warning for each method and key provider which is not referenced, directly or through other
+// key providers, by any of the configured targets.
+func unusedDiags(cfg *config.EncryptionConfig, reg registry.Registry) hcl.Diagnostics {
+	t := &referenceTracker{
+		cfg: cfg,
+		reg: reg,
+
+		methods:      make(map[method.Addr]bool),
+		keyProviders: make(map[keyprovider.Addr]bool),
+	}
+
+	if cfg.State != nil {
+		t.trackTarget(cfg.State.AsTargetConfig())
+	}
+	if cfg.Plan != nil {
+		t.trackTarget(cfg.Plan.AsTargetConfig())
+	}
+	if cfg.Remote != nil {
+		t.trackTarget(cfg.Remote.Default)
+		for _, target := range cfg.Remote.Targets {
+			t.trackTarget(target.AsTargetConfig())
+		}
+	}
+
+	var diags hcl.Diagnostics
+	for _, m := range cfg.MethodConfigs {
+		addr, addrDiags := m.Addr()
+		if addrDiags.HasErrors() || t.methods[addr] {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Unused encryption method",
+			Detail:   fmt.Sprintf("The encryption method %s is not used by the state, plan, or any remote state data source.", addr),
+			Subject:  m.Body.MissingItemRange().Ptr(),
+		})
+	}
+	for _, kp := range cfg.KeyProviderConfigs {
+		addr, addrDiags := kp.Addr()
+		if addrDiags.HasErrors() || t.keyProviders[addr] {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Unused encryption key provider",
+			Detail:   fmt.Sprintf("The key provider %s is not used by any encryption method of the state, plan, or any remote state data source.", addr),
+			Subject:  kp.Body.MissingItemRange().Ptr(),
+		})
+	}
+	return diags
+}
+
+func (t *referenceTracker) trackTarget(target *config.TargetConfig) {
+	if target == nil {
+		return
+	}
+	if target.Method != nil {
+		for _, traversal := range target.Method.Variables() {
+			typeName, name, ok := traversalAddr(traversal, "method")
+			if !ok {
+				continue
+			}
+			if m, ok := t.cfg.GetMethod(typeName, name); ok {
+				t.trackMethod(m)
+			}
+		}
+	}
+	for _, fallback := range target.Fallbacks {
+		t.trackTarget(fallback)
+	}
+}
+
+func (t *referenceTracker) trackMethod(cfg config.MethodConfig) {
+	addr, diags := cfg.Addr()
+	if diags.HasErrors() || t.methods[addr] {
+		return
+	}
+	t.methods[addr] = true
+
+	descriptor, err := t.reg.GetMethodDescriptor(method.ID(cfg.Type))
+	if err != nil {
+		// Reported when setting up the method.
+		return
+	}
+	t.trackBody(cfg.Body, descriptor.ConfigStruct())
+}
+
+func (t *referenceTracker) trackKeyProvider(cfg config.KeyProviderConfig) {
+	addr, diags := cfg.Addr()
+	if diags.HasErrors() || t.keyProviders[addr] {
+		return
+	}
+	t.keyProviders[addr] = true
+
+	descriptor, err := t.reg.GetKeyProviderDescriptor(keyprovider.ID(cfg.Type))
+	if err != nil {
+		// Reported when setting up the key provider.
+		return
+	}
+	t.trackBody(cfg.Body, descriptor.ConfigStruct())
+}
+
+// trackBody marks the key providers referenced in the given body as used.
+func (t *referenceTracker) trackBody(body hcl.Body, configStruct any) {
+	traversals, _ := gohcl.VariablesInBody(body, configStruct)
+	for _, traversal := range traversals {
+		typeName, name, ok := traversalAddr(traversal, "key_provider")
+		if !ok {
+			continue
+		}
+		if kp, ok := t.cfg.GetKeyProvider(typeName, name); ok {
+			t.trackKeyProvider(kp)
+		}
+	}
+}
+
+// traversalAddr returns the type and name from a <root>.<type>.<name> traversal.
+func traversalAddr(traversal hcl.Traversal, root string) (string, string, bool) {
+	if len(traversal) < 3 || traversal.RootName() != root {
+		return "", "", false
+	}
+	typeAttr, typeOk := traversal[1].(hcl.TraverseAttr)
+	nameAttr, nameOk := traversal[2].(hcl.TraverseAttr)
+	if !typeOk || !nameOk {
+		return "", "", false
+	}
+	return typeAttr.Name, nameAttr.Name, true
+}
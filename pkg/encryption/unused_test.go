@@ -0,0 +1,159 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/we-dcode/opentofu/pkg/encryption/config"
+	"github.com/we-dcode/opentofu/pkg/encryption/keyprovider/static"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/aesgcm"
+	"github.com/we-dcode/opentofu/pkg/encryption/method/unencrypted"
+	"github.com/we-dcode/opentofu/pkg/encryption/registry/lockingencryptionregistry"
+)
+
+func TestUnusedDiags(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		rawConfig string
+		want      []string
+	}{
+		"all-used": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+		},
+		"unused-key-provider": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				key_provider "static" "copy" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			want: []string{
+				"The key provider key_provider.static.copy is not used by any encryption method of the state, plan, or any remote state data source.",
+			},
+		},
+		"unused-method": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				key_provider "static" "other" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				method "aes_gcm" "other" {
+					keys = key_provider.static.other
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+			want: []string{
+				"The encryption method method.aes_gcm.other is not used by the state, plan, or any remote state data source.",
+				"The key provider key_provider.static.other is not used by any encryption method of the state, plan, or any remote state data source.",
+			},
+		},
+		"transitive": {
+			// The static key provider doesn't actually accept a reference, but we only track references here.
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = key_provider.static.chained
+				}
+				key_provider "static" "chained" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				state {
+					method = method.aes_gcm.example
+				}
+			`,
+		},
+		"fallback-and-remote": {
+			rawConfig: `
+				key_provider "static" "basic" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				key_provider "static" "remote" {
+					key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+				}
+				method "aes_gcm" "example" {
+					keys = key_provider.static.basic
+				}
+				method "aes_gcm" "remote" {
+					keys = key_provider.static.remote
+				}
+				method "unencrypted" "migration" {}
+				plan {
+					method = method.aes_gcm.example
+					fallback {
+						method = method.unencrypted.migration
+					}
+				}
+				remote_state_data_sources {
+					remote_state_data_source "foo" {
+						method = method.aes_gcm.remote
+					}
+				}
+			`,
+		},
+	}
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(static.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, diags := config.LoadConfigFromString("Test Config Source", test.rawConfig)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			var got []string
+			for _, diag := range unusedDiags(cfg, reg) {
+				if diag.Severity != hcl.DiagWarning {
+					t.Errorf("unexpected diagnostic severity: %s", diag.Error())
+				}
+				got = append(got, diag.Detail)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("wrong warnings\ngot:  %q\nwant: %q", got, test.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,9 @@ package grpcwrap
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
 
 	"github.com/we-dcode/opentofu/pkg/plugin/convert"
 	"github.com/we-dcode/opentofu/pkg/providers"
@@ -78,8 +81,10 @@ func (p *provider) GetSchema(_ context.Context, req *tfplugin5.GetProviderSchema
 	return resp, nil
 }
 
-func (p *provider) PrepareProviderConfig(_ context.Context, req *tfplugin5.PrepareProviderConfig_Request) (*tfplugin5.PrepareProviderConfig_Response, error) {
-	resp := &tfplugin5.PrepareProviderConfig_Response{}
+func (p *provider) PrepareProviderConfig(_ context.Context, req *tfplugin5.PrepareProviderConfig_Request) (resp *tfplugin5.PrepareProviderConfig_Response, _ error) {
+	resp = &tfplugin5.PrepareProviderConfig_Response{}
+	defer recoverProviderPanic("PrepareProviderConfig", &resp.Diagnostics)
+
 	ty := p.schema.Provider.Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue(req.Config, ty)
@@ -97,8 +102,10 @@ func (p *provider) PrepareProviderConfig(_ context.Context, req *tfplugin5.Prepa
 	return resp, nil
 }
 
-func (p *provider) ValidateResourceTypeConfig(_ context.Context, req *tfplugin5.ValidateResourceTypeConfig_Request) (*tfplugin5.ValidateResourceTypeConfig_Response, error) {
-	resp := &tfplugin5.ValidateResourceTypeConfig_Response{}
+func (p *provider) ValidateResourceTypeConfig(_ context.Context, req *tfplugin5.ValidateResourceTypeConfig_Request) (resp *tfplugin5.ValidateResourceTypeConfig_Response, _ error) {
+	resp = &tfplugin5.ValidateResourceTypeConfig_Response{}
+	defer recoverProviderPanic("ValidateResourceTypeConfig", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue(req.Config, ty)
@@ -116,8 +123,10 @@ func (p *provider) ValidateResourceTypeConfig(_ context.Context, req *tfplugin5.
 	return resp, nil
 }
 
-func (p *provider) ValidateDataSourceConfig(_ context.Context, req *tfplugin5.ValidateDataSourceConfig_Request) (*tfplugin5.ValidateDataSourceConfig_Response, error) {
-	resp := &tfplugin5.ValidateDataSourceConfig_Response{}
+func (p *provider) ValidateDataSourceConfig(_ context.Context, req *tfplugin5.ValidateDataSourceConfig_Request) (resp *tfplugin5.ValidateDataSourceConfig_Response, _ error) {
+	resp = &tfplugin5.ValidateDataSourceConfig_Response{}
+	defer recoverProviderPanic("ValidateDataSourceConfig", &resp.Diagnostics)
+
 	ty := p.schema.DataSources[req.TypeName].Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue(req.Config, ty)
@@ -135,8 +144,10 @@ func (p *provider) ValidateDataSourceConfig(_ context.Context, req *tfplugin5.Va
 	return resp, nil
 }
 
-func (p *provider) UpgradeResourceState(_ context.Context, req *tfplugin5.UpgradeResourceState_Request) (*tfplugin5.UpgradeResourceState_Response, error) {
-	resp := &tfplugin5.UpgradeResourceState_Response{}
+func (p *provider) UpgradeResourceState(_ context.Context, req *tfplugin5.UpgradeResourceState_Request) (resp *tfplugin5.UpgradeResourceState_Response, _ error) {
+	resp = &tfplugin5.UpgradeResourceState_Response{}
+	defer recoverProviderPanic("UpgradeResourceState", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	upgradeResp := p.provider.UpgradeResourceState(providers.UpgradeResourceStateRequest{
@@ -161,8 +172,10 @@ func (p *provider) UpgradeResourceState(_ context.Context, req *tfplugin5.Upgrad
 	return resp, nil
 }
 
-func (p *provider) Configure(_ context.Context, req *tfplugin5.Configure_Request) (*tfplugin5.Configure_Response, error) {
-	resp := &tfplugin5.Configure_Response{}
+func (p *provider) Configure(_ context.Context, req *tfplugin5.Configure_Request) (resp *tfplugin5.Configure_Response, _ error) {
+	resp = &tfplugin5.Configure_Response{}
+	defer recoverProviderPanic("Configure", &resp.Diagnostics)
+
 	ty := p.schema.Provider.Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue(req.Config, ty)
@@ -180,8 +193,10 @@ func (p *provider) Configure(_ context.Context, req *tfplugin5.Configure_Request
 	return resp, nil
 }
 
-func (p *provider) ReadResource(_ context.Context, req *tfplugin5.ReadResource_Request) (*tfplugin5.ReadResource_Response, error) {
-	resp := &tfplugin5.ReadResource_Response{}
+func (p *provider) ReadResource(_ context.Context, req *tfplugin5.ReadResource_Request) (resp *tfplugin5.ReadResource_Response, _ error) {
+	resp = &tfplugin5.ReadResource_Response{}
+	defer recoverProviderPanic("ReadResource", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	stateVal, err := decodeDynamicValue(req.CurrentState, ty)
@@ -219,8 +234,10 @@ func (p *provider) ReadResource(_ context.Context, req *tfplugin5.ReadResource_R
 	return resp, nil
 }
 
-func (p *provider) PlanResourceChange(_ context.Context, req *tfplugin5.PlanResourceChange_Request) (*tfplugin5.PlanResourceChange_Response, error) {
-	resp := &tfplugin5.PlanResourceChange_Response{}
+func (p *provider) PlanResourceChange(_ context.Context, req *tfplugin5.PlanResourceChange_Request) (resp *tfplugin5.PlanResourceChange_Response, _ error) {
+	resp = &tfplugin5.PlanResourceChange_Response{}
+	defer recoverProviderPanic("PlanResourceChange", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	priorStateVal, err := decodeDynamicValue(req.PriorState, ty)
@@ -276,8 +293,10 @@ func (p *provider) PlanResourceChange(_ context.Context, req *tfplugin5.PlanReso
 	return resp, nil
 }
 
-func (p *provider) ApplyResourceChange(_ context.Context, req *tfplugin5.ApplyResourceChange_Request) (*tfplugin5.ApplyResourceChange_Response, error) {
-	resp := &tfplugin5.ApplyResourceChange_Response{}
+func (p *provider) ApplyResourceChange(_ context.Context, req *tfplugin5.ApplyResourceChange_Request) (resp *tfplugin5.ApplyResourceChange_Response, _ error) {
+	resp = &tfplugin5.ApplyResourceChange_Response{}
+	defer recoverProviderPanic("ApplyResourceChange", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	priorStateVal, err := decodeDynamicValue(req.PriorState, ty)
@@ -329,8 +348,9 @@ func (p *provider) ApplyResourceChange(_ context.Context, req *tfplugin5.ApplyRe
 	return resp, nil
 }
 
-func (p *provider) ImportResourceState(_ context.Context, req *tfplugin5.ImportResourceState_Request) (*tfplugin5.ImportResourceState_Response, error) {
-	resp := &tfplugin5.ImportResourceState_Response{}
+func (p *provider) ImportResourceState(_ context.Context, req *tfplugin5.ImportResourceState_Request) (resp *tfplugin5.ImportResourceState_Response, _ error) {
+	resp = &tfplugin5.ImportResourceState_Response{}
+	defer recoverProviderPanic("ImportResourceState", &resp.Diagnostics)
 
 	importResp := p.provider.ImportResourceState(providers.ImportResourceStateRequest{
 		TypeName: req.TypeName,
@@ -356,12 +376,42 @@ func (p *provider) ImportResourceState(_ context.Context, req *tfplugin5.ImportR
 	return resp, nil
 }
 
-func (p *provider) MoveResourceState(context.Context, *tfplugin5.MoveResourceState_Request) (*tfplugin5.MoveResourceState_Response, error) {
-	panic("Not Implemented")
+func (p *provider) MoveResourceState(_ context.Context, req *tfplugin5.MoveResourceState_Request) (resp *tfplugin5.MoveResourceState_Response, _ error) {
+	resp = &tfplugin5.MoveResourceState_Response{}
+	defer recoverProviderPanic("MoveResourceState", &resp.Diagnostics)
+
+	ty := p.schema.ResourceTypes[req.TargetTypeName].Block.ImpliedType()
+
+	moveResp := p.provider.MoveResourceState(providers.MoveResourceStateRequest{
+		SourceProviderAddress: req.SourceProviderAddress,
+		SourceTypeName:        req.SourceTypeName,
+		SourceSchemaVersion:   req.SourceSchemaVersion,
+		SourceStateJSON:       req.SourceState.Json,
+		SourcePrivate:         req.SourcePrivate,
+		TargetTypeName:        req.TargetTypeName,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, moveResp.Diagnostics)
+	if moveResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+
+	dv, err := encodeDynamicValue(moveResp.TargetState, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	resp.TargetState = dv
+	resp.TargetPrivate = moveResp.TargetPrivate
+
+	return resp, nil
 }
 
-func (p *provider) ReadDataSource(_ context.Context, req *tfplugin5.ReadDataSource_Request) (*tfplugin5.ReadDataSource_Response, error) {
-	resp := &tfplugin5.ReadDataSource_Response{}
+func (p *provider) ReadDataSource(_ context.Context, req *tfplugin5.ReadDataSource_Request) (resp *tfplugin5.ReadDataSource_Response, _ error) {
+	resp = &tfplugin5.ReadDataSource_Response{}
+	defer recoverProviderPanic("ReadDataSource", &resp.Diagnostics)
+
 	ty := p.schema.DataSources[req.TypeName].Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue(req.Config, ty)
@@ -396,8 +446,15 @@ func (p *provider) ReadDataSource(_ context.Context, req *tfplugin5.ReadDataSour
 	return resp, nil
 }
 
-func (p *provider) Stop(context.Context, *tfplugin5.Stop_Request) (*tfplugin5.Stop_Response, error) {
-	resp := &tfplugin5.Stop_Response{}
+func (p *provider) Stop(context.Context, *tfplugin5.Stop_Request) (resp *tfplugin5.Stop_Response, _ error) {
+	resp = &tfplugin5.Stop_Response{}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[ERROR] provider panicked in Stop: %v\n%s", r, debug.Stack())
+			resp.Error = fmt.Sprintf("provider crashed in Stop: %v", r)
+		}
+	}()
+
 	err := p.provider.Stop()
 	if err != nil {
 		resp.Error = err.Error()
@@ -413,6 +470,22 @@ func (p *provider) CallFunction(context.Context, *tfplugin5.CallFunction_Request
 	panic("Not Implemented")
 }
 
+// recoverProviderPanic turns a panic in the named RPC into an error diagnostic appended to
+// diags, so that a crashing provider is reported to the client instead of
+// taking down the whole plugin process. It must be deferred directly.
+func recoverProviderPanic(rpc string, diags *[]*tfplugin5.Diagnostic) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Printf("[ERROR] provider panicked in %s: %v\n%s", rpc, r, debug.Stack())
+	*diags = append(*diags, &tfplugin5.Diagnostic{
+		Severity: tfplugin5.Diagnostic_ERROR,
+		Summary:  fmt.Sprintf("Provider crashed in %s", rpc),
+		Detail:   fmt.Sprintf("The provider panicked while handling %s: %v", rpc, r),
+	})
+}
+
 // decode a DynamicValue from either the JSON or MsgPack encoding.
 func decodeDynamicValue(v *tfplugin5.DynamicValue, ty cty.Type) (cty.Value, error) {
 	// always return a valid value
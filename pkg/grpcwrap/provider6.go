@@ -7,6 +7,9 @@ package grpcwrap
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
 
 	"github.com/we-dcode/opentofu/pkg/plugin6/convert"
 	"github.com/we-dcode/opentofu/pkg/providers"
@@ -78,8 +81,10 @@ func (p *provider6) GetProviderSchema(_ context.Context, req *tfplugin6.GetProvi
 	return resp, nil
 }
 
-func (p *provider6) ValidateProviderConfig(_ context.Context, req *tfplugin6.ValidateProviderConfig_Request) (*tfplugin6.ValidateProviderConfig_Response, error) {
-	resp := &tfplugin6.ValidateProviderConfig_Response{}
+func (p *provider6) ValidateProviderConfig(_ context.Context, req *tfplugin6.ValidateProviderConfig_Request) (resp *tfplugin6.ValidateProviderConfig_Response, _ error) {
+	resp = &tfplugin6.ValidateProviderConfig_Response{}
+	defer recoverProvider6Panic("ValidateProviderConfig", &resp.Diagnostics)
+
 	ty := p.schema.Provider.Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue6(req.Config, ty)
@@ -97,8 +102,10 @@ func (p *provider6) ValidateProviderConfig(_ context.Context, req *tfplugin6.Val
 	return resp, nil
 }
 
-func (p *provider6) ValidateResourceConfig(_ context.Context, req *tfplugin6.ValidateResourceConfig_Request) (*tfplugin6.ValidateResourceConfig_Response, error) {
-	resp := &tfplugin6.ValidateResourceConfig_Response{}
+func (p *provider6) ValidateResourceConfig(_ context.Context, req *tfplugin6.ValidateResourceConfig_Request) (resp *tfplugin6.ValidateResourceConfig_Response, _ error) {
+	resp = &tfplugin6.ValidateResourceConfig_Response{}
+	defer recoverProvider6Panic("ValidateResourceConfig", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue6(req.Config, ty)
@@ -116,8 +123,10 @@ func (p *provider6) ValidateResourceConfig(_ context.Context, req *tfplugin6.Val
 	return resp, nil
 }
 
-func (p *provider6) ValidateDataResourceConfig(_ context.Context, req *tfplugin6.ValidateDataResourceConfig_Request) (*tfplugin6.ValidateDataResourceConfig_Response, error) {
-	resp := &tfplugin6.ValidateDataResourceConfig_Response{}
+func (p *provider6) ValidateDataResourceConfig(_ context.Context, req *tfplugin6.ValidateDataResourceConfig_Request) (resp *tfplugin6.ValidateDataResourceConfig_Response, _ error) {
+	resp = &tfplugin6.ValidateDataResourceConfig_Response{}
+	defer recoverProvider6Panic("ValidateDataResourceConfig", &resp.Diagnostics)
+
 	ty := p.schema.DataSources[req.TypeName].Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue6(req.Config, ty)
@@ -135,8 +144,10 @@ func (p *provider6) ValidateDataResourceConfig(_ context.Context, req *tfplugin6
 	return resp, nil
 }
 
-func (p *provider6) UpgradeResourceState(_ context.Context, req *tfplugin6.UpgradeResourceState_Request) (*tfplugin6.UpgradeResourceState_Response, error) {
-	resp := &tfplugin6.UpgradeResourceState_Response{}
+func (p *provider6) UpgradeResourceState(_ context.Context, req *tfplugin6.UpgradeResourceState_Request) (resp *tfplugin6.UpgradeResourceState_Response, _ error) {
+	resp = &tfplugin6.UpgradeResourceState_Response{}
+	defer recoverProvider6Panic("UpgradeResourceState", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	upgradeResp := p.provider.UpgradeResourceState(providers.UpgradeResourceStateRequest{
@@ -161,8 +172,10 @@ func (p *provider6) UpgradeResourceState(_ context.Context, req *tfplugin6.Upgra
 	return resp, nil
 }
 
-func (p *provider6) ConfigureProvider(_ context.Context, req *tfplugin6.ConfigureProvider_Request) (*tfplugin6.ConfigureProvider_Response, error) {
-	resp := &tfplugin6.ConfigureProvider_Response{}
+func (p *provider6) ConfigureProvider(_ context.Context, req *tfplugin6.ConfigureProvider_Request) (resp *tfplugin6.ConfigureProvider_Response, _ error) {
+	resp = &tfplugin6.ConfigureProvider_Response{}
+	defer recoverProvider6Panic("ConfigureProvider", &resp.Diagnostics)
+
 	ty := p.schema.Provider.Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue6(req.Config, ty)
@@ -180,8 +193,10 @@ func (p *provider6) ConfigureProvider(_ context.Context, req *tfplugin6.Configur
 	return resp, nil
 }
 
-func (p *provider6) ReadResource(_ context.Context, req *tfplugin6.ReadResource_Request) (*tfplugin6.ReadResource_Response, error) {
-	resp := &tfplugin6.ReadResource_Response{}
+func (p *provider6) ReadResource(_ context.Context, req *tfplugin6.ReadResource_Request) (resp *tfplugin6.ReadResource_Response, _ error) {
+	resp = &tfplugin6.ReadResource_Response{}
+	defer recoverProvider6Panic("ReadResource", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	stateVal, err := decodeDynamicValue6(req.CurrentState, ty)
@@ -219,8 +234,10 @@ func (p *provider6) ReadResource(_ context.Context, req *tfplugin6.ReadResource_
 	return resp, nil
 }
 
-func (p *provider6) PlanResourceChange(_ context.Context, req *tfplugin6.PlanResourceChange_Request) (*tfplugin6.PlanResourceChange_Response, error) {
-	resp := &tfplugin6.PlanResourceChange_Response{}
+func (p *provider6) PlanResourceChange(_ context.Context, req *tfplugin6.PlanResourceChange_Request) (resp *tfplugin6.PlanResourceChange_Response, _ error) {
+	resp = &tfplugin6.PlanResourceChange_Response{}
+	defer recoverProvider6Panic("PlanResourceChange", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	priorStateVal, err := decodeDynamicValue6(req.PriorState, ty)
@@ -276,8 +293,10 @@ func (p *provider6) PlanResourceChange(_ context.Context, req *tfplugin6.PlanRes
 	return resp, nil
 }
 
-func (p *provider6) ApplyResourceChange(_ context.Context, req *tfplugin6.ApplyResourceChange_Request) (*tfplugin6.ApplyResourceChange_Response, error) {
-	resp := &tfplugin6.ApplyResourceChange_Response{}
+func (p *provider6) ApplyResourceChange(_ context.Context, req *tfplugin6.ApplyResourceChange_Request) (resp *tfplugin6.ApplyResourceChange_Response, _ error) {
+	resp = &tfplugin6.ApplyResourceChange_Response{}
+	defer recoverProvider6Panic("ApplyResourceChange", &resp.Diagnostics)
+
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
 
 	priorStateVal, err := decodeDynamicValue6(req.PriorState, ty)
@@ -329,8 +348,9 @@ func (p *provider6) ApplyResourceChange(_ context.Context, req *tfplugin6.ApplyR
 	return resp, nil
 }
 
-func (p *provider6) ImportResourceState(_ context.Context, req *tfplugin6.ImportResourceState_Request) (*tfplugin6.ImportResourceState_Response, error) {
-	resp := &tfplugin6.ImportResourceState_Response{}
+func (p *provider6) ImportResourceState(_ context.Context, req *tfplugin6.ImportResourceState_Request) (resp *tfplugin6.ImportResourceState_Response, _ error) {
+	resp = &tfplugin6.ImportResourceState_Response{}
+	defer recoverProvider6Panic("ImportResourceState", &resp.Diagnostics)
 
 	importResp := p.provider.ImportResourceState(providers.ImportResourceStateRequest{
 		TypeName: req.TypeName,
@@ -356,12 +376,42 @@ func (p *provider6) ImportResourceState(_ context.Context, req *tfplugin6.Import
 	return resp, nil
 }
 
-func (p *provider6) MoveResourceState(context.Context, *tfplugin6.MoveResourceState_Request) (*tfplugin6.MoveResourceState_Response, error) {
-	panic("Not Implemented")
+func (p *provider6) MoveResourceState(_ context.Context, req *tfplugin6.MoveResourceState_Request) (resp *tfplugin6.MoveResourceState_Response, _ error) {
+	resp = &tfplugin6.MoveResourceState_Response{}
+	defer recoverProvider6Panic("MoveResourceState", &resp.Diagnostics)
+
+	ty := p.schema.ResourceTypes[req.TargetTypeName].Block.ImpliedType()
+
+	moveResp := p.provider.MoveResourceState(providers.MoveResourceStateRequest{
+		SourceProviderAddress: req.SourceProviderAddress,
+		SourceTypeName:        req.SourceTypeName,
+		SourceSchemaVersion:   req.SourceSchemaVersion,
+		SourceStateJSON:       req.SourceState.Json,
+		SourcePrivate:         req.SourcePrivate,
+		TargetTypeName:        req.TargetTypeName,
+	})
+
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, moveResp.Diagnostics)
+	if moveResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+
+	dv, err := encodeDynamicValue6(moveResp.TargetState, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	resp.TargetState = dv
+	resp.TargetPrivate = moveResp.TargetPrivate
+
+	return resp, nil
 }
 
-func (p *provider6) ReadDataSource(_ context.Context, req *tfplugin6.ReadDataSource_Request) (*tfplugin6.ReadDataSource_Response, error) {
-	resp := &tfplugin6.ReadDataSource_Response{}
+func (p *provider6) ReadDataSource(_ context.Context, req *tfplugin6.ReadDataSource_Request) (resp *tfplugin6.ReadDataSource_Response, _ error) {
+	resp = &tfplugin6.ReadDataSource_Response{}
+	defer recoverProvider6Panic("ReadDataSource", &resp.Diagnostics)
+
 	ty := p.schema.DataSources[req.TypeName].Block.ImpliedType()
 
 	configVal, err := decodeDynamicValue6(req.Config, ty)
@@ -396,8 +446,15 @@ func (p *provider6) ReadDataSource(_ context.Context, req *tfplugin6.ReadDataSou
 	return resp, nil
 }
 
-func (p *provider6) StopProvider(context.Context, *tfplugin6.StopProvider_Request) (*tfplugin6.StopProvider_Response, error) {
-	resp := &tfplugin6.StopProvider_Response{}
+func (p *provider6) StopProvider(context.Context, *tfplugin6.StopProvider_Request) (resp *tfplugin6.StopProvider_Response, _ error) {
+	resp = &tfplugin6.StopProvider_Response{}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[ERROR] provider panicked in StopProvider: %v\n%s", r, debug.Stack())
+			resp.Error = fmt.Sprintf("provider crashed in StopProvider: %v", r)
+		}
+	}()
+
 	err := p.provider.Stop()
 	if err != nil {
 		resp.Error = err.Error()
@@ -413,6 +470,22 @@ func (p *provider6) CallFunction(context.Context, *tfplugin6.CallFunction_Reques
 	panic("Not Implemented")
 }
 
+// recoverProvider6Panic turns a panic in the named RPC into an error diagnostic appended to
+// diags, so that a crashing provider is reported to the client instead of
+// taking down the whole plugin process. It must be deferred directly.
+func recoverProvider6Panic(rpc string, diags *[]*tfplugin6.Diagnostic) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Printf("[ERROR] provider panicked in %s: %v\n%s", rpc, r, debug.Stack())
+	*diags = append(*diags, &tfplugin6.Diagnostic{
+		Severity: tfplugin6.Diagnostic_ERROR,
+		Summary:  fmt.Sprintf("Provider crashed in %s", rpc),
+		Detail:   fmt.Sprintf("The provider panicked while handling %s: %v", rpc, r),
+	})
+}
+
 // decode a DynamicValue from either the JSON or MsgPack encoding.
 func decodeDynamicValue6(v *tfplugin6.DynamicValue, ty cty.Type) (cty.Value, error) {
 	// always return a valid value
@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcwrap
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	simple "github.com/we-dcode/opentofu/pkg/provider-simple"
+	"github.com/we-dcode/opentofu/pkg/providers"
+	"github.com/we-dcode/opentofu/pkg/tfplugin5"
+	"github.com/we-dcode/opentofu/pkg/tfplugin6"
+)
+
+// panickingProvider is a provider which panics when planning a resource change.
+type panickingProvider struct {
+	providers.Interface
+}
+
+func (p panickingProvider) PlanResourceChange(providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	panic("boom")
+}
+
+func TestProvider_panic(t *testing.T) {
+	server := Provider(panickingProvider{simple.Provider()})
+
+	resp, err := server.PlanResourceChange(context.Background(), &tfplugin5.PlanResourceChange_Request{
+		TypeName: "simple_resource",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp == nil {
+		t.Fatal("expected a response, got nil")
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(resp.Diagnostics))
+	}
+	diag := resp.Diagnostics[0]
+	if diag.Severity != tfplugin5.Diagnostic_ERROR {
+		t.Errorf("expected an error diagnostic, got %s", diag.Severity)
+	}
+	if want := "Provider crashed in PlanResourceChange"; diag.Summary != want {
+		t.Errorf("wrong summary\ngot:  %s\nwant: %s", diag.Summary, want)
+	}
+	if !strings.Contains(diag.Detail, "boom") {
+		t.Errorf("expected the detail to contain the panic message, got: %s", diag.Detail)
+	}
+}
+
+func TestProvider6_panic(t *testing.T) {
+	server := Provider6(panickingProvider{simple.Provider()})
+
+	resp, err := server.PlanResourceChange(context.Background(), &tfplugin6.PlanResourceChange_Request{
+		TypeName: "simple_resource",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp == nil {
+		t.Fatal("expected a response, got nil")
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(resp.Diagnostics))
+	}
+	diag := resp.Diagnostics[0]
+	if diag.Severity != tfplugin6.Diagnostic_ERROR {
+		t.Errorf("expected an error diagnostic, got %s", diag.Severity)
+	}
+	if want := "Provider crashed in PlanResourceChange"; diag.Summary != want {
+		t.Errorf("wrong summary\ngot:  %s\nwant: %s", diag.Summary, want)
+	}
+	if !strings.Contains(diag.Detail, "boom") {
+		t.Errorf("expected the detail to contain the panic message, got: %s", diag.Detail)
+	}
+}
@@ -85,6 +85,23 @@ func (p *provisioner) ProvisionResource(req *tfplugin5.ProvisionResource_Request
 		return nil
 	}
 
+	// If the client cancels the call or goes away while the provisioner is
+	// still running, stop the provisioner so that it doesn't keep running
+	// commands nobody is waiting for. This is what makes an interrupt reliably
+	// reach a hung provisioner, as the client may not get to call Stop.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-srv.Context().Done():
+			log.Printf("[WARN] provisioner call cancelled by the client, stopping the provisioner")
+			if err := p.provisioner.Stop(); err != nil {
+				log.Printf("[ERROR] failed to stop the provisioner: %s", err)
+			}
+		case <-done:
+		}
+	}()
+
 	resp := p.provisioner.ProvisionResource(provisioners.ProvisionResourceRequest{
 		Config:     configVal,
 		Connection: connVal,
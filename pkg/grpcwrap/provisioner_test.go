@@ -0,0 +1,110 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grpcwrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/we-dcode/opentofu/pkg/configs/configschema"
+	"github.com/we-dcode/opentofu/pkg/provisioners"
+	"github.com/we-dcode/opentofu/pkg/tfplugin5"
+)
+
+// blockingProvisioner is a provisioner which runs until it is stopped.
+type blockingProvisioner struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started chan struct{}
+}
+
+func newBlockingProvisioner() *blockingProvisioner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &blockingProvisioner{
+		ctx:     ctx,
+		cancel:  cancel,
+		started: make(chan struct{}),
+	}
+}
+
+func (p *blockingProvisioner) GetSchema() provisioners.GetSchemaResponse {
+	return provisioners.GetSchemaResponse{
+		Provisioner: &configschema.Block{},
+	}
+}
+
+func (p *blockingProvisioner) ValidateProvisionerConfig(provisioners.ValidateProvisionerConfigRequest) provisioners.ValidateProvisionerConfigResponse {
+	return provisioners.ValidateProvisionerConfigResponse{}
+}
+
+func (p *blockingProvisioner) ProvisionResource(provisioners.ProvisionResourceRequest) provisioners.ProvisionResourceResponse {
+	close(p.started)
+	<-p.ctx.Done()
+	return provisioners.ProvisionResourceResponse{}
+}
+
+func (p *blockingProvisioner) Stop() error {
+	p.cancel()
+	return nil
+}
+
+func (p *blockingProvisioner) Close() error {
+	return nil
+}
+
+// provisionResourceServer is a ProvisionResource stream with a context
+// controlled by the test.
+type provisionResourceServer struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s provisionResourceServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s provisionResourceServer) Send(*tfplugin5.ProvisionResource_Response) error {
+	return nil
+}
+
+func TestProvisioner_cancelled(t *testing.T) {
+	p := newBlockingProvisioner()
+	server := Provisioner(p)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ProvisionResource(&tfplugin5.ProvisionResource_Request{}, provisionResourceServer{ctx: ctx})
+	}()
+
+	select {
+	case <-p.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("provisioner was not started")
+	}
+
+	cancel()
+
+	select {
+	case <-p.ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("provisioner context was not cancelled")
+	}
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ProvisionResource did not return")
+	}
+}
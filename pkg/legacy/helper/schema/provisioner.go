@@ -9,7 +9,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os/exec"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/we-dcode/opentofu/pkg/configs/configschema"
@@ -50,11 +53,42 @@ type Provisioner struct {
 	// and should be used when individual field validation is not enough.
 	ValidateFunc func(*tofu.ResourceConfig) ([]string, []error)
 
-	stopCtx       context.Context
-	stopCtxCancel context.CancelFunc
-	stopOnce      sync.Once
+	// StopTimeout is the grace period given to ApplyFunc to clean up after
+	// Stop is called. Stop cancels the context given to ApplyFunc right away,
+	// but only cancels the context in ProvHardStopKey once StopTimeout has
+	// passed. This is optional: when zero, both are cancelled immediately.
+	StopTimeout time.Duration
+
+	// Phases are the phases of the resource lifecycle the provisioner may be
+	// attached to. This is optional: when empty, the provisioner can be used
+	// in every phase. ValidatePhase rejects configurations attaching the
+	// provisioner to any other phase.
+	Phases []ProvisionerPhase
+
+	stopCtx           context.Context
+	stopCtxCancel     context.CancelFunc
+	hardStopCtx       context.Context
+	hardStopCtxCancel context.CancelFunc
+	stopOnce          sync.Once
+
+	exitCodeLock sync.Mutex
+	exitCode     *ExitCode
 }
 
+// ProvisionerPhase is a phase of the resource lifecycle a provisioner can be
+// attached to, as selected by the "when" argument of the provisioner block.
+type ProvisionerPhase string
+
+const (
+	// ProvisionerPhaseCreate is for provisioners running after the resource
+	// was created.
+	ProvisionerPhaseCreate ProvisionerPhase = "create"
+
+	// ProvisionerPhaseDestroy is for provisioners running before the
+	// resource is destroyed.
+	ProvisionerPhaseDestroy ProvisionerPhase = "destroy"
+)
+
 // Keys that can be used to access data in the context parameters for
 // Provisioners.
 var (
@@ -71,11 +105,79 @@ var (
 	// This returns a tofu.UIOutput. Guaranteed to never be nil.
 	ProvOutputKey = contextKey("provider output")
 
+	// This returns a ProgressFunc for reporting structured progress to the
+	// UI. Guaranteed to never be nil, but progress is silently discarded if
+	// the UI doesn't support it.
+	ProvProgressKey = contextKey("provider progress")
+
+	// This returns a context.Context which is cancelled once the
+	// provisioner's StopTimeout has passed after Stop was called, at which
+	// point the provisioner must give up on any cleanup and return.
+	// Guaranteed to never be nil.
+	ProvHardStopKey = contextKey("provider hard stop")
+
 	// This returns the raw InstanceState passed to Apply. Guaranteed to
 	// be set, but may be nil.
 	ProvRawStateKey = contextKey("provider raw state")
+
+	// This returns an *ExitCode in which ApplyFunc records the exit code of
+	// the last command it ran. Guaranteed to never be nil.
+	ProvExitCodeKey = contextKey("provider exit code")
 )
 
+// ExitCode holds the exit code of the last command run by a provisioner.
+type ExitCode struct {
+	mu   sync.Mutex
+	code int
+	set  bool
+}
+
+// Set records the given exit code.
+func (c *ExitCode) Set(code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.code = code
+	c.set = true
+}
+
+// Record records the exit code of a command from the error returned by
+// running it. A nil error is recorded as a zero exit code, while errors that
+// don't carry an exit code, such as a failure to start the command, leave
+// the previously recorded exit code unchanged.
+func (c *ExitCode) Record(err error) {
+	if err == nil {
+		c.Set(0)
+		return
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		c.Set(exitErr.ExitCode())
+	}
+}
+
+// Get returns the last recorded exit code. The boolean result is false if
+// no exit code was recorded.
+func (c *ExitCode) Get() (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.code, c.set
+}
+
+// ProgressFunc reports that a provisioner has reached the given phase.
+// Percent is the overall completion from 0 to 100, or negative if unknown.
+type ProgressFunc func(phase string, percent float64)
+
+// progressFunc returns a ProgressFunc reporting to the given output, if it
+// supports structured progress.
+func progressFunc(o tofu.UIOutput) ProgressFunc {
+	if po, ok := o.(interface {
+		Progress(phase string, percent float64)
+	}); ok {
+		return po.Progress
+	}
+	return func(string, float64) {}
+}
+
 // InternalValidate should be called to validate the structure
 // of the provisioner.
 //
@@ -106,23 +208,66 @@ func (p *Provisioner) InternalValidate() error {
 			"ApplyFunc must not be nil"))
 	}
 
+	seen := make(map[ProvisionerPhase]bool, len(p.Phases))
+	for _, phase := range p.Phases {
+		switch phase {
+		case ProvisionerPhaseCreate, ProvisionerPhaseDestroy:
+		default:
+			validationErrors = multierror.Append(validationErrors, fmt.Errorf(
+				"Phases: unknown phase %q", phase))
+			continue
+		}
+		if seen[phase] {
+			validationErrors = multierror.Append(validationErrors, fmt.Errorf(
+				"Phases: duplicate phase %q", phase))
+		}
+		seen[phase] = true
+	}
+
 	return validationErrors
 }
 
+// SupportsPhase returns true if the provisioner may be attached to the given
+// phase of the resource lifecycle.
+func (p *Provisioner) SupportsPhase(phase ProvisionerPhase) bool {
+	if len(p.Phases) == 0 {
+		return true
+	}
+	for _, supported := range p.Phases {
+		if supported == phase {
+			return true
+		}
+	}
+	return false
+}
+
 // StopContext returns a context that checks whether a provisioner is stopped.
 func (p *Provisioner) StopContext() context.Context {
 	p.stopOnce.Do(p.stopInit)
 	return p.stopCtx
 }
 
+// HardStopContext returns a context that is cancelled once StopTimeout has
+// passed after the provisioner was stopped.
+func (p *Provisioner) HardStopContext() context.Context {
+	p.stopOnce.Do(p.stopInit)
+	return p.hardStopCtx
+}
+
 func (p *Provisioner) stopInit() {
 	p.stopCtx, p.stopCtxCancel = context.WithCancel(context.Background())
+	p.hardStopCtx, p.hardStopCtxCancel = context.WithCancel(context.Background())
 }
 
 // Stop implementation of tofu.ResourceProvisioner interface.
 func (p *Provisioner) Stop() error {
 	p.stopOnce.Do(p.stopInit)
 	p.stopCtxCancel()
+	if p.StopTimeout > 0 {
+		time.AfterFunc(p.StopTimeout, p.hardStopCtxCancel)
+	} else {
+		p.hardStopCtxCancel()
+	}
 	return nil
 }
 
@@ -176,15 +321,37 @@ func (p *Provisioner) Apply(
 		}
 	}
 
+	exitCode := new(ExitCode)
+	p.exitCodeLock.Lock()
+	p.exitCode = exitCode
+	p.exitCodeLock.Unlock()
+
 	// Build the context and call the function
 	ctx := p.StopContext()
 	ctx = context.WithValue(ctx, ProvConnDataKey, connData)
 	ctx = context.WithValue(ctx, ProvConfigDataKey, configData)
 	ctx = context.WithValue(ctx, ProvOutputKey, o)
+	ctx = context.WithValue(ctx, ProvProgressKey, progressFunc(o))
 	ctx = context.WithValue(ctx, ProvRawStateKey, s)
+	ctx = context.WithValue(ctx, ProvHardStopKey, p.HardStopContext())
+	ctx = context.WithValue(ctx, ProvExitCodeKey, exitCode)
 	return p.ApplyFunc(ctx)
 }
 
+// LastExitCode returns the exit code recorded by the last call to Apply. The
+// boolean result is false if Apply wasn't called yet or ApplyFunc didn't
+// record an exit code.
+func (p *Provisioner) LastExitCode() (int, bool) {
+	p.exitCodeLock.Lock()
+	exitCode := p.exitCode
+	p.exitCodeLock.Unlock()
+
+	if exitCode == nil {
+		return 0, false
+	}
+	return exitCode.Get()
+}
+
 // Validate implements the tofu.ResourceProvisioner interface.
 func (p *Provisioner) Validate(c *tofu.ResourceConfig) (ws []string, es []error) {
 	if err := p.InternalValidate(); err != nil {
@@ -208,3 +375,24 @@ func (p *Provisioner) Validate(c *tofu.ResourceConfig) (ws []string, es []error)
 
 	return ws, es
 }
+
+// ValidatePhase validates the configuration like Validate, and additionally
+// rejects it if the provisioner is attached to a phase of the resource
+// lifecycle it doesn't support.
+func (p *Provisioner) ValidatePhase(c *tofu.ResourceConfig, phase ProvisionerPhase) (ws []string, es []error) {
+	ws, es = p.Validate(c)
+	if p != nil && !p.SupportsPhase(phase) {
+		es = append(es, fmt.Errorf(
+			"This provisioner can't be used as a %s-time provisioner, it only supports: %s",
+			phase, joinPhases(p.Phases)))
+	}
+	return ws, es
+}
+
+func joinPhases(phases []ProvisionerPhase) string {
+	names := make([]string, len(phases))
+	for i, phase := range phases {
+		names[i] = string(phase)
+	}
+	return strings.Join(names, ", ")
+}
@@ -8,7 +8,9 @@ package schema
 import (
 	"context"
 	"fmt"
+	"os/exec"
 	"reflect"
+	"runtime"
 	"testing"
 	"time"
 
@@ -141,6 +143,89 @@ func TestProvisionerValidate(t *testing.T) {
 	}
 }
 
+func TestProvisionerInternalValidate_phases(t *testing.T) {
+	cases := map[string]struct {
+		Phases []ProvisionerPhase
+		Err    bool
+	}{
+		"unset": {
+			Phases: nil,
+			Err:    false,
+		},
+		"destroy only": {
+			Phases: []ProvisionerPhase{ProvisionerPhaseDestroy},
+			Err:    false,
+		},
+		"create and destroy": {
+			Phases: []ProvisionerPhase{ProvisionerPhaseCreate, ProvisionerPhaseDestroy},
+			Err:    false,
+		},
+		"unknown phase": {
+			Phases: []ProvisionerPhase{"update"},
+			Err:    true,
+		},
+		"duplicate phase": {
+			Phases: []ProvisionerPhase{ProvisionerPhaseCreate, ProvisionerPhaseCreate},
+			Err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &Provisioner{
+				ApplyFunc: noopApply,
+				Phases:    tc.Phases,
+			}
+			err := p.InternalValidate()
+			if (err != nil) != tc.Err {
+				t.Fatalf("unexpected result: %v", err)
+			}
+		})
+	}
+}
+
+func TestProvisionerValidatePhase(t *testing.T) {
+	cases := map[string]struct {
+		Phases []ProvisionerPhase
+		Phase  ProvisionerPhase
+		Err    bool
+	}{
+		"unset allows create": {
+			Phases: nil,
+			Phase:  ProvisionerPhaseCreate,
+			Err:    false,
+		},
+		"unset allows destroy": {
+			Phases: nil,
+			Phase:  ProvisionerPhaseDestroy,
+			Err:    false,
+		},
+		"destroy only allows destroy": {
+			Phases: []ProvisionerPhase{ProvisionerPhaseDestroy},
+			Phase:  ProvisionerPhaseDestroy,
+			Err:    false,
+		},
+		"destroy only rejects create": {
+			Phases: []ProvisionerPhase{ProvisionerPhaseDestroy},
+			Phase:  ProvisionerPhaseCreate,
+			Err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &Provisioner{
+				ApplyFunc: noopApply,
+				Phases:    tc.Phases,
+			}
+			_, es := p.ValidatePhase(tofu.NewResourceConfigRaw(nil), tc.Phase)
+			if len(es) > 0 != tc.Err {
+				t.Fatalf("unexpected errors: %v", es)
+			}
+		})
+	}
+}
+
 func TestProvisionerApply(t *testing.T) {
 	cases := []struct {
 		Name   string
@@ -239,6 +324,42 @@ func TestProvisionerApply_nilState(t *testing.T) {
 	}
 }
 
+func TestProvisionerApply_exitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	var fromContext int
+	p := &Provisioner{
+		ApplyFunc: func(ctx context.Context) error {
+			exitCode := ctx.Value(ProvExitCodeKey).(*ExitCode)
+
+			err := exec.Command("/bin/sh", "-c", "exit 3").Run()
+			exitCode.Record(err)
+
+			fromContext, _ = exitCode.Get()
+			return err
+		},
+	}
+
+	if _, ok := p.LastExitCode(); ok {
+		t.Fatal("unexpected exit code before apply")
+	}
+
+	err := p.Apply(nil, nil, tofu.NewResourceConfigRaw(nil))
+	if err == nil {
+		t.Fatal("expected an error for a non-zero exit code")
+	}
+
+	if fromContext != 3 {
+		t.Fatalf("wrong exit code in context: %d", fromContext)
+	}
+	code, ok := p.LastExitCode()
+	if !ok || code != 3 {
+		t.Fatalf("wrong last exit code: %d, %t", code, ok)
+	}
+}
+
 func TestProvisionerStop(t *testing.T) {
 	var p Provisioner
 
@@ -337,3 +458,92 @@ func TestProvisionerStop_stopFirst(t *testing.T) {
 		t.Fatal("should be stopped")
 	}
 }
+
+func TestProvisionerStop_timeout(t *testing.T) {
+	p := &Provisioner{StopTimeout: 50 * time.Millisecond}
+
+	if err := p.Stop(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	select {
+	case <-p.StopContext().Done():
+	case <-time.After(10 * time.Millisecond):
+		t.Fatal("should be soft stopped")
+	}
+
+	select {
+	case <-p.HardStopContext().Done():
+		t.Fatal("should not be hard stopped before the timeout")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select {
+	case <-p.HardStopContext().Done():
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("should be hard stopped after the timeout")
+	}
+}
+
+func TestProvisionerStop_applyTimeout(t *testing.T) {
+	tests := map[string]struct {
+		// The ApplyFunc reports whether it was hard cancelled
+		ApplyFunc    func(ctx context.Context) bool
+		WantHardStop bool
+	}{
+		"cooperative": {
+			ApplyFunc: func(ctx context.Context) bool {
+				<-ctx.Done()
+				// Flush within the grace period
+				time.Sleep(10 * time.Millisecond)
+				return ctx.Value(ProvHardStopKey).(context.Context).Err() != nil
+			},
+			WantHardStop: false,
+		},
+		"ignores soft stop": {
+			ApplyFunc: func(ctx context.Context) bool {
+				<-ctx.Value(ProvHardStopKey).(context.Context).Done()
+				return true
+			},
+			WantHardStop: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			hardStopped := make(chan bool, 1)
+			p := &Provisioner{
+				StopTimeout: 200 * time.Millisecond,
+				ApplyFunc: func(ctx context.Context) error {
+					hardStopped <- test.ApplyFunc(ctx)
+					return nil
+				},
+			}
+
+			doneCh := make(chan struct{})
+			go func() {
+				p.Apply(nil, &tofu.InstanceState{}, tofu.NewResourceConfigRaw(map[string]interface{}{}))
+				close(doneCh)
+			}()
+
+			// Should block
+			select {
+			case <-doneCh:
+				t.Fatal("should not be done")
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			p.Stop()
+
+			select {
+			case <-doneCh:
+			case <-time.After(time.Second):
+				t.Fatal("should be done")
+			}
+
+			if got := <-hardStopped; got != test.WantHardStop {
+				t.Fatalf("wrong hard stop state %t; want %t", got, test.WantHardStop)
+			}
+		})
+	}
+}
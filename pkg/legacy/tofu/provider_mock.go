@@ -362,6 +362,10 @@ func (p *MockProvider) ReadDataSource(r providers.ReadDataSourceRequest) provide
 	return p.ReadDataSourceResponse
 }
 
+func (p *MockProvider) MoveResourceState(r providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
+	panic("Not Implemented")
+}
+
 func (p *MockProvider) GetFunctions() providers.GetFunctionsResponse {
 	panic("Not Implemented")
 }
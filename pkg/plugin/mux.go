@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/we-dcode/opentofu/pkg/plugin6"
+	proto "github.com/we-dcode/opentofu/pkg/tfplugin5"
+	proto6 "github.com/we-dcode/opentofu/pkg/tfplugin6"
+)
+
+// MuxServeOpts are the configurations to serve a provider over both plugin
+// protocol 5 and 6 from a single binary.
+type MuxServeOpts struct {
+	GRPCProviderFunc  GRPCProviderFunc
+	GRPCProvider6Func plugin6.GRPCProviderFunc
+}
+
+// Mux serves a provider over whichever of plugin protocol 5 or 6 the client
+// negotiates. This is intended for testing protocol negotiation, so that a
+// single test provider binary can exercise both protocols.
+type Mux struct {
+	opts MuxServeOpts
+}
+
+// NewMux returns a Mux for the given provider servers. It returns an error
+// if the two servers don't declare the same resource and data source types,
+// so that mismatched implementations are caught before the provider is served.
+func NewMux(opts *MuxServeOpts) (*Mux, error) {
+	if opts.GRPCProviderFunc == nil || opts.GRPCProvider6Func == nil {
+		return nil, errors.New("both a protocol 5 and a protocol 6 provider server are required")
+	}
+
+	ctx := context.Background()
+	resp5, err := opts.GRPCProviderFunc().GetSchema(ctx, &proto.GetProviderSchema_Request{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the protocol 5 provider schema: %w", err)
+	}
+	resp6, err := opts.GRPCProvider6Func().GetProviderSchema(ctx, &proto6.GetProviderSchema_Request{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the protocol 6 provider schema: %w", err)
+	}
+
+	var errs []error
+	if err := compareMuxTypes("resource", resp5.ResourceSchemas, resp6.ResourceSchemas); err != nil {
+		errs = append(errs, err)
+	}
+	if err := compareMuxTypes("data source", resp5.DataSourceSchemas, resp6.DataSourceSchemas); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &Mux{opts: *opts}, nil
+}
+
+// compareMuxTypes returns an error describing the types which are only
+// declared by one of the two protocol versions.
+func compareMuxTypes[V5, V6 any](kind string, types5 map[string]V5, types6 map[string]V6) error {
+	var only5, only6 []string
+	for name := range types5 {
+		if _, ok := types6[name]; !ok {
+			only5 = append(only5, name)
+		}
+	}
+	for name := range types6 {
+		if _, ok := types5[name]; !ok {
+			only6 = append(only6, name)
+		}
+	}
+	if len(only5) == 0 && len(only6) == 0 {
+		return nil
+	}
+	sort.Strings(only5)
+	sort.Strings(only6)
+	return fmt.Errorf("the provider servers disagree on %s types: only protocol 5 declares %q, only protocol 6 declares %q", kind, only5, only6)
+}
+
+// Serve serves the provider. This function never returns and should be the
+// final function called in the main function of the plugin.
+func (m *Mux) Serve() {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig:  Handshake,
+		VersionedPlugins: m.pluginSet(),
+		GRPCServer:       plugin.DefaultGRPCServer,
+	})
+}
+
+func (m *Mux) pluginSet() map[int]plugin.PluginSet {
+	return map[int]plugin.PluginSet{
+		5: {
+			ProviderPluginName: &GRPCProviderPlugin{
+				GRPCProvider: m.opts.GRPCProviderFunc,
+			},
+		},
+		6: {
+			plugin6.ProviderPluginName: &plugin6.GRPCProviderPlugin{
+				GRPCProvider: m.opts.GRPCProvider6Func,
+			},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/plugin6"
+	proto "github.com/we-dcode/opentofu/pkg/tfplugin5"
+	proto6 "github.com/we-dcode/opentofu/pkg/tfplugin6"
+)
+
+type muxTestProvider5 struct {
+	proto.UnimplementedProviderServer
+	resp *proto.GetProviderSchema_Response
+}
+
+func (p *muxTestProvider5) GetSchema(context.Context, *proto.GetProviderSchema_Request) (*proto.GetProviderSchema_Response, error) {
+	return p.resp, nil
+}
+
+type muxTestProvider6 struct {
+	proto6.UnimplementedProviderServer
+	resp *proto6.GetProviderSchema_Response
+}
+
+func (p *muxTestProvider6) GetProviderSchema(context.Context, *proto6.GetProviderSchema_Request) (*proto6.GetProviderSchema_Response, error) {
+	return p.resp, nil
+}
+
+func muxTestOpts(resources5, resources6, dataSources5, dataSources6 []string) *MuxServeOpts {
+	resp5 := &proto.GetProviderSchema_Response{
+		ResourceSchemas:   map[string]*proto.Schema{},
+		DataSourceSchemas: map[string]*proto.Schema{},
+	}
+	for _, name := range resources5 {
+		resp5.ResourceSchemas[name] = &proto.Schema{}
+	}
+	for _, name := range dataSources5 {
+		resp5.DataSourceSchemas[name] = &proto.Schema{}
+	}
+	resp6 := &proto6.GetProviderSchema_Response{
+		ResourceSchemas:   map[string]*proto6.Schema{},
+		DataSourceSchemas: map[string]*proto6.Schema{},
+	}
+	for _, name := range resources6 {
+		resp6.ResourceSchemas[name] = &proto6.Schema{}
+	}
+	for _, name := range dataSources6 {
+		resp6.DataSourceSchemas[name] = &proto6.Schema{}
+	}
+
+	return &MuxServeOpts{
+		GRPCProviderFunc: func() proto.ProviderServer {
+			return &muxTestProvider5{resp: resp5}
+		},
+		GRPCProvider6Func: func() proto6.ProviderServer {
+			return &muxTestProvider6{resp: resp6}
+		},
+	}
+}
+
+func TestNewMux(t *testing.T) {
+	opts := muxTestOpts(
+		[]string{"simple_resource"}, []string{"simple_resource"},
+		[]string{"simple_resource"}, []string{"simple_resource"},
+	)
+	mux, err := NewMux(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	plugins := mux.pluginSet()
+	if _, ok := plugins[5][ProviderPluginName].(*GRPCProviderPlugin); !ok {
+		t.Errorf("protocol 5 provider plugin is missing")
+	}
+	if _, ok := plugins[6][plugin6.ProviderPluginName].(*plugin6.GRPCProviderPlugin); !ok {
+		t.Errorf("protocol 6 provider plugin is missing")
+	}
+}
+
+func TestNewMux_mismatch(t *testing.T) {
+	tests := map[string]struct {
+		opts    *MuxServeOpts
+		wantErr string
+	}{
+		"resources": {
+			opts: muxTestOpts(
+				[]string{"simple_resource", "simple_old"}, []string{"simple_resource", "simple_new"},
+				nil, nil,
+			),
+			wantErr: `disagree on resource types: only protocol 5 declares ["simple_old"], only protocol 6 declares ["simple_new"]`,
+		},
+		"data sources": {
+			opts: muxTestOpts(
+				nil, nil,
+				[]string{"simple_resource"}, nil,
+			),
+			wantErr: `disagree on data source types: only protocol 5 declares ["simple_resource"], only protocol 6 declares []`,
+		},
+		"missing server": {
+			opts:    &MuxServeOpts{},
+			wantErr: "both a protocol 5 and a protocol 6 provider server are required",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewMux(test.opts)
+			if err == nil {
+				t.Fatal("succeeded, but want error")
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.wantErr)
+			}
+		})
+	}
+}
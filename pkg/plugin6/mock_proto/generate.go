@@ -6,3 +6,10 @@
 //go:generate go run github.com/golang/mock/mockgen -destination mock.go github.com/we-dcode/opentofu/pkg/tfplugin6 ProviderClient
 
 package mock_tfplugin6
+
+import "github.com/we-dcode/opentofu/pkg/tfplugin6"
+
+// MockProviderClient must cover the whole tfplugin6.ProviderClient interface,
+// so that a mock which wasn't regenerated after an update of the protocol
+// fails to compile rather than silently missing the new RPCs.
+var _ tfplugin6.ProviderClient = (*MockProviderClient)(nil)
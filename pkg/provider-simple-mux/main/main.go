@@ -0,0 +1,36 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// This is a test provider serving both plugin protocol 5 and 6, for testing
+// the protocol negotiation with OpenTofu.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/we-dcode/opentofu/pkg/grpcwrap"
+	"github.com/we-dcode/opentofu/pkg/plugin"
+	simple "github.com/we-dcode/opentofu/pkg/provider-simple"
+	simple6 "github.com/we-dcode/opentofu/pkg/provider-simple-v6"
+	"github.com/we-dcode/opentofu/pkg/tfplugin5"
+	"github.com/we-dcode/opentofu/pkg/tfplugin6"
+)
+
+func main() {
+	mux, err := plugin.NewMux(&plugin.MuxServeOpts{
+		GRPCProviderFunc: func() tfplugin5.ProviderServer {
+			return grpcwrap.Provider(simple.Provider())
+		},
+		GRPCProvider6Func: func() tfplugin6.ProviderServer {
+			return grpcwrap.Provider6(simple6.Provider())
+		},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to start the provider: %s\n", err)
+		os.Exit(1)
+	}
+	mux.Serve()
+}
@@ -74,6 +74,11 @@ type Interface interface {
 	// ImportResourceState requests that the given resource be imported.
 	ImportResourceState(ImportResourceStateRequest) ImportResourceStateResponse
 
+	// MoveResourceState requests that the given resource state be moved from
+	// another resource type, possibly of another provider, to a resource type
+	// of this provider.
+	MoveResourceState(MoveResourceStateRequest) MoveResourceStateResponse
+
 	// ReadDataSource returns the data source's current state.
 	ReadDataSource(ReadDataSourceRequest) ReadDataSourceResponse
 
@@ -456,6 +461,45 @@ func (ir ImportedResource) AsInstanceObject() *states.ResourceInstanceObject {
 	}
 }
 
+type MoveResourceStateRequest struct {
+	// SourceProviderAddress is the address of the provider that the resource
+	// is being moved from.
+	SourceProviderAddress string
+
+	// SourceTypeName is the name of the resource type that the resource is
+	// being moved from.
+	SourceTypeName string
+
+	// SourceSchemaVersion is the version of the source resource type's schema
+	// that created the source state.
+	SourceSchemaVersion int64
+
+	// SourceStateJSON is the raw state of the resource being moved, as stored
+	// in the state. Because the source schema may be unknown to this provider,
+	// it is passed without decoding.
+	SourceStateJSON []byte
+
+	// SourcePrivate is the private state of the resource being moved.
+	SourcePrivate []byte
+
+	// TargetTypeName is the name of the resource type that the resource is
+	// being moved to.
+	TargetTypeName string
+}
+
+type MoveResourceStateResponse struct {
+	// TargetState is the state of the resource after it has been moved,
+	// conforming to the schema of the target resource type.
+	TargetState cty.Value
+
+	// TargetPrivate is the private state of the resource after it has been
+	// moved.
+	TargetPrivate []byte
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
 type ReadDataSourceRequest struct {
 	// TypeName is the name of the data source type to Read.
 	TypeName string
@@ -59,6 +59,20 @@ type UIOutput interface {
 	Output(string)
 }
 
+// ProgressOutput is an optional extension of UIOutput for reporting the
+// progress of a long-running provisioner as structured events, rather than as
+// lines of output. Provisioners must check for it with a type assertion on
+// the UIOutput they were given, and fall back to Output if it isn't
+// implemented.
+type ProgressOutput interface {
+	UIOutput
+
+	// Progress reports that the provisioner has reached the given phase.
+	// Percent is the overall completion from 0 to 100, or negative if the
+	// provisioner can't estimate it.
+	Progress(phase string, percent float64)
+}
+
 type ValidateProvisionerConfigRequest struct {
 	// Config is the complete configuration to be used for the provisioner.
 	Config cty.Value
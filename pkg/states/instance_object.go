@@ -49,6 +49,12 @@ type ResourceInstanceObject struct {
 	// destroy operations, we need to record the status to ensure a resource
 	// removed from the config will still be destroyed in the same manner.
 	CreateBeforeDestroy bool
+
+	// NeedsRefresh is set when the object was stored without reading it back
+	// from the provider, such as when an import skipped its refresh. The
+	// value may then be incomplete until the next refresh, which clears it.
+	// The flag is saved in state files, as "needs_refresh".
+	NeedsRefresh bool
 }
 
 // ObjectStatus represents the status of a RemoteObject.
@@ -135,6 +141,7 @@ func (o *ResourceInstanceObject) Encode(ty cty.Type, schemaVersion uint64) (*Res
 		Status:              o.Status,
 		Dependencies:        dependencies,
 		CreateBeforeDestroy: o.CreateBeforeDestroy,
+		NeedsRefresh:        o.NeedsRefresh,
 	}, nil
 }
 
@@ -64,6 +64,7 @@ type ResourceInstanceObjectSrc struct {
 	Status              ObjectStatus
 	Dependencies        []addrs.ConfigResource
 	CreateBeforeDestroy bool
+	NeedsRefresh        bool
 }
 
 // Decode unmarshals the raw representation of the object attributes. Pass the
@@ -102,6 +103,7 @@ func (os *ResourceInstanceObjectSrc) Decode(ty cty.Type) (*ResourceInstanceObjec
 		Dependencies:        os.Dependencies,
 		Private:             os.Private,
 		CreateBeforeDestroy: os.CreateBeforeDestroy,
+		NeedsRefresh:        os.NeedsRefresh,
 	}, nil
 }
 
@@ -6,8 +6,10 @@
 package states
 
 import (
-	"github.com/we-dcode/opentofu/pkg/addrs"
 	"github.com/zclconf/go-cty/cty"
+
+	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/lang/marks"
 )
 
 // OutputValue represents the state of a particular output value.
@@ -19,3 +21,25 @@ type OutputValue struct {
 	Value     cty.Value
 	Sensitive bool
 }
+
+// MarkedValue returns the value of the output, marked as sensitive if the
+// output is sensitive.
+func (os *OutputValue) MarkedValue() cty.Value {
+	if os.Sensitive {
+		return os.Value.Mark(marks.Sensitive)
+	}
+	return os.Value
+}
+
+// OutputValuesObject returns the given output values, keyed by output name as
+// returned by Module.OutputValues, as a single object value with one attribute
+// per output. The values of sensitive outputs are marked as sensitive, so the
+// result can be traversed with the usual cty functions without losing track
+// of which parts of it are sensitive.
+func OutputValuesObject(outputs map[string]*OutputValue) cty.Value {
+	attrs := make(map[string]cty.Value, len(outputs))
+	for name, os := range outputs {
+		attrs[name] = os.MarkedValue()
+	}
+	return cty.ObjectVal(attrs)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package states
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/we-dcode/opentofu/pkg/lang/marks"
+)
+
+func TestOutputValuesObject(t *testing.T) {
+	state := NewState()
+	root := state.RootModule()
+	root.SetOutputValue("plain", cty.StringVal("hello"), false)
+	root.SetOutputValue("secret", cty.ObjectVal(map[string]cty.Value{
+		"password": cty.StringVal("hunter2"),
+	}), true)
+
+	got := OutputValuesObject(root.OutputValues)
+	want := cty.ObjectVal(map[string]cty.Value{
+		"plain": cty.StringVal("hello"),
+		"secret": cty.ObjectVal(map[string]cty.Value{
+			"password": cty.StringVal("hunter2"),
+		}).Mark(marks.Sensitive),
+	})
+	if !got.RawEquals(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	// Traversing into a sensitive output keeps the value sensitive.
+	password := got.GetAttr("secret").GetAttr("password")
+	if !password.HasMark(marks.Sensitive) {
+		t.Errorf("nested value of sensitive output is not marked as sensitive")
+	}
+	if got.GetAttr("plain").IsMarked() {
+		t.Errorf("value of non-sensitive output is marked")
+	}
+}
+
+func TestOutputValuesObject_empty(t *testing.T) {
+	got := OutputValuesObject(nil)
+	if !got.RawEquals(cty.EmptyObjectVal) {
+		t.Fatalf("wrong result %#v, want an empty object", got)
+	}
+}
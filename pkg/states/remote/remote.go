@@ -26,6 +26,31 @@ type ClientForcePusher interface {
 	EnableForcePush()
 }
 
+// ClientConditionalPutter is an optional interface that allows a remote
+// state to only write the state if it wasn't changed by another process
+// since it was last read, for storage that supports conditional writes.
+// State only uses it once EnableConditionalWrites was called.
+type ClientConditionalPutter interface {
+	Client
+
+	// PutIfMatch stores data like Put, but only if the MD5 of the stored
+	// state is still md5, which is the MD5 of the Payload last returned by
+	// Get, or nil if Get returned no state. Otherwise nothing is stored and
+	// an error wrapping statemgr.ErrStateChanged is returned.
+	PutIfMatch(data []byte, md5 []byte) error
+}
+
+// ClientVersionReporter is an optional interface that allows a remote
+// state backend to report the version its storage assigned to the state
+// written by the last successful call to Put or PutIfMatch.
+type ClientVersionReporter interface {
+	Client
+
+	// StoredVersion returns the version assigned to the last written state,
+	// or statemgr.UnknownStoredVersion if it isn't known.
+	StoredVersion() int64
+}
+
 // ClientLocker is an optional interface that allows a remote state
 // backend to enable state lock/unlock.
 type ClientLocker interface {
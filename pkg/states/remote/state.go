@@ -7,6 +7,7 @@ package remote
 
 import (
 	"bytes"
+	"crypto/md5"
 	"fmt"
 	"log"
 	"sync"
@@ -46,6 +47,27 @@ type State struct {
 	state, readState     *states.State
 	disableLocks         bool
 
+	// readMD5 is the MD5 of the stored state that was last read or written,
+	// which is used as the precondition of conditional writes. It's nil if
+	// there was no stored state.
+	readMD5 []byte
+
+	// If this is set and the client implements ClientConditionalPutter, the
+	// state manager only writes the state if the stored state wasn't changed
+	// since it was last read. Otherwise (by default) it overwrites the stored
+	// state.
+	conditionalWrites bool
+
+	// forcePush is set by a forced WriteStateForMigration, to overwrite the
+	// stored state on the next PersistState even if it has changed.
+	forcePush bool
+
+	// storedVersion is the version the storage assigned to the state last
+	// written by PersistState, if hasStoredVersion is set. It's only known
+	// for clients implementing ClientVersionReporter.
+	storedVersion    int64
+	hasStoredVersion bool
+
 	// If this is set then the state manager will decline to store intermediate
 	// state snapshots created while a OpenTofu Core apply operation is in
 	// progress. Otherwise (by default) it will accept persistent snapshots
@@ -56,6 +78,7 @@ type State struct {
 var _ statemgr.Full = (*State)(nil)
 var _ statemgr.Migrator = (*State)(nil)
 var _ local.IntermediateStateConditionalPersister = (*State)(nil)
+var _ statemgr.StoredVersionReporter = (*State)(nil)
 
 func NewState(client Client, enc encryption.StateEncryption) *State {
 	return &State{
@@ -68,6 +91,14 @@ func (s *State) DisableIntermediateSnapshots() {
 	s.disableIntermediateSnapshots = true
 }
 
+// EnableConditionalWrites makes PersistState refuse to overwrite a stored
+// state that was changed since it was last read, if the client implements
+// ClientConditionalPutter. How strictly this is enforced depends on the
+// client, so it complements state locking rather than replacing it.
+func (s *State) EnableConditionalWrites() {
+	s.conditionalWrites = true
+}
+
 // statemgr.Reader impl.
 func (s *State) State() *states.State {
 	s.mu.Lock()
@@ -129,6 +160,7 @@ func (s *State) WriteStateForMigration(f *statefile.File, force bool) error {
 	if force && isForcePusher {
 		c.EnableForcePush()
 	}
+	s.forcePush = force
 
 	// We create a deep copy of the state here, because the caller also has
 	// a reference to the given object and can potentially go on to mutate
@@ -159,6 +191,7 @@ func (s *State) refreshState() error {
 	// no remote state is OK
 	if payload == nil {
 		s.readState = nil
+		s.readMD5 = nil
 		s.lineage = ""
 		s.serial = 0
 		return nil
@@ -179,6 +212,7 @@ func (s *State) refreshState() error {
 	s.readSerial = stateFile.Serial
 	s.readEncryption = stateFile.EncryptionStatus
 	s.readState = s.state.DeepCopy()
+	s.readMD5 = payload.MD5
 	return nil
 }
 
@@ -227,7 +261,14 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 		return err
 	}
 
-	err = s.Client.Put(buf.Bytes())
+	data := buf.Bytes()
+	if c, ok := s.Client.(ClientConditionalPutter); ok && s.conditionalWrites && !s.forcePush {
+		// Only overwrite the state we've read, so we don't destroy the
+		// changes of another process which wrote the state in the meantime.
+		err = c.PutIfMatch(data, s.readMD5)
+	} else {
+		err = s.Client.Put(data)
+	}
 	if err != nil {
 		return err
 	}
@@ -242,9 +283,32 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 	s.readLineage = s.lineage
 	s.readEncryption = encryption.StatusSatisfied
 	s.readSerial = s.serial
+	sum := md5.Sum(data)
+	s.readMD5 = sum[:]
+	s.forcePush = false
+	if c, ok := s.Client.(ClientVersionReporter); ok {
+		s.storedVersion = c.StoredVersion()
+		s.hasStoredVersion = s.storedVersion != statemgr.UnknownStoredVersion
+	} else {
+		s.hasStoredVersion = false
+	}
 	return nil
 }
 
+// StoredStateVersion returns the version the client's storage assigned to
+// the state last written by PersistState.
+//
+// This is an implementation of statemgr.StoredVersionReporter.
+func (s *State) StoredStateVersion() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.hasStoredVersion {
+		return statemgr.UnknownStoredVersion
+	}
+	return s.storedVersion
+}
+
 // ShouldPersistIntermediateState implements local.IntermediateStateConditionalPersister
 func (s *State) ShouldPersistIntermediateState(info *local.IntermediateStatePersistInfo) bool {
 	if s.disableIntermediateSnapshots {
@@ -848,3 +848,66 @@ func TestState_IsLockingEnabled(t *testing.T) {
 		})
 	}
 }
+
+// mockClientVersionReporter is a mock client whose storage assigns the next
+// version to every state it stores.
+type mockClientVersionReporter struct {
+	*mockClient
+	version int64
+}
+
+func (c *mockClientVersionReporter) Put(data []byte) error {
+	if err := c.mockClient.Put(data); err != nil {
+		return err
+	}
+	c.version++
+	return nil
+}
+
+func (c *mockClientVersionReporter) StoredVersion() int64 {
+	if c.version == 0 {
+		return statemgr.UnknownStoredVersion
+	}
+	return c.version
+}
+
+var _ ClientVersionReporter = &mockClientVersionReporter{}
+
+func TestState_StoredStateVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		client Client
+		want   int64
+	}{
+		{
+			name:   "ClientVersionReporter",
+			client: &mockClientVersionReporter{mockClient: &mockClient{}, version: 4},
+			want:   5,
+		},
+		{
+			name:   "Client without version reporting",
+			client: &mockClient{},
+			want:   statemgr.UnknownStoredVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewState(tt.client, encryption.StateEncryptionDisabled())
+			if got := s.StoredStateVersion(); got != statemgr.UnknownStoredVersion {
+				t.Fatalf("expected an unknown version before PersistState, got %d", got)
+			}
+
+			if err := s.WriteState(states.NewState()); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if err := s.PersistState(nil); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := s.StoredStateVersion(); got != tt.want {
+				t.Errorf("StoredStateVersion() = %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
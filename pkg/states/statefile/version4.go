@@ -180,6 +180,7 @@ func prepareStateV4(sV4 *stateV4) (*File, tfdiags.Diagnostics) {
 			obj := &states.ResourceInstanceObjectSrc{
 				SchemaVersion:       isV4.SchemaVersion,
 				CreateBeforeDestroy: isV4.CreateBeforeDestroy,
+				NeedsRefresh:        isV4.NeedsRefresh,
 			}
 
 			{
@@ -578,6 +579,7 @@ func appendInstanceObjectStateV4(rs *states.Resource, is *states.ResourceInstanc
 		PrivateRaw:              privateRaw,
 		Dependencies:            deps,
 		CreateBeforeDestroy:     obj.CreateBeforeDestroy,
+		NeedsRefresh:            obj.NeedsRefresh,
 	}), diags
 }
 
@@ -794,6 +796,13 @@ type instanceObjectStateV4 struct {
 	Dependencies []string `json:"dependencies,omitempty"`
 
 	CreateBeforeDestroy bool `json:"create_before_destroy,omitempty"`
+
+	// NeedsRefresh records states.ResourceInstanceObjectSrc.NeedsRefresh, so
+	// that a later run still knows the object was never read back from its
+	// provider. It's omitted unless set, so existing state files don't
+	// change, and earlier versions, which ignore unknown properties, just
+	// drop it when they write the state again.
+	NeedsRefresh bool `json:"needs_refresh,omitempty"`
 }
 
 type checkResultsV4 struct {
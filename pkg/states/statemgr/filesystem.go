@@ -13,6 +13,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 	"time"
 
@@ -69,6 +70,7 @@ var (
 	_ Full           = (*Filesystem)(nil)
 	_ PersistentMeta = (*Filesystem)(nil)
 	_ Migrator       = (*Filesystem)(nil)
+	_ Renamer        = (*Filesystem)(nil)
 )
 
 // NewFilesystem creates a filesystem-based state manager that reads and writes
@@ -409,6 +411,20 @@ func (s *Filesystem) Unlock(id string) error {
 	return unlockErr
 }
 
+// LockStatus returns the lock information written alongside the state by the
+// process holding the lock, or nil if there is none. The lock information of a
+// process which crashed without unlocking is left behind, so such a lock is
+// still reported.
+//
+// This is an implementation of LockReporter.
+func (s *Filesystem) LockStatus() (*LockInfo, error) {
+	info, err := s.lockInfo()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return info, err
+}
+
 // StateSnapshotMeta returns the metadata from the most recently persisted
 // or refreshed persistent state snapshot.
 //
@@ -426,6 +442,32 @@ func (s *Filesystem) StateSnapshotMeta() SnapshotMeta {
 	}
 }
 
+// StateLastModified returns the modification time of the state file.
+//
+// This is an implementation of LastModifiedReporter.
+func (s *Filesystem) StateLastModified() (time.Time, bool, error) {
+	// Until the first snapshot is written, the latest snapshot is the one at
+	// readPath, just as in refreshState.
+	path := s.path
+	if s.stateFileOut == nil {
+		path = s.readPath
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if info.Size() == 0 {
+		// An empty file is created when the state is locked before any
+		// snapshot was written, so it doesn't count as a snapshot.
+		return time.Time{}, false, nil
+	}
+	return info.ModTime(), true, nil
+}
+
 // StateForMigration is part of our implementation of Migrator.
 func (s *Filesystem) StateForMigration() *statefile.File {
 	return s.file.DeepCopy()
@@ -472,6 +514,46 @@ func (s *Filesystem) WriteStateForMigration(f *statefile.File, force bool) error
 	return s.persistState(nil)
 }
 
+// RenameTo is our implementation of Renamer. If dst is also a Filesystem
+// manager reading from and writing to a single path, the state file is
+// moved with os.Rename, which replaces the destination file atomically.
+//
+// Windows doesn't allow renaming the state file while the lock holds it
+// open, so there the caller falls back to copying the state instead.
+func (s *Filesystem) RenameTo(dst Full) (bool, error) {
+	d, ok := dst.(*Filesystem)
+	if !ok || runtime.GOOS == "windows" {
+		return false, nil
+	}
+	if s.readPath != s.path || d.readPath != d.path {
+		return false, nil
+	}
+	if s.path == d.path {
+		return false, fmt.Errorf("cannot rename the state at %s to itself", s.path)
+	}
+
+	defer s.mutex()()
+	defer d.mutex()()
+
+	if s.lockID == "" || d.lockID == "" {
+		return false, fmt.Errorf("both states must be locked to rename %s to %s", s.path, d.path)
+	}
+
+	log.Printf("[TRACE] statemgr.Filesystem: renaming %s to %s", s.path, d.path)
+	if err := os.MkdirAll(filepath.Dir(d.path), 0755); err != nil {
+		return false, err
+	}
+	if err := os.Rename(s.path, d.path); err != nil {
+		return false, fmt.Errorf("failed to rename %s to %s: %w", s.path, d.path, err)
+	}
+
+	// The destination manager's open file is the one that was replaced, so
+	// update its in-memory snapshot to match what is now stored at its path.
+	d.file = s.file.DeepCopy()
+	d.readFile = s.readFile.DeepCopy()
+	return true, nil
+}
+
 // Open the state file, creating the directories and file as needed.
 func (s *Filesystem) createStateFiles() error {
 	log.Printf("[TRACE] statemgr.Filesystem: preparing to manage state snapshots at %s", s.path)
@@ -76,6 +76,14 @@ type OptionalLocker interface {
 	IsLockingEnabled() bool
 }
 
+// LockReporter is an optional extension to Locker for state managers that
+// can report whether the state is locked without trying to acquire the lock.
+type LockReporter interface {
+	// LockStatus returns the information about the lock currently held on
+	// the state, or nil if the state isn't locked.
+	LockStatus() (*LockInfo, error)
+}
+
 // test hook to verify that LockWithContext has attempted a lock
 var postLockHook func()
 
@@ -6,6 +6,9 @@
 package statemgr
 
 import (
+	"errors"
+	"time"
+
 	version "github.com/hashicorp/go-version"
 
 	"github.com/we-dcode/opentofu/pkg/states"
@@ -86,6 +89,11 @@ type Persister interface {
 	PersistState(*tofu.Schemas) error
 }
 
+// ErrStateChanged is returned, possibly wrapped, by PersistState when the
+// persistent snapshot was changed by another process since it was last read,
+// and the new snapshot was not written to avoid destroying those changes.
+var ErrStateChanged = errors.New("state changed since last read")
+
 // PersistentMeta is an optional extension to Persistent that allows inspecting
 // the metadata associated with the snapshot that was most recently either
 // read by RefreshState or written by PersistState.
@@ -100,6 +108,29 @@ type PersistentMeta interface {
 	StateSnapshotMeta() SnapshotMeta
 }
 
+// LastModifiedReporter is an optional extension to Persistent for managers
+// whose storage records when the latest persistent snapshot was written.
+type LastModifiedReporter interface {
+	// StateLastModified returns the time at which the latest persistent
+	// snapshot was written to storage. The boolean result is false if there
+	// is no persistent snapshot yet.
+	StateLastModified() (time.Time, bool, error)
+}
+
+// UnknownStoredVersion is returned by StoredVersionReporter.StoredStateVersion
+// if the storage didn't report the version of the latest written snapshot.
+const UnknownStoredVersion int64 = -1
+
+// StoredVersionReporter is an optional extension to Persistent for managers
+// whose storage assigns its own version number to each persistent snapshot,
+// such as the serial of a state version in a remote backend.
+type StoredVersionReporter interface {
+	// StoredStateVersion returns the version the storage assigned to the
+	// snapshot most recently written by PersistState, or UnknownStoredVersion
+	// if no snapshot was written yet or the storage doesn't report it.
+	StoredStateVersion() int64
+}
+
 // SnapshotMeta contains metadata about a persisted state snapshot.
 //
 // This metadata is usually (but not necessarily) included as part of the
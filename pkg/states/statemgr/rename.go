@@ -0,0 +1,121 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	multierror "github.com/hashicorp/go-multierror"
+
+	"github.com/we-dcode/opentofu/pkg/states/statefile"
+)
+
+// Renamer is an optional interface implemented by state managers that are
+// capable of moving their persisted snapshot to the location of another
+// manager natively, without copying it.
+//
+// This interface is used when available by function Rename. See that
+// function for more information on how it is used.
+type Renamer interface {
+	// RenameTo atomically moves the persisted snapshot of the receiver to
+	// the location managed by dst, replacing whatever is stored there.
+	//
+	// If the receiver can't natively move its snapshot to dst, for example
+	// because dst is a different kind of manager, RenameTo must return
+	// false without changing anything so that the caller can fall back to
+	// copying the snapshot instead.
+	//
+	// The caller holds a lock on both managers for the duration of the call.
+	RenameTo(dst Full) (bool, error)
+}
+
+// Rename moves the state snapshot stored by src to dst, for example when
+// renaming a workspace. dst must not already contain a non-empty state.
+//
+// Both managers are locked for the duration of the call. If src implements
+// the optional interface Renamer and it is able to move its snapshot to dst
+// then that move is used, and deleteSrc is not called.
+//
+// Otherwise the snapshot is copied to dst as with Migrate and persisted.
+// The copy is read back from dst and compared with the source, and only if
+// it matches is deleteSrc called to remove the source. If any step fails
+// then src is left in place. deleteSrc is called while the locks are still
+// held, so it must not try to lock src itself. It may be nil, in which case
+// src is left in place even after a successful copy.
+//
+// After a successful call, both managers should be discarded, and a new
+// manager should be created for dst to access the moved snapshot.
+func Rename(dst, src Full, deleteSrc func() error) (err error) {
+	info := NewLockInfo()
+	info.Operation = "rename"
+
+	srcID, err := src.Lock(info)
+	if err != nil {
+		return fmt.Errorf("failed to lock the source state: %w", err)
+	}
+	defer func() {
+		if unlockErr := src.Unlock(srcID); unlockErr != nil {
+			err = multierror.Append(err, fmt.Errorf("failed to unlock the source state: %w", unlockErr))
+		}
+	}()
+
+	dstID, err := dst.Lock(info)
+	if err != nil {
+		return fmt.Errorf("failed to lock the destination state: %w", err)
+	}
+	defer func() {
+		if unlockErr := dst.Unlock(dstID); unlockErr != nil {
+			err = multierror.Append(err, fmt.Errorf("failed to unlock the destination state: %w", unlockErr))
+		}
+	}()
+
+	if err := src.RefreshState(); err != nil {
+		return fmt.Errorf("failed to read the source state: %w", err)
+	}
+	if err := dst.RefreshState(); err != nil {
+		return fmt.Errorf("failed to read the destination state: %w", err)
+	}
+	if !dst.State().Empty() {
+		return errors.New("the destination already contains a non-empty state")
+	}
+
+	if r, ok := src.(Renamer); ok {
+		renamed, err := r.RenameTo(dst)
+		if err != nil {
+			return err
+		}
+		if renamed {
+			return nil
+		}
+	}
+
+	log.Printf("[TRACE] statemgr.Rename: no native move available, copying the state instead")
+	if err := Migrate(dst, src); err != nil {
+		return fmt.Errorf("failed to copy the state: %w", err)
+	}
+	if err := dst.PersistState(nil); err != nil {
+		return fmt.Errorf("failed to persist the copied state: %w", err)
+	}
+
+	// Read the copy back before removing anything, so that the source is
+	// never deleted unless the destination is known to hold the same state.
+	if err := dst.RefreshState(); err != nil {
+		return fmt.Errorf("failed to read back the copied state: %w", err)
+	}
+	if !statefile.StatesMarshalEqual(dst.State(), src.State()) {
+		return errors.New("the copied state doesn't match the source state; the source state was left in place")
+	}
+
+	if deleteSrc == nil {
+		return nil
+	}
+	if err := deleteSrc(); err != nil {
+		return fmt.Errorf("the state was copied, but the source could not be removed: %w", err)
+	}
+	return nil
+}
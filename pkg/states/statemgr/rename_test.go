@@ -0,0 +1,145 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statemgr
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/we-dcode/opentofu/pkg/encryption"
+	"github.com/we-dcode/opentofu/pkg/states/statefile"
+)
+
+func TestRename_filesystem(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the state file can't be renamed natively while it is locked on Windows")
+	}
+	defer testOverrideVersion(t, "1.2.3")()
+	src := testFilesystem(t)
+	defer os.Remove(src.path)
+	dstPath := filepath.Join(t.TempDir(), "renamed", "terraform.tfstate")
+	dst := NewFilesystem(dstPath, encryption.StateEncryptionDisabled())
+
+	err := Rename(dst, src, func() error {
+		t.Fatal("the source must not be deleted separately when it was renamed")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(src.path); !os.IsNotExist(err) {
+		t.Fatalf("expected the source state to be gone, got: %v", err)
+	}
+	for _, path := range []string{src.lockInfoPath(), dst.lockInfoPath()} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected lock info %s to be removed, got: %v", path, err)
+		}
+	}
+
+	renamed := NewFilesystem(dstPath, encryption.StateEncryptionDisabled())
+	if err := renamed.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := renamed.StateSnapshotMeta().Lineage, "test-lineage"; got != want {
+		t.Fatalf("wrong lineage %q; want %q", got, want)
+	}
+	if !statefile.StatesMarshalEqual(renamed.State(), TestFullInitialState()) {
+		t.Fatalf("wrong renamed state\n%s", renamed.State())
+	}
+}
+
+func TestRename_copy(t *testing.T) {
+	defer testOverrideVersion(t, "1.2.3")()
+	fs := testFilesystem(t)
+	defer os.Remove(fs.path)
+	dstPath := filepath.Join(t.TempDir(), "terraform.tfstate")
+
+	// Hide the Renamer implementation, so that the state must be copied.
+	src := renameTestCopyOnly{fs}
+	dst := renameTestCopyOnly{NewFilesystem(dstPath, encryption.StateEncryptionDisabled())}
+
+	deleted := false
+	err := Rename(dst, src, func() error {
+		// The copy must be complete before the source is removed.
+		copied := NewFilesystem(dstPath, encryption.StateEncryptionDisabled())
+		if err := copied.RefreshState(); err != nil {
+			t.Fatal(err)
+		}
+		if !statefile.StatesMarshalEqual(copied.State(), TestFullInitialState()) {
+			t.Fatalf("source deleted before the state was copied\n%s", copied.State())
+		}
+		deleted = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Fatal("expected the source to be deleted")
+	}
+}
+
+func TestRename_destinationNotEmpty(t *testing.T) {
+	defer testOverrideVersion(t, "1.2.3")()
+	src := testFilesystem(t)
+	defer os.Remove(src.path)
+	dst := testFilesystem(t)
+	defer os.Remove(dst.path)
+
+	err := Rename(dst, src, nil)
+	if err == nil || !strings.Contains(err.Error(), "already contains a non-empty state") {
+		t.Fatalf("expected an error about the destination state, got: %v", err)
+	}
+	if _, err := os.Stat(src.path); err != nil {
+		t.Fatalf("expected the source state to be left in place: %s", err)
+	}
+}
+
+func TestRename_locked(t *testing.T) {
+	defer testOverrideVersion(t, "1.2.3")()
+	src := testFilesystem(t)
+	defer os.Remove(src.path)
+	dstPath := filepath.Join(t.TempDir(), "terraform.tfstate")
+
+	info := NewLockInfo()
+	info.Operation = "test"
+	lockID, err := src.Lock(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := src.Unlock(lockID); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Locks are per process, so the rename must be attempted by another one.
+	out, err := exec.Command("go", "run", "testdata/renamestate.go", src.path, dstPath).CombinedOutput()
+	if err != nil {
+		t.Fatal("unexpected rename failure", err, string(out))
+	}
+	if !strings.Contains(string(out), "rename failed") {
+		t.Fatal("expected 'rename failed', got", string(out))
+	}
+
+	if _, err := os.Stat(src.path); err != nil {
+		t.Fatalf("expected the source state to be left in place: %s", err)
+	}
+	if _, err := os.Stat(dstPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no destination state, got: %v", err)
+	}
+}
+
+// renameTestCopyOnly wraps a Full state manager, hiding any optional
+// interfaces it implements.
+type renameTestCopyOnly struct {
+	Full
+}
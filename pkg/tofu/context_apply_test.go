@@ -4370,6 +4370,59 @@ func TestContext2Apply_Provisioner_compute(t *testing.T) {
 	}
 }
 
+func TestContext2Apply_provisionerProgress(t *testing.T) {
+	m := testModule(t, "apply-provisioner-compute")
+	p := testProvider("aws")
+	pr := testProvisioner()
+	p.PlanResourceChangeFn = testDiffFn
+	p.ApplyResourceChangeFn = testApplyFn
+	pr.ProvisionResourceFn = func(req provisioners.ProvisionResourceRequest) (resp provisioners.ProvisionResourceResponse) {
+		progress, ok := req.UIOutput.(provisioners.ProgressOutput)
+		if !ok {
+			t.Fatalf("UIOutput does not support progress")
+		}
+		progress.Progress("installing", 50)
+		return
+	}
+	h := new(MockHook)
+	ctx := testContext2(t, &ContextOpts{
+		Hooks: []Hook{h},
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+		Provisioners: map[string]provisioners.Factory{
+			"shell": testProvisionerFuncFixed(pr),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+		Mode: plans.NormalMode,
+		SetVariables: InputValues{
+			"value": &InputValue{
+				Value:      cty.NumberIntVal(1),
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	_, diags = ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	if !h.ProvisionProgressCalled {
+		t.Fatalf("ProvisionProgress hook not called")
+	}
+	if got, want := h.ProvisionProgressPhase, "installing"; got != want {
+		t.Errorf("wrong phase %q; want %q", got, want)
+	}
+	if got, want := h.ProvisionProgressPercent, 50.0; got != want {
+		t.Errorf("wrong percent %v; want %v", got, want)
+	}
+	if h.ProvisionOutputCalled {
+		t.Errorf("ProvisionOutput hook called for a progress report")
+	}
+}
+
 func TestContext2Apply_provisionerCreateFail(t *testing.T) {
 	m := testModule(t, "apply-provisioner-fail-create")
 	p := testProvider("aws")
@@ -8,12 +8,14 @@ package tofu
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/we-dcode/opentofu/pkg/addrs"
 	"github.com/we-dcode/opentofu/pkg/configs"
+	"github.com/we-dcode/opentofu/pkg/genconfig"
 	"github.com/we-dcode/opentofu/pkg/instances"
 	"github.com/we-dcode/opentofu/pkg/states"
 	"github.com/we-dcode/opentofu/pkg/tfdiags"
@@ -27,6 +29,49 @@ type ImportOpts struct {
 	// SetVariables are the variables set outside of the configuration,
 	// such as on the command line, in variables files, etc.
 	SetVariables InputValues
+
+	// GenerateConfigOut tells OpenTofu where to write generated configuration
+	// for command line import targets that have no resource block in the
+	// configuration. If empty, every target must already exist in the
+	// configuration.
+	GenerateConfigOut string
+
+	// TargetsFile is the path to an HCL or JSON file describing additional
+	// targets to import, in the format accepted by LoadImportTargetsFile.
+	// Targets loaded from the file are imported along with Targets.
+	TargetsFile string
+
+	// DryRun requests a preview of the import. The provider is still asked
+	// to import and read each target, and the returned diagnostics report
+	// any problems such as collisions with existing objects, but the
+	// returned state is the previous run state, unchanged, and no generated
+	// configuration is written. The objects that would have been stored
+	// are reported in the Objects of the results of ImportWithResults.
+	DryRun bool
+
+	// SkipRefresh stores the objects exactly as returned by the provider's
+	// import, without reading them back from the provider first. The stored
+	// objects are flagged as needing a refresh, which is saved with the state,
+	// so the next refreshing plan takes care of them and a plan that doesn't
+	// refresh warns about them.
+	SkipRefresh bool
+
+	// AllowDataSources permits command line targets addressing data
+	// resources, which are otherwise rejected. Nothing is imported for such
+	// a target: the data source is read using its configuration and the
+	// result is stored as the object of the data resource instance, for
+	// example to cache the result or to seed the state for a test. The ID of
+	// the target is not used.
+	AllowDataSources bool
+
+	// SortImportedResources sorts the additional objects the provider
+	// returns for a target by their "id" attribute before assigning them
+	// addresses. The first object, which gets the target address, keeps its
+	// place. When a provider returns several objects of the same type, the
+	// ones after the first get addresses with a numeric suffix, and sorting
+	// makes these addresses independent of the order of the provider's
+	// response.
+	SortImportedResources bool
 }
 
 // CommandLineImportTarget is a target that we need to import, that originated from the CLI command
@@ -229,6 +274,138 @@ func (ri *ImportResolver) GetImport(address addrs.AbsResourceInstance) *Evaluate
 	return nil
 }
 
+// ImportTargetResult describes the outcome of importing a single import
+// target.
+type ImportTargetResult struct {
+	// Addr is the address the target was imported into. It is the zero value
+	// for targets originating from import blocks, whose address is only
+	// resolved during the walk.
+	Addr addrs.AbsResourceInstance
+
+	// ImportedTypes are the resource types of the objects the provider
+	// returned for the target. A type is empty if the provider did not set it.
+	ImportedTypes []string
+
+	// Diagnostics are the diagnostics reported while importing the target.
+	Diagnostics tfdiags.Diagnostics
+
+	// Success is true if every object returned by the provider for the target
+	// was written to the state, or would have been with ImportOpts.DryRun.
+	Success bool
+
+	// Objects are the objects imported for the target, in the order they
+	// were written to the state. With ImportOpts.DryRun they are the objects
+	// that would have been written.
+	Objects []ImportedObject
+
+	// written counts the objects of the target written to the state, and
+	// handled counts the objects OpenTofu has finished with, successfully or
+	// not. writtenAddrs are the addresses the written objects were stored at.
+	written      int
+	handled      int
+	writtenAddrs []addrs.AbsResourceInstance
+
+	// done is set once the target has been reported as finished.
+	done bool
+}
+
+// ImportedObject is an object imported for an import target, along with the
+// address it was stored at.
+type ImportedObject struct {
+	Addr   addrs.AbsResourceInstance
+	Object *states.ResourceInstanceObjectSrc
+}
+
+func (r *ImportTargetResult) succeeded() bool {
+	return !r.Diagnostics.HasErrors() && len(r.ImportedTypes) > 0 && r.written == len(r.ImportedTypes)
+}
+
+// importResultTracker collects the results of the command line import
+// targets as the import graph is walked. A nil tracker ignores all records.
+type importResultTracker struct {
+	mu      sync.Mutex
+	results map[string]*ImportTargetResult
+
+	// total is the number of command line import targets, and completed the
+	// number of them finished so far. They are used to report the progress
+	// of the import to the hooks.
+	total     int
+	completed int
+}
+
+func newImportResultTracker(total int) *importResultTracker {
+	return &importResultTracker{
+		results: make(map[string]*ImportTargetResult),
+		total:   total,
+	}
+}
+
+// record calls fn with the result for the given import target address,
+// creating the result if needed.
+func (t *importResultTracker) record(addr addrs.AbsResourceInstance, fn func(r *ImportTargetResult)) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.results[addr.String()]
+	if !ok {
+		r = &ImportTargetResult{Addr: addr}
+		t.results[addr.String()] = r
+	}
+	fn(r)
+}
+
+// finish marks the given import target as finished, unless it already is,
+// and reports the progress of the import to the hooks.
+func (t *importResultTracker) finish(ctx EvalContext, addr addrs.AbsResourceInstance) {
+	if t == nil {
+		return
+	}
+
+	// The hooks are called with the lock held, so that they see the
+	// progress in order even though targets finish concurrently.
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.results[addr.String()]
+	if !ok || r.done {
+		return
+	}
+	r.done = true
+	t.completed++
+	result := *r
+	result.Success = r.succeeded()
+	ctx.Hook(func(h Hook) (HookAction, error) {
+		h.ImportProgress(result, t.completed, t.total)
+		return HookActionContinue, nil
+	})
+}
+
+// resultsFor returns a result for each of the given targets, in the same
+// order. Targets that were never reached during the walk are reported as
+// unsuccessful.
+func (t *importResultTracker) resultsFor(targets []*ImportTarget) []ImportTargetResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ret := make([]ImportTargetResult, len(targets))
+	for i, target := range targets {
+		if !target.IsFromImportCommandLine() {
+			continue
+		}
+		ret[i].Addr = target.CommandLineImportTarget.Addr
+		r, ok := t.results[target.CommandLineImportTarget.Addr.String()]
+		if !ok {
+			continue
+		}
+		ret[i] = *r
+		ret[i].Success = r.succeeded()
+	}
+	return ret
+}
+
 // Import takes already-created external resources and brings them
 // under OpenTofu management. Import requires the exact type, name, and ID
 // of the resources to import.
@@ -239,7 +416,31 @@ func (ri *ImportResolver) GetImport(address addrs.AbsResourceInstance) *Evaluate
 // Further, this operation also gracefully handles partial state. If during
 // an import there is a failure, all previously imported resources remain
 // imported.
+//
+// Import never modifies prevRunState. When opts.DryRun is set the returned
+// state has the same content as prevRunState and no configuration is
+// generated, so the import has no side-effects other than the calls to the
+// providers.
+//
+// Import targets are walked as part of the import graph, so targets that do
+// not depend on each other are imported concurrently, bounded by the
+// Parallelism of the context. Each provider instance is still configured
+// exactly once, before any import that uses it, and the resulting state is
+// independent of the order in which the imports complete.
 func (c *Context) Import(ctx context.Context, config *configs.Config, prevRunState *states.State, opts *ImportOpts) (*states.State, tfdiags.Diagnostics) {
+	state, _, diags := c.ImportWithResults(ctx, config, prevRunState, opts)
+	return state, diags
+}
+
+// ImportWithResults is like Import, but also returns a result for each import
+// target describing whether it was imported, the resource types the provider
+// returned for it and the diagnostics that concern it. The results are in the
+// same order as opts.Targets, followed by the targets loaded from
+// opts.TargetsFile.
+//
+// The returned diagnostics still include all diagnostics, including those
+// that do not concern any single target.
+func (c *Context) ImportWithResults(ctx context.Context, config *configs.Config, prevRunState *states.State, opts *ImportOpts) (*states.State, []ImportTargetResult, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	// Hold a lock since we can modify our own state here
@@ -252,24 +453,61 @@ func (c *Context) Import(ctx context.Context, config *configs.Config, prevRunSta
 
 	variables := opts.SetVariables
 
+	targets := opts.Targets
+	if opts.TargetsFile != "" {
+		fileTargets, fileDiags := LoadImportTargetsFile(opts.TargetsFile)
+		diags = diags.Append(fileDiags)
+		if fileDiags.HasErrors() {
+			return state, nil, diags
+		}
+		targets = append(targets[:len(targets):len(targets)], fileTargets...)
+	}
+
+	total := 0
+	for _, target := range targets {
+		if target.IsFromImportCommandLine() {
+			total++
+		}
+	}
+	results := newImportResultTracker(total)
+
+	diags = diags.Append(validateDataSourceImportTargets(config, targets, opts.AllowDataSources))
+	if diags.HasErrors() {
+		return state, results.resultsFor(targets), diags
+	}
+
+	var generateTargets []*CommandLineImportTarget
+	if genconfig.ShouldWriteConfig(opts.GenerateConfigOut) {
+		diags = diags.Append(genconfig.ValidateTargetFile(opts.GenerateConfigOut))
+		diags = diags.Append(c.validateImportTargets(config, targets, opts.GenerateConfigOut))
+		if diags.HasErrors() {
+			return state, results.resultsFor(targets), diags
+		}
+		generateTargets = commandLineImportTargetsWithoutConfig(config, targets)
+	}
+
 	providerFunctionTracker := make(ProviderFunctionMapping)
 
 	// Initialize our graph builder
 	builder := &PlanGraphBuilder{
-		ImportTargets:           opts.Targets,
+		ImportTargets:           targets,
 		Config:                  config,
 		State:                   state,
 		RootVariableValues:      variables,
 		Plugins:                 c.plugins,
 		Operation:               walkImport,
 		ProviderFunctionTracker: providerFunctionTracker,
+		GenerateConfigPath:      opts.GenerateConfigOut,
+		importResults:           results,
+		skipImportRefresh:       opts.SkipRefresh,
+		sortImportedResources:   opts.SortImportedResources,
 	}
 
 	// Build the graph
 	graph, graphDiags := builder.Build(addrs.RootModuleInstance)
 	diags = diags.Append(graphDiags)
 	if graphDiags.HasErrors() {
-		return state, diags
+		return state, results.resultsFor(targets), diags
 	}
 
 	// Walk it
@@ -280,7 +518,9 @@ func (c *Context) Import(ctx context.Context, config *configs.Config, prevRunSta
 	})
 	diags = diags.Append(walkDiags)
 	if walkDiags.HasErrors() {
-		return state, diags
+		targetResults := results.resultsFor(targets)
+		setImportedObjects(targetResults, walker.State.Close())
+		return state, targetResults, diags
 	}
 
 	// Data sources which could not be read during the import plan will be
@@ -289,5 +529,138 @@ func (c *Context) Import(ctx context.Context, config *configs.Config, prevRunSta
 	walker.State.RemovePlannedResourceInstanceObjects()
 
 	newState := walker.State.Close()
-	return newState, diags
+	targetResults := results.resultsFor(targets)
+	setImportedObjects(targetResults, newState)
+
+	if opts.DryRun {
+		// The imported objects are only reported in the results.
+		return state, targetResults, diags
+	}
+
+	if len(generateTargets) > 0 {
+		diags = diags.Append(c.writeImportGeneratedConfig(newState, generateTargets, opts.GenerateConfigOut))
+	}
+
+	return newState, targetResults, diags
+}
+
+// setImportedObjects sets the Objects of the given results to the objects
+// stored in the given state for their targets.
+func setImportedObjects(results []ImportTargetResult, state *states.State) {
+	for i := range results {
+		for _, addr := range results[i].writtenAddrs {
+			is := state.ResourceInstance(addr)
+			if is == nil || is.Current == nil {
+				continue
+			}
+			results[i].Objects = append(results[i].Objects, ImportedObject{
+				Addr:   addr,
+				Object: is.Current,
+			})
+		}
+	}
+}
+
+// validateDataSourceImportTargets checks the command line import targets
+// addressing data resources. They are only allowed if allow is set, and since
+// the data source is read using its configuration, the data resource must be
+// declared in the configuration.
+func validateDataSourceImportTargets(config *configs.Config, targets []*ImportTarget, allow bool) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, target := range targets {
+		if !target.IsFromImportCommandLine() || target.Addr.Resource.Resource.Mode != addrs.DataResourceMode {
+			continue
+		}
+		if !allow {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot import into a data resource",
+				fmt.Sprintf("%s is a data resource. Only managed resources can be imported.", target.Addr),
+			))
+			continue
+		}
+		if mc := config.Descendent(target.StaticAddr().Module); mc == nil || mc.Module.ResourceByAddr(target.StaticAddr().Resource) == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Data resource not declared in configuration",
+				fmt.Sprintf("The data source for %s is read using its configuration, so it must be declared in a data block before it can be imported.", target.Addr),
+			))
+		}
+	}
+	return diags
+}
+
+// commandLineImportTargetsWithoutConfig returns the command line import
+// targets whose resource has no block in the configuration, and so need
+// configuration generated for them.
+func commandLineImportTargetsWithoutConfig(config *configs.Config, targets []*ImportTarget) []*CommandLineImportTarget {
+	var ret []*CommandLineImportTarget
+	for _, target := range targets {
+		if !target.IsFromImportCommandLine() {
+			continue
+		}
+		if mc := config.Descendent(target.StaticAddr().Module); mc != nil && mc.Module.ResourceByAddr(target.StaticAddr().Resource) != nil {
+			continue
+		}
+		ret = append(ret, target.CommandLineImportTarget)
+	}
+	return ret
+}
+
+// writeImportGeneratedConfig generates resource blocks for the given
+// imported targets from their new state and the provider schema, and writes
+// them to the file at out.
+func (c *Context) writeImportGeneratedConfig(state *states.State, targets []*CommandLineImportTarget, out string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	var writer io.Writer
+
+	for _, target := range targets {
+		rs := state.Resource(target.Addr.ContainingResource())
+		is := state.ResourceInstance(target.Addr)
+		if rs == nil || is == nil || is.Current == nil {
+			// The import failed, which has already been reported.
+			continue
+		}
+
+		schema, _, err := c.plugins.ResourceTypeSchema(rs.ProviderConfig.Provider, target.Addr.Resource.Resource.Mode, target.Addr.Resource.Resource.Type)
+		if err != nil || schema == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot generate configuration for imported resource",
+				fmt.Sprintf("The provider %s does not have a schema for the resource type %q, so OpenTofu cannot generate configuration for %s.", rs.ProviderConfig.Provider, target.Addr.Resource.Resource.Type, target.Addr),
+			))
+			continue
+		}
+
+		obj, err := is.Current.Decode(schema.ImpliedType())
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("failed to decode imported state for %s: %w", target.Addr, err))
+			continue
+		}
+
+		attrs, moreDiags := generateResourceConfigAttributes(target.Addr, obj.Value, schema, rs.ProviderConfig)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			continue
+		}
+
+		change := genconfig.Change{
+			Addr:            target.Addr.String(),
+			ImportID:        target.ID,
+			GeneratedConfig: genconfig.WrapResourceContents(target.Addr, attrs),
+		}
+		writer, _, moreDiags = change.MaybeWriteConfig(writer, out)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			break
+		}
+	}
+
+	if closer, ok := writer.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			diags = diags.Append(fmt.Errorf("failed to close generated configuration file %s: %w", out, err))
+		}
+	}
+
+	return diags
 }
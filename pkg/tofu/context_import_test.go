@@ -6,12 +6,17 @@
 package tofu
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty-debug/ctydebug"
@@ -19,6 +24,7 @@ import (
 
 	"github.com/we-dcode/opentofu/pkg/addrs"
 	"github.com/we-dcode/opentofu/pkg/configs/configschema"
+	"github.com/we-dcode/opentofu/pkg/plans"
 	"github.com/we-dcode/opentofu/pkg/providers"
 	"github.com/we-dcode/opentofu/pkg/states"
 	"github.com/we-dcode/opentofu/pkg/tfdiags"
@@ -66,6 +72,107 @@ func TestContextImport_basic(t *testing.T) {
 	}
 }
 
+func TestContextImport_private(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "import-provider")
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	private := []byte("private-data")
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "aws_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("foo"),
+				}),
+				Private: private,
+			},
+		},
+	}
+
+	addr := addrs.RootModuleInstance.ResourceInstance(
+		addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
+	)
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addr,
+					ID:   "bar",
+				},
+			},
+		},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	// The private data must be passed to the refresh following the import...
+	if !p.ReadResourceCalled {
+		t.Fatal("ReadResource not called")
+	}
+	if got := p.ReadResourceRequest.Private; !bytes.Equal(got, private) {
+		t.Errorf("wrong private data in ReadResource request\ngot:  %q\nwant: %q", got, private)
+	}
+
+	// ...and stored in the resulting state.
+	instance := state.ResourceInstance(addr)
+	if instance == nil || instance.Current == nil {
+		t.Fatalf("no state for %s", addr)
+	}
+	if got := instance.Current.Private; !bytes.Equal(got, private) {
+		t.Errorf("wrong private data in state\ngot:  %q\nwant: %q", got, private)
+	}
+}
+
+func TestContextImport_targetsFile(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "import-provider")
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "aws_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("foo"),
+				}),
+			},
+		},
+	}
+
+	targetsFile := filepath.Join(t.TempDir(), "imports.hcl")
+	src := `
+import {
+  to = aws_instance.foo
+  id = "bar"
+}
+`
+	if err := os.WriteFile(targetsFile, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		TargetsFile: targetsFile,
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	actual := strings.TrimSpace(state.String())
+	expected := strings.TrimSpace(testImportStr)
+	if actual != expected {
+		t.Fatalf("wrong final state\ngot:\n%s\nwant:\n%s", actual, expected)
+	}
+}
+
 // import 1 of count instances in the configuration
 func TestContextImport_countIndex(t *testing.T) {
 	p := testProvider("aws")
@@ -188,6 +295,8 @@ func TestContextImport_multiInstanceProviderConfig(t *testing.T) {
 
 	// Unlike most context tests, this one uses a real factory function so that
 	// we can instantiate multiple instances and distinguish them.
+	var instancesMu sync.Mutex
+	var instances []*MockProvider
 	providerFactory := func() (providers.Interface, error) {
 		// The following uses log.Printf instead of t.Logf so that the logs can interleave with the
 		// verbose trace logs produced by the main logic in this package, to make the order of operations clearer.
@@ -197,6 +306,9 @@ func TestContextImport_multiInstanceProviderConfig(t *testing.T) {
 		ret := &MockProvider{}
 		var configuredMarker cty.Value
 		log.Printf("[TRACE] TestContextImport_multiInstanceProviderConfig: creating new instance of provider 'test' at %p", ret)
+		instancesMu.Lock()
+		instances = append(instances, ret)
+		instancesMu.Unlock()
 
 		ret.GetProviderSchemaResponse = providerSchema
 		ret.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
@@ -299,6 +411,282 @@ func TestContextImport_multiInstanceProviderConfig(t *testing.T) {
 	if diff := cmp.Diff(wantObjState, gotObjState, ctydebug.CmpOptions); diff != "" {
 		t.Error("wrong final object state\n" + diff)
 	}
+
+	// Every provider instance that imported something must have been
+	// configured first.
+	for _, instance := range instances {
+		for _, call := range instance.Calls() {
+			if call.Method == "ImportResourceState" {
+				instance.AssertCallOrder(t, "ConfigureProvider", "ImportResourceState", "ReadResource")
+				break
+			}
+		}
+	}
+}
+
+func TestContextImport_multiInstanceProviderConfigMissingKey(t *testing.T) {
+	// The resource selects a provider instance by a key that the provider
+	// configuration's for_each doesn't have, so the import must fail with an
+	// error that names the available keys.
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			terraform {
+				required_providers {
+					test = {
+						source = "terraform.io/builtin/test"
+					}
+				}
+			}
+
+			provider "test" {
+				alias = "multi"
+				for_each = {
+					a = {}
+					b = {}
+				}
+			}
+
+			resource "test_thing" "test" {
+				for_each = { "foo" = "c" }
+				provider = test.multi[each.value]
+			}
+		`})
+
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.ResourceTypes = map[string]providers.Schema{
+		"test_thing": {
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewBuiltInProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	addr := addrs.RootModuleInstance.ResourceInstance(
+		addrs.ManagedResourceMode, "test_thing", "test", addrs.StringKey("foo"),
+	)
+	_, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addr,
+					ID:   "fake-import-id",
+				},
+			},
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("expected an error importing with a missing provider instance")
+	}
+	if p.ImportResourceStateCalled {
+		t.Fatal("ImportResourceState was called")
+	}
+
+	var found bool
+	for _, diag := range diags {
+		desc := diag.Description()
+		if desc.Summary != "Provider instance not found" {
+			continue
+		}
+		found = true
+		want := `The configuration of test_thing.test["foo"] selects the provider instance provider["terraform.io/builtin/test"].multi["c"], but there is no instance with the key ["c"]. The available instance keys are ["a"], ["b"].`
+		if desc.Detail != want {
+			t.Errorf("wrong error detail\ngot:  %s\nwant: %s", desc.Detail, want)
+		}
+	}
+	if !found {
+		t.Fatalf("missing the provider instance error, got: %s", diags.Err())
+	}
+}
+
+func TestContextImport_forEachStringKeys(t *testing.T) {
+	// Imports into two for_each instances in one call, with each instance
+	// using a different provider instance selected by each.value.
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			terraform {
+				required_providers {
+					test = {
+						source = "terraform.io/builtin/test"
+					}
+				}
+			}
+
+			provider "test" {
+				alias = "multi"
+				for_each = {
+					a = {}
+					b = {}
+				}
+
+				marker = each.key
+			}
+
+			resource "test_thing" "test" {
+				for_each = { "foo" = "a", "bar" = "b" }
+				provider = test.multi[each.value]
+			}
+		`})
+
+	resourceTypeSchema := providers.Schema{
+		Block: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"id": {
+					Type:     cty.String,
+					Computed: true,
+				},
+				"import_marker": {
+					Type:     cty.String,
+					Computed: true,
+				},
+			},
+		},
+	}
+	providerSchema := &providers.GetProviderSchemaResponse{
+		Provider: providers.Schema{
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"marker": {
+						Type:     cty.String,
+						Required: true,
+					},
+				},
+			},
+		},
+		ResourceTypes: map[string]providers.Schema{
+			"test_thing": resourceTypeSchema,
+		},
+	}
+
+	providerFactory := func() (providers.Interface, error) {
+		ret := &MockProvider{}
+		var configuredMarker cty.Value
+		ret.GetProviderSchemaResponse = providerSchema
+		ret.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+			configuredMarker = req.Config.GetAttr("marker")
+			return providers.ConfigureProviderResponse{}
+		}
+		ret.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+			return providers.ImportResourceStateResponse{
+				ImportedResources: []providers.ImportedResource{
+					{
+						TypeName: "test_thing",
+						State: cty.ObjectVal(map[string]cty.Value{
+							"id":            cty.StringVal(req.ID),
+							"import_marker": configuredMarker,
+						}),
+					},
+				},
+			}
+		}
+		return ret, nil
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewBuiltInProvider("test"): providerFactory,
+		},
+	})
+
+	// The addresses use the same syntax as the command line arguments.
+	fooAddr := mustResourceInstanceAddr(`test_thing.test["foo"]`)
+	barAddr := mustResourceInstanceAddr(`test_thing.test["bar"]`)
+	if got, want := fooAddr.Resource.Key, addrs.StringKey("foo"); got != want {
+		t.Fatalf("wrong instance key %#v; want %#v", got, want)
+	}
+
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: fooAddr,
+					ID:   "foo-id",
+				},
+			},
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: barAddr,
+					ID:   "bar-id",
+				},
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	for addr, wantProviderKey := range map[string]addrs.InstanceKey{
+		`test_thing.test["foo"]`: addrs.StringKey("a"),
+		`test_thing.test["bar"]`: addrs.StringKey("b"),
+	} {
+		instAddr := mustResourceInstanceAddr(addr)
+		resourceState := state.Resource(instAddr.ContainingResource())
+		if resourceState == nil {
+			t.Fatalf("no state for %s", instAddr.ContainingResource())
+		}
+		instanceState := resourceState.Instances[instAddr.Resource.Key]
+		if instanceState == nil || instanceState.Current == nil {
+			t.Fatalf("no state for %s", instAddr)
+		}
+		if got := instanceState.ProviderKey; got != wantProviderKey {
+			t.Errorf("wrong provider key for %s %s; want %s", instAddr, got, wantProviderKey)
+		}
+
+		obj, err := instanceState.Current.Decode(resourceTypeSchema.Block.ImpliedType())
+		if err != nil {
+			t.Fatalf("failed to decode %s: %s", instAddr, err)
+		}
+		if got, want := obj.Value.GetAttr("import_marker"), cty.StringVal(string(wantProviderKey.(addrs.StringKey))); !got.RawEquals(want) {
+			t.Errorf("wrong import_marker for %s %#v; want %#v", instAddr, got, want)
+		}
+	}
+}
+
+func TestContextImport_forEachUnknownKey(t *testing.T) {
+	p := testProvider("aws")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "aws_instance" "foo" {
+				for_each = toset(["a", "b"])
+			}
+		`})
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "aws_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("foo"),
+				}),
+			},
+		},
+	}
+
+	_, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: mustResourceInstanceAddr(`aws_instance.foo["c"]`),
+					ID:   "bar",
+				},
+			},
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("expected an error, got none")
+	}
+	if got, want := diags.Err().Error(), `aws_instance.foo["a"]`; !strings.Contains(got, want) {
+		t.Fatalf("expected the error to list %s, got: %s", want, got)
+	}
 }
 
 func TestContextImport_importResourceWithSensitiveDataSource(t *testing.T) {
@@ -445,47 +833,416 @@ func TestContextImport_collision(t *testing.T) {
 	}
 }
 
-func TestContextImport_missingType(t *testing.T) {
+func TestContextImport_dryRun(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "import-provider")
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	var mu sync.Mutex
+	importCalls := 0
+	p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+		mu.Lock()
+		importCalls++
+		mu.Unlock()
+		return providers.ImportResourceStateResponse{
+			ImportedResources: []providers.ImportedResource{
+				{
+					TypeName: "aws_instance",
+					State: cty.ObjectVal(map[string]cty.Value{
+						"id": cty.StringVal("foo"),
+					}),
+				},
+			},
+		}
+	}
+
+	addr := addrs.RootModuleInstance.ResourceInstance(
+		addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
+	)
+	prevRunState := states.NewState()
+	state, results, diags := ctx.ImportWithResults(context.Background(), m, prevRunState, &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addr,
+					ID:   "bar",
+				},
+			},
+		},
+		DryRun: true,
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	if importCalls != 1 {
+		t.Fatalf("expected ImportResourceState to be called once, got %d calls", importCalls)
+	}
+	if !p.ReadResourceCalled {
+		t.Fatal("expected ReadResource to be called")
+	}
+	if !prevRunState.Empty() {
+		t.Fatalf("previous run state was modified:\n%s", prevRunState)
+	}
+	if !state.Empty() {
+		t.Fatalf("expected the previous run state to be returned, got:\n%s", state)
+	}
+
+	// The object that would have been imported is only reported in the
+	// result of the target.
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if !results[0].Success {
+		t.Fatalf("expected the import to succeed, got diagnostics: %s", results[0].Diagnostics.Err())
+	}
+	if len(results[0].Objects) != 1 {
+		t.Fatalf("expected 1 imported object, got %d", len(results[0].Objects))
+	}
+	imported := results[0].Objects[0]
+	if !imported.Addr.Equal(addr) {
+		t.Fatalf("wrong address %s; want %s", imported.Addr, addr)
+	}
+	obj, err := imported.Object.Decode(p.GetProviderSchemaResponse.ResourceTypes["aws_instance"].Block.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := obj.Value.GetAttr("id"), cty.StringVal("foo"); !got.RawEquals(want) {
+		t.Fatalf("wrong imported id %#v; want %#v", got, want)
+	}
+}
+
+func TestContextImport_dryRunCollision(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "import-provider")
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "aws_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsFlat: map[string]string{
+					"id": "bar",
+				},
+				Status: states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("aws"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+
+	var mu sync.Mutex
+	importCalls := 0
+	p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+		mu.Lock()
+		importCalls++
+		mu.Unlock()
+		return providers.ImportResourceStateResponse{
+			ImportedResources: []providers.ImportedResource{
+				{
+					TypeName: "aws_instance",
+					State: cty.ObjectVal(map[string]cty.Value{
+						"id": cty.StringVal("foo"),
+					}),
+				},
+			},
+		}
+	}
+
+	_, diags := ctx.Import(context.Background(), m, state, &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
+					),
+					ID: "bar",
+				},
+			},
+		},
+		DryRun: true,
+	})
+	if !diags.HasErrors() {
+		t.Fatalf("succeeded; want an error indicating that the resource already exists in state")
+	}
+	if got, want := diags.Err().Error(), "Resource already managed by OpenTofu"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+	if importCalls != 1 {
+		t.Fatalf("expected ImportResourceState to be called once, got %d calls", importCalls)
+	}
+
+	actual := strings.TrimSpace(state.String())
+	expected := `aws_instance.foo:
+  ID = bar
+  provider = provider["registry.opentofu.org/hashicorp/aws"]`
+	if actual != expected {
+		t.Fatalf("previous run state was modified:\n%s", actual)
+	}
+}
+
+func TestContextImport_missingType(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "import-provider")
+
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("foo"),
+				}),
+			},
+		},
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
+					),
+					ID: "bar",
+				},
+			},
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("should error")
+	}
+
+	actual := strings.TrimSpace(state.String())
+	expected := "<no state>"
+	if actual != expected {
+		t.Fatalf("bad: \n%s", actual)
+	}
+}
+
+func TestContextImport_results(t *testing.T) {
+	p := testProvider("aws")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "aws" {
+  foo = "bar"
+}
+
+resource "aws_instance" "foo" {
+}
+
+resource "aws_instance" "bar" {
+}
+
+resource "aws_instance" "baz" {
+}
+`})
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	fooAddr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey)
+	barAddr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "aws_instance", "bar", addrs.NoKey)
+	bazAddr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "aws_instance", "baz", addrs.NoKey)
+
+	// aws_instance.foo is already managed, so importing it again collides.
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			fooAddr,
+			&states.ResourceInstanceObjectSrc{
+				AttrsFlat: map[string]string{
+					"id": "foo",
+				},
+				Status: states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("aws"),
+				Module:   addrs.RootModule,
+			},
+			addrs.NoKey,
+		)
+	})
+
+	p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+		typeName := "aws_instance"
+		if req.ID == "bar" {
+			// The provider fails to report the type of the imported object.
+			typeName = ""
+		}
+		return providers.ImportResourceStateResponse{
+			ImportedResources: []providers.ImportedResource{
+				{
+					TypeName: typeName,
+					State: cty.ObjectVal(map[string]cty.Value{
+						"id": cty.StringVal(req.ID),
+					}),
+				},
+			},
+		}
+	}
+
+	_, results, diags := ctx.ImportWithResults(context.Background(), m, state, &ImportOpts{
+		Targets: []*ImportTarget{
+			{CommandLineImportTarget: &CommandLineImportTarget{Addr: fooAddr, ID: "foo"}},
+			{CommandLineImportTarget: &CommandLineImportTarget{Addr: barAddr, ID: "bar"}},
+			{CommandLineImportTarget: &CommandLineImportTarget{Addr: bazAddr, ID: "baz"}},
+		},
+	})
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors for the colliding and the untyped targets")
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	tests := []struct {
+		addr    addrs.AbsResourceInstance
+		types   []string
+		success bool
+		wantErr string
+	}{
+		{fooAddr, []string{"aws_instance"}, false, "Resource already managed by OpenTofu"},
+		{barAddr, []string{""}, false, "didn't set type"},
+		{bazAddr, []string{"aws_instance"}, true, ""},
+	}
+	for i, test := range tests {
+		got := results[i]
+		if !got.Addr.Equal(test.addr) {
+			t.Errorf("result %d: wrong address %s; want %s", i, got.Addr, test.addr)
+		}
+		if diff := cmp.Diff(test.types, got.ImportedTypes); diff != "" {
+			t.Errorf("result %d: wrong imported types\n%s", i, diff)
+		}
+		if got.Success != test.success {
+			t.Errorf("result %d: wrong success %t; want %t", i, got.Success, test.success)
+		}
+		if test.wantErr == "" {
+			if got.Diagnostics.HasErrors() {
+				t.Errorf("result %d: unexpected errors: %s", i, got.Diagnostics.Err())
+			}
+		} else if !got.Diagnostics.HasErrors() || !strings.Contains(got.Diagnostics.Err().Error(), test.wantErr) {
+			t.Errorf("result %d: wrong diagnostics %v; want an error containing %q", i, got.Diagnostics.Err(), test.wantErr)
+		}
+	}
+}
+
+func TestContextImport_progress(t *testing.T) {
 	p := testProvider("aws")
-	m := testModule(t, "import-provider")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "aws" {
+  foo = "bar"
+}
 
-	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
-		ImportedResources: []providers.ImportedResource{
-			{
-				State: cty.ObjectVal(map[string]cty.Value{
-					"id": cty.StringVal("foo"),
-				}),
-			},
-		},
-	}
+resource "aws_instance" "foo" {
+}
+
+resource "aws_instance" "bar" {
+}
 
+resource "aws_instance" "baz" {
+}
+`})
+	hook := &importProgressHook{}
 	ctx := testContext2(t, &ContextOpts{
+		Hooks: []Hook{hook},
 		Providers: map[addrs.Provider]providers.Factory{
 			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
 		},
 	})
 
-	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
-		Targets: []*ImportTarget{
+	fooAddr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey)
+	barAddr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "aws_instance", "bar", addrs.NoKey)
+	bazAddr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "aws_instance", "baz", addrs.NoKey)
+
+	p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+		var resp providers.ImportResourceStateResponse
+		if req.ID == "bar" {
+			resp.Diagnostics = resp.Diagnostics.Append(errors.New("no such object"))
+			return resp
+		}
+		resp.ImportedResources = []providers.ImportedResource{
 			{
-				CommandLineImportTarget: &CommandLineImportTarget{
-					Addr: addrs.RootModuleInstance.ResourceInstance(
-						addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
-					),
-					ID: "bar",
-				},
+				TypeName: "aws_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal(req.ID),
+				}),
 			},
+		}
+		return resp
+	}
+
+	_, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{CommandLineImportTarget: &CommandLineImportTarget{Addr: fooAddr, ID: "foo"}},
+			{CommandLineImportTarget: &CommandLineImportTarget{Addr: barAddr, ID: "bar"}},
+			{CommandLineImportTarget: &CommandLineImportTarget{Addr: bazAddr, ID: "baz"}},
 		},
 	})
 	if !diags.HasErrors() {
-		t.Fatal("should error")
+		t.Fatal("succeeded; want an error for the failing target")
 	}
 
-	actual := strings.TrimSpace(state.String())
-	expected := "<no state>"
-	if actual != expected {
-		t.Fatalf("bad: \n%s", actual)
+	// The targets are imported concurrently, so they can finish in any
+	// order, but each is reported exactly once and the count always grows.
+	if len(hook.calls) != 3 {
+		t.Fatalf("expected 3 progress reports, got %d", len(hook.calls))
+	}
+	gotSuccess := make(map[string]bool)
+	for i, call := range hook.calls {
+		if call.completed != i+1 || call.total != 3 {
+			t.Errorf("report %d: wrong progress %d/%d; want %d/3", i, call.completed, call.total, i+1)
+		}
+		gotSuccess[call.result.Addr.String()] = call.result.Success
+	}
+	wantSuccess := map[string]bool{
+		fooAddr.String(): true,
+		barAddr.String(): false,
+		bazAddr.String(): true,
 	}
+	if diff := cmp.Diff(wantSuccess, gotSuccess); diff != "" {
+		t.Errorf("wrong results\n%s", diff)
+	}
+}
+
+type importProgressCall struct {
+	result           ImportTargetResult
+	completed, total int
+}
+
+// importProgressHook records the calls to ImportProgress.
+type importProgressHook struct {
+	NilHook
+
+	mu    sync.Mutex
+	calls []importProgressCall
+}
+
+func (h *importProgressHook) ImportProgress(result ImportTargetResult, completed, total int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = append(h.calls, importProgressCall{result, completed, total})
 }
 
 func TestContextImport_moduleProvider(t *testing.T) {
@@ -795,6 +1552,235 @@ data "aws_data_source" "bar" {
 	}
 }
 
+func TestContextImport_skipRefresh(t *testing.T) {
+	p := testProvider("aws")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "aws" {
+  foo = "bar"
+}
+
+resource "aws_instance" "foo" {
+}
+`})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "aws_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id":  cty.StringVal("foo"),
+					"foo": cty.StringVal("imported"),
+				}),
+			},
+		},
+	}
+
+	p.ReadResourceFn = func(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+		t.Errorf("ReadResource must not be called when the refresh is skipped")
+		return providers.ReadResourceResponse{NewState: req.PriorState}
+	}
+
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
+					),
+					ID: "bar",
+				},
+			},
+		},
+		SkipRefresh: true,
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if p.ReadResourceCalled {
+		t.Fatal("ReadResource was called")
+	}
+
+	ri := state.ResourceInstance(mustResourceInstanceAddr("aws_instance.foo"))
+	if ri == nil || ri.Current == nil {
+		t.Fatal("no state is recorded for resource instance aws_instance.foo")
+	}
+	obj, err := ri.Current.Decode(p.GetProviderSchemaResponse.ResourceTypes["aws_instance"].Block.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := obj.Value.GetAttr("foo"), cty.StringVal("imported"); !got.RawEquals(want) {
+		t.Fatalf("wrong foo attribute %#v; want %#v", got, want)
+	}
+	if !ri.Current.NeedsRefresh {
+		t.Fatal("expected the imported object to be flagged as needing a refresh")
+	}
+
+	// A plan without refreshing warns about the object, since it only has
+	// what the import returned.
+	_, diags = ctx.Plan(context.Background(), m, state, &PlanOpts{
+		Mode:        plans.NormalMode,
+		SkipRefresh: true,
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if len(diags) != 1 || diags[0].Description().Summary != "Resource instance was never refreshed" {
+		t.Fatalf("expected a warning about the unrefreshed object, got: %s", diags.ErrWithWarnings())
+	}
+
+	// A refreshing plan reads the object and clears the flag.
+	p.ReadResourceFn = nil
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if !p.ReadResourceCalled {
+		t.Fatal("ReadResource was not called by the refreshing plan")
+	}
+	ri = plan.PriorState.ResourceInstance(mustResourceInstanceAddr("aws_instance.foo"))
+	if ri == nil || ri.Current == nil {
+		t.Fatal("no prior state for resource instance aws_instance.foo")
+	}
+	if ri.Current.NeedsRefresh {
+		t.Fatal("expected the refresh to clear the flag")
+	}
+}
+
+func TestContextImport_dataSource(t *testing.T) {
+	p := testProvider("aws")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "aws" {
+  foo = "bar"
+}
+
+data "aws_data_source" "foo" {
+  foo = "requested"
+}
+`})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+		if got, want := req.Config.GetAttr("foo"), cty.StringVal("requested"); !got.RawEquals(want) {
+			t.Errorf("wrong foo in the data source config %#v; want %#v", got, want)
+		}
+		return providers.ReadDataSourceResponse{
+			State: cty.ObjectVal(map[string]cty.Value{
+				"id":  cty.StringVal("read"),
+				"foo": cty.StringVal("requested"),
+			}),
+		}
+	}
+
+	addr := addrs.RootModuleInstance.ResourceInstance(
+		addrs.DataResourceMode, "aws_data_source", "foo", addrs.NoKey,
+	)
+	targets := []*ImportTarget{
+		{
+			CommandLineImportTarget: &CommandLineImportTarget{
+				Addr: addr,
+				ID:   "unused",
+			},
+		},
+	}
+
+	// Data resource targets are rejected unless explicitly allowed.
+	_, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: targets,
+	})
+	if !diags.HasErrors() {
+		t.Fatal("expected an error importing a data resource")
+	}
+	if got, want := diags.Err().Error(), "Cannot import into a data resource"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+	if p.ReadDataSourceCalled {
+		t.Fatal("ReadDataSource was called for a rejected target")
+	}
+
+	state, results, diags := ctx.ImportWithResults(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets:          targets,
+		AllowDataSources: true,
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if p.ImportResourceStateCalled {
+		t.Fatal("ImportResourceState was called for a data resource")
+	}
+	if p.ReadResourceCalled {
+		t.Fatal("ReadResource was called for a data resource")
+	}
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("expected the target to succeed, got: %#v", results)
+	}
+
+	ri := state.ResourceInstance(addr)
+	if ri == nil || ri.Current == nil {
+		t.Fatalf("no state is recorded for resource instance %s", addr)
+	}
+	if ri.Current.Status != states.ObjectReady {
+		t.Fatalf("wrong object status %s; want %s", ri.Current.Status, states.ObjectReady)
+	}
+	obj, err := ri.Current.Decode(p.GetProviderSchemaResponse.DataSources["aws_data_source"].Block.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.StringVal("read"),
+		"foo": cty.StringVal("requested"),
+	})
+	if !obj.Value.RawEquals(want) {
+		t.Fatalf("wrong stored object\ngot:  %#v\nwant: %#v", obj.Value, want)
+	}
+	if rs := state.Resource(addr.ContainingResource()); rs.Addr.Resource.Mode != addrs.DataResourceMode {
+		t.Fatalf("stored as a %s resource; want a data resource", rs.Addr.Resource.Mode)
+	}
+}
+
+func TestContextImport_dataSourceWithoutConfig(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "import-provider")
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.DataResourceMode, "aws_data_source", "missing", addrs.NoKey,
+					),
+					ID: "unused",
+				},
+			},
+		},
+		AllowDataSources: true,
+	})
+	if !diags.HasErrors() {
+		t.Fatal("expected an error importing a data resource without configuration")
+	}
+	if got, want := diags.Err().Error(), "Data resource not declared in configuration"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
 func TestContextImport_refreshNil(t *testing.T) {
 	p := testProvider("aws")
 	m := testModule(t, "import-provider")
@@ -954,10 +1940,80 @@ func TestContextImport_moduleDiff(t *testing.T) {
 		Targets: []*ImportTarget{
 			{
 				CommandLineImportTarget: &CommandLineImportTarget{
-					Addr: addrs.RootModuleInstance.Child("child", addrs.IntKey(0)).ResourceInstance(
+					Addr: addrs.RootModuleInstance.Child("child", addrs.IntKey(0)).ResourceInstance(
+						addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
+					),
+					ID: "baz",
+				},
+			},
+		},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	actual := strings.TrimSpace(state.String())
+	expected := strings.TrimSpace(testImportModuleStr)
+	if actual != expected {
+		t.Fatalf("\nexpected: %q\ngot:      %q\n", expected, actual)
+	}
+}
+
+func TestContextImport_multiState(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "import-provider")
+
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		Provider: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"foo": {Type: cty.String, Optional: true},
+			},
+		},
+		ResourceTypes: map[string]*configschema.Block{
+			"aws_instance": {
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+			"aws_instance_thing": {
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+	})
+
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "aws_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("foo"),
+				}),
+			},
+			{
+				TypeName: "aws_instance_thing",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("bar"),
+				}),
+			},
+		},
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
 						addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
 					),
-					ID: "baz",
+					ID: "bar",
 				},
 			},
 		},
@@ -967,13 +2023,13 @@ func TestContextImport_moduleDiff(t *testing.T) {
 	}
 
 	actual := strings.TrimSpace(state.String())
-	expected := strings.TrimSpace(testImportModuleStr)
+	expected := strings.TrimSpace(testImportMultiStr)
 	if actual != expected {
-		t.Fatalf("\nexpected: %q\ngot:      %q\n", expected, actual)
+		t.Fatalf("bad: \n%s", actual)
 	}
 }
 
-func TestContextImport_multiState(t *testing.T) {
+func TestContextImport_multiStateSame(t *testing.T) {
 	p := testProvider("aws")
 	m := testModule(t, "import-provider")
 
@@ -1011,6 +2067,12 @@ func TestContextImport_multiState(t *testing.T) {
 					"id": cty.StringVal("bar"),
 				}),
 			},
+			{
+				TypeName: "aws_instance_thing",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("qux"),
+				}),
+			},
 		},
 	}
 
@@ -1037,13 +2099,13 @@ func TestContextImport_multiState(t *testing.T) {
 	}
 
 	actual := strings.TrimSpace(state.String())
-	expected := strings.TrimSpace(testImportMultiStr)
+	expected := strings.TrimSpace(testImportMultiSameStr)
 	if actual != expected {
 		t.Fatalf("bad: \n%s", actual)
 	}
 }
 
-func TestContextImport_multiStateSame(t *testing.T) {
+func TestContextImport_multiStateSameSorted(t *testing.T) {
 	p := testProvider("aws")
 	m := testModule(t, "import-provider")
 
@@ -1067,6 +2129,10 @@ func TestContextImport_multiStateSame(t *testing.T) {
 		},
 	})
 
+	// The additional objects are not in the order of their IDs, so without
+	// sorting "zed" would get the unsuffixed address. The first object is
+	// the one that was asked for, so it keeps the target address although
+	// "bar" sorts before it.
 	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
 		ImportedResources: []providers.ImportedResource{
 			{
@@ -1077,6 +2143,12 @@ func TestContextImport_multiStateSame(t *testing.T) {
 			},
 			{
 				TypeName: "aws_instance_thing",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id": cty.StringVal("zed"),
+				}),
+			},
+			{
+				TypeName: "aws_instance",
 				State: cty.ObjectVal(map[string]cty.Value{
 					"id": cty.StringVal("bar"),
 				}),
@@ -1084,7 +2156,7 @@ func TestContextImport_multiStateSame(t *testing.T) {
 			{
 				TypeName: "aws_instance_thing",
 				State: cty.ObjectVal(map[string]cty.Value{
-					"id": cty.StringVal("qux"),
+					"id": cty.StringVal("abc"),
 				}),
 			},
 		},
@@ -1107,15 +2179,16 @@ func TestContextImport_multiStateSame(t *testing.T) {
 				},
 			},
 		},
+		SortImportedResources: true,
 	})
 	if diags.HasErrors() {
 		t.Fatalf("unexpected errors: %s", diags.Err())
 	}
 
 	actual := strings.TrimSpace(state.String())
-	expected := strings.TrimSpace(testImportMultiSameStr)
-	if actual != expected {
-		t.Fatalf("bad: \n%s", actual)
+	expected := strings.TrimSpace(testImportMultiSameSortedStr)
+	if diff := cmp.Diff(actual, expected); len(diff) > 0 {
+		t.Fatalf("wrong final state\ngot:\n%s\nwant:\n%s\ndiff:\n%s", actual, expected, diff)
 	}
 }
 
@@ -1218,6 +2291,82 @@ resource "test_resource" "unused" {
 	}
 }
 
+func TestContextImport_nullRequiredWarning(t *testing.T) {
+	p := testProvider("aws")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_resource" "test" {
+}
+`,
+	})
+
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_resource": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":       {Type: cty.String, Computed: true},
+					"required": {Type: cty.String, Required: true},
+					"optional": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	})
+
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "test_resource",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id":       cty.StringVal("test"),
+					"required": cty.NullVal(cty.String),
+					"optional": cty.NullVal(cty.String),
+				}),
+			},
+		},
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.ManagedResourceMode, "test_resource", "test", addrs.NoKey,
+					),
+					ID: "test",
+				},
+			},
+		},
+	})
+	if diags.HasErrors() {
+		t.Fatal(diags.ErrWithWarnings())
+	}
+
+	// The object is still imported, the missing argument is only a warning.
+	if ri := state.ResourceInstance(mustResourceInstanceAddr("test_resource.test")); ri == nil || ri.Current == nil {
+		t.Fatal("no state is recorded for resource instance test_resource.test")
+	}
+
+	if len(diags) != 1 {
+		t.Fatalf("expected exactly one warning, got %d: %s", len(diags), diags.ErrWithWarnings())
+	}
+	desc := diags[0].Description()
+	if got, want := diags[0].Severity(), tfdiags.Warning; got != want {
+		t.Fatalf("wrong severity %s; want %s", got, want)
+	}
+	if got, want := desc.Summary, "Imported object is missing required arguments"; got != want {
+		t.Fatalf("wrong summary %q; want %q", got, want)
+	}
+	if !strings.Contains(desc.Detail, `test_resource.test has no value for the required argument(s) "required".`) {
+		t.Fatalf("wrong detail: %s", desc.Detail)
+	}
+}
+
 // New resources in the config during import won't exist for evaluation
 // purposes (until import is upgraded to using a complete plan). This means
 // that references to them are unknown, but in the case of single instances, we
@@ -1402,9 +2551,229 @@ aws_instance_thing.foo-1:
   provider = provider["registry.opentofu.org/hashicorp/aws"]
 `
 
+const testImportMultiSameSortedStr = `
+aws_instance.foo:
+  ID = foo
+  provider = provider["registry.opentofu.org/hashicorp/aws"]
+aws_instance.foo-1:
+  ID = bar
+  provider = provider["registry.opentofu.org/hashicorp/aws"]
+aws_instance_thing.foo:
+  ID = abc
+  provider = provider["registry.opentofu.org/hashicorp/aws"]
+aws_instance_thing.foo-1:
+  ID = zed
+  provider = provider["registry.opentofu.org/hashicorp/aws"]
+`
+
 const testImportRefreshStr = `
 aws_instance.foo:
   ID = foo
   provider = provider["registry.opentofu.org/hashicorp/aws"]
   foo = bar
 `
+
+func TestContextImport_parallelProviderInstances(t *testing.T) {
+	// Import targets that use independent provider configurations must be
+	// imported concurrently, within the limit of the context's parallelism.
+	// Each provider instance below blocks its import until the other one
+	// has started, so a sequential walk would time out.
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "aws" {}
+
+			provider "aws" {
+				alias = "alt"
+			}
+
+			resource "aws_instance" "a" {}
+
+			resource "aws_instance" "b" {
+				provider = aws.alt
+			}
+		`})
+
+	var started sync.WaitGroup
+	started.Add(2)
+	allStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(allStarted)
+	}()
+
+	providerFactory := func() (providers.Interface, error) {
+		p := testProvider("aws")
+		p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+			ResourceTypes: map[string]*configschema.Block{
+				"aws_instance": {
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		})
+		p.ImportResourceStateFn = func(req providers.ImportResourceStateRequest) (resp providers.ImportResourceStateResponse) {
+			started.Done()
+			select {
+			case <-allStarted:
+			case <-time.After(10 * time.Second):
+				resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("import of %q was not run concurrently with the other import", req.ID))
+				return resp
+			}
+			resp.ImportedResources = []providers.ImportedResource{
+				{
+					TypeName: req.TypeName,
+					State: cty.ObjectVal(map[string]cty.Value{
+						"id": cty.StringVal(req.ID),
+					}),
+				},
+			}
+			return resp
+		}
+		return p, nil
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Parallelism: 2,
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): providerFactory,
+		},
+	})
+
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.ManagedResourceMode, "aws_instance", "a", addrs.NoKey,
+					),
+					ID: "a",
+				},
+			},
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.ManagedResourceMode, "aws_instance", "b", addrs.NoKey,
+					),
+					ID: "b",
+				},
+			},
+		},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	for _, name := range []string{"a", "b"} {
+		addr := mustResourceInstanceAddr("aws_instance." + name)
+		if state.ResourceInstance(addr) == nil {
+			t.Errorf("%s was not imported", addr)
+		}
+	}
+}
+
+func TestContextImport_generateConfig(t *testing.T) {
+	p := testProvider("aws")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "aws" {}
+		`})
+
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"aws_instance": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":  {Type: cty.String, Computed: true},
+					"arn": {Type: cty.String, Computed: true},
+					"ami": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	})
+	p.ImportResourceStateResponse = &providers.ImportResourceStateResponse{
+		ImportedResources: []providers.ImportedResource{
+			{
+				TypeName: "aws_instance",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id":  cty.StringVal("foo"),
+					"arn": cty.StringVal("arn:aws:ec2:foo"),
+					"ami": cty.StringVal("ami-\"quoted\""),
+				}),
+			},
+		},
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	out := filepath.Join(t.TempDir(), "generated.tf")
+	state, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.ManagedResourceMode, "aws_instance", "foo", addrs.NoKey,
+					),
+					ID: "bar",
+				},
+			},
+		},
+		GenerateConfigOut: out,
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if state.ResourceInstance(mustResourceInstanceAddr("aws_instance.foo")) == nil {
+		t.Fatalf("aws_instance.foo was not imported")
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read generated config: %s", err)
+	}
+	want := `# __generated__ by OpenTofu
+# Please review these resources and move them into your main configuration files.
+
+# __generated__ by OpenTofu from "bar"
+resource "aws_instance" "foo" {
+  ami = "ami-\"quoted\""
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Fatalf("wrong generated config\n%s", diff)
+	}
+}
+
+func TestContextImport_generateConfigMissingSchema(t *testing.T) {
+	p := testProvider("aws")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			provider "aws" {}
+		`})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Import(context.Background(), m, states.NewState(), &ImportOpts{
+		Targets: []*ImportTarget{
+			{
+				CommandLineImportTarget: &CommandLineImportTarget{
+					Addr: addrs.RootModuleInstance.ResourceInstance(
+						addrs.ManagedResourceMode, "aws_nonexistent", "foo", addrs.NoKey,
+					),
+					ID: "bar",
+				},
+			},
+		},
+		GenerateConfigOut: filepath.Join(t.TempDir(), "generated.tf"),
+	})
+	if !diags.HasErrors() {
+		t.Fatal("should error")
+	}
+}
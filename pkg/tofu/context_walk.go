@@ -53,6 +53,7 @@ func (c *Context) walk(ctx context.Context, graph *Graph, operation walkOperatio
 	log.Printf("[DEBUG] Starting graph walk: %s", operation.String())
 
 	walker := c.graphWalker(operation, opts)
+	walker.TraceContext = ctx
 
 	// Watch for a stop so we can call the provider Stop() API.
 	watchStop, watchWait := c.watchStop(walker)
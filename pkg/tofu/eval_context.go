@@ -6,6 +6,8 @@
 package tofu
 
 import (
+	"context"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/we-dcode/opentofu/pkg/addrs"
 	"github.com/we-dcode/opentofu/pkg/checks"
@@ -28,6 +30,10 @@ type EvalContext interface {
 	// via tofu.Context.Stop()
 	Stopped() <-chan struct{}
 
+	// TraceContext returns the context carrying the span of the graph walk
+	// this EvalContext belongs to, so that child spans can be started from it.
+	TraceContext() context.Context
+
 	// Path is the current module path.
 	Path() addrs.ModuleInstance
 
@@ -55,6 +61,10 @@ type EvalContext interface {
 	// that owns the given provider before calling this method.
 	Provider(addrs.AbsProviderConfig, addrs.InstanceKey) providers.Interface
 
+	// ProviderInstanceKeys returns the keys of the initialized instances of
+	// the provider with the given address, in a consistent order.
+	ProviderInstanceKeys(addrs.AbsProviderConfig) []addrs.InstanceKey
+
 	// ProviderSchema retrieves the schema for a particular provider, which
 	// must have already been initialized with InitProvider.
 	//
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 
 	"github.com/hashicorp/hcl/v2"
@@ -36,6 +37,10 @@ type BuiltinEvalContext struct {
 	// StopContext is the context used to track whether we're complete
 	StopContext context.Context
 
+	// TraceContextValue is the context of the graph walk, used as the parent
+	// of the spans started during evaluation.
+	TraceContextValue context.Context
+
 	// PathValue is the Path that this context is operating within.
 	PathValue addrs.ModuleInstance
 
@@ -104,6 +109,10 @@ func (ctx *BuiltinEvalContext) Stopped() <-chan struct{} {
 	return ctx.StopContext.Done()
 }
 
+func (ctx *BuiltinEvalContext) TraceContext() context.Context {
+	return traceContextOrBackground(ctx.TraceContextValue)
+}
+
 func (ctx *BuiltinEvalContext) Hook(fn func(Hook) (HookAction, error)) error {
 	for _, h := range ctx.Hooks {
 		action, err := fn(h)
@@ -182,6 +191,21 @@ func (ctx *BuiltinEvalContext) Provider(addr addrs.AbsProviderConfig, key addrs.
 	return pm[key]
 }
 
+func (ctx *BuiltinEvalContext) ProviderInstanceKeys(addr addrs.AbsProviderConfig) []addrs.InstanceKey {
+	ctx.ProviderLock.Lock()
+	defer ctx.ProviderLock.Unlock()
+
+	pm := ctx.ProviderCache[addr.String()]
+	keys := make([]addrs.InstanceKey, 0, len(pm))
+	for key := range pm {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return addrs.InstanceKeyLess(keys[i], keys[j])
+	})
+	return keys
+}
+
 func (ctx *BuiltinEvalContext) ProviderSchema(addr addrs.AbsProviderConfig) (providers.ProviderSchema, error) {
 	return ctx.Plugins.ProviderSchema(addr.Provider)
 }
@@ -6,6 +6,8 @@
 package tofu
 
 import (
+	"context"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/we-dcode/opentofu/pkg/addrs"
@@ -30,6 +32,8 @@ type MockEvalContext struct {
 	StoppedCalled bool
 	StoppedValue  <-chan struct{}
 
+	TraceContextValue context.Context
+
 	HookCalled bool
 	HookHook   Hook
 	HookError  error
@@ -47,6 +51,10 @@ type MockEvalContext struct {
 	ProviderAddr     addrs.AbsProviderConfig
 	ProviderProvider providers.Interface
 
+	ProviderInstanceKeysCalled bool
+	ProviderInstanceKeysAddr   addrs.AbsProviderConfig
+	ProviderInstanceKeysKeys   []addrs.InstanceKey
+
 	ProviderSchemaCalled bool
 	ProviderSchemaAddr   addrs.AbsProviderConfig
 	ProviderSchemaSchema providers.ProviderSchema
@@ -167,6 +175,10 @@ func (c *MockEvalContext) Stopped() <-chan struct{} {
 	return c.StoppedValue
 }
 
+func (c *MockEvalContext) TraceContext() context.Context {
+	return traceContextOrBackground(c.TraceContextValue)
+}
+
 func (c *MockEvalContext) Hook(fn func(Hook) (HookAction, error)) error {
 	c.HookCalled = true
 	if c.HookHook != nil {
@@ -196,6 +208,12 @@ func (c *MockEvalContext) Provider(addr addrs.AbsProviderConfig, _ addrs.Instanc
 	return c.ProviderProvider
 }
 
+func (c *MockEvalContext) ProviderInstanceKeys(addr addrs.AbsProviderConfig) []addrs.InstanceKey {
+	c.ProviderInstanceKeysCalled = true
+	c.ProviderInstanceKeysAddr = addr
+	return c.ProviderInstanceKeysKeys
+}
+
 func (c *MockEvalContext) ProviderSchema(addr addrs.AbsProviderConfig) (providers.ProviderSchema, error) {
 	c.ProviderSchemaCalled = true
 	c.ProviderSchemaAddr = addr
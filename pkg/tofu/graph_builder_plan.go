@@ -97,6 +97,18 @@ type PlanGraphBuilder struct {
 	GenerateConfigPath string
 
 	ProviderFunctionTracker ProviderFunctionMapping
+
+	// importResults, if set, collects the outcome of each command line
+	// import target during an import walk.
+	importResults *importResultTracker
+
+	// skipImportRefresh disables the refresh of objects imported from the
+	// command line during an import walk.
+	skipImportRefresh bool
+
+	// sortImportedResources sorts the objects imported for command line
+	// targets by their ID during an import walk.
+	sortImportedResources bool
 }
 
 // See GraphBuilder
@@ -352,6 +364,10 @@ func (b *PlanGraphBuilder) initImport() {
 			// as the new state, and users are not expecting the import process
 			// to update any other instances in state.
 			skipRefresh: true,
+
+			importResults:         b.importResults,
+			skipImportRefresh:     b.skipImportRefresh,
+			sortImportedResources: b.sortImportedResources,
 		}
 	}
 }
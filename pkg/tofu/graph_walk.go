@@ -6,7 +6,11 @@
 package tofu
 
 import (
+	"sync"
+	"time"
+
 	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/dag"
 	"github.com/we-dcode/opentofu/pkg/tfdiags"
 )
 
@@ -28,3 +32,146 @@ func (NullGraphWalker) EvalContext() EvalContext
 func (NullGraphWalker) EnterPath(addrs.ModuleInstance) EvalContext                   { return new(MockEvalContext) }
 func (NullGraphWalker) ExitPath(addrs.ModuleInstance)                                {}
 func (NullGraphWalker) Execute(EvalContext, GraphNodeExecutable) tfdiags.Diagnostics { return nil }
+
+// GraphWalkEventKind describes the kind of a GraphWalkEvent.
+type GraphWalkEventKind string
+
+const (
+	GraphWalkEnterPath GraphWalkEventKind = "enter"
+	GraphWalkExitPath  GraphWalkEventKind = "exit"
+	GraphWalkExecute   GraphWalkEventKind = "execute"
+)
+
+// GraphWalkEvent is a single callback recorded by TraceGraphWalker.
+type GraphWalkEvent struct {
+	Kind GraphWalkEventKind
+
+	// Path is the module instance entered or exited. It is only set for
+	// GraphWalkEnterPath and GraphWalkExitPath events.
+	Path addrs.ModuleInstance
+
+	// Node and NodeName are the node visited, and its name in the graph. They
+	// are only set for GraphWalkExecute events.
+	Node     GraphNodeExecutable
+	NodeName string
+}
+
+// TraceGraphWalker is a GraphWalker implementation that doesn't execute any
+// nodes, but instead records the order in which the walk visits them.
+//
+// It never calls into providers or any other external components, so it is
+// safe to use for debugging the ordering of a graph. The graph is still
+// walked concurrently, so the trace of independent nodes may differ between
+// walks.
+type TraceGraphWalker struct {
+	NullGraphWalker
+
+	mu     sync.Mutex
+	events []GraphWalkEvent
+}
+
+var _ GraphWalker = (*TraceGraphWalker)(nil)
+
+func (w *TraceGraphWalker) EnterPath(path addrs.ModuleInstance) EvalContext {
+	w.record(GraphWalkEvent{Kind: GraphWalkEnterPath, Path: path})
+	return w.NullGraphWalker.EnterPath(path)
+}
+
+func (w *TraceGraphWalker) ExitPath(path addrs.ModuleInstance) {
+	w.record(GraphWalkEvent{Kind: GraphWalkExitPath, Path: path})
+}
+
+func (w *TraceGraphWalker) Execute(_ EvalContext, node GraphNodeExecutable) tfdiags.Diagnostics {
+	w.record(GraphWalkEvent{Kind: GraphWalkExecute, Node: node, NodeName: dag.VertexName(node)})
+	return nil
+}
+
+// Trace returns the events recorded so far, in the order they happened.
+func (w *TraceGraphWalker) Trace() []GraphWalkEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ret := make([]GraphWalkEvent, len(w.events))
+	copy(ret, w.events)
+	return ret
+}
+
+func (w *TraceGraphWalker) record(event GraphWalkEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.events = append(w.events, event)
+}
+
+// TimingGraphWalker is a GraphWalker implementation that wraps another
+// walker and records how long each node took to execute, so that slow nodes
+// can be found without setting up full tracing.
+//
+// The durations are keyed by the name of the node in the graph, and are
+// accumulated if a node with the same name is executed more than once, for
+// example when the same walker is used for several walks.
+//
+// A walker with a threshold only records nodes whose execution took longer
+// than the threshold, which keeps the memory used for huge graphs bounded by
+// the number of slow nodes. Once a node has been recorded, all of its later
+// executions are accumulated.
+type TimingGraphWalker struct {
+	GraphWalker
+
+	// now returns the current time, and can be overridden in tests.
+	now func() time.Time
+
+	threshold time.Duration
+
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+var _ GraphWalker = (*TimingGraphWalker)(nil)
+
+// NewTimingGraphWalker returns a TimingGraphWalker which delegates all calls
+// to the given walker.
+func NewTimingGraphWalker(walker GraphWalker) *TimingGraphWalker {
+	return &TimingGraphWalker{
+		GraphWalker: walker,
+		now:         time.Now,
+		durations:   make(map[string]time.Duration),
+	}
+}
+
+// NewTimingGraphWalkerWithThreshold returns a TimingGraphWalker which
+// delegates all calls to the given walker, and only records the nodes whose
+// execution took longer than threshold.
+func NewTimingGraphWalkerWithThreshold(walker GraphWalker, threshold time.Duration) *TimingGraphWalker {
+	w := NewTimingGraphWalker(walker)
+	w.threshold = threshold
+	return w
+}
+
+func (w *TimingGraphWalker) Execute(ctx EvalContext, node GraphNodeExecutable) tfdiags.Diagnostics {
+	start := w.now()
+	diags := w.GraphWalker.Execute(ctx, node)
+	elapsed := w.now().Sub(start)
+
+	name := dag.VertexName(node)
+	w.mu.Lock()
+	if _, recorded := w.durations[name]; recorded || w.threshold == 0 || elapsed > w.threshold {
+		w.durations[name] += elapsed
+	}
+	w.mu.Unlock()
+
+	return diags
+}
+
+// Durations returns the time spent executing each node so far, keyed by the
+// name of the node.
+func (w *TimingGraphWalker) Durations() map[string]time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ret := make(map[string]time.Duration, len(w.durations))
+	for name, d := range w.durations {
+		ret[name] = d
+	}
+	return ret
+}
@@ -42,6 +42,7 @@ type ContextGraphWalker struct {
 	MoveResults             refactoring.MoveResults // Read-only record of earlier processing of move statements
 	Operation               walkOperation
 	StopContext             context.Context
+	TraceContext            context.Context
 	RootVariableValues      InputValues
 	Config                  *configs.Config
 	PlanTimestamp           time.Time
@@ -101,6 +102,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 
 	ctx := &BuiltinEvalContext{
 		StopContext:             w.StopContext,
+		TraceContextValue:       w.TraceContext,
 		Hooks:                   w.Context.hooks,
 		InputValue:              w.Context.uiInput,
 		InstanceExpanderValue:   w.InstanceExpander,
@@ -6,9 +6,180 @@
 package tofu
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/dag"
+	"github.com/we-dcode/opentofu/pkg/tfdiags"
 )
 
 func TestNullGraphWalker_impl(t *testing.T) {
 	var _ GraphWalker = NullGraphWalker{}
 }
+
+func TestTraceGraphWalker(t *testing.T) {
+	modulePath := addrs.RootModuleInstance.Child("child", addrs.NoKey)
+
+	a := &traceTestNode{name: "a"}
+	b := &traceTestModuleNode{traceTestNode{name: "b"}, modulePath}
+	c := &traceTestNode{name: "c"}
+
+	// c depends on b, which depends on a.
+	g := &Graph{Path: addrs.RootModuleInstance}
+	g.Add(a)
+	g.Add(b)
+	g.Add(c)
+	g.Connect(dag.BasicEdge(b, a))
+	g.Connect(dag.BasicEdge(c, b))
+
+	walker := &TraceGraphWalker{}
+	if diags := g.Walk(context.Background(), walker); diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	var got []string
+	for _, event := range walker.Trace() {
+		switch event.Kind {
+		case GraphWalkExecute:
+			got = append(got, string(event.Kind)+" "+event.NodeName)
+		default:
+			got = append(got, string(event.Kind)+" "+event.Path.String())
+		}
+	}
+	want := []string{
+		"execute a",
+		"enter module.child",
+		"execute b",
+		"exit module.child",
+		"execute c",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong trace\n%s", diff)
+	}
+}
+
+func TestTimingGraphWalker(t *testing.T) {
+	a := &traceTestNode{name: "a"}
+	b := &traceTestNode{name: "b"}
+	c := &traceTestNode{name: "c"}
+
+	// The nodes form a chain, so they are executed one at a time and the
+	// fake clock is only advanced by the node being timed.
+	g := &Graph{Path: addrs.RootModuleInstance}
+	g.Add(a)
+	g.Add(b)
+	g.Add(c)
+	g.Connect(dag.BasicEdge(b, a))
+	g.Connect(dag.BasicEdge(c, b))
+
+	clock := time.Unix(0, 0)
+	inner := &timingTestWalker{
+		clock: &clock,
+		durations: map[string]time.Duration{
+			"a": 1 * time.Second,
+			"b": 2 * time.Second,
+			"c": 3 * time.Second,
+		},
+	}
+	walker := NewTimingGraphWalker(inner)
+	walker.now = func() time.Time { return clock }
+
+	// Walking twice accumulates the durations of each node.
+	for i := 0; i < 2; i++ {
+		if diags := g.Walk(context.Background(), walker); diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+	}
+
+	want := map[string]time.Duration{
+		"a": 2 * time.Second,
+		"b": 4 * time.Second,
+		"c": 6 * time.Second,
+	}
+	if diff := cmp.Diff(want, walker.Durations()); diff != "" {
+		t.Fatalf("wrong durations\n%s", diff)
+	}
+}
+
+func TestTimingGraphWalker_threshold(t *testing.T) {
+	g := &Graph{Path: addrs.RootModuleInstance}
+	var prev dag.Vertex
+	durations := map[string]time.Duration{}
+	for i := 0; i < 100; i++ {
+		// Every tenth node is slow, the others are fast.
+		name := fmt.Sprintf("fast%d", i)
+		d := time.Millisecond
+		if i%10 == 0 {
+			name = fmt.Sprintf("slow%d", i)
+			d = time.Second
+		}
+		durations[name] = d
+
+		n := &traceTestNode{name: name}
+		g.Add(n)
+		if prev != nil {
+			g.Connect(dag.BasicEdge(n, prev))
+		}
+		prev = n
+	}
+
+	clock := time.Unix(0, 0)
+	inner := &timingTestWalker{
+		clock:     &clock,
+		durations: durations,
+	}
+	walker := NewTimingGraphWalkerWithThreshold(inner, 100*time.Millisecond)
+	walker.now = func() time.Time { return clock }
+
+	if diags := g.Walk(context.Background(), walker); diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	want := map[string]time.Duration{}
+	for i := 0; i < 100; i += 10 {
+		want[fmt.Sprintf("slow%d", i)] = time.Second
+	}
+	if diff := cmp.Diff(want, walker.Durations()); diff != "" {
+		t.Fatalf("wrong durations\n%s", diff)
+	}
+}
+
+// timingTestWalker is a GraphWalker which advances a fake clock by a fixed
+// duration for each executed node, instead of executing it.
+type timingTestWalker struct {
+	NullGraphWalker
+
+	clock     *time.Time
+	durations map[string]time.Duration
+}
+
+func (w *timingTestWalker) Execute(_ EvalContext, node GraphNodeExecutable) tfdiags.Diagnostics {
+	*w.clock = w.clock.Add(w.durations[dag.VertexName(node)])
+	return nil
+}
+
+type traceTestNode struct {
+	name string
+}
+
+func (n *traceTestNode) Name() string {
+	return n.name
+}
+
+func (n *traceTestNode) Execute(EvalContext, walkOperation) tfdiags.Diagnostics {
+	panic("TraceGraphWalker must not execute nodes")
+}
+
+type traceTestModuleNode struct {
+	traceTestNode
+	path addrs.ModuleInstance
+}
+
+func (n *traceTestModuleNode) Path() addrs.ModuleInstance {
+	return n.path
+}
@@ -58,6 +58,7 @@ type Hook interface {
 	//      PreProvisionInstanceStep(aws_instance.foo[1], "remote-exec")
 	//               ProvisionOutput(aws_instance.foo[1], "remote-exec", "Installing foo...")
 	//               ProvisionOutput(aws_instance.foo[1], "remote-exec", "Configuring bar...")
+	//             ProvisionProgress(aws_instance.foo[1], "remote-exec", "configure", 50)
 	//     PostProvisionInstanceStep(aws_instance.foo[1], "remote-exec", nil)
 	//         PostProvisionInstance(aws_instance.foo[1], ...)
 	//
@@ -65,11 +66,16 @@ type Hook interface {
 	// This will be called multiple times as output comes in, with each call
 	// representing one line of output. It cannot control whether the
 	// provisioner continues running.
+	//
+	// ProvisionProgress is called when a provisioner reports structured
+	// progress through provisioners.ProgressOutput. Percent is negative if
+	// the provisioner couldn't estimate its completion.
 	PreProvisionInstance(addr addrs.AbsResourceInstance, state cty.Value) (HookAction, error)
 	PostProvisionInstance(addr addrs.AbsResourceInstance, state cty.Value) (HookAction, error)
 	PreProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string) (HookAction, error)
 	PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string, err error) (HookAction, error)
 	ProvisionOutput(addr addrs.AbsResourceInstance, typeName string, line string)
+	ProvisionProgress(addr addrs.AbsResourceInstance, typeName string, phase string, percent float64)
 
 	// PreRefresh and PostRefresh are called before and after a single
 	// resource state is refreshed, respectively.
@@ -82,6 +88,12 @@ type Hook interface {
 	PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error)
 	PostImportState(addr addrs.AbsResourceInstance, imported []providers.ImportedResource) (HookAction, error)
 
+	// ImportProgress is called each time OpenTofu finishes with one of the
+	// command line import targets of an import operation, whether or not it
+	// was imported successfully. Completed is the number of targets finished
+	// so far, including this one, out of total.
+	ImportProgress(result ImportTargetResult, completed, total int)
+
 	// PrePlanImport and PostPlanImport are called during a plan before and after planning to import
 	// a new resource using the configuration-driven import workflow.
 	PrePlanImport(addr addrs.AbsResourceInstance, importID string) (HookAction, error)
@@ -156,6 +168,9 @@ func (*NilHook) PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typeNa
 func (*NilHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName string, line string) {
 }
 
+func (*NilHook) ProvisionProgress(addr addrs.AbsResourceInstance, typeName string, phase string, percent float64) {
+}
+
 func (*NilHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	return HookActionContinue, nil
 }
@@ -172,6 +187,9 @@ func (*NilHook) PostImportState(addr addrs.AbsResourceInstance, imported []provi
 	return HookActionContinue, nil
 }
 
+func (*NilHook) ImportProgress(result ImportTargetResult, completed, total int) {
+}
+
 func (h *NilHook) PrePlanImport(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	return HookActionContinue, nil
 }
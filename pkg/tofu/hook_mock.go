@@ -86,6 +86,12 @@ type MockHook struct {
 	ProvisionOutputProvisionerType string
 	ProvisionOutputMessage         string
 
+	ProvisionProgressCalled          bool
+	ProvisionProgressAddr            addrs.AbsResourceInstance
+	ProvisionProgressProvisionerType string
+	ProvisionProgressPhase           string
+	ProvisionProgressPercent         float64
+
 	PreRefreshCalled     bool
 	PreRefreshAddr       addrs.AbsResourceInstance
 	PreRefreshGen        states.Generation
@@ -113,6 +119,11 @@ type MockHook struct {
 	PostImportStateReturn    HookAction
 	PostImportStateError     error
 
+	ImportProgressCalled    bool
+	ImportProgressResult    ImportTargetResult
+	ImportProgressCompleted int
+	ImportProgressTotal     int
+
 	PrePlanImportCalled bool
 	PrePlanImportAddr   addrs.AbsResourceInstance
 	PrePlanImportReturn HookAction
@@ -249,6 +260,17 @@ func (h *MockHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName stri
 	h.ProvisionOutputMessage = line
 }
 
+func (h *MockHook) ProvisionProgress(addr addrs.AbsResourceInstance, typeName string, phase string, percent float64) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.ProvisionProgressCalled = true
+	h.ProvisionProgressAddr = addr
+	h.ProvisionProgressProvisionerType = typeName
+	h.ProvisionProgressPhase = phase
+	h.ProvisionProgressPercent = percent
+}
+
 func (h *MockHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	h.Lock()
 	defer h.Unlock()
@@ -291,6 +313,16 @@ func (h *MockHook) PostImportState(addr addrs.AbsResourceInstance, imported []pr
 	return h.PostImportStateReturn, h.PostImportStateError
 }
 
+func (h *MockHook) ImportProgress(result ImportTargetResult, completed, total int) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.ImportProgressCalled = true
+	h.ImportProgressResult = result
+	h.ImportProgressCompleted = completed
+	h.ImportProgressTotal = total
+}
+
 func (h *MockHook) PrePlanImport(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	h.Lock()
 	defer h.Unlock()
@@ -60,6 +60,9 @@ func (h *stopHook) PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typ
 func (h *stopHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName string, line string) {
 }
 
+func (h *stopHook) ProvisionProgress(addr addrs.AbsResourceInstance, typeName string, phase string, percent float64) {
+}
+
 func (h *stopHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	return h.hook()
 }
@@ -76,6 +79,9 @@ func (h *stopHook) PostImportState(addr addrs.AbsResourceInstance, imported []pr
 	return h.hook()
 }
 
+func (h *stopHook) ImportProgress(result ImportTargetResult, completed, total int) {
+}
+
 func (h *stopHook) PrePlanImport(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	return h.hook()
 }
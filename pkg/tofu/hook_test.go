@@ -101,6 +101,12 @@ func (h *testHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName stri
 	h.Calls = append(h.Calls, &testHookCall{"ProvisionOutput", addr.String()})
 }
 
+func (h *testHook) ProvisionProgress(addr addrs.AbsResourceInstance, typeName string, phase string, percent float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Calls = append(h.Calls, &testHookCall{"ProvisionProgress", addr.String()})
+}
+
 func (h *testHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -129,6 +135,12 @@ func (h *testHook) PostImportState(addr addrs.AbsResourceInstance, imported []pr
 	return HookActionContinue, nil
 }
 
+func (h *testHook) ImportProgress(result ImportTargetResult, completed, total int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Calls = append(h.Calls, &testHookCall{"ImportProgress", result.Addr.String()})
+}
+
 func (h *testHook) PrePlanImport(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -0,0 +1,153 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/tfdiags"
+)
+
+// importTargetsFileSchema is the schema of a file describing many import
+// targets at once. The file consists of any number of "import" blocks, each
+// with a static "to" address and a string "id":
+//
+//	import {
+//	  to = aws_instance.example
+//	  id = "i-abcd1234"
+//	}
+//
+// Files with a .json suffix use the JSON variant of the same structure, where
+// "to" is given as a string.
+var importTargetsFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "import"},
+	},
+}
+
+var importTargetsFileBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "to", Required: true},
+		{Name: "id", Required: true},
+	},
+}
+
+// LoadImportTargetsFile reads the import targets described in the file at
+// the given path, returning them as command line import targets ready to be
+// used in ImportOpts.
+//
+// Every entry is validated, and all problems found in the file are returned
+// together rather than stopping at the first invalid entry.
+func LoadImportTargetsFile(filename string) ([]*ImportTarget, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	parser := hclparse.NewParser()
+	var f *hcl.File
+	var hclDiags hcl.Diagnostics
+	if strings.HasSuffix(filename, ".json") {
+		f, hclDiags = parser.ParseJSONFile(filename)
+	} else {
+		f, hclDiags = parser.ParseHCLFile(filename)
+	}
+	diags = diags.Append(hclDiags)
+	if f == nil {
+		// If we encountered an error loading the file then those errors
+		// should already be in diags from the above, but the file might
+		// also be nil itself and so we can't decode from it.
+		return nil, diags
+	}
+
+	content, hclDiags := f.Body.Content(importTargetsFileSchema)
+	diags = diags.Append(hclDiags)
+
+	var targets []*ImportTarget
+	seen := make(map[string]hcl.Range)
+	for _, block := range content.Blocks {
+		target, moreDiags := decodeImportTargetsFileBlock(block)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			continue
+		}
+
+		key := target.Addr.String()
+		if prev, exists := seen[key]; exists {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate import target",
+				Detail:   fmt.Sprintf("An import target for %s was already declared at %s. A resource instance can only be imported once.", key, prev),
+				Subject:  block.DefRange.Ptr(),
+			})
+			continue
+		}
+		seen[key] = block.DefRange
+
+		targets = append(targets, &ImportTarget{CommandLineImportTarget: target})
+	}
+
+	return targets, diags
+}
+
+func decodeImportTargetsFileBlock(block *hcl.Block) (*CommandLineImportTarget, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	content, hclDiags := block.Body.Content(importTargetsFileBlockSchema)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	var addr addrs.AbsResourceInstance
+	traversal, hclDiags := hcl.AbsTraversalForExpr(content.Attributes["to"].Expr)
+	diags = diags.Append(hclDiags)
+	if !hclDiags.HasErrors() {
+		var addrDiags tfdiags.Diagnostics
+		addr, addrDiags = addrs.ParseAbsResourceInstance(traversal)
+		diags = diags.Append(addrDiags)
+		if !addrDiags.HasErrors() && addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid import target",
+				Detail:   "Only managed resources can be imported.",
+				Subject:  content.Attributes["to"].Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	var id string
+	idExpr := content.Attributes["id"].Expr
+	idVal, hclDiags := idExpr.Value(nil)
+	diags = diags.Append(hclDiags)
+	if !hclDiags.HasErrors() {
+		strVal, err := convert.Convert(idVal, cty.String)
+		switch {
+		case err != nil || strVal.IsNull() || !strVal.IsKnown() || strVal.AsString() == "":
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid import id",
+				Detail:   "The import ID must be a non-empty string.",
+				Subject:  idExpr.Range().Ptr(),
+			})
+		default:
+			id = strVal.AsString()
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return &CommandLineImportTarget{
+		Addr: addr,
+		ID:   id,
+	}, diags
+}
@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadImportTargetsFile(t *testing.T) {
+	testCases := map[string]struct {
+		filename  string
+		src       string
+		want      map[string]string
+		wantDiags int
+	}{
+		"hcl": {
+			filename: "imports.hcl",
+			src: `
+import {
+  to = aws_instance.foo
+  id = "i-foo"
+}
+
+import {
+  to = module.child.aws_instance.bar["baz"]
+  id = "i-bar"
+}
+`,
+			want: map[string]string{
+				"aws_instance.foo":                     "i-foo",
+				`module.child.aws_instance.bar["baz"]`: "i-bar",
+			},
+		},
+		"json": {
+			filename: "imports.json",
+			src: `{
+  "import": [
+    {"to": "aws_instance.foo", "id": "i-foo"},
+    {"to": "aws_instance.bar[0]", "id": "i-bar"}
+  ]
+}`,
+			want: map[string]string{
+				"aws_instance.foo":    "i-foo",
+				"aws_instance.bar[0]": "i-bar",
+			},
+		},
+		"all errors reported": {
+			filename: "imports.hcl",
+			src: `
+import {
+  to = aws_instance
+  id = "i-foo"
+}
+
+import {
+  to = data.aws_instance.bar
+  id = "i-bar"
+}
+
+import {
+  to = aws_instance.baz
+  id = ""
+}
+
+import {
+  to = aws_instance.ok
+  id = "i-ok"
+}
+
+import {
+  to = aws_instance.ok
+  id = "i-ok-again"
+}
+`,
+			wantDiags: 4,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), tc.filename)
+			if err := os.WriteFile(filename, []byte(tc.src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			targets, diags := LoadImportTargetsFile(filename)
+			if tc.wantDiags > 0 {
+				if len(diags) != tc.wantDiags {
+					t.Fatalf("expected %d diagnostics, got %d: %s", tc.wantDiags, len(diags), diags.Err())
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Err())
+			}
+
+			if len(targets) != len(tc.want) {
+				t.Fatalf("expected %d targets, got %d", len(tc.want), len(targets))
+			}
+			for _, target := range targets {
+				if !target.IsFromImportCommandLine() {
+					t.Fatalf("target %s is not a command line import target", target.StaticAddr())
+				}
+				wantID, ok := tc.want[target.Addr.String()]
+				if !ok {
+					t.Fatalf("unexpected target %s", target.Addr)
+				}
+				if target.ID != wantID {
+					t.Errorf("wrong ID for %s: got %q, want %q", target.Addr, target.ID, wantID)
+				}
+			}
+		})
+	}
+}
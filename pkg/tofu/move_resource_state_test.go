@@ -0,0 +1,126 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/we-dcode/opentofu/pkg/configs/configschema"
+	"github.com/we-dcode/opentofu/pkg/providers"
+	"github.com/we-dcode/opentofu/pkg/states"
+)
+
+// testMoveResourceState moves the given source object to the target resource
+// type using the MoveResourceState call of the given provider, and returns
+// the resulting object decoded against the target resource type's schema.
+// The provider must be configured, and any error diagnostics fail the test.
+func testMoveResourceState(t *testing.T, p *MockProvider, sourceTypeName string, src *states.ResourceInstanceObjectSrc, targetTypeName string) *states.ResourceInstanceObject {
+	t.Helper()
+
+	resp := p.MoveResourceState(providers.MoveResourceStateRequest{
+		SourceProviderAddress: "registry.opentofu.org/hashicorp/test",
+		SourceTypeName:        sourceTypeName,
+		SourceSchemaVersion:   int64(src.SchemaVersion),
+		SourceStateJSON:       src.AttrsJSON,
+		SourcePrivate:         src.Private,
+		TargetTypeName:        targetTypeName,
+	})
+	if resp.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected errors: %s", resp.Diagnostics.Err())
+	}
+
+	resourceTypeSchema, ok := p.GetProviderSchemaResponse.ResourceTypes[targetTypeName]
+	if !ok {
+		t.Fatalf("no schema for resource type %q", targetTypeName)
+	}
+
+	obj := &states.ResourceInstanceObject{
+		Status:  states.ObjectReady,
+		Value:   resp.TargetState,
+		Private: resp.TargetPrivate,
+	}
+	moved, err := obj.Encode(resourceTypeSchema.Block.ImpliedType(), uint64(resourceTypeSchema.Version))
+	if err != nil {
+		t.Fatalf("failed to encode the moved object: %s", err)
+	}
+	got, err := moved.Decode(resourceTypeSchema.Block.ImpliedType())
+	if err != nil {
+		t.Fatalf("failed to decode the moved object: %s", err)
+	}
+	return got
+}
+
+func TestMoveResourceState_renameAttribute(t *testing.T) {
+	p := new(MockProvider)
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"old_type": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":       {Type: cty.String, Computed: true},
+					"hostname": {Type: cty.String, Optional: true},
+				},
+			},
+			"new_type": {
+				Attributes: map[string]*configschema.Attribute{
+					"id":   {Type: cty.String, Computed: true},
+					"host": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	})
+	p.MoveResourceStateFn = func(req providers.MoveResourceStateRequest) (resp providers.MoveResourceStateResponse) {
+		if req.SourceTypeName != "old_type" {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("can't move from %q", req.SourceTypeName))
+			return resp
+		}
+
+		old, err := ctyjson.Unmarshal(req.SourceStateJSON, cty.Object(map[string]cty.Type{
+			"id":       cty.String,
+			"hostname": cty.String,
+		}))
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(err)
+			return resp
+		}
+
+		resp.TargetState = cty.ObjectVal(map[string]cty.Value{
+			"id":   old.GetAttr("id"),
+			"host": old.GetAttr("hostname"),
+		})
+		resp.TargetPrivate = req.SourcePrivate
+		return resp
+	}
+	p.ConfigureProvider(providers.ConfigureProviderRequest{})
+
+	src := &states.ResourceInstanceObjectSrc{
+		Status:    states.ObjectReady,
+		AttrsJSON: []byte(`{"id":"foo","hostname":"example.com"}`),
+		Private:   []byte("private"),
+	}
+	got := testMoveResourceState(t, p, "old_type", src, "new_type")
+
+	want := cty.ObjectVal(map[string]cty.Value{
+		"id":   cty.StringVal("foo"),
+		"host": cty.StringVal("example.com"),
+	})
+	if !got.Value.RawEquals(want) {
+		t.Fatalf("wrong moved value\ngot:  %#v\nwant: %#v", got.Value, want)
+	}
+	if string(got.Private) != "private" {
+		t.Fatalf("wrong moved private state %q", got.Private)
+	}
+
+	if !p.MoveResourceStateCalled {
+		t.Fatal("MoveResourceState not called")
+	}
+	if got, want := p.MoveResourceStateRequest.TargetTypeName, "new_type"; got != want {
+		t.Fatalf("wrong target type %q, want %q", got, want)
+	}
+}
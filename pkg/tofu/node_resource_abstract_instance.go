@@ -542,6 +542,7 @@ func (n *NodeAbstractResourceInstance) planDestroy(ctx EvalContext, currentState
 
 	// Allow the provider to check the destroy plan, and insert any necessary
 	// private data.
+	span := startProviderSpan(ctx, "PlanResourceChange", providerOperationPlan, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, n.Addr)
 	resp := provider.PlanResourceChange(providers.PlanResourceChangeRequest{
 		TypeName:         n.Addr.Resource.Resource.Type,
 		Config:           nullVal,
@@ -550,6 +551,7 @@ func (n *NodeAbstractResourceInstance) planDestroy(ctx EvalContext, currentState
 		PriorPrivate:     currentState.Private,
 		ProviderMeta:     metaConfigVal,
 	})
+	endProviderSpan(span, resp.Diagnostics)
 
 	// We may not have a config for all destroys, but we want to reference it in
 	// the diagnostics if we do.
@@ -711,7 +713,9 @@ func (n *NodeAbstractResourceInstance) refresh(ctx EvalContext, deposedKey state
 		ProviderMeta: metaConfigVal,
 	}
 
+	span := startProviderSpan(ctx, "ReadResource", providerOperationRead, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, n.Addr)
 	resp := provider.ReadResource(providerReq)
+	endProviderSpan(span, resp.Diagnostics)
 	if n.Config != nil {
 		resp.Diagnostics = resp.Diagnostics.InConfigBody(n.Config.Config, n.Addr.String())
 	}
@@ -753,6 +757,7 @@ func (n *NodeAbstractResourceInstance) refresh(ctx EvalContext, deposedKey state
 	ret := state.DeepCopy()
 	ret.Value = newState
 	ret.Private = resp.Private
+	ret.NeedsRefresh = false
 
 	// We have no way to exempt provider using the legacy SDK from this check,
 	// so we can only log inconsistencies with the updated state values.
@@ -944,6 +949,7 @@ func (n *NodeAbstractResourceInstance) plan(
 		return nil, nil, keyData, diags
 	}
 
+	span := startProviderSpan(ctx, "PlanResourceChange", providerOperationPlan, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, n.Addr)
 	resp := provider.PlanResourceChange(providers.PlanResourceChangeRequest{
 		TypeName:         n.Addr.Resource.Resource.Type,
 		Config:           unmarkedConfigVal,
@@ -952,6 +958,7 @@ func (n *NodeAbstractResourceInstance) plan(
 		PriorPrivate:     priorPrivate,
 		ProviderMeta:     metaConfigVal,
 	})
+	endProviderSpan(span, resp.Diagnostics)
 
 	diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, n.Addr.String()))
 	if diags.HasErrors() {
@@ -1185,6 +1192,7 @@ func (n *NodeAbstractResourceInstance) plan(
 		// create a new proposed value from the null state and the config
 		proposedNewVal = objchange.ProposedNew(schema, nullPriorVal, unmarkedConfigVal)
 
+		replaceSpan := startProviderSpan(ctx, "PlanResourceChange", providerOperationPlan, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, n.Addr)
 		resp = provider.PlanResourceChange(providers.PlanResourceChangeRequest{
 			TypeName:         n.Addr.Resource.Resource.Type,
 			Config:           unmarkedConfigVal,
@@ -1193,6 +1201,7 @@ func (n *NodeAbstractResourceInstance) plan(
 			PriorPrivate:     plannedPrivate,
 			ProviderMeta:     metaConfigVal,
 		})
+		endProviderSpan(replaceSpan, resp.Diagnostics)
 		// We need to tread carefully here, since if there are any warnings
 		// in here they probably also came out of our previous call to
 		// PlanResourceChange above, and so we don't want to repeat them.
@@ -1610,12 +1619,14 @@ func (n *NodeAbstractResourceInstance) readDataSource(ctx EvalContext, configVal
 		ProviderMeta: metaConfigVal,
 	}
 	var resp providers.ReadDataSourceResponse
+	span := startProviderSpan(ctx, "ReadDataSource", providerOperationRead, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, n.Addr)
 	if tfp, ok := provider.(ProviderWithEncryption); ok {
 		// Special case for terraform_remote_state
 		resp = tfp.ReadDataSourceEncrypted(req, n.Addr, ctx.GetEncryption())
 	} else {
 		resp = provider.ReadDataSource(req)
 	}
+	endProviderSpan(span, resp.Diagnostics)
 	diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, n.Addr.String()))
 	if diags.HasErrors() {
 		return newVal, diags
@@ -2254,13 +2265,19 @@ func (n *NodeAbstractResourceInstance) applyProvisioners(ctx EvalContext, state
 			}
 		}
 
-		// The output function
+		// The output functions
 		outputFn := func(msg string) {
 			ctx.Hook(func(h Hook) (HookAction, error) {
 				h.ProvisionOutput(n.Addr, prov.Type, msg)
 				return HookActionContinue, nil
 			})
 		}
+		progressFn := func(phase string, percent float64) {
+			ctx.Hook(func(h Hook) (HookAction, error) {
+				h.ProvisionProgress(n.Addr, prov.Type, phase, percent)
+				return HookActionContinue, nil
+			})
+		}
 
 		// If our config or connection info contains any marked values, ensure
 		// those are stripped out before sending to the provisioner. Unlike
@@ -2280,9 +2297,12 @@ func (n *NodeAbstractResourceInstance) applyProvisioners(ctx EvalContext, state
 					return HookActionContinue, nil
 				})
 			}
+			// Phase names may be derived from the config too, so we drop
+			// the progress events entirely.
+			progressFn = nil
 		}
 
-		output := CallbackUIOutput{OutputFn: outputFn}
+		output := CallbackUIOutput{OutputFn: outputFn, ProgressFn: progressFn}
 		resp := provisioner.ProvisionResource(provisioners.ProvisionResourceRequest{
 			Config:     unmarkedConfig,
 			Connection: unmarkedConnInfo,
@@ -2456,6 +2476,7 @@ func (n *NodeAbstractResourceInstance) apply(
 		return newState, diags
 	}
 
+	span := startProviderSpan(ctx, "ApplyResourceChange", providerOperationApply, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, n.Addr)
 	resp := provider.ApplyResourceChange(providers.ApplyResourceChangeRequest{
 		TypeName:       n.Addr.Resource.Resource.Type,
 		PriorState:     unmarkedBefore,
@@ -2464,6 +2485,7 @@ func (n *NodeAbstractResourceInstance) apply(
 		PlannedPrivate: change.Private,
 		ProviderMeta:   metaConfigVal,
 	})
+	endProviderSpan(span, resp.Diagnostics)
 
 	applyDiags := resp.Diagnostics
 	if applyConfig != nil {
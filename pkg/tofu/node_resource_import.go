@@ -8,6 +8,11 @@ package tofu
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/we-dcode/opentofu/pkg/addrs"
 	"github.com/we-dcode/opentofu/pkg/configs"
@@ -28,6 +33,17 @@ type graphNodeImportState struct {
 	Config        *configs.Resource   // Config is the resource in the config
 
 	states []providers.ImportedResource
+
+	// results, if set, records the outcome of this import target.
+	results *importResultTracker
+
+	// skipRefresh stores the imported objects without refreshing them.
+	skipRefresh bool
+
+	// sortImported sorts the additional imported objects by their ID, so
+	// that the addresses assigned to them don't depend on the provider's
+	// response order.
+	sortImported bool
 }
 
 var (
@@ -79,6 +95,17 @@ func (n *graphNodeImportState) Execute(ctx EvalContext, op walkOperation) (diags
 	// Reset our states
 	n.states = nil
 
+	defer func() {
+		n.results.record(n.Addr, func(r *ImportTargetResult) {
+			r.Diagnostics = r.Diagnostics.Append(diags)
+		})
+		// Unless there are imported objects left to write to the state,
+		// we're done with this target.
+		if diags.HasErrors() || len(n.states) == 0 {
+			n.results.finish(ctx, n.Addr)
+		}
+	}()
+
 	// FIXME, yuck: borrowing some logic that's currently only available for the abstract resource instance
 	// node, even though graphNodeImportState doesn't actually embed that type for some reason.
 	// Let's factor this logic out somewhere that's explicitly shareable.
@@ -99,6 +126,11 @@ func (n *graphNodeImportState) Execute(ctx EvalContext, op walkOperation) (diags
 	n.ResolvedProviderKey = asAbsNode.ResolvedProviderKey
 	log.Printf("[TRACE] graphNodeImportState: importing using %s", n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey))
 
+	if n.ResolvedProviderKey != addrs.NoKey && ctx.Provider(n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey) == nil {
+		diags = diags.Append(missingProviderInstanceDiag(ctx, n.Addr, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey))
+		return diags
+	}
+
 	provider, _, err := getProvider(ctx, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
@@ -116,20 +148,43 @@ func (n *graphNodeImportState) Execute(ctx EvalContext, op walkOperation) (diags
 		return diags
 	}
 
-	resp := provider.ImportResourceState(providers.ImportResourceStateRequest{
-		TypeName: n.Addr.Resource.Resource.Type,
-		ID:       n.ID,
-	})
-	diags = diags.Append(resp.Diagnostics)
-	if diags.HasErrors() {
-		return diags
+	var imported []providers.ImportedResource
+	if n.Addr.Resource.Resource.Mode == addrs.DataResourceMode {
+		// Data resources can't be imported, so we store the result of
+		// reading the data source instead. Context.Import only lets these
+		// targets through if the caller explicitly allowed it.
+		var readDiags tfdiags.Diagnostics
+		imported, readDiags = n.readDataSource(ctx, asAbsNode)
+		diags = diags.Append(readDiags)
+		if diags.HasErrors() {
+			return diags
+		}
+	} else {
+		span := startProviderSpan(ctx, "ImportResourceState", providerOperationImport, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, absAddr)
+		resp := provider.ImportResourceState(providers.ImportResourceStateRequest{
+			TypeName: n.Addr.Resource.Resource.Type,
+			ID:       n.ID,
+		})
+		endProviderSpan(span, resp.Diagnostics)
+		diags = diags.Append(resp.Diagnostics)
+		if diags.HasErrors() {
+			return diags
+		}
+		imported = resp.ImportedResources
+		if n.sortImported {
+			sortImportedResources(imported)
+		}
 	}
 
-	imported := resp.ImportedResources
 	for _, obj := range imported {
 		log.Printf("[TRACE] graphNodeImportState: import %s %q produced instance object of type %s", absAddr.String(), n.ID, obj.TypeName)
 	}
 	n.states = imported
+	n.results.record(n.Addr, func(r *ImportTargetResult) {
+		for _, obj := range imported {
+			r.ImportedTypes = append(r.ImportedTypes, obj.TypeName)
+		}
+	})
 
 	// Call post-import hook
 	diags = diags.Append(ctx.Hook(func(h Hook) (HookAction, error) {
@@ -138,6 +193,74 @@ func (n *graphNodeImportState) Execute(ctx EvalContext, op walkOperation) (diags
 	return diags
 }
 
+// missingProviderInstanceDiag returns an error diagnostic for a resource
+// instance whose configuration selects an instance of a provider
+// configuration that doesn't exist, naming the instance keys that do.
+func missingProviderInstanceDiag(ctx EvalContext, addr addrs.AbsResourceInstance, providerAddr addrs.AbsProviderConfig, key addrs.InstanceKey) tfdiags.Diagnostic {
+	var keys []string
+	for _, k := range ctx.ProviderInstanceKeys(providerAddr) {
+		if k != addrs.NoKey {
+			keys = append(keys, k.String())
+		}
+	}
+	availableMsg := "The provider configuration has no instances with keys."
+	if len(keys) != 0 {
+		availableMsg = fmt.Sprintf("The available instance keys are %s.", strings.Join(keys, ", "))
+	}
+
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		"Provider instance not found",
+		fmt.Sprintf(
+			"The configuration of %s selects the provider instance %s, but there is no instance with the key %s. %s",
+			addr, providerAddr.InstanceString(key), key, availableMsg,
+		),
+	)
+}
+
+// readDataSource reads the data source of a data resource target using its
+// configuration, returning the result in place of the objects an import of a
+// managed resource would return.
+func (n *graphNodeImportState) readDataSource(ctx EvalContext, asAbsNode *NodeAbstractResourceInstance) ([]providers.ImportedResource, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if n.Config == nil {
+		// Should be caught by Context.Import, so we don't bother with a pretty error here
+		diags = diags.Append(fmt.Errorf("no configuration for data resource %s", n.Addr))
+		return nil, diags
+	}
+
+	forEach, _ := evaluateForEachExpression(n.Config.ForEach, ctx, n.Addr)
+	keyData := EvalDataForInstanceKey(n.Addr.Resource.Key, forEach)
+	configVal, _, configDiags := ctx.EvaluateBlock(n.Config.Config, n.Schema, nil, keyData)
+	diags = diags.Append(configDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if !configVal.IsWhollyKnown() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Data source configuration is not known",
+			fmt.Sprintf("The configuration of %s refers to values that are not known yet, so OpenTofu cannot read the data source during import.", n.Addr),
+		))
+		return nil, diags
+	}
+
+	log.Printf("[TRACE] graphNodeImportState: reading data source for %s", n.Addr)
+	val, readDiags := asAbsNode.readDataSource(ctx, configVal)
+	diags = diags.Append(readDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return []providers.ImportedResource{
+		{
+			TypeName: n.Addr.Resource.Resource.Type,
+			State:    val,
+		},
+	}, diags
+}
+
 // GraphNodeDynamicExpandable impl.
 //
 // We use DynamicExpand as a way to generate the subgraph of refreshes
@@ -189,6 +312,10 @@ func (n *graphNodeImportState) DynamicExpand(ctx EvalContext) (*Graph, error) {
 		}
 	}
 	if diags.HasErrors() {
+		n.results.record(n.Addr, func(r *ImportTargetResult) {
+			r.Diagnostics = r.Diagnostics.Append(diags)
+		})
+		n.results.finish(ctx, n.Addr)
 		// Bail out early, then.
 		return nil, diags.Err()
 	}
@@ -199,6 +326,7 @@ func (n *graphNodeImportState) DynamicExpand(ctx EvalContext) (*Graph, error) {
 	// safe.
 	for i, state := range n.states {
 		g.Add(&graphNodeImportStateSub{
+			ImportAddr:          n.Addr,
 			TargetAddr:          addrs[i],
 			State:               state,
 			ResolvedProvider:    n.ResolvedProvider,
@@ -206,6 +334,8 @@ func (n *graphNodeImportState) DynamicExpand(ctx EvalContext) (*Graph, error) {
 			Schema:              n.Schema,
 			SchemaVersion:       n.SchemaVersion,
 			Config:              n.Config,
+			results:             n.results,
+			skipRefresh:         n.skipRefresh,
 		})
 	}
 
@@ -219,6 +349,7 @@ func (n *graphNodeImportState) DynamicExpand(ctx EvalContext) (*Graph, error) {
 // and is part of the subgraph. This node is responsible for refreshing
 // and adding a resource to the state once it is imported.
 type graphNodeImportStateSub struct {
+	ImportAddr          addrs.AbsResourceInstance // ImportAddr is the address of the import target this result belongs to
 	TargetAddr          addrs.AbsResourceInstance
 	State               providers.ImportedResource
 	ResolvedProvider    ResolvedProvider
@@ -227,6 +358,13 @@ type graphNodeImportStateSub struct {
 	Schema        *configschema.Block // Schema for processing the configuration body
 	SchemaVersion uint64              // Schema version of "Schema", as decided by the provider
 	Config        *configs.Resource   // Config is the resource in the config
+
+	// results, if set, records the outcome of the import target.
+	results *importResultTracker
+
+	// skipRefresh stores the imported object as returned by the provider,
+	// flagged as needing a refresh, instead of refreshing it.
+	skipRefresh bool
 }
 
 var (
@@ -244,6 +382,22 @@ func (n *graphNodeImportStateSub) Path() addrs.ModuleInstance {
 
 // GraphNodeExecutable impl.
 func (n *graphNodeImportStateSub) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
+	defer func() {
+		var last bool
+		n.results.record(n.ImportAddr, func(r *ImportTargetResult) {
+			r.Diagnostics = r.Diagnostics.Append(diags)
+			if !diags.HasErrors() {
+				r.written++
+				r.writtenAddrs = append(r.writtenAddrs, n.TargetAddr)
+			}
+			r.handled++
+			last = r.handled == len(r.ImportedTypes)
+		})
+		if last {
+			n.results.finish(ctx, n.ImportAddr)
+		}
+	}()
+
 	// If the Ephemeral type isn't set, then it is an error
 	if n.State.TypeName == "" {
 		diags = diags.Append(fmt.Errorf("import of %s didn't set type", n.TargetAddr.String()))
@@ -252,7 +406,6 @@ func (n *graphNodeImportStateSub) Execute(ctx EvalContext, op walkOperation) (di
 
 	state := n.State.AsInstanceObject()
 
-	// Refresh
 	riNode := &NodeAbstractResourceInstance{
 		Addr: n.TargetAddr,
 		NodeAbstractResource: NodeAbstractResource{
@@ -260,10 +413,20 @@ func (n *graphNodeImportStateSub) Execute(ctx EvalContext, op walkOperation) (di
 		},
 		ResolvedProviderKey: n.ResolvedProviderKey,
 	}
-	state, refreshDiags := riNode.refresh(ctx, states.NotDeposed, state)
-	diags = diags.Append(refreshDiags)
-	if diags.HasErrors() {
-		return diags
+	if n.TargetAddr.Resource.Resource.Mode == addrs.DataResourceMode {
+		// The object was just read from the data source, so there's nothing
+		// to refresh.
+		log.Printf("[TRACE] graphNodeImportStateSub: storing the data source result for %s", n.TargetAddr)
+	} else if n.skipRefresh {
+		log.Printf("[TRACE] graphNodeImportStateSub: not refreshing %s as requested", n.TargetAddr)
+		state.NeedsRefresh = true
+	} else {
+		var refreshDiags tfdiags.Diagnostics
+		state, refreshDiags = riNode.refresh(ctx, states.NotDeposed, state)
+		diags = diags.Append(refreshDiags)
+		if diags.HasErrors() {
+			return diags
+		}
 	}
 
 	// Verify the existence of the imported resource
@@ -285,6 +448,24 @@ func (n *graphNodeImportStateSub) Execute(ctx EvalContext, op walkOperation) (di
 		return diags
 	}
 
+	// Import doesn't fail if the provider left required attributes unset,
+	// but the next plan would, so let the user know what is missing.
+	_, providerSchema, err := riNode.getProvider(ctx)
+	if err == nil {
+		schema, _ := providerSchema.SchemaForResourceAddr(n.TargetAddr.Resource.ContainingResource())
+		if missing := nullRequiredAttributes(schema, state.Value); len(missing) != 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Imported object is missing required arguments",
+				fmt.Sprintf(
+					"The object imported into %s has no value for the required argument(s) %s. "+
+						"Set them in the configuration of the resource before running the next plan.",
+					n.TargetAddr, strings.Join(missing, ", "),
+				),
+			))
+		}
+	}
+
 	// Insert marks from configuration
 	if n.Config != nil {
 		// Since the import command allow import resource with incomplete configuration, we ignore diagnostics here
@@ -299,3 +480,62 @@ func (n *graphNodeImportStateSub) Execute(ctx EvalContext, op walkOperation) (di
 	diags = diags.Append(riNode.writeResourceInstanceState(ctx, state, workingState))
 	return diags
 }
+
+// sortImportedResources sorts the given imported objects in place by their
+// "id" attribute. The first object is the one the import target asked for and
+// gets the target address, so it stays first and only the objects after it
+// are sorted. Objects without a known string ID are sorted after all others,
+// keeping their relative order.
+func sortImportedResources(imported []providers.ImportedResource) {
+	if len(imported) < 2 {
+		return
+	}
+	imported = imported[1:]
+	sort.SliceStable(imported, func(i, j int) bool {
+		idI, okI := importedResourceID(imported[i])
+		idJ, okJ := importedResourceID(imported[j])
+		if !okI || !okJ {
+			return okI && !okJ
+		}
+		return idI < idJ
+	})
+}
+
+// importedResourceID returns the value of the "id" attribute of the given
+// imported object, if it has a known string ID.
+func importedResourceID(obj providers.ImportedResource) (string, bool) {
+	if obj.State == cty.NilVal {
+		return "", false
+	}
+	val, _ := obj.State.UnmarkDeep()
+	if val.IsNull() || !val.IsKnown() || !val.Type().IsObjectType() || !val.Type().HasAttribute("id") {
+		return "", false
+	}
+	id := val.GetAttr("id")
+	if id.IsNull() || !id.IsKnown() || !id.Type().Equals(cty.String) {
+		return "", false
+	}
+	return id.AsString(), true
+}
+
+// nullRequiredAttributes returns the quoted names of the required top-level
+// attributes in the given schema whose value in val is null, in
+// lexicographical order.
+func nullRequiredAttributes(schema *configschema.Block, val cty.Value) []string {
+	if schema == nil || val.IsNull() || !val.Type().IsObjectType() {
+		return nil
+	}
+	val, _ = val.UnmarkDeep()
+
+	var missing []string
+	for name, attr := range schema.Attributes {
+		if !attr.Required || !val.Type().HasAttribute(name) {
+			continue
+		}
+		if val.GetAttr(name).IsNull() {
+			missing = append(missing, strconv.Quote(name))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
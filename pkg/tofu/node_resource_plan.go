@@ -47,6 +47,18 @@ type nodeExpandPlannableResource struct {
 	// structure in the future, as we need to compare for equality and take the
 	// union of multiple groups of dependencies.
 	dependencies []addrs.ConfigResource
+
+	// importResults collects the outcome of command line import targets. It
+	// is only set during an import walk.
+	importResults *importResultTracker
+
+	// skipImportRefresh disables the refresh of objects imported from the
+	// command line. It is only set during an import walk.
+	skipImportRefresh bool
+
+	// sortImportedResources sorts the objects imported for command line
+	// targets by their ID. It is only set during an import walk.
+	sortImportedResources bool
 }
 
 var (
@@ -290,6 +302,11 @@ func (n *nodeExpandPlannableResource) expandResourceInstances(globalCtx EvalCont
 	// actions is in the per-instance function we're about to call, because
 	// we need to evaluate it on a per-instance basis.
 
+	diags = diags.Append(n.validateCommandLineImportTargets(resAddr, instanceAddrs))
+	if diags.HasErrors() {
+		return diags.ErrWithWarnings()
+	}
+
 	for _, addr := range instanceAddrs {
 		// If this resource is participating in the "checks" mechanism then our
 		// caller will need to know all of our expanded instance addresses as
@@ -315,6 +332,62 @@ func (n *nodeExpandPlannableResource) expandResourceInstances(globalCtx EvalCont
 	return diags.ErrWithWarnings()
 }
 
+// validateCommandLineImportTargets checks that every import target given on
+// the command line for the given resource names one of its declared
+// instances. Otherwise, for example because of a mistyped instance key, there
+// would be no instance to import into.
+func (n *nodeExpandPlannableResource) validateCommandLineImportTargets(resAddr addrs.AbsResource, instanceAddrs []addrs.AbsResourceInstance) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	for _, importTarget := range n.importTargets {
+		if !importTarget.IsFromImportCommandLine() {
+			continue
+		}
+		targetAddr := importTarget.CommandLineImportTarget.Addr
+		if !targetAddr.ContainingResource().Equal(resAddr) {
+			continue
+		}
+
+		found := false
+		for _, addr := range instanceAddrs {
+			if addr.Equal(targetAddr) {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		if len(instanceAddrs) == 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Import to non-existent resource instance",
+				fmt.Sprintf(
+					"Cannot import into %s because %s doesn't have any instances in the current configuration.",
+					targetAddr, resAddr,
+				),
+			))
+			continue
+		}
+
+		var possibleValidAddrs strings.Builder
+		for _, addr := range instanceAddrs {
+			fmt.Fprintf(&possibleValidAddrs, "\n  %s", addr)
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Import to non-existent resource instance",
+			fmt.Sprintf(
+				"Cannot import into %s because the configuration doesn't declare an instance with that address. Instance keys must match exactly, including the quotes around string keys used with for_each.\n\nThe declared instances of %s are:%s",
+				targetAddr, resAddr, possibleValidAddrs.String(),
+			),
+		))
+	}
+
+	return diags
+}
+
 func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext, addr addrs.AbsResource, instanceAddrs []addrs.AbsResourceInstance) (*Graph, error) {
 	var diags tfdiags.Diagnostics
 
@@ -346,6 +419,9 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 					Schema:           n.Schema,
 					SchemaVersion:    n.SchemaVersion,
 					Config:           n.Config,
+					results:          n.importResults,
+					skipRefresh:      n.skipImportRefresh,
+					sortImported:     n.sortImportedResources,
 				}
 			}
 		}
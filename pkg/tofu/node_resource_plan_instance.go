@@ -255,6 +255,21 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx EvalContext)
 		}
 	}
 
+	// An object imported without a refresh only holds what the provider
+	// returned from the import, so let the user know the plan is based on it.
+	if n.skipRefresh && !n.skipPlanChanges && instanceRefreshState != nil && instanceRefreshState.NeedsRefresh {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Resource instance was never refreshed",
+			fmt.Sprintf(
+				"%s was imported without reading it from the provider, and refreshing is disabled for this plan. "+
+					"The plan is based on the object as returned by the import, which may be incomplete. "+
+					"Run a plan with refreshing enabled to read the full object.",
+				n.Addr,
+			),
+		))
+	}
+
 	// Refresh, maybe
 	// The import process handles its own refresh
 	if !n.skipRefresh && !importing {
@@ -484,10 +499,12 @@ func (n *NodePlannableResourceInstance) importState(ctx EvalContext, addr addrs.
 		return nil, diags
 	}
 
+	span := startProviderSpan(ctx, "ImportResourceState", providerOperationImport, n.ResolvedProvider.ProviderConfig, n.ResolvedProviderKey, addr)
 	resp := provider.ImportResourceState(providers.ImportResourceStateRequest{
 		TypeName: addr.Resource.Resource.Type,
 		ID:       importId,
 	})
+	endProviderSpan(span, resp.Diagnostics)
 	diags = diags.Append(resp.Diagnostics)
 	if diags.HasErrors() {
 		return nil, diags
@@ -674,6 +691,14 @@ func (n *NodePlannableResourceInstance) shouldImport(ctx EvalContext) bool {
 // generateHCLStringAttributes produces a string in HCL format for the given
 // resource state and schema without the surrounding block.
 func (n *NodePlannableResourceInstance) generateHCLStringAttributes(addr addrs.AbsResourceInstance, state *states.ResourceInstanceObject, schema *configschema.Block) (string, tfdiags.Diagnostics) {
+	return generateResourceConfigAttributes(addr, state.Value, schema, n.ResolvedProvider.ProviderConfig)
+}
+
+// generateResourceConfigAttributes produces a string in HCL format for the
+// given resource value and schema without the surrounding block. Attributes
+// that cannot be set in configuration, such as computed-only attributes, are
+// omitted.
+func generateResourceConfigAttributes(addr addrs.AbsResourceInstance, value cty.Value, schema *configschema.Block, provider addrs.AbsProviderConfig) (string, tfdiags.Diagnostics) {
 	filteredSchema := schema.Filter(
 		configschema.FilterOr(
 			configschema.FilterReadOnlyAttribute,
@@ -695,11 +720,11 @@ func (n *NodePlannableResourceInstance) generateHCLStringAttributes(addr addrs.A
 	)
 
 	providerAddr := addrs.LocalProviderConfig{
-		LocalName: n.ResolvedProvider.ProviderConfig.Provider.Type,
-		Alias:     n.ResolvedProvider.ProviderConfig.Alias,
+		LocalName: provider.Provider.Type,
+		Alias:     provider.Alias,
 	}
 
-	return genconfig.GenerateResourceContents(addr, filteredSchema, providerAddr, state.Value)
+	return genconfig.GenerateResourceContents(addr, filteredSchema, providerAddr, value)
 }
 
 // mergeDeps returns the union of 2 sets of dependencies
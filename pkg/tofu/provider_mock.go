@@ -7,6 +7,7 @@ package tofu
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/zclconf/go-cty/cty"
@@ -82,6 +83,11 @@ type MockProvider struct {
 	ImportResourceStateRequest  providers.ImportResourceStateRequest
 	ImportResourceStateFn       func(providers.ImportResourceStateRequest) providers.ImportResourceStateResponse
 
+	MoveResourceStateCalled   bool
+	MoveResourceStateResponse *providers.MoveResourceStateResponse
+	MoveResourceStateRequest  providers.MoveResourceStateRequest
+	MoveResourceStateFn       func(providers.MoveResourceStateRequest) providers.MoveResourceStateResponse
+
 	ReadDataSourceCalled   bool
 	ReadDataSourceResponse *providers.ReadDataSourceResponse
 	ReadDataSourceRequest  providers.ReadDataSourceRequest
@@ -98,11 +104,52 @@ type MockProvider struct {
 
 	CloseCalled bool
 	CloseError  error
+
+	// calls is the ordered log of the calls made to the provider. It has its
+	// own lock, since Stop can be called concurrently with other methods.
+	callsLock sync.Mutex
+	calls     []MockProviderCall
+}
+
+// MockProviderCall describes a single call made to a MockProvider, for tests
+// that care about the order of the calls.
+type MockProviderCall struct {
+	// Method is the name of the providers.Interface method called.
+	Method string
+
+	// Args are the key arguments of the call, such as the resource type name
+	// and the import ID for ImportResourceState.
+	Args []string
+}
+
+func (c MockProviderCall) String() string {
+	if len(c.Args) == 0 {
+		return c.Method
+	}
+	return fmt.Sprintf("%s(%s)", c.Method, strings.Join(c.Args, ", "))
+}
+
+func (p *MockProvider) recordCall(method string, args ...string) {
+	p.callsLock.Lock()
+	defer p.callsLock.Unlock()
+
+	p.calls = append(p.calls, MockProviderCall{Method: method, Args: args})
+}
+
+// Calls returns the ordered log of the calls made to the provider so far.
+func (p *MockProvider) Calls() []MockProviderCall {
+	p.callsLock.Lock()
+	defer p.callsLock.Unlock()
+
+	ret := make([]MockProviderCall, len(p.calls))
+	copy(ret, p.calls)
+	return ret
 }
 
 func (p *MockProvider) GetProviderSchema() providers.GetProviderSchemaResponse {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("GetProviderSchema")
 	p.GetProviderSchemaCalled = true
 	return p.getProviderSchema()
 }
@@ -125,6 +172,7 @@ func (p *MockProvider) getProviderSchema() providers.GetProviderSchemaResponse {
 func (p *MockProvider) ValidateProviderConfig(r providers.ValidateProviderConfigRequest) (resp providers.ValidateProviderConfigResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("ValidateProviderConfig")
 
 	p.ValidateProviderConfigCalled = true
 	p.ValidateProviderConfigRequest = r
@@ -143,6 +191,7 @@ func (p *MockProvider) ValidateProviderConfig(r providers.ValidateProviderConfig
 func (p *MockProvider) ValidateResourceConfig(r providers.ValidateResourceConfigRequest) (resp providers.ValidateResourceConfigResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("ValidateResourceConfig", r.TypeName)
 
 	p.ValidateResourceConfigCalled = true
 	p.ValidateResourceConfigRequest = r
@@ -175,6 +224,7 @@ func (p *MockProvider) ValidateResourceConfig(r providers.ValidateResourceConfig
 func (p *MockProvider) ValidateDataResourceConfig(r providers.ValidateDataResourceConfigRequest) (resp providers.ValidateDataResourceConfigResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("ValidateDataResourceConfig", r.TypeName)
 
 	p.ValidateDataResourceConfigCalled = true
 	p.ValidateDataResourceConfigRequest = r
@@ -205,6 +255,7 @@ func (p *MockProvider) ValidateDataResourceConfig(r providers.ValidateDataResour
 func (p *MockProvider) UpgradeResourceState(r providers.UpgradeResourceStateRequest) (resp providers.UpgradeResourceStateResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("UpgradeResourceState", r.TypeName)
 
 	if !p.ConfigureProviderCalled {
 		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Configure not called before UpgradeResourceState %q", r.TypeName))
@@ -254,6 +305,7 @@ func (p *MockProvider) UpgradeResourceState(r providers.UpgradeResourceStateRequ
 func (p *MockProvider) ConfigureProvider(r providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("ConfigureProvider")
 
 	p.ConfigureProviderCalled = true
 	p.ConfigureProviderRequest = r
@@ -275,6 +327,7 @@ func (p *MockProvider) Stop() error {
 	// be cancelled.  The provider itself is responsible for handling
 	// any concurrency concerns in this case.
 
+	p.recordCall("Stop")
 	p.StopCalled = true
 	if p.StopFn != nil {
 		return p.StopFn()
@@ -286,6 +339,7 @@ func (p *MockProvider) Stop() error {
 func (p *MockProvider) ReadResource(r providers.ReadResourceRequest) (resp providers.ReadResourceResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("ReadResource", r.TypeName)
 
 	p.ReadResourceCalled = true
 	p.ReadResourceRequest = r
@@ -327,6 +381,7 @@ func (p *MockProvider) ReadResource(r providers.ReadResourceRequest) (resp provi
 func (p *MockProvider) PlanResourceChange(r providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("PlanResourceChange", r.TypeName)
 
 	if !p.ConfigureProviderCalled {
 		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Configure not called before PlanResourceChange %q", r.TypeName))
@@ -407,6 +462,7 @@ func (p *MockProvider) PlanResourceChange(r providers.PlanResourceChangeRequest)
 func (p *MockProvider) ApplyResourceChange(r providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("ApplyResourceChange", r.TypeName)
 	p.ApplyResourceChangeCalled = true
 	p.ApplyResourceChangeRequest = r
 
@@ -462,6 +518,7 @@ func (p *MockProvider) ApplyResourceChange(r providers.ApplyResourceChangeReques
 func (p *MockProvider) ImportResourceState(r providers.ImportResourceStateRequest) (resp providers.ImportResourceStateResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("ImportResourceState", r.TypeName, r.ID)
 
 	if !p.ConfigureProviderCalled {
 		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Configure not called before ImportResourceState %q", r.TypeName))
@@ -502,9 +559,50 @@ func (p *MockProvider) ImportResourceState(r providers.ImportResourceStateReques
 	return resp
 }
 
+func (p *MockProvider) MoveResourceState(r providers.MoveResourceStateRequest) (resp providers.MoveResourceStateResponse) {
+	p.Lock()
+	defer p.Unlock()
+	p.recordCall("MoveResourceState", r.SourceTypeName, r.TargetTypeName)
+
+	if !p.ConfigureProviderCalled {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Configure not called before MoveResourceState %q", r.TargetTypeName))
+		return resp
+	}
+
+	schema, ok := p.getProviderSchema().ResourceTypes[r.TargetTypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("no schema found for %q", r.TargetTypeName))
+		return resp
+	}
+
+	p.MoveResourceStateCalled = true
+	p.MoveResourceStateRequest = r
+
+	if p.MoveResourceStateFn != nil {
+		return p.MoveResourceStateFn(r)
+	}
+
+	if p.MoveResourceStateResponse != nil {
+		return *p.MoveResourceStateResponse
+	}
+
+	// Without a response set, the source state is expected to already
+	// conform to the target schema.
+	v, err := ctyjson.Unmarshal(r.SourceStateJSON, schema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	resp.TargetState = v
+	resp.TargetPrivate = r.SourcePrivate
+
+	return resp
+}
+
 func (p *MockProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("ReadDataSource", r.TypeName)
 
 	if !p.ConfigureProviderCalled {
 		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Configure not called before ReadDataSource %q", r.TypeName))
@@ -528,6 +626,7 @@ func (p *MockProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp p
 func (p *MockProvider) GetFunctions() (resp providers.GetFunctionsResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("GetFunctions")
 
 	p.GetFunctionsCalled = true
 
@@ -544,6 +643,7 @@ func (p *MockProvider) GetFunctions() (resp providers.GetFunctionsResponse) {
 func (p *MockProvider) CallFunction(r providers.CallFunctionRequest) (resp providers.CallFunctionResponse) {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("CallFunction", r.Name)
 
 	p.CallFunctionCalled = true
 	p.CallFunctionRequest = r
@@ -561,6 +661,7 @@ func (p *MockProvider) CallFunction(r providers.CallFunctionRequest) (resp provi
 func (p *MockProvider) Close() error {
 	p.Lock()
 	defer p.Unlock()
+	p.recordCall("Close")
 
 	p.CloseCalled = true
 	return p.CloseError
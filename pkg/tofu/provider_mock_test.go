@@ -0,0 +1,35 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/we-dcode/opentofu/pkg/providers"
+)
+
+func TestMockProvider_calls(t *testing.T) {
+	p := simpleMockProvider()
+
+	p.ConfigureProvider(providers.ConfigureProviderRequest{})
+	p.ImportResourceState(providers.ImportResourceStateRequest{
+		TypeName: "test_object",
+		ID:       "foo",
+	})
+
+	got := p.Calls()
+	want := []MockProviderCall{
+		{Method: "ConfigureProvider"},
+		{Method: "ImportResourceState", Args: []string{"test_object", "foo"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong calls\n%s", diff)
+	}
+
+	p.AssertCallOrder(t, "ConfigureProvider", "ImportResourceState")
+}
@@ -6,6 +6,8 @@
 package tofu
 
 import (
+	"testing"
+
 	"github.com/we-dcode/opentofu/pkg/configs/configschema"
 	"github.com/we-dcode/opentofu/pkg/providers"
 	"github.com/zclconf/go-cty/cty"
@@ -140,3 +142,34 @@ func getProviderSchemaResponseFromProviderSchema(providerSchema *ProviderSchema)
 
 	return resp
 }
+
+// AssertCallOrder fails the test unless each of the given methods was called,
+// and the first call of each method happened after the first call of the
+// method before it.
+//
+// For example, AssertCallOrder(t, "ConfigureProvider", "ImportResourceState")
+// checks the provider was configured before anything was imported.
+func (p *MockProvider) AssertCallOrder(t testing.TB, methods ...string) {
+	t.Helper()
+
+	calls := p.Calls()
+	prev := -1
+	for i, method := range methods {
+		first := -1
+		for ix, call := range calls {
+			if call.Method == method {
+				first = ix
+				break
+			}
+		}
+		switch {
+		case first < 0:
+			t.Errorf("%s was never called\ncalls: %s", method, calls)
+			return
+		case first < prev:
+			t.Errorf("%s was called before %s\ncalls: %s", method, methods[i-1], calls)
+			return
+		}
+		prev = first
+	}
+}
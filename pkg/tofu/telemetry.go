@@ -0,0 +1,78 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/tfdiags"
+)
+
+var tracer trace.Tracer
+
+func init() {
+	tracer = otel.Tracer("github.com/we-dcode/opentofu/pkg/tofu")
+}
+
+// The attribute keys used on the spans wrapping provider calls. These are
+// part of OpenTofu's telemetry output, so they must not be changed casually.
+const (
+	traceAttrProviderAddress   = attribute.Key("opentofu.provider.address")
+	traceAttrProviderOperation = attribute.Key("opentofu.provider.operation")
+	traceAttrResourceType      = attribute.Key("opentofu.resource.type")
+	traceAttrResourceAddress   = attribute.Key("opentofu.resource.address")
+)
+
+// The operations recorded in the traceAttrProviderOperation attribute.
+const (
+	providerOperationPlan   = "plan"
+	providerOperationApply  = "apply"
+	providerOperationRead   = "read"
+	providerOperationImport = "import"
+)
+
+// startProviderSpan starts a span for a call to the given method of a
+// provider, as a child of the span of the graph walk the given EvalContext
+// belongs to. The caller must end the returned span once the call returns.
+//
+// When no trace exporter is configured the span is a no-op, and we skip
+// building its attributes entirely to keep the graph walk cheap.
+func startProviderSpan(ctx EvalContext, method, operation string, provider addrs.AbsProviderConfig, providerKey addrs.InstanceKey, addr addrs.AbsResourceInstance) trace.Span {
+	_, span := tracer.Start(ctx.TraceContext(), method)
+	if span.IsRecording() {
+		span.SetAttributes(
+			traceAttrProviderAddress.String(provider.InstanceString(providerKey)),
+			traceAttrProviderOperation.String(operation),
+			traceAttrResourceType.String(addr.Resource.Resource.Type),
+			traceAttrResourceAddress.String(addr.String()),
+		)
+	}
+	return span
+}
+
+// endProviderSpan records the diagnostics returned by a provider call on
+// the given span, and then ends it.
+func endProviderSpan(span trace.Span, diags tfdiags.Diagnostics) {
+	if diags.HasErrors() {
+		span.SetStatus(codes.Error, diags.Err().Error())
+	}
+	span.End()
+}
+
+// traceContextOrBackground returns ctx, or the background context if ctx is
+// nil, which is the case for graph walks started without a context in tests.
+func traceContextOrBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
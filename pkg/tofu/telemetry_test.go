@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/we-dcode/opentofu/pkg/addrs"
+	"github.com/we-dcode/opentofu/pkg/providers"
+	"github.com/we-dcode/opentofu/pkg/states"
+)
+
+func TestProviderSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	oldTracer := tracer
+	tracer = tp.Tracer("test")
+	t.Cleanup(func() {
+		tracer = oldTracer
+	})
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "aws_instance" "foo" {
+}
+`,
+	})
+	p := testProvider("aws")
+	p.PlanResourceChangeFn = testDiffFn
+	p.ApplyResourceChangeFn = testApplyFn
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	plan, diags := ctx.Plan(parentCtx, m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	_, diags = ctx.Apply(parentCtx, plan, m)
+	assertNoErrors(t, diags)
+	parent.End()
+
+	got := map[string]map[attribute.Key]string{}
+	for _, span := range recorder.Ended() {
+		if span.Name() == "parent" {
+			continue
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of the graph walk's span", span.Name())
+		}
+		attrs := map[attribute.Key]string{}
+		for _, attr := range span.Attributes() {
+			attrs[attr.Key] = attr.Value.Emit()
+		}
+		got[span.Name()] = attrs
+	}
+
+	for method, operation := range map[string]string{
+		"PlanResourceChange":  providerOperationPlan,
+		"ApplyResourceChange": providerOperationApply,
+	} {
+		attrs, ok := got[method]
+		if !ok {
+			t.Errorf("no span recorded for %s", method)
+			continue
+		}
+		want := map[attribute.Key]string{
+			traceAttrProviderAddress:   `provider["registry.opentofu.org/hashicorp/aws"]`,
+			traceAttrProviderOperation: operation,
+			traceAttrResourceType:      "aws_instance",
+			traceAttrResourceAddress:   "aws_instance.foo",
+		}
+		for k, v := range want {
+			if attrs[k] != v {
+				t.Errorf("wrong %s attribute on the %s span\ngot:  %q\nwant: %q", k, method, attrs[k], v)
+			}
+		}
+	}
+}
+
+func TestProviderSpans_noExporter(t *testing.T) {
+	ctx := &MockEvalContext{}
+	span := startProviderSpan(ctx, "ReadResource", providerOperationRead, addrs.AbsProviderConfig{}, addrs.NoKey, addrs.AbsResourceInstance{})
+	defer span.End()
+
+	if span.IsRecording() {
+		t.Fatal("span is recording without a configured exporter")
+	}
+}
@@ -6,9 +6,16 @@
 package tofu
 
 type CallbackUIOutput struct {
-	OutputFn func(string)
+	OutputFn   func(string)
+	ProgressFn func(phase string, percent float64)
 }
 
 func (o *CallbackUIOutput) Output(v string) {
 	o.OutputFn(v)
 }
+
+func (o *CallbackUIOutput) Progress(phase string, percent float64) {
+	if o.ProgressFn != nil {
+		o.ProgressFn(phase, percent)
+	}
+}
@@ -22,3 +22,9 @@ func (o *ProvisionerUIOutput) Output(msg string) {
 		h.ProvisionOutput(o.InstanceAddr, o.ProvisionerType, msg)
 	}
 }
+
+func (o *ProvisionerUIOutput) Progress(phase string, percent float64) {
+	for _, h := range o.Hooks {
+		h.ProvisionProgress(o.InstanceAddr, o.ProvisionerType, phase, percent)
+	}
+}
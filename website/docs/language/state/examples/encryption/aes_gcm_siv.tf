@@ -0,0 +1,9 @@
+terraform {
+  encryption {
+    # Key provider configuration here
+
+    method "aes_gcm_siv" "yourname" {
+      keys = key_provider.yourkeyprovider.yourname
+    }
+  }
+}
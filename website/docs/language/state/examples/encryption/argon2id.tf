@@ -0,0 +1,23 @@
+terraform {
+  encryption {
+    key_provider "argon2id" "foo" {
+      # Specify a long / complex passphrase (min. 16 characters)
+      passphrase = "correct-horse-battery-staple"
+
+      # Adjust the key length to the encryption method (default: 32)
+      key_length = 32
+
+      # Specify the memory to use in KiB (min. 19456, default: 65536)
+      memory = 65536
+
+      # Specify the number of passes over the memory (default: 3)
+      iterations = 3
+
+      # Specify the number of lanes (1-255, default: 4)
+      parallelism = 4
+
+      # Specify the salt length in bytes (default: 32)
+      salt_length = 32
+    }
+  }
+}
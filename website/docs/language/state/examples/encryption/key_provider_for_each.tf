@@ -0,0 +1,21 @@
+variable "passphrases" {
+  type      = map(string)
+  sensitive = true
+}
+
+terraform {
+  encryption {
+    key_provider "pbkdf2" "region" {
+      # One instance is created per element, e.g. key_provider.pbkdf2.region["eu"]
+      for_each = var.passphrases
+
+      passphrase = each.value
+    }
+    method "aes_gcm" "eu" {
+      keys = key_provider.pbkdf2.region["eu"]
+    }
+    state {
+      method = method.aes_gcm.eu
+    }
+  }
+}